@@ -0,0 +1,89 @@
+package wui
+
+// app keeps track of all open windows. It is only ever used on the UI thread.
+// Other goroutines use Window.Post and Window.Invoke to run code there.
+var app application
+
+type application struct {
+	// windows are all open windows, in the order they were opened.
+	windows []*Window
+	// active is the window that was activated last.
+	active *Window
+	// closed are the windows that were destroyed but not cleaned up yet.
+	closed  []*Window
+	running bool
+	quit    bool
+}
+
+func (a *application) add(w *Window) {
+	a.windows = append(a.windows, w)
+}
+
+func (a *application) remove(w *Window) {
+	for i := range a.windows {
+		if a.windows[i] == w {
+			a.windows = append(a.windows[:i], a.windows[i+1:]...)
+			break
+		}
+	}
+	if a.active == w {
+		a.active = nil
+	}
+}
+
+// top returns the window that new modal windows and message boxes belong to.
+// This is the active window or, if no window is active, the last opened one.
+func (a *application) top() *Window {
+	if a.active != nil {
+		return a.active
+	}
+	if len(a.windows) > 0 {
+		return a.windows[len(a.windows)-1]
+	}
+	return nil
+}
+
+// cleanUp releases the resources of the windows that were closed.
+func (a *application) cleanUp() {
+	for len(a.closed) > 0 {
+		w := a.closed[0]
+		a.closed = a.closed[1:]
+		w.destroy()
+	}
+}
+
+// Run processes the user input for all open windows. It returns when the last
+// window was closed or when Quit was called. Window.Show calls Run for you so
+// you only need Run if you open your windows with Window.Open.
+//
+// Run must be called on the goroutine that opened the windows. It returns
+// right away if no window is open or if Run is already running.
+func Run() {
+	if app.running {
+		return
+	}
+	app.running = true
+	app.quit = false
+	ui.runMessageLoop(func() bool {
+		app.cleanUp()
+		return app.quit || len(app.windows) == 0
+	})
+	app.running = false
+	app.quit = false
+
+	// After Quit there might still be windows left open.
+	for _, w := range append([]*Window(nil), app.windows...) {
+		w.Destroy()
+	}
+	app.windows = nil
+	app.cleanUp()
+}
+
+// Quit makes Run return. All windows that are still open are destroyed. Their
+// OnCanClose and OnClose functions are not called. Quit must be called on the
+// UI thread, e.g. from an event handler. From other goroutines use
+//
+//	w.Post(wui.Quit)
+func Quit() {
+	app.quit = true
+}
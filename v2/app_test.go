@@ -0,0 +1,91 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestRunReturnsWhenLastWindowCloses(t *testing.T) {
+	h := UseHeadless()
+
+	w1 := NewWindow()
+	w2 := NewWindow()
+	check.Eq(t, w1.Open(), nil)
+	check.Eq(t, w2.Open(), nil)
+	check.Eq(t, w1.Open().Error(), "wui.Window.Open: window already visible")
+
+	w1.Post(func() {
+		handle := w2.Handle()
+		w1.Close()
+		_, ok := h.Control(handle)
+		check.Eq(t, ok, true)
+		w2.Close()
+	})
+	Run()
+
+	check.Eq(t, w1.Handle(), uintptr(0))
+	check.Eq(t, w2.Handle(), uintptr(0))
+}
+
+func TestShowInsideRunningLoopReturnsRightAway(t *testing.T) {
+	UseHeadless()
+
+	main := NewWindow()
+	second := NewWindow()
+	secondShown := false
+	main.SetOnShow(func() {
+		check.Eq(t, second.Show(), nil)
+		secondShown = second.Handle() != 0
+		main.Post(main.Close)
+	})
+	second.SetOnShow(func() {
+		second.Post(second.Close)
+	})
+	check.Eq(t, main.Show(), nil)
+
+	check.Eq(t, secondShown, true)
+	check.Eq(t, main.Handle(), uintptr(0))
+	check.Eq(t, second.Handle(), uintptr(0))
+}
+
+func TestQuitDestroysAllWindows(t *testing.T) {
+	UseHeadless()
+
+	closeCalls := 0
+	var windows []*Window
+	for i := 0; i < 3; i++ {
+		w := NewWindow()
+		w.SetOnClose(func() { closeCalls++ })
+		check.Eq(t, w.Open(), nil)
+		windows = append(windows, w)
+	}
+	windows[1].Post(Quit)
+	Run()
+
+	for _, w := range windows {
+		check.Eq(t, w.Handle(), uintptr(0))
+	}
+	check.Eq(t, closeCalls, 0)
+}
+
+func TestEveryWindowHasItsOwnShortcuts(t *testing.T) {
+	h := UseHeadless()
+
+	var pressed []string
+	w1 := NewWindow()
+	w1.SetShortcut(func() { pressed = append(pressed, "w1") }, KeyControl, KeyA)
+	w2 := NewWindow()
+	w2.SetShortcut(func() { pressed = append(pressed, "w2") }, KeyControl, KeyB)
+	w1.Open()
+	w2.Open()
+
+	h.PressShortcut(w1, KeyControl, KeyA)
+	h.PressShortcut(w1, KeyControl, KeyB)
+	h.PressShortcut(w2, KeyControl, KeyA)
+	h.PressShortcut(w2, KeyControl, KeyB)
+	check.Eq(t, pressed, []string{"w1", "w2"})
+
+	w1.Post(Quit)
+	Run()
+}
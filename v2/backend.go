@@ -0,0 +1,276 @@
+package wui
+
+import (
+	"image"
+	"time"
+)
+
+// ui is the backend that creates and manipulates the native windows and
+// controls. On Windows this defaults to the Win32 API, on other platforms, and
+// after calling UseHeadless, all windows live in memory only.
+var ui backend = newDefaultBackend()
+
+// backend abstracts the native windowing system. All handles are opaque to the
+// rest of the library, they are only ever passed back to the backend.
+type backend interface {
+	createWindow(w *Window) error
+	// runMessageLoop processes the messages for all windows until done
+	// returns true. It calls done after every message.
+	runMessageLoop(done func() bool)
+	// wake makes the message loop run the window's queued calls. It is the
+	// only backend function that is safe to call from any goroutine.
+	wake(window uintptr)
+	// isUIThread returns true if it is called on the thread that created the
+	// windows and runs their message loop. It returns false before the first
+	// window was created. Like wake, it is safe to call from any goroutine.
+	isUIThread() bool
+	close(window uintptr)
+	bounds(window uintptr) (x, y, width, height int)
+	clientBounds(window uintptr) (x, y, width, height int)
+	frameSize(style, exStyle uint, hasMenu bool) (left, top, right, bottom int)
+	windowState(window uintptr) (WindowState, bool)
+	setWindowState(window uintptr, s WindowState)
+	setBackground(window uintptr, c Color)
+	setCursor(window uintptr, cursor uintptr)
+	setIcon(window uintptr, icon uintptr)
+	setAlpha(window uintptr, alpha uint8)
+	setCloseButtonEnabled(window uintptr, enabled bool)
+	setForeground(window uintptr)
+	setAccelerators(window, table uintptr, keys []accelerator) uintptr
+	scroll(window uintptr, dx, dy int)
+	monitor(window uintptr) uintptr
+	// dpi returns the DPI of the monitor that the window is on.
+	dpi(window uintptr) int
+	// setTimer starts or restarts the window's timer with the given ID. The
+	// window's timerFired is called every interval.
+	setTimer(window, id uintptr, interval time.Duration)
+	killTimer(window, id uintptr)
+	// registerHotKey makes the window's hotKeyPressed be called with the ID
+	// when the user presses the keys, in any program. It returns
+	// errHotKeyInUse if the keys are already registered.
+	registerHotKey(window uintptr, id int, keys hotKeyKeys) error
+	unregisterHotKey(window uintptr, id int)
+	now() time.Time
+
+	createMenu() uintptr
+	// insertMenuItem inserts the item before the one at the position.
+	insertMenuItem(menu uintptr, pos int, item menuItemInfo)
+	// setMenuItem changes the item at the position, its ID and sub-menu stay
+	// the same.
+	setMenuItem(menu uintptr, pos int, item menuItemInfo)
+	// removeMenuItem removes the item at the position and destroys its
+	// sub-menu.
+	removeMenuItem(menu uintptr, pos int)
+	setMenuBar(window, menu uintptr)
+	drawMenuBar(window uintptr)
+	createPopupMenu() uintptr
+	// popupMenu shows the menu at the screen position and returns the ID of
+	// the clicked item or 0 if no item was clicked.
+	popupMenu(window, menu uintptr, x, y int) uint
+	// destroyMenu destroys the menu and all its sub-menus.
+	destroyMenu(menu uintptr)
+
+	// setTrayIcon adds or updates the window's tray icon with the given ID.
+	setTrayIcon(window uintptr, id uint, icon uintptr, toolTip string)
+	removeTrayIcon(window uintptr, id uint)
+	showTrayNotification(
+		window uintptr,
+		id uint,
+		title, text string,
+		kind NotificationKind,
+	)
+
+	createChild(
+		parent uintptr,
+		id int,
+		exStyle uint,
+		className string,
+		style uint,
+		x, y, width, height int,
+	) uintptr
+	destroy(handle uintptr)
+	setBounds(handle uintptr, x, y, width, height int)
+	text(handle uintptr) string
+	setText(handle uintptr, text string)
+	setEnabled(handle uintptr, enabled bool)
+	setVisible(handle uintptr, visible bool)
+	style(handle uintptr) (style, exStyle uint)
+	setStyle(handle uintptr, style, exStyle uint)
+	repaint(handle uintptr)
+	setFont(handle uintptr, font uintptr)
+	focus(handle uintptr)
+	focused() uintptr
+	keyDown(key Key) bool
+	hookControl(handle uintptr, hooks controlHooks)
+	notifyParent(handle uintptr, cmd uintptr)
+	forwardNotifications(handle uintptr)
+
+	checked(handle uintptr) bool
+	setChecked(handle uintptr, checked bool)
+
+	selection(handle uintptr) (start, end int)
+	setSelection(handle uintptr, start, end int)
+	passwordChar(handle uintptr) rune
+	setPasswordChar(handle uintptr, char rune)
+	textLimit(handle uintptr) int
+	setTextLimit(handle uintptr, limit int)
+	setReadOnly(handle uintptr, readOnly bool)
+	setErrorText(handle uintptr, text string)
+
+	// setToolTipOptions sets the timing of the tooltip window and the width at
+	// which its lines wrap. Durations of 0 mean the system default.
+	setToolTipOptions(toolTip uintptr, delay, duration time.Duration, maxWidth int)
+	// addToolTip registers the control with the tooltip. The text is queried
+	// from the window's toolTipText when the tooltip is about to appear.
+	addToolTip(toolTip, window, control uintptr)
+	removeToolTip(toolTip, window, control uintptr)
+
+	addItem(handle uintptr, item string)
+	clearItems(handle uintptr)
+	selectedItem(handle uintptr) int
+	selectItem(handle uintptr, index int)
+
+	setSliderRange(handle uintptr, min, max int)
+	sliderPosition(handle uintptr) int
+	setSliderPosition(handle uintptr, pos int)
+	setSliderTickFrequency(handle uintptr, n int)
+	setSliderArrowIncrement(handle uintptr, inc int)
+	setSliderMouseIncrement(handle uintptr, inc int)
+
+	setProgressMarquee(handle uintptr, marquee bool)
+	setProgressRange(handle uintptr, max int)
+	setProgress(handle uintptr, pos int)
+
+	setUpDownBuddy(handle, buddy uintptr)
+	setUpDownRange(handle uintptr, min, max int32)
+	upDownPosition(handle uintptr) int32
+	setUpDownPosition(handle uintptr, pos int32)
+
+	setListViewColumns(handle uintptr, headers []string)
+	insertListViewRow(handle uintptr, row int)
+	setListViewCell(handle uintptr, col, row int, text string)
+	deleteListViewRow(handle uintptr, row int)
+	pressKey(handle uintptr, key Key)
+
+	// setTreeImages replaces the image list of the tree view. The images all
+	// have the same size, pass no images to remove the list.
+	setTreeImages(handle uintptr, images []*Image)
+	// insertTreeItem appends an item to the children of parent, or to the
+	// top-level items if parent is 0, and returns the new item.
+	insertTreeItem(handle, parent uintptr, info treeItem) uintptr
+	setTreeItem(handle, item uintptr, info treeItem)
+	// deleteTreeItem deletes the item and all its children.
+	deleteTreeItem(handle, item uintptr)
+	expandTreeItem(handle, item uintptr, expand bool)
+	// selectTreeItem selects the item, 0 removes the selection.
+	selectTreeItem(handle, item uintptr)
+
+	// setTabImages replaces the image list of the tab control. The images all
+	// have the same size, pass no images to remove the list.
+	setTabImages(handle uintptr, images []*Image)
+	// insertTab inserts a tab before the one at the index.
+	insertTab(handle uintptr, index int, info tabItem)
+	setTab(handle uintptr, index int, info tabItem)
+	deleteTab(handle uintptr, index int)
+	// selectTab selects the tab at the index, an invalid index removes the
+	// selection.
+	selectTab(handle uintptr, index int)
+	// tabInsets returns the distances, in pixels, from the tab control's
+	// borders to the area in which it shows the selected page.
+	tabInsets(handle uintptr) (left, top, right, bottom int)
+
+	// setStatusParts divides the status bar into parts that end at the given
+	// right edges, in pixels. A right edge of -1 reaches the status bar's
+	// border.
+	setStatusParts(handle uintptr, rights []int)
+	setStatusText(handle uintptr, part int, text string)
+	// setStatusIcon sets the icon of the part, 0 removes it.
+	setStatusIcon(handle uintptr, part int, icon uintptr)
+	// statusBarHeight returns the height of the status bar in pixels.
+	statusBarHeight(handle uintptr) int
+
+	createFont(desc FontDesc) (handle uintptr, exactMatch bool)
+	deleteFont(font uintptr)
+	loadIcon(id uint16) uintptr
+	createIcon(data []byte) uintptr
+	loadIconResource(id int) uintptr
+	loadIconFile(path string) (uintptr, error)
+	loadCursor(id uint16) uintptr
+	createCursor(x, y, width, height int, and, xor []byte) uintptr
+	createBitmap(img *image.RGBA) uintptr
+	// iconBitmap returns the icon as a bitmap with alpha channel, in the size
+	// of a small icon.
+	iconBitmap(icon uintptr) uintptr
+	sysColor(index int) Color
+
+	messageBox(owner uintptr, caption, text string, flags uint) int
+	openFiles(
+		owner uintptr,
+		title, initPath string,
+		filters []fileFilter,
+		filterIndex int,
+		multiSelect bool,
+	) (bool, []string)
+	saveFile(
+		owner uintptr,
+		title, initPath string,
+		filters []fileFilter,
+		filterIndex int,
+	) (ok bool, path string, selectedFilter int)
+	selectFolder(owner uintptr, title string) (bool, string)
+
+	registerClipboardFormat(name string) (ClipboardFormat, error)
+	hasClipboardFormat(f ClipboardFormat) bool
+	// clipboardData returns nil if the clipboard does not contain the format.
+	clipboardData(f ClipboardFormat) ([]byte, error)
+	// setClipboardData replaces the clipboard contents with the items.
+	setClipboardData(items []clipboardItem) error
+}
+
+// controlHooks are callbacks for messages that a control would otherwise
+// handle itself. Nil functions are not called.
+type controlHooks struct {
+	// char returns true if the character was handled and must not be passed
+	// on to the control.
+	char        func(r rune) bool
+	keyDown     func(key int)
+	keyUp       func(key int)
+	focusGained func()
+	focusLost   func()
+	mouseMove   func(x, y int)
+	mouseLeave  func()
+	mouseDown   func(button MouseButton, x, y int)
+	mouseUp     func(button MouseButton, x, y int)
+	doubleClick func(button MouseButton, x, y int)
+	mouseWheel  func(x, y int, delta float64)
+	// wantsMouse returns true if a control that is transparent to the mouse,
+	// like a static control, should receive mouse messages anyway. Without
+	// it, these controls only get mouseMove.
+	wantsMouse func() bool
+	// contextMenu is called when the user right-clicks the control or presses
+	// the menu key or Shift+F10 while it has the focus. x and y are screen
+	// coordinates. It returns false if the control has no context menu, the
+	// request then goes to the control's parent.
+	contextMenu func(x, y int) bool
+}
+
+// painter does the drawing for a Canvas.
+type painter interface {
+	handle() uintptr
+	pushDrawRegion(x, y, width, height int)
+	popDrawRegion()
+	clearDrawRegions()
+	rect(x, y, width, height int, c Color, fill bool)
+	line(x1, y1, x2, y2 int, c Color)
+	ellipse(x, y, width, height int, c Color, fill bool)
+	polyline(p []Point, c Color)
+	polygon(p []Point, c Color)
+	arc(x, y, width, height, x1, y1, x2, y2 int, c Color)
+	pie(x, y, width, height, x1, y1, x2, y2 int, c Color, fill bool)
+	textExtent(s string) (width, height int)
+	textOut(x, y int, s string, c Color)
+	textRectExtent(s string, width int) (int, int)
+	textRect(x, y, width, height int, s string, f Format, c Color)
+	setFont(f *Font)
+	drawImage(img *Image, src Rectangle, destX, destY int)
+}
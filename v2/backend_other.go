@@ -0,0 +1,8 @@
+//go:build !windows
+// +build !windows
+
+package wui
+
+func newDefaultBackend() backend {
+	return newHeadless()
+}
@@ -0,0 +1,702 @@
+package wui
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+// winAPI is the backend that uses the Win32 API. It is only ever used from the
+// UI thread so it needs no synchronization, except for uiThread.
+type winAPI struct {
+	// uiThread is the ID of the thread that created the top-level windows. It
+	// is accessed atomically, see isUIThread.
+	uiThread uint32
+	// classes holds the window classes that were registered for top-level
+	// windows. They are unregistered when the window is destroyed.
+	classes map[w32.HWND]w32.ATOM
+	// backBuffers are the off-screen bitmaps that PaintBoxes draw to.
+	backBuffers map[w32.HWND]*backBuffer
+	// hooks are the controlHooks installed with hookControl, by subclass ID.
+	hooks      map[uintptr]controlHooks
+	nextHookID uintptr
+	// errorTexts are the messages of invalid edit controls, see setErrorText.
+	errorTexts map[w32.HWND]string
+	// dropTargets are the IDropTargets of the top-level windows.
+	dropTargets    map[w32.HWND]*dropTarget
+	oleInitialized bool
+	// clipboardListener is the window that receives WM_CLIPBOARDUPDATE.
+	clipboardListener w32.HWND
+	// trayIcons are the tray icons that were added with Shell_NotifyIcon.
+	trayIcons map[trayIconKey]bool
+	// toolTipText is the text that the tooltip asked for last.
+	toolTipText []uint16
+}
+
+func newDefaultBackend() backend {
+	return &winAPI{
+		classes:     make(map[w32.HWND]w32.ATOM),
+		backBuffers: make(map[w32.HWND]*backBuffer),
+		hooks:       make(map[uintptr]controlHooks),
+		errorTexts:  make(map[w32.HWND]string),
+		dropTargets: make(map[w32.HWND]*dropTarget),
+		trayIcons:   make(map[trayIconKey]bool),
+	}
+}
+
+func (*winAPI) createChild(
+	parent uintptr,
+	id int,
+	exStyle uint,
+	className string,
+	style uint,
+	x, y, width, height int,
+) uintptr {
+	p := w32.HWND(parent)
+	return uintptr(w32.CreateWindowExStr(
+		exStyle,
+		className,
+		"",
+		style,
+		x, y, width, height,
+		p, w32.HMENU(id), w32.HINSTANCE(w32.GetWindowLong(p, w32.GWL_HINSTANCE)), nil,
+	))
+}
+
+func (a *winAPI) destroy(handle uintptr) {
+	h := w32.HWND(handle)
+	w32.DestroyWindow(h)
+	if b, ok := a.backBuffers[h]; ok {
+		b.release()
+		delete(a.backBuffers, h)
+	}
+	if atom, ok := a.classes[h]; ok {
+		w32.UnregisterClassAtom(atom, w32.GetModuleHandle(""))
+		delete(a.classes, h)
+	}
+}
+
+func (*winAPI) setBounds(handle uintptr, x, y, width, height int) {
+	w32.SetWindowPos(
+		w32.HWND(handle), 0,
+		x, y, width, height,
+		w32.SWP_NOOWNERZORDER|w32.SWP_NOZORDER,
+	)
+}
+
+func (*winAPI) text(handle uintptr) string {
+	return w32.GetWindowText(w32.HWND(handle))
+}
+
+func (*winAPI) setText(handle uintptr, text string) {
+	w32.SetWindowText(w32.HWND(handle), text)
+}
+
+func (*winAPI) setEnabled(handle uintptr, enabled bool) {
+	w32.EnableWindow(w32.HWND(handle), enabled)
+}
+
+func (*winAPI) setVisible(handle uintptr, visible bool) {
+	h := w32.HWND(handle)
+	if visible {
+		w32.ShowWindow(h, w32.SW_SHOW)
+	} else {
+		w32.ShowWindow(h, w32.SW_HIDE)
+	}
+	w32.InvalidateRect(h, nil, true)
+}
+
+func (*winAPI) style(handle uintptr) (style, exStyle uint) {
+	h := w32.HWND(handle)
+	style = uint(w32.GetWindowLongPtr(h, w32.GWL_STYLE))
+	exStyle = uint(w32.GetWindowLongPtr(h, w32.GWL_EXSTYLE))
+	return
+}
+
+func (*winAPI) setStyle(handle uintptr, style, exStyle uint) {
+	h := w32.HWND(handle)
+	w32.SetWindowLongPtr(h, w32.GWL_STYLE, uintptr(style))
+	w32.SetWindowLongPtr(h, w32.GWL_EXSTYLE, uintptr(exStyle))
+	// SetWindowPos is necessary to make Windows realize the changes in styles.
+	w32.SetWindowPos(
+		h, 0, 0, 0, 0, 0,
+		w32.SWP_FRAMECHANGED|w32.SWP_NOMOVE|w32.SWP_NOZORDER|
+			w32.SWP_NOSIZE|w32.SWP_NOACTIVATE,
+	)
+}
+
+func (*winAPI) repaint(handle uintptr) {
+	w32.InvalidateRect(w32.HWND(handle), nil, true)
+}
+
+func (*winAPI) setFont(handle uintptr, font uintptr) {
+	w32.SendMessage(w32.HWND(handle), w32.WM_SETFONT, font, 1)
+}
+
+func (*winAPI) focus(handle uintptr) {
+	w32.SetFocus(w32.HWND(handle))
+}
+
+func (*winAPI) focused() uintptr {
+	return uintptr(w32.GetFocus())
+}
+
+func (*winAPI) keyDown(key Key) bool {
+	return w32.GetKeyState(int(key))&0x8000 != 0
+}
+
+func (a *winAPI) hookControl(handle uintptr, hooks controlHooks) {
+	a.nextHookID++
+	a.hooks[a.nextHookID] = hooks
+	w32.SetWindowSubclass(w32.HWND(handle), hookProc, a.nextHookID, 0)
+}
+
+// hookProc is the subclass procedure for all controls that have hooks
+// installed. Each call to hookControl adds another subclass so the latest hooks
+// are called first.
+var hookProc = syscall.NewCallback(func(
+	window w32.HWND,
+	msg uint32,
+	wParam, lParam uintptr,
+	subclassID uintptr,
+	refData uintptr,
+) uintptr {
+	a := ui.(*winAPI)
+	hooks := a.hooks[subclassID]
+	switch msg {
+	case w32.WM_CHAR:
+		if hooks.char != nil && hooks.char(rune(wParam)) {
+			return 0
+		}
+	case w32.WM_KEYDOWN:
+		if hooks.keyDown != nil {
+			hooks.keyDown(int(wParam))
+		}
+	case w32.WM_KEYUP:
+		if hooks.keyUp != nil {
+			hooks.keyUp(int(wParam))
+		}
+	case w32.WM_SETFOCUS:
+		if hooks.focusGained != nil {
+			hooks.focusGained()
+		}
+	case w32.WM_KILLFOCUS:
+		if hooks.focusLost != nil {
+			hooks.focusLost()
+		}
+	case w32.WM_NCHITTEST:
+		// Static controls are transparent to the mouse, the mouse messages go
+		// to their parent. We report mouse movement anyway and only take the
+		// other mouse messages if the control wants them.
+		ret := w32.DefSubclassProc(window, msg, wParam, lParam)
+		if int32(ret) == w32.HTTRANSPARENT {
+			if hooks.wantsMouse != nil && hooks.wantsMouse() {
+				return w32.HTCLIENT
+			}
+			if hooks.mouseMove != nil {
+				x, y := mousePos(lParam)
+				x, y, _ = w32.ScreenToClient(window, x, y)
+				hooks.mouseMove(x, y)
+			}
+		}
+		return ret
+	case w32.WM_MOUSEMOVE:
+		if hooks.mouseLeave != nil {
+			// We have to ask for WM_MOUSELEAVE every time the mouse enters.
+			w32.TrackMouseEvent(&w32.TRACKMOUSEEVENT{
+				CbSize:    uint32(unsafe.Sizeof(w32.TRACKMOUSEEVENT{})),
+				DwFlags:   w32.TME_LEAVE,
+				HwndTrack: window,
+			})
+		}
+		if hooks.mouseMove != nil {
+			hooks.mouseMove(mousePos(lParam))
+		}
+	case w32.WM_MOUSELEAVE:
+		if hooks.mouseLeave != nil {
+			hooks.mouseLeave()
+		}
+	case w32.WM_LBUTTONDOWN, w32.WM_MBUTTONDOWN, w32.WM_RBUTTONDOWN:
+		if hooks.mouseDown != nil {
+			x, y := mousePos(lParam)
+			hooks.mouseDown(mouseButton(msg), x, y)
+		}
+	case w32.WM_LBUTTONUP, w32.WM_MBUTTONUP, w32.WM_RBUTTONUP:
+		if hooks.mouseUp != nil {
+			x, y := mousePos(lParam)
+			hooks.mouseUp(mouseButton(msg), x, y)
+		}
+	case w32.WM_LBUTTONDBLCLK, w32.WM_MBUTTONDBLCLK, w32.WM_RBUTTONDBLCLK:
+		if hooks.doubleClick != nil {
+			x, y := mousePos(lParam)
+			hooks.doubleClick(mouseButton(msg), x, y)
+		}
+	case w32.WM_MOUSEWHEEL:
+		if hooks.mouseWheel != nil {
+			// The wheel position is given in screen coordinates.
+			x, y := mousePos(lParam)
+			x, y, _ = w32.ScreenToClient(window, x, y)
+			delta := float64(int16((wParam&0xFFFF0000)>>16)) / 120
+			hooks.mouseWheel(x, y, delta)
+		}
+	case w32.WM_CONTEXTMENU:
+		if hooks.contextMenu != nil {
+			x, y := contextMenuPos(window, lParam)
+			if hooks.contextMenu(x, y) {
+				return 0
+			}
+		}
+	case w32.WM_NCDESTROY:
+		delete(a.hooks, subclassID)
+	}
+	return w32.DefSubclassProc(window, msg, wParam, lParam)
+})
+
+// mousePos extracts the signed mouse coordinates from a mouse message.
+func mousePos(lParam uintptr) (x, y int) {
+	return int(int16(lParam & 0xFFFF)), int(int16((lParam & 0xFFFF0000) >> 16))
+}
+
+// contextMenuPos returns the screen position of a WM_CONTEXTMENU message. If
+// the menu was requested with the keyboard, the position is -1,-1 and we use
+// the top-left corner of the window's client area instead.
+func contextMenuPos(window w32.HWND, lParam uintptr) (x, y int) {
+	x, y = mousePos(lParam)
+	if x == -1 && y == -1 {
+		x, y = w32.ClientToScreen(window, 0, 0)
+	}
+	return x, y
+}
+
+// mouseButton returns the button of a button down, up or double-click message.
+func mouseButton(msg uint32) MouseButton {
+	switch msg {
+	case w32.WM_MBUTTONDOWN, w32.WM_MBUTTONUP, w32.WM_MBUTTONDBLCLK:
+		return MouseButtonMiddle
+	case w32.WM_RBUTTONDOWN, w32.WM_RBUTTONUP, w32.WM_RBUTTONDBLCLK:
+		return MouseButtonRight
+	}
+	return MouseButtonLeft
+}
+
+func (*winAPI) notifyParent(handle uintptr, cmd uintptr) {
+	h := w32.HWND(handle)
+	id := w32.GetDlgCtrlID(h)
+	w32.SendMessage(
+		parentOf(h),
+		w32.WM_COMMAND,
+		uintptr(id)&0xFFFF|(cmd<<16),
+		handle,
+	)
+}
+
+// parentOf returns the parent of the given child window.
+func parentOf(child w32.HWND) w32.HWND {
+	return w32.HWND(w32.GetWindowLongPtr(child, w32.GWLP_HWNDPARENT))
+}
+
+func (*winAPI) forwardNotifications(handle uintptr) {
+	w32.SetWindowSubclass(w32.HWND(handle), forwardProc, 0, 0)
+}
+
+// forwardProc passes the notifications that a container receives from its
+// child controls on to its own parent, until they reach the top-level window.
+var forwardProc = syscall.NewCallback(func(
+	window w32.HWND,
+	msg uint32,
+	wParam, lParam uintptr,
+	subclassID uintptr,
+	refData uintptr,
+) uintptr {
+	switch msg {
+	case w32.WM_COMMAND, w32.WM_DRAWITEM, w32.WM_NOTIFY:
+		// Some notifications, e.g. of tree views, need the window's result.
+		return w32.SendMessage(parentOf(window), msg, wParam, lParam)
+	default:
+		return w32.DefSubclassProc(window, msg, wParam, lParam)
+	}
+})
+
+func (*winAPI) frameSize(style, exStyle uint, hasMenu bool) (left, top, right, bottom int) {
+	var r w32.RECT
+	w32.AdjustWindowRectEx(&r, style, hasMenu, exStyle)
+	return -int(r.Left), -int(r.Top), int(r.Right), int(r.Bottom)
+}
+
+func (*winAPI) checked(handle uintptr) bool {
+	return w32.SendMessage(w32.HWND(handle), w32.BM_GETCHECK, 0, 0) == w32.BST_CHECKED
+}
+
+func (*winAPI) setChecked(handle uintptr, checked bool) {
+	var state uintptr = w32.BST_UNCHECKED
+	if checked {
+		state = w32.BST_CHECKED
+	}
+	w32.SendMessage(w32.HWND(handle), w32.BM_SETCHECK, state, 0)
+}
+
+func (*winAPI) selection(handle uintptr) (start, end int) {
+	var s, e uint32
+	w32.SendMessage(
+		w32.HWND(handle),
+		w32.EM_GETSEL,
+		uintptr(unsafe.Pointer(&s)),
+		uintptr(unsafe.Pointer(&e)),
+	)
+	return int(s), int(e)
+}
+
+func (*winAPI) setSelection(handle uintptr, start, end int) {
+	h := w32.HWND(handle)
+	w32.SendMessage(h, w32.EM_SETSEL, uintptr(uint32(start)), uintptr(uint32(end)))
+	w32.SendMessage(h, w32.EM_SCROLLCARET, 0, 0)
+}
+
+func (*winAPI) passwordChar(handle uintptr) rune {
+	return rune(w32.SendMessage(w32.HWND(handle), w32.EM_GETPASSWORDCHAR, 0, 0))
+}
+
+func (*winAPI) setPasswordChar(handle uintptr, char rune) {
+	w32.SendMessage(w32.HWND(handle), w32.EM_SETPASSWORDCHAR, uintptr(char), 0)
+}
+
+func (*winAPI) textLimit(handle uintptr) int {
+	return int(w32.SendMessage(w32.HWND(handle), w32.EM_GETLIMITTEXT, 0, 0))
+}
+
+func (*winAPI) setTextLimit(handle uintptr, limit int) {
+	w32.SendMessage(w32.HWND(handle), w32.EM_SETLIMITTEXT, uintptr(limit), 0)
+}
+
+func (*winAPI) setReadOnly(handle uintptr, readOnly bool) {
+	var w uintptr
+	if readOnly {
+		w = 1
+	}
+	w32.SendMessage(w32.HWND(handle), w32.EM_SETREADONLY, w, 0)
+}
+
+func (a *winAPI) setErrorText(handle uintptr, text string) {
+	h := w32.HWND(handle)
+	if _, ok := a.errorTexts[h]; !ok {
+		w32.SetWindowSubclass(h, errorProc, 0, 0)
+	}
+	a.errorTexts[h] = text
+	if text == "" {
+		w32.SendMessage(h, emHideBalloonTip, 0, 0)
+	} else if w32.GetFocus() == h {
+		showBalloonTip(h, text)
+	}
+	w32.RedrawWindow(h, nil, 0, w32.RDW_FRAME|w32.RDW_INVALIDATE)
+}
+
+// errorProc draws a red border around edit controls that have an error text
+// and shows the text when they get the focus.
+var errorProc = syscall.NewCallback(func(
+	window w32.HWND,
+	msg uint32,
+	wParam, lParam uintptr,
+	subclassID uintptr,
+	refData uintptr,
+) uintptr {
+	a := ui.(*winAPI)
+	switch msg {
+	case w32.WM_NCPAINT:
+		ret := w32.DefSubclassProc(window, msg, wParam, lParam)
+		if a.errorTexts[window] != "" {
+			drawErrorBorder(window)
+		}
+		return ret
+	case w32.WM_SETFOCUS:
+		ret := w32.DefSubclassProc(window, msg, wParam, lParam)
+		if text := a.errorTexts[window]; text != "" {
+			showBalloonTip(window, text)
+		}
+		return ret
+	case w32.WM_NCDESTROY:
+		delete(a.errorTexts, window)
+	}
+	return w32.DefSubclassProc(window, msg, wParam, lParam)
+})
+
+// drawErrorBorder paints over the 2 pixel client edge of an edit control.
+func drawErrorBorder(window w32.HWND) {
+	r := w32.GetWindowRect(window)
+	width, height := r.Width(), r.Height()
+	dc := w32.GetWindowDC(window)
+	defer w32.ReleaseDC(window, dc)
+	const red = 0x0000FF // COLORREF is 0x00BBGGRR.
+	brush := w32.CreateSolidBrush(red)
+	defer w32.DeleteObject(w32.HGDIOBJ(brush))
+	const border = 2
+	for _, edge := range []w32.RECT{
+		{Left: 0, Top: 0, Right: width, Bottom: border},
+		{Left: 0, Top: height - border, Right: width, Bottom: height},
+		{Left: 0, Top: 0, Right: border, Bottom: height},
+		{Left: width - border, Top: 0, Right: width, Bottom: height},
+	} {
+		w32.FillRect(dc, &edge, brush)
+	}
+}
+
+func showBalloonTip(window w32.HWND, text string) {
+	tip := editBalloonTip{
+		title: syscall.StringToUTF16Ptr(""),
+		text:  syscall.StringToUTF16Ptr(text),
+		icon:  w32.TTI_ERROR,
+	}
+	tip.size = uint32(unsafe.Sizeof(tip))
+	w32.SendMessage(window, emShowBalloonTip, 0, uintptr(unsafe.Pointer(&tip)))
+}
+
+// isComboBox tells combo boxes apart from list boxes which have their own set
+// of messages for the same operations.
+func isComboBox(h w32.HWND) bool {
+	name, _ := w32.GetClassName(h)
+	return name == "ComboBox"
+}
+
+func (*winAPI) addItem(handle uintptr, item string) {
+	h := w32.HWND(handle)
+	ptr, _ := syscall.UTF16PtrFromString(item)
+	var msg uint32 = w32.LB_ADDSTRING
+	if isComboBox(h) {
+		msg = w32.CB_ADDSTRING
+	}
+	w32.SendMessage(h, msg, 0, uintptr(unsafe.Pointer(ptr)))
+}
+
+func (*winAPI) clearItems(handle uintptr) {
+	h := w32.HWND(handle)
+	var msg uint32 = w32.LB_RESETCONTENT
+	if isComboBox(h) {
+		msg = w32.CB_RESETCONTENT
+	}
+	w32.SendMessage(h, msg, 0, 0)
+}
+
+func (*winAPI) selectedItem(handle uintptr) int {
+	h := w32.HWND(handle)
+	var msg uint32 = w32.LB_GETCURSEL
+	if isComboBox(h) {
+		msg = w32.CB_GETCURSEL
+	}
+	return int(w32.SendMessage(h, msg, 0, 0))
+}
+
+func (*winAPI) selectItem(handle uintptr, index int) {
+	h := w32.HWND(handle)
+	var msg uint32 = w32.LB_SETCURSEL
+	if isComboBox(h) {
+		msg = w32.CB_SETCURSEL
+	}
+	w32.SendMessage(h, msg, uintptr(index), 0)
+}
+
+func (*winAPI) setSliderRange(handle uintptr, min, max int) {
+	const redraw = 1
+	w32.SendMessage(w32.HWND(handle), w32.TBM_SETRANGEMIN, 0, uintptr(min))
+	w32.SendMessage(w32.HWND(handle), w32.TBM_SETRANGEMAX, redraw, uintptr(max))
+}
+
+func (*winAPI) sliderPosition(handle uintptr) int {
+	return int(w32.SendMessage(w32.HWND(handle), w32.TBM_GETPOS, 0, 0))
+}
+
+func (*winAPI) setSliderPosition(handle uintptr, pos int) {
+	const redraw = 1
+	w32.SendMessage(w32.HWND(handle), w32.TBM_SETPOS, redraw, uintptr(pos))
+}
+
+func (*winAPI) setSliderTickFrequency(handle uintptr, n int) {
+	w32.SendMessage(w32.HWND(handle), w32.TBM_SETTICFREQ, uintptr(n), 0)
+}
+
+func (*winAPI) setSliderArrowIncrement(handle uintptr, inc int) {
+	w32.SendMessage(w32.HWND(handle), w32.TBM_SETLINESIZE, 0, uintptr(inc))
+}
+
+func (*winAPI) setSliderMouseIncrement(handle uintptr, inc int) {
+	w32.SendMessage(w32.HWND(handle), w32.TBM_SETPAGESIZE, 0, uintptr(inc))
+}
+
+func (*winAPI) setProgressMarquee(handle uintptr, marquee bool) {
+	var w uintptr
+	if marquee {
+		w = 1
+	}
+	w32.SendMessage(w32.HWND(handle), w32.PBM_SETMARQUEE, w, 0)
+}
+
+func (*winAPI) setProgressRange(handle uintptr, max int) {
+	w32.SendMessage(w32.HWND(handle), w32.PBM_SETRANGE32, 0, uintptr(max))
+}
+
+func (*winAPI) setProgress(handle uintptr, pos int) {
+	w32.SendMessage(w32.HWND(handle), w32.PBM_SETPOS, uintptr(pos), 0)
+}
+
+func (*winAPI) setUpDownBuddy(handle, buddy uintptr) {
+	w32.SendMessage(w32.HWND(handle), w32.UDM_SETBUDDY, buddy, 0)
+}
+
+func (*winAPI) setUpDownRange(handle uintptr, min, max int32) {
+	w32.SendMessage(w32.HWND(handle), w32.UDM_SETRANGE32, uintptr(min), uintptr(max))
+}
+
+func (*winAPI) upDownPosition(handle uintptr) int32 {
+	return int32(w32.SendMessage(w32.HWND(handle), w32.UDM_GETPOS32, 0, 0))
+}
+
+func (*winAPI) setUpDownPosition(handle uintptr, pos int32) {
+	w32.SendMessage(w32.HWND(handle), w32.UDM_SETPOS32, 0, uintptr(pos))
+}
+
+func (*winAPI) setListViewColumns(handle uintptr, headers []string) {
+	h := w32.HWND(handle)
+	w32.SendMessage(h, w32.LVM_SETEXTENDEDLISTVIEWSTYLE, 0,
+		w32.LVS_EX_FULLROWSELECT|w32.LVS_EX_DOUBLEBUFFER|w32.LVS_EX_GRIDLINES)
+
+	hdc := w32.GetDC(h)
+	defer w32.ReleaseDC(h, hdc)
+	for i := range headers {
+		header, _ := syscall.UTF16PtrFromString(headers[i])
+		var w int32 = 5
+		size, ok := w32.GetTextExtentPoint32(hdc, headers[i])
+		if ok {
+			w = size.CX
+		}
+		w32.SendMessage(h, w32.LVM_INSERTCOLUMN, uintptr(i), uintptr(unsafe.Pointer(
+			&w32.LVCOLUMN{
+				Mask:     w32.LVCF_FMT | w32.LVCF_WIDTH | w32.LVCF_TEXT | w32.LVCF_SUBITEM,
+				Fmt:      w32.LVCFMT_CENTER,
+				Cx:       w + 12, // we need a margin or the headers will not be fully displayed
+				PszText:  header,
+				ISubItem: int32(i + 1),
+			})))
+	}
+}
+
+func (*winAPI) insertListViewRow(handle uintptr, row int) {
+	w32.SendMessage(
+		w32.HWND(handle),
+		w32.LVM_INSERTITEM,
+		0,
+		uintptr(unsafe.Pointer(&w32.LVITEM{IItem: int32(row)})),
+	)
+}
+
+func (*winAPI) setListViewCell(handle uintptr, col, row int, text string) {
+	t, _ := syscall.UTF16PtrFromString(text)
+	w32.SendMessage(w32.HWND(handle), w32.LVM_SETITEMTEXT, uintptr(row),
+		uintptr(unsafe.Pointer(&w32.LVITEM{
+			Mask:     w32.LVIF_TEXT,
+			PszText:  t,
+			ISubItem: int32(col),
+		})))
+}
+
+func (*winAPI) deleteListViewRow(handle uintptr, row int) {
+	w32.SendMessage(w32.HWND(handle), w32.LVM_DELETEITEM, uintptr(row), 0)
+}
+
+func (*winAPI) pressKey(handle uintptr, key Key) {
+	w32.SendMessage(w32.HWND(handle), w32.WM_KEYDOWN, uintptr(key), 0)
+	w32.SendMessage(w32.HWND(handle), w32.WM_KEYUP, uintptr(key), 0)
+}
+
+func (*winAPI) deleteFont(font uintptr) {
+	w32.DeleteObject(w32.HGDIOBJ(font))
+}
+
+func (*winAPI) createFont(desc FontDesc) (handle uintptr, exactMatch bool) {
+	var weight int32 = w32.FW_NORMAL
+	if desc.Bold {
+		weight = w32.FW_BOLD
+	}
+	byteBool := func(b bool) byte {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	logfont := w32.LOGFONT{
+		Height:         int32(desc.Height),
+		Width:          0,
+		Escapement:     0,
+		Orientation:    0,
+		Weight:         weight,
+		Italic:         byteBool(desc.Italic),
+		Underline:      byteBool(desc.Underlined),
+		StrikeOut:      byteBool(desc.StrikedOut),
+		CharSet:        w32.DEFAULT_CHARSET,
+		OutPrecision:   w32.OUT_CHARACTER_PRECIS,
+		ClipPrecision:  w32.CLIP_CHARACTER_PRECIS,
+		Quality:        w32.DEFAULT_QUALITY,
+		PitchAndFamily: w32.DEFAULT_PITCH | w32.FF_DONTCARE,
+	}
+	logfont.SetFaceName(desc.Name)
+
+	w32.EnumFontFamiliesEx(w32.GetDC(0), logfont, func(*w32.ENUMLOGFONTEX, *w32.ENUMTEXTMETRIC, w32.FontType) bool {
+		exactMatch = true
+		return false
+	})
+
+	return uintptr(w32.CreateFontIndirect(&logfont)), exactMatch
+}
+
+func (*winAPI) loadIcon(id uint16) uintptr {
+	return uintptr(w32.LoadIcon(0, w32.MakeIntResource(id)))
+}
+
+func (*winAPI) createIcon(data []byte) uintptr {
+	return uintptr(w32.CreateIconFromResource(
+		unsafe.Pointer(&data[0]),
+		uint32(len(data)),
+		true, // true for icons, false for cursors.
+		// 0x30000 is a magic constant from the docs:
+		// https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-createiconfromresource
+		0x30000,
+	))
+}
+
+func (*winAPI) loadIconResource(id int) uintptr {
+	return uintptr(w32.LoadImage(
+		w32.GetModuleHandle(""),
+		w32.MakeIntResource(uint16(id)),
+		w32.IMAGE_ICON,
+		0,
+		0,
+		w32.LR_DEFAULTSIZE|w32.LR_SHARED,
+	))
+}
+
+func (*winAPI) loadIconFile(path string) (uintptr, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, errors.New("invalid path: " + err.Error())
+	}
+	return uintptr(w32.LoadImage(
+		0,
+		p,
+		w32.IMAGE_ICON,
+		0, 0,
+		w32.LR_LOADFROMFILE|w32.LR_DEFAULTSIZE,
+	)), nil
+}
+
+func (*winAPI) loadCursor(id uint16) uintptr {
+	return uintptr(w32.LoadCursor(0, w32.MakeIntResource(id)))
+}
+
+func (*winAPI) createCursor(x, y, width, height int, and, xor []byte) uintptr {
+	return uintptr(w32.CreateCursor(
+		w32.GetModuleHandle(""), x, y, width, height, and, xor,
+	))
+}
+
+func (*winAPI) sysColor(index int) Color {
+	return Color(w32.GetSysColor(index))
+}
@@ -0,0 +1,346 @@
+// Package binding connects the properties of wui controls to the fields of Go
+// structs. Instead of copying values from the controls into your data by hand
+// in every event handler, you bind them once:
+//
+//	var person struct {
+//		Name    string
+//		Age     int
+//		Married bool
+//	}
+//	b := binding.New()
+//	b.Bind(binding.Text(nameEdit), &person.Name)
+//	b.Bind(binding.Value(ageUpDown), &person.Age)
+//	b.Bind(binding.Checked(marriedCheckBox), &person.Married)
+//
+// When the user changes a control, its value is written to the field. To
+// change a field in code, use Set, it updates the field's controls as well:
+//
+//	b.Set(&person.Age, 42)
+//
+// The Binder cannot see fields that you assign directly. Call Push after doing
+// that to show the new values in the controls.
+//
+// Unbind removes a single binding, Close removes all of them, e.g. before the
+// model is thrown away while the controls stay.
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Property is a value of a control, e.g. the text of an EditLine. Use the
+// functions in this package, like Text or Checked, to create the properties of
+// wui controls.
+type Property interface {
+	// Get returns the current value.
+	Get() interface{}
+	// Set changes the value.
+	Set(v interface{})
+	// OnChange registers f to be called whenever the user changes the value.
+	// Calling unsubscribe removes f again.
+	OnChange(f func()) (unsubscribe func())
+}
+
+// Converter converts between the value of a property and the value of a
+// field, if they have different types. Functions that are nil leave the value
+// as it is.
+type Converter struct {
+	ToControl func(field interface{}) (interface{}, error)
+	ToModel   func(control interface{}) (interface{}, error)
+}
+
+// Binder keeps a set of bindings. Its methods must be called on the UI thread.
+type Binder struct {
+	bindings []*binding
+	onError  func(p Property, err error)
+}
+
+// New returns a Binder without bindings.
+func New() *Binder {
+	return &Binder{}
+}
+
+type binding struct {
+	property  Property
+	field     reflect.Value
+	converter Converter
+	// pushing is true while we set the property, the change events that this
+	// causes must not write the value back.
+	pushing     bool
+	unsubscribe func()
+}
+
+// Bind connects the property to the variable that field points to, e.g.
+// &person.Name. The property is set to the field's value right away.
+//
+// Without a Converter, the field must have the same type as the property's
+// value or another numeric type for numeric properties. You can pass one
+// Converter to convert between different types.
+func (b *Binder) Bind(p Property, field interface{}, c ...Converter) error {
+	v := reflect.ValueOf(field)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("binding.Binder.Bind: field must be a non-nil pointer")
+	}
+	return b.bind(p, v.Elem(), c, "binding.Binder.Bind: ")
+}
+
+// BindTag connects the property to the field of model, which must be a
+// pointer to a struct, that has the given tag name, e.g. for a model of type
+//
+//	struct {
+//		Name string `binding:"name"`
+//	}
+//
+// use BindTag(p, &model, "name").
+func (b *Binder) BindTag(p Property, model interface{}, tag string, c ...Converter) error {
+	const errPrefix = "binding.Binder.BindTag: "
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New(errPrefix + "model must be a pointer to a struct")
+	}
+	field, ok := taggedField(v.Elem(), tag)
+	if !ok {
+		return fmt.Errorf(errPrefix+"%v has no field with tag %q", v.Elem().Type(), tag)
+	}
+	return b.bind(p, field, c, errPrefix)
+}
+
+// taggedField finds the field with the binding tag, also in embedded structs.
+func taggedField(s reflect.Value, tag string) (reflect.Value, bool) {
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Type().Field(i)
+		if f.Tag.Get("binding") == tag && f.PkgPath == "" {
+			return s.Field(i), true
+		}
+		if f.Anonymous && s.Field(i).Kind() == reflect.Struct {
+			if field, ok := taggedField(s.Field(i), tag); ok {
+				return field, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func (b *Binder) bind(p Property, field reflect.Value, c []Converter, errPrefix string) error {
+	if len(c) > 1 {
+		return errors.New(errPrefix + "only one Converter allowed")
+	}
+	bind := &binding{property: p, field: field}
+	if len(c) == 1 {
+		bind.converter = c[0]
+	}
+	if bind.converter.ToControl == nil || bind.converter.ToModel == nil {
+		propType := reflect.TypeOf(p.Get())
+		if bind.converter.ToModel == nil && !assignable(propType, field.Type()) {
+			return fmt.Errorf(errPrefix+"cannot bind %v property to %v field", propType, field.Type())
+		}
+		if bind.converter.ToControl == nil && !assignable(field.Type(), propType) {
+			return fmt.Errorf(errPrefix+"cannot bind %v property to %v field", propType, field.Type())
+		}
+	}
+	if err := bind.push(); err != nil {
+		return errors.New(errPrefix + err.Error())
+	}
+	bind.unsubscribe = p.OnChange(func() {
+		if !bind.pushing {
+			if err := bind.pull(); err != nil && b.onError != nil {
+				b.onError(p, err)
+			}
+		}
+	})
+	if bind.unsubscribe == nil {
+		bind.unsubscribe = func() {}
+	}
+	b.bindings = append(b.bindings, bind)
+	return nil
+}
+
+// assignable returns true if values of type from can be stored in type to.
+// Numbers can be converted into each other.
+func assignable(from, to reflect.Type) bool {
+	if from == nil {
+		return false
+	}
+	return from.AssignableTo(to) || isNumber(from) && isNumber(to)
+}
+
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Unbind removes all bindings of the property. Its changes are no longer
+// written to the model and Push and Pull skip it.
+func (b *Binder) Unbind(p Property) {
+	kept := b.bindings[:0]
+	for _, bind := range b.bindings {
+		if bind.property == p {
+			bind.unsubscribe()
+		} else {
+			kept = append(kept, bind)
+		}
+	}
+	for i := len(kept); i < len(b.bindings); i++ {
+		b.bindings[i] = nil
+	}
+	b.bindings = kept
+}
+
+// Close removes all bindings. The Binder can be used again afterwards.
+func (b *Binder) Close() {
+	for _, bind := range b.bindings {
+		bind.unsubscribe()
+	}
+	b.bindings = nil
+}
+
+// SetOnError sets a function that is called when a value that the user
+// entered cannot be converted for the model. In that case the field keeps its
+// old value.
+func (b *Binder) SetOnError(f func(p Property, err error)) {
+	b.onError = f
+}
+
+// Set stores value in the variable that field points to, e.g. &person.Age, and
+// shows it in all properties that are bound to it. value is converted like the
+// values of numeric properties. The field does not need to be bound, in that
+// case Set only assigns it.
+func (b *Binder) Set(field, value interface{}) error {
+	const errPrefix = "binding.Binder.Set: "
+	f := reflect.ValueOf(field)
+	if f.Kind() != reflect.Ptr || f.IsNil() {
+		return errors.New(errPrefix + "field must be a non-nil pointer")
+	}
+	v, err := convert(reflect.ValueOf(value), f.Elem().Type())
+	if err != nil {
+		return errors.New(errPrefix + err.Error())
+	}
+	f.Elem().Set(v)
+	for _, bind := range b.bindings {
+		// A struct and its first field have the same address, the types
+		// tell them apart.
+		if bind.field.Addr().Pointer() == f.Pointer() &&
+			bind.field.Type() == f.Elem().Type() {
+			if err := bind.push(); err != nil {
+				return errors.New(errPrefix + err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// Push sets all bound properties to the values of their fields. Call it after
+// assigning fields directly instead of using Set.
+func (b *Binder) Push() error {
+	for _, bind := range b.bindings {
+		if err := bind.push(); err != nil {
+			return errors.New("binding.Binder.Push: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// Pull sets all bound fields to the values of their properties. This happens
+// automatically when the user changes a control, you only need Pull if you
+// set the controls in code.
+func (b *Binder) Pull() error {
+	for _, bind := range b.bindings {
+		if err := bind.pull(); err != nil {
+			return errors.New("binding.Binder.Pull: " + err.Error())
+		}
+	}
+	return nil
+}
+
+func (b *binding) push() error {
+	var value interface{} = b.field.Interface()
+	if b.converter.ToControl != nil {
+		var err error
+		value, err = b.converter.ToControl(value)
+		if err != nil {
+			return err
+		}
+	}
+	v, err := convert(reflect.ValueOf(value), reflect.TypeOf(b.property.Get()))
+	if err != nil {
+		return err
+	}
+	b.pushing = true
+	defer func() { b.pushing = false }()
+	b.property.Set(v.Interface())
+	return nil
+}
+
+func (b *binding) pull() error {
+	value := b.property.Get()
+	if b.converter.ToModel != nil {
+		var err error
+		value, err = b.converter.ToModel(value)
+		if err != nil {
+			return err
+		}
+	}
+	v, err := convert(reflect.ValueOf(value), b.field.Type())
+	if err != nil {
+		return err
+	}
+	b.field.Set(v)
+	return nil
+}
+
+func convert(v reflect.Value, to reflect.Type) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Value{}, fmt.Errorf("cannot use nil as %v", to)
+	}
+	if !assignable(v.Type(), to) {
+		return reflect.Value{}, fmt.Errorf("cannot use %#v as %v", v.Interface(), to)
+	}
+	if v.Type().AssignableTo(to) {
+		return v, nil
+	}
+	c := v.Convert(to)
+	if changed(v, c) {
+		return reflect.Value{}, fmt.Errorf("%v does not fit into %v", v.Interface(), to)
+	}
+	return c, nil
+}
+
+// changed returns true if converting the number v to c did not keep its value,
+// e.g. because it overflowed or lost its fraction. Conversions to floats may
+// round, they only must not overflow.
+func changed(v, c reflect.Value) bool {
+	if c.Kind() == reflect.Float32 || c.Kind() == reflect.Float64 {
+		return c.OverflowFloat(floatOf(v))
+	}
+	if isNegative(v) != isNegative(c) {
+		return true
+	}
+	return c.Convert(v.Type()).Interface() != v.Interface()
+}
+
+func floatOf(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return float64(v.Uint())
+}
+
+func isNegative(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() < 0
+	}
+	return false
+}
@@ -0,0 +1,267 @@
+package binding
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/wui/v2"
+)
+
+// fakeProperty behaves like a control that calls its change handler on every
+// Set, like EditLine does.
+type fakeProperty struct {
+	value    interface{}
+	onChange func()
+}
+
+func (p *fakeProperty) Get() interface{} { return p.value }
+
+func (p *fakeProperty) Set(v interface{}) {
+	p.value = v
+	if p.onChange != nil {
+		p.onChange()
+	}
+}
+
+func (p *fakeProperty) OnChange(f func()) func() {
+	p.onChange = f
+	return func() { p.onChange = nil }
+}
+
+func TestBindPushesFieldAndPullsUserChanges(t *testing.T) {
+	var name string = "initial"
+	p := &fakeProperty{value: ""}
+	b := New()
+	check.Eq(t, b.Bind(p, &name), nil)
+	check.Eq(t, p.value, "initial")
+
+	p.Set("typed")
+	check.Eq(t, name, "typed")
+
+	name = "model"
+	check.Eq(t, b.Push(), nil)
+	check.Eq(t, p.value, "model")
+}
+
+func TestSetUpdatesFieldAndItsProperties(t *testing.T) {
+	var model struct {
+		Age  int
+		Name string
+	}
+	age := &fakeProperty{value: 0}
+	ageText := &fakeProperty{value: ""}
+	name := &fakeProperty{value: ""}
+	b := New()
+	check.Eq(t, b.Bind(age, &model.Age), nil)
+	check.Eq(t, b.Bind(ageText, &model.Age, IntText), nil)
+	check.Eq(t, b.Bind(name, &model.Name), nil)
+
+	check.Eq(t, b.Set(&model.Age, int8(42)), nil)
+	check.Eq(t, model.Age, 42)
+	check.Eq(t, age.value, 42)
+	check.Eq(t, ageText.value, "42")
+	check.Eq(t, name.value, "")
+
+	err := b.Set(&model.Age, "old")
+	check.Eq(t, err.Error(), "binding.Binder.Set: cannot use \"old\" as int")
+	check.Eq(t, model.Age, 42)
+	err = b.Set(model.Age, 1)
+	check.Eq(t, err.Error(), "binding.Binder.Set: field must be a non-nil pointer")
+
+	unbound := 0
+	check.Eq(t, b.Set(&unbound, 5), nil)
+	check.Eq(t, unbound, 5)
+}
+
+func TestUnboundPropertiesAreLeftAlone(t *testing.T) {
+	var first, second string
+	p1 := &fakeProperty{value: ""}
+	p2 := &fakeProperty{value: ""}
+	b := New()
+	check.Eq(t, b.Bind(p1, &first), nil)
+	check.Eq(t, b.Bind(p2, &second), nil)
+
+	b.Unbind(p1)
+	p1.Set("typed")
+	check.Eq(t, first, "")
+	first = "model"
+	check.Eq(t, b.Push(), nil)
+	check.Eq(t, p1.value, "typed")
+	p2.Set("still bound")
+	check.Eq(t, second, "still bound")
+
+	b.Close()
+	p2.Set("typed")
+	check.Eq(t, second, "still bound")
+	check.Eq(t, len(b.bindings), 0)
+}
+
+func TestNumbersAreConverted(t *testing.T) {
+	var f float32 = 2.5
+	p := &fakeProperty{value: 0.0}
+	check.Eq(t, New().Bind(p, &f), nil)
+	check.Eq(t, p.value, 2.5)
+	p.Set(7.0)
+	check.Eq(t, f, float32(7))
+}
+
+func TestNumbersThatDoNotFitAreNotConverted(t *testing.T) {
+	var small int8
+	p := &fakeProperty{value: 0}
+	b := New()
+	var errs []string
+	b.SetOnError(func(_ Property, err error) { errs = append(errs, err.Error()) })
+	check.Eq(t, b.Bind(p, &small), nil)
+	p.Set(127)
+	check.Eq(t, small, int8(127))
+	p.Set(300)
+	check.Eq(t, small, int8(127))
+
+	var count uint
+	p = &fakeProperty{value: 0}
+	check.Eq(t, b.Bind(p, &count), nil)
+	p.Set(-1)
+	check.Eq(t, count, uint(0))
+
+	var whole int
+	p = &fakeProperty{value: 0.0}
+	check.Eq(t, b.Bind(p, &whole), nil)
+	p.Set(1.5)
+	check.Eq(t, whole, 0)
+
+	check.Eq(t, errs, []string{
+		"300 does not fit into int8",
+		"-1 does not fit into uint",
+		"1.5 does not fit into int",
+	})
+
+	big := 1 << 40
+	err := New().Bind(&fakeProperty{value: int32(0)}, &big)
+	check.Eq(t, err.Error(), "binding.Binder.Bind: 1099511627776 does not fit into int32")
+}
+
+func TestIncompatibleTypesCannotBeBound(t *testing.T) {
+	var n int
+	err := New().Bind(&fakeProperty{value: ""}, &n)
+	check.Eq(t, err.Error(), "binding.Binder.Bind: cannot bind string property to int field")
+	err = New().Bind(&fakeProperty{value: ""}, n)
+	check.Eq(t, err.Error(), "binding.Binder.Bind: field must be a non-nil pointer")
+}
+
+func TestBindTagFindsFieldInEmbeddedStruct(t *testing.T) {
+	type Address struct {
+		City string `binding:"city"`
+	}
+	var model struct {
+		Name string `binding:"name"`
+		Address
+	}
+	model.City = "Berlin"
+
+	p := &fakeProperty{value: ""}
+	b := New()
+	check.Eq(t, b.BindTag(p, &model, "city"), nil)
+	check.Eq(t, p.value, "Berlin")
+	p.Set("Paris")
+	check.Eq(t, model.City, "Paris")
+
+	err := b.BindTag(p, &model, "street")
+	check.Eq(t, err.Error(), `binding.Binder.BindTag: struct { Name string "binding:\"name\""; binding.Address } has no field with tag "street"`)
+}
+
+func TestConverterErrorsKeepOldFieldValue(t *testing.T) {
+	age := 30
+	p := &fakeProperty{value: ""}
+	b := New()
+	var errs []error
+	b.SetOnError(func(_ Property, err error) { errs = append(errs, err) })
+	check.Eq(t, b.Bind(p, &age, IntText), nil)
+	check.Eq(t, p.value, "30")
+
+	p.Set("31")
+	check.Eq(t, age, 31)
+	p.Set("thirty")
+	check.Eq(t, age, 31)
+	check.Eq(t, len(errs), 1)
+	_, isNumErr := errs[0].(*strconv.NumError)
+	check.Eq(t, isNumErr, true)
+}
+
+func TestTextConvertersAcceptAllNumberSizes(t *testing.T) {
+	var model struct {
+		Year  int16
+		Count uint
+		Ratio float32
+	}
+	model.Year = 1999
+	model.Ratio = 0.1
+	year := &fakeProperty{value: ""}
+	count := &fakeProperty{value: ""}
+	ratio := &fakeProperty{value: ""}
+	b := New()
+	var errs []string
+	b.SetOnError(func(_ Property, err error) { errs = append(errs, err.Error()) })
+	check.Eq(t, b.Bind(year, &model.Year, IntText), nil)
+	check.Eq(t, b.Bind(count, &model.Count, IntText), nil)
+	check.Eq(t, b.Bind(ratio, &model.Ratio, FloatText), nil)
+	check.Eq(t, year.value, "1999")
+	check.Eq(t, count.value, "0")
+	check.Eq(t, ratio.value, "0.1")
+
+	year.Set(" +2024 ")
+	count.Set("-3")
+	ratio.Set("0.5")
+	check.Eq(t, model.Year, int16(2024))
+	check.Eq(t, model.Count, uint(0))
+	check.Eq(t, model.Ratio, float32(0.5))
+	check.Eq(t, errs, []string{"-3 does not fit into uint"})
+
+	var large uint64 = math.MaxUint64
+	p := &fakeProperty{value: ""}
+	check.Eq(t, b.Bind(p, &large, IntText), nil)
+	check.Eq(t, p.value, "18446744073709551615")
+	p.Set("18446744073709551614")
+	check.Eq(t, large, uint64(math.MaxUint64-1))
+
+	err := New().Bind(&fakeProperty{value: ""}, new(string), IntText)
+	check.Eq(t, err.Error(), "binding.Binder.Bind: IntText cannot convert string")
+}
+
+func TestControlPropertiesKeepExistingHandlers(t *testing.T) {
+	h := wui.UseHeadless()
+
+	var model struct {
+		Name    string
+		Married bool
+	}
+	model.Name = "Ann"
+
+	w := wui.NewWindow()
+	edit := wui.NewEditLine()
+	w.Add(edit)
+	married := wui.NewCheckBox()
+	w.Add(married)
+	oldHandlerCalled := false
+	married.SetOnChange(func(bool) { oldHandlerCalled = true })
+
+	b := New()
+	if err := b.Bind(Text(edit), &model.Name); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Bind(Checked(married), &model.Married); err != nil {
+		t.Fatal(err)
+	}
+
+	w.SetOnShow(func() {
+		h.Type(edit, "e")
+		h.Click(married)
+		w.Close()
+	})
+	w.Show()
+
+	if model.Name != "Anne" || !model.Married || !oldHandlerCalled {
+		t.Errorf("got %+v, old handler called: %v", model, oldHandlerCalled)
+	}
+}
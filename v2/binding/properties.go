@@ -0,0 +1,157 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gonutz/wui/v2"
+)
+
+// prop implements Property with functions.
+type prop struct {
+	get      func() interface{}
+	set      func(v interface{})
+	onChange func(f func()) (unsubscribe func())
+}
+
+func (p *prop) Get() interface{}         { return p.get() }
+func (p *prop) Set(v interface{})        { p.set(v) }
+func (p *prop) OnChange(f func()) func() { return p.onChange(f) }
+
+// TextControl is a control with an editable text, i.e. a wui.EditLine or a
+// wui.TextEdit.
+type TextControl interface {
+	Text() string
+	SetText(string)
+	TextChangeEvent() *wui.Event
+}
+
+// Text binds the text of an EditLine or TextEdit. Its value is a string.
+func Text(c TextControl) Property {
+	return &prop{
+		get: func() interface{} { return c.Text() },
+		set: func(v interface{}) { c.SetText(v.(string)) },
+		onChange: func(f func()) func() {
+			return c.TextChangeEvent().Subscribe(f)
+		},
+	}
+}
+
+// Checked binds the check state of a CheckBox. Its value is a bool.
+func Checked(c *wui.CheckBox) Property {
+	return &prop{
+		get: func() interface{} { return c.Checked() },
+		set: func(v interface{}) { c.SetChecked(v.(bool)) },
+		onChange: func(f func()) func() {
+			return c.ChangeEvent().Subscribe(func(bool) { f() })
+		},
+	}
+}
+
+// RadioChecked binds the check state of a RadioButton. Its value is a bool.
+func RadioChecked(r *wui.RadioButton) Property {
+	return &prop{
+		get: func() interface{} { return r.Checked() },
+		set: func(v interface{}) { r.SetChecked(v.(bool)) },
+		onChange: func(f func()) func() {
+			return r.CheckEvent().Subscribe(func(bool) { f() })
+		},
+	}
+}
+
+// Value binds the value of an IntUpDown. Its value is an int.
+func Value(n *wui.IntUpDown) Property {
+	return &prop{
+		get: func() interface{} { return n.Value() },
+		set: func(v interface{}) { n.SetValue(v.(int)) },
+		onChange: func(f func()) func() {
+			return n.ValueChangeEvent().Subscribe(func(int) { f() })
+		},
+	}
+}
+
+// FloatValue binds the value of a FloatUpDown. Its value is a float64.
+func FloatValue(n *wui.FloatUpDown) Property {
+	return &prop{
+		get: func() interface{} { return n.Value() },
+		set: func(v interface{}) { n.SetValue(v.(float64)) },
+		onChange: func(f func()) func() {
+			return n.ValueChangeEvent().Subscribe(func(float64) { f() })
+		},
+	}
+}
+
+// CursorPosition binds the cursor position of a Slider. Its value is an int.
+func CursorPosition(s *wui.Slider) Property {
+	return &prop{
+		get: func() interface{} { return s.CursorPosition() },
+		set: func(v interface{}) { s.SetCursorPosition(v.(int)) },
+		onChange: func(f func()) func() {
+			return s.ChangeEvent().Subscribe(func(int) { f() })
+		},
+	}
+}
+
+// IndexControl is a control with a selectable item, i.e. a wui.ComboBox or a
+// wui.StringList.
+type IndexControl interface {
+	SelectedIndex() int
+	SetSelectedIndex(int)
+	ChangeEvent() *wui.IntEvent
+}
+
+// SelectedIndex binds the selected item of a ComboBox or StringList. Its value
+// is an int, -1 means that no item is selected.
+func SelectedIndex(c IndexControl) Property {
+	return &prop{
+		get: func() interface{} { return c.SelectedIndex() },
+		set: func(v interface{}) { c.SetSelectedIndex(v.(int)) },
+		onChange: func(f func()) func() {
+			return c.ChangeEvent().Subscribe(func(int) { f() })
+		},
+	}
+}
+
+// IntText converts between an integer field of any size and a string
+// property, e.g. to bind the Text of an EditLine to a number.
+var IntText = Converter{
+	ToControl: func(field interface{}) (interface{}, error) {
+		v := reflect.ValueOf(field)
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(v.Int(), 10), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64, reflect.Uintptr:
+			return strconv.FormatUint(v.Uint(), 10), nil
+		}
+		return nil, fmt.Errorf("IntText cannot convert %T", field)
+	},
+	ToModel: func(control interface{}) (interface{}, error) {
+		// Positive numbers may be too large for an int64 but fit into an
+		// unsigned field. The Binder checks that the number fits the field.
+		text := strings.TrimSpace(control.(string))
+		if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+			return strconv.ParseInt(text, 10, 64)
+		}
+		return strconv.ParseUint(text, 10, 64)
+	},
+}
+
+// FloatText converts between a float32 or float64 field and a string property.
+var FloatText = Converter{
+	ToControl: func(field interface{}) (interface{}, error) {
+		v := reflect.ValueOf(field)
+		switch v.Kind() {
+		case reflect.Float32:
+			return strconv.FormatFloat(v.Float(), 'g', -1, 32), nil
+		case reflect.Float64:
+			return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+		}
+		return nil, fmt.Errorf("FloatText cannot convert %T", field)
+	},
+	ToModel: func(control interface{}) (interface{}, error) {
+		return strconv.ParseFloat(strings.TrimSpace(control.(string)), 64)
+	},
+}
@@ -0,0 +1,165 @@
+package wui
+
+// NewHBox creates a layout that places controls next to each other from left
+// to right.
+func NewHBox() *Box {
+	return &Box{stretch: make(map[Control]float64)}
+}
+
+// NewVBox creates a layout that places controls below each other from top to
+// bottom.
+func NewVBox() *Box {
+	return &Box{vertical: true, stretch: make(map[Control]float64)}
+}
+
+// Box is a Layout that places controls in a single row or column, in the order
+// in which they were added to the container. Across the row or column, the
+// controls fill the whole container.
+//
+// Along the row or column every control gets its preferred size. Space that is
+// left over is shared among the controls with a stretch factor, see
+// SetStretch. If there is not enough space, the controls shrink down to their
+// minimum sizes.
+type Box struct {
+	layoutBase
+	vertical bool
+	spacing  int
+	stretch  map[Control]float64
+}
+
+var _ Layout = (*Box)(nil)
+
+// Vertical returns true for a VBox and false for an HBox.
+func (b *Box) Vertical() bool {
+	return b.vertical
+}
+
+// Spacing returns the space in pixels between two controls.
+func (b *Box) Spacing() int {
+	return b.spacing
+}
+
+// SetSpacing sets the space in pixels between two controls.
+func (b *Box) SetSpacing(s int) {
+	b.spacing = s
+	b.update()
+}
+
+// Stretch returns the stretch factor of the control, see SetStretch.
+func (b *Box) Stretch(c Control) float64 {
+	return b.stretch[c]
+}
+
+// SetStretch sets how much of the left over space the control gets. The space
+// is shared proportionally to the factors, e.g. a control with factor 2 gets
+// twice as much additional space as one with factor 1. Controls with factor 0,
+// the default, keep their preferred size.
+func (b *Box) SetStretch(c Control, factor float64) {
+	if factor < 0 {
+		factor = 0
+	}
+	b.stretch[c] = factor
+	b.update()
+}
+
+func (b *Box) forget(c Control) {
+	b.layoutBase.forget(c)
+	delete(b.stretch, c)
+}
+
+// along returns the size of s along the box's direction and across it.
+func (b *Box) along(s size) (along, across int) {
+	if b.vertical {
+		return s.height, s.width
+	}
+	return s.width, s.height
+}
+
+func (b *Box) arrange(width, height int, items []layoutItem) []Rectangle {
+	x0, y0, width, height := shrink(width, height, b.margin)
+	length, thickness := width, height
+	if b.vertical {
+		length, thickness = height, width
+	}
+
+	sizes := make([]int, len(items))
+	total := spacingFor(len(items), b.spacing)
+	for i, item := range items {
+		sizes[i], _ = b.along(item.preferred)
+		total += sizes[i]
+	}
+
+	if total <= length {
+		weights := make([]float64, len(items))
+		for i, item := range items {
+			weights[i] = b.stretch[item.control]
+		}
+		for i, extra := range share(length-total, weights) {
+			sizes[i] += extra
+		}
+	} else {
+		// Shrink the controls proportionally to how much they can shrink.
+		shrinkable := make([]float64, len(items))
+		for i, item := range items {
+			min, _ := b.along(item.min)
+			shrinkable[i] = float64(maxInt(0, sizes[i]-min))
+		}
+		for i, less := range shareUpTo(total-length, shrinkable) {
+			sizes[i] -= less
+		}
+	}
+
+	bounds := make([]Rectangle, len(items))
+	for i, pos := range starts(0, sizes, b.spacing) {
+		if b.vertical {
+			bounds[i] = Rectangle{X: x0, Y: y0 + pos, Width: thickness, Height: sizes[i]}
+		} else {
+			bounds[i] = Rectangle{X: x0 + pos, Y: y0, Width: sizes[i], Height: thickness}
+		}
+	}
+	return bounds
+}
+
+// shareUpTo shares total like share does but no part gets more than its
+// weight. Weights must be integer values.
+func shareUpTo(total int, weights []float64) []int {
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	if float64(total) >= sum {
+		parts := make([]int, len(weights))
+		for i, w := range weights {
+			parts[i] = int(w)
+		}
+		return parts
+	}
+	parts := share(total, weights)
+	for i := range parts {
+		// Rounding might give a part one pixel too many.
+		if parts[i] > int(weights[i]) {
+			parts[i] = int(weights[i])
+		}
+	}
+	return parts
+}
+
+func (b *Box) measure(items []layoutItem) (preferred, min size) {
+	var length, minLength, thickness, minThickness int
+	for _, item := range items {
+		along, across := b.along(item.preferred)
+		minAlong, minAcross := b.along(item.min)
+		length += along
+		minLength += minAlong
+		thickness = maxInt(thickness, across)
+		minThickness = maxInt(minThickness, minAcross)
+	}
+	s := spacingFor(len(items), b.spacing)
+	length += s
+	minLength += s
+	m := 2 * b.margin
+	if b.vertical {
+		return size{thickness + m, length + m}, size{minThickness + m, minLength + m}
+	}
+	return size{length + m, thickness + m}, size{minLength + m, minThickness + m}
+}
@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewButton() *Button {
 	return &Button{}
@@ -8,7 +8,7 @@ func NewButton() *Button {
 
 type Button struct {
 	textControl
-	onClick func()
+	onClick Event
 }
 
 var _ Control = (*Button)(nil)
@@ -18,11 +18,15 @@ func (*Button) canFocus() bool {
 }
 
 func (b *Button) OnTabFocus() func() {
-	return b.onTabFocus
+	return b.onTabFocus.primary
 }
 
 func (b *Button) SetOnTabFocus(f func()) {
-	b.onTabFocus = f
+	b.onTabFocus.primary = f
+}
+
+func (b *Button) TabFocusEvent() *Event {
+	return &b.onTabFocus
 }
 
 func (*Button) eatsTabs() bool {
@@ -30,19 +34,23 @@ func (*Button) eatsTabs() bool {
 }
 
 func (b *Button) OnClick() func() {
-	return b.onClick
+	return b.onClick.primary
 }
 
 func (b *Button) SetOnClick(f func()) {
-	b.onClick = f
+	b.onClick.primary = f
+}
+
+func (b *Button) ClickEvent() *Event {
+	return &b.onClick
 }
 
 func (b *Button) create(id int) {
-	b.textControl.create(id, 0, "BUTTON", w32.WS_TABSTOP|w32.BS_PUSHBUTTON)
+	b.textControl.create(id, 0, "BUTTON", win.WS_TABSTOP|win.BS_PUSHBUTTON)
 }
 
 func (b *Button) handleNotification(cmd uintptr) {
-	if cmd == w32.BN_CLICKED && b.onClick != nil {
-		b.onClick()
+	if cmd == win.BN_CLICKED {
+		b.onClick.fire()
 	}
 }
@@ -0,0 +1,346 @@
+package wui
+
+import (
+	"image"
+	"reflect"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+type backBuffer struct {
+	w, h int
+	dc   w32.HDC
+	bmp  w32.HBITMAP
+}
+
+func (b *backBuffer) setMinSize(hdc w32.HDC, w, h int) {
+	if w > b.w || h > b.h {
+		b.release()
+		b.dc = w32.CreateCompatibleDC(hdc)
+		b.bmp = w32.CreateCompatibleBitmap(hdc, w, h)
+		b.w = w
+		b.h = h
+	}
+}
+
+func (b *backBuffer) release() {
+	if b.dc != 0 {
+		w32.DeleteObject(w32.HGDIOBJ(b.bmp))
+		w32.DeleteDC(b.dc)
+		b.dc = 0
+	}
+}
+
+func (w *Window) onWM_DRAWITEM(wParam, lParam uintptr) {
+	index := wParam
+	if 0 <= index && index < uintptr(len(w.controls)) {
+		if p, ok := w.controls[index].(*PaintBox); ok {
+			if !p.onPaint.empty() {
+				drawItem := ((*w32.DRAWITEMSTRUCT)(unsafe.Pointer(lParam)))
+				// create a back buffer
+				api := ui.(*winAPI)
+				buf := api.backBuffers[w32.HWND(p.handle)]
+				if buf == nil {
+					buf = &backBuffer{}
+					api.backBuffers[w32.HWND(p.handle)] = buf
+				}
+				buf.setMinSize(drawItem.HDC, p.width, p.height)
+				bmpOld := w32.SelectObject(buf.dc, w32.HGDIOBJ(buf.bmp))
+				gdi := &gdiPainter{hdc: buf.dc}
+				p.paint(&Canvas{
+					painter: gdi,
+					width:   p.width,
+					height:  p.height,
+				})
+
+				// blit the backbuffer to the front
+				w32.BitBlt(
+					drawItem.HDC, 0, 0, p.width, p.height,
+					buf.dc, 0, 0, w32.SRCCOPY,
+				)
+				w32.SelectObject(buf.dc, bmpOld)
+			}
+		}
+	}
+}
+
+// gdiPainter draws to a device context with the Windows GDI.
+type gdiPainter struct {
+	hdc     w32.HDC
+	regions []w32.HRGN
+}
+
+func (c *gdiPainter) handle() uintptr {
+	return uintptr(c.hdc)
+}
+
+func (c *gdiPainter) pushDrawRegion(x, y, width, height int) {
+	r := w32.CreateRectRgn(x, y, x+width, y+height)
+	if len(c.regions) > 0 {
+		w32.CombineRgn(r, r, c.regions[len(c.regions)-1], w32.RGN_AND)
+	}
+	c.regions = append(c.regions, r)
+	w32.SelectClipRgn(c.hdc, r)
+}
+
+func (c *gdiPainter) popDrawRegion() {
+	n := len(c.regions)
+	if n == 0 {
+		return
+	}
+	if n == 1 {
+		w32.SelectClipRgn(c.hdc, 0)
+	} else {
+		w32.SelectClipRgn(c.hdc, c.regions[n-2])
+	}
+	w32.DeleteObject(w32.HGDIOBJ(c.regions[n-1]))
+	c.regions = c.regions[:n-1]
+}
+
+func (c *gdiPainter) clearDrawRegions() {
+	w32.SelectClipRgn(c.hdc, 0)
+	for _, r := range c.regions {
+		w32.DeleteObject(w32.HGDIOBJ(r))
+	}
+	c.regions = c.regions[:0]
+}
+
+func (c *gdiPainter) setPen(color Color) {
+	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
+	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
+}
+
+func (c *gdiPainter) setBrush(color Color, fill bool) {
+	if fill {
+		w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_BRUSH))
+		w32.SetDCBrushColor(c.hdc, w32.COLORREF(color))
+	} else {
+		w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
+	}
+}
+
+func (c *gdiPainter) rect(x, y, width, height int, color Color, fill bool) {
+	c.setPen(color)
+	c.setBrush(color, fill)
+	w32.Rectangle(c.hdc, x, y, x+width, y+height)
+}
+
+func (c *gdiPainter) line(x1, y1, x2, y2 int, color Color) {
+	c.setPen(color)
+	w32.MoveToEx(c.hdc, x1, y1, nil)
+	w32.LineTo(c.hdc, x2, y2)
+}
+
+func (c *gdiPainter) ellipse(x, y, width, height int, color Color, fill bool) {
+	c.setPen(color)
+	c.setBrush(color, fill)
+	w32.Ellipse(c.hdc, x, y, x+width, y+height)
+}
+
+func (c *gdiPainter) polyline(p []Point, color Color) {
+	c.setPen(color)
+	c.setBrush(color, false)
+	w32.PolylineMem(c.hdc, unsafe.Pointer(&p[0]), len(p))
+}
+
+func (c *gdiPainter) polygon(p []Point, color Color) {
+	c.setPen(color)
+	c.setBrush(color, true)
+	w32.PolygonMem(c.hdc, unsafe.Pointer(&p[0]), len(p))
+}
+
+func (c *gdiPainter) arc(x, y, width, height, x1, y1, x2, y2 int, color Color) {
+	c.setPen(color)
+	w32.Arc(c.hdc, x, y, x+width, y+height, x1, y1, x2, y2)
+}
+
+func (c *gdiPainter) pie(x, y, width, height, x1, y1, x2, y2 int, color Color, fill bool) {
+	c.setPen(color)
+	c.setBrush(color, fill)
+	w32.Pie(c.hdc, x, y, x+width, y+height, x1, y1, x2, y2)
+}
+
+func (c *gdiPainter) textExtent(s string) (width, height int) {
+	size, ok := w32.GetTextExtentPoint32(c.hdc, s)
+	if ok {
+		width = int(size.CX)
+		height = int(size.CY)
+	}
+	return
+}
+
+func (c *gdiPainter) textOut(x, y int, s string, color Color) {
+	w32.SetBkMode(c.hdc, w32.TRANSPARENT)
+	w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
+	w32.SetTextColor(c.hdc, w32.COLORREF(color))
+	w32.TextOut(c.hdc, x, y, s)
+	w32.SetBkMode(c.hdc, w32.OPAQUE)
+}
+
+func (c *gdiPainter) textRectExtent(s string, givenWidth int) (width, height int) {
+	var flags uint = w32.DT_WORDBREAK | w32.DT_NOFULLWIDTHCHARBREAK | w32.DT_EXPANDTABS
+	var r w32.RECT
+	r.Right = int32(givenWidth)
+	w32.DrawText(c.hdc, s, &r, flags|w32.DT_CALCRECT)
+	return int(r.Width()), int(r.Height())
+}
+
+func (c *gdiPainter) textRect(x, y, w, h int, s string, format Format, color Color) {
+	w32.SetBkMode(c.hdc, w32.TRANSPARENT)
+	w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
+	w32.SetTextColor(c.hdc, w32.COLORREF(color))
+	r := w32.RECT{
+		Left:   int32(x),
+		Top:    int32(y),
+		Right:  int32(x + w),
+		Bottom: int32(y + h),
+	}
+	var flags uint = w32.DT_WORDBREAK | w32.DT_NOFULLWIDTHCHARBREAK | w32.DT_EXPANDTABS
+	// add the appropriate horizontal positioning flag
+	switch format {
+	default:
+		flags |= w32.DT_LEFT
+	case FormatTopCenter, FormatCenter, FormatBottomCenter:
+		flags |= w32.DT_CENTER
+	case FormatTopRight, FormatCenterRight, FormatBottomRight:
+		flags |= w32.DT_RIGHT
+	}
+	// w32.DrawText will only respect w32.DT_VCENTER and w32.DT_BOTTOM if the
+	// single-line option is also set, this means that we actually have to do
+	// the work of positioning the text vertically ourselves
+	switch format {
+	default:
+		w32.DrawText(c.hdc, s, &r, flags)
+	case FormatCenterLeft, FormatCenter, FormatCenterRight:
+		calc := r
+		w32.DrawText(c.hdc, s, &calc, flags|w32.DT_CALCRECT)
+		if calc.Height() < r.Height() {
+			r.Top += (r.Height() - calc.Height()) / 2
+		}
+		w32.DrawText(c.hdc, s, &r, flags)
+	case FormatBottomLeft, FormatBottomCenter, FormatBottomRight:
+		calc := r
+		w32.DrawText(c.hdc, s, &calc, flags|w32.DT_CALCRECT)
+		if calc.Height() < r.Height() {
+			r.Top += r.Height() - calc.Height()
+		}
+		w32.DrawText(c.hdc, s, &r, flags)
+	}
+	w32.SetBkMode(c.hdc, w32.OPAQUE)
+}
+
+func (c *gdiPainter) setFont(font *Font) {
+	w32.SelectObject(c.hdc, w32.HGDIOBJ(font.handle))
+}
+
+func (c *gdiPainter) drawImage(img *Image, src Rectangle, destX, destY int) {
+	hdcMem := w32.CreateCompatibleDC(c.hdc)
+	old := w32.SelectObject(hdcMem, w32.HGDIOBJ(img.bitmap))
+
+	w32.AlphaBlend(
+		c.hdc,
+		destX, destY, src.Width, src.Height,
+		hdcMem,
+		src.X, src.Y, src.Width, src.Height,
+		w32.BLENDFUNC{
+			BlendOp:             w32.AC_SRC_OVER,
+			BlendFlags:          0,
+			SourceConstantAlpha: 255,
+			AlphaFormat:         w32.AC_SRC_ALPHA,
+		},
+	)
+
+	w32.SelectObject(hdcMem, old)
+	w32.DeleteDC(hdcMem)
+}
+
+func (*winAPI) createBitmap(img *image.RGBA) uintptr {
+	var bmp w32.BITMAPINFO
+	bmp.BmiHeader.BiSize = uint32(unsafe.Sizeof(bmp.BmiHeader))
+	bmp.BmiHeader.BiWidth = int32(img.Bounds().Dx())
+	bmp.BmiHeader.BiHeight = -int32(img.Bounds().Dy())
+	bmp.BmiHeader.BiPlanes = 1
+	bmp.BmiHeader.BiBitCount = 32
+	bmp.BmiHeader.BiCompression = w32.BI_RGB
+
+	var bits unsafe.Pointer
+	bitmap := w32.CreateDIBSection(0, &bmp, 0, &bits, 0, 0)
+	pixels := img.Pix
+	var dest []byte
+	hdrp := (*reflect.SliceHeader)(unsafe.Pointer(&dest))
+	hdrp.Data = uintptr(bits)
+	hdrp.Len = len(pixels)
+	hdrp.Cap = hdrp.Len
+	// swap red and blue because we need BGR and not RGB on Windows
+	for i := 0; i < len(pixels); i += 4 {
+		dest[i+0] = pixels[i+2]
+		dest[i+1] = pixels[i+1]
+		dest[i+2] = pixels[i+0]
+		dest[i+3] = pixels[i+3]
+	}
+	return uintptr(bitmap)
+}
+
+func (a *winAPI) iconBitmap(icon uintptr) uintptr {
+	// We draw the icon on black and on white to find its alpha channel. Where
+	// the icon is transparent, the background shows through and the two
+	// differ. On black, the colors are already premultiplied by alpha.
+	size := w32.GetSystemMetrics(w32.SM_CXSMICON)
+	black := drawIconPixels(icon, size, 0x00)
+	white := drawIconPixels(icon, size, 0xFF)
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for i := 0; i < len(img.Pix); i += 4 {
+		diff := int(white[i+1]) - int(black[i+1])
+		if diff < 0 {
+			diff = 0
+		}
+		if diff > 255 {
+			diff = 255
+		}
+		alpha := uint8(255 - diff)
+		img.Pix[i+0] = minByte(black[i+2], alpha)
+		img.Pix[i+1] = minByte(black[i+1], alpha)
+		img.Pix[i+2] = minByte(black[i+0], alpha)
+		img.Pix[i+3] = alpha
+	}
+	return a.createBitmap(img)
+}
+
+// drawIconPixels draws the icon on the given gray background and returns the
+// 32 bit BGRA pixels, top-down.
+func drawIconPixels(icon uintptr, size int, background byte) []byte {
+	var bmp w32.BITMAPINFO
+	bmp.BmiHeader.BiSize = uint32(unsafe.Sizeof(bmp.BmiHeader))
+	bmp.BmiHeader.BiWidth = int32(size)
+	bmp.BmiHeader.BiHeight = -int32(size)
+	bmp.BmiHeader.BiPlanes = 1
+	bmp.BmiHeader.BiBitCount = 32
+	bmp.BmiHeader.BiCompression = w32.BI_RGB
+
+	dc := w32.CreateCompatibleDC(0)
+	defer w32.DeleteDC(dc)
+	var bits unsafe.Pointer
+	bitmap := w32.CreateDIBSection(dc, &bmp, 0, &bits, 0, 0)
+	if bitmap == 0 {
+		return make([]byte, 4*size*size)
+	}
+	defer w32.DeleteObject(w32.HGDIOBJ(bitmap))
+	n := 4 * size * size
+	pixels := (*[1 << 30]byte)(bits)[:n:n]
+	for i := range pixels {
+		pixels[i] = background
+	}
+	old := w32.SelectObject(dc, w32.HGDIOBJ(bitmap))
+	w32.DrawIconEx(dc, 0, 0, w32.HICON(icon), size, size, 0, 0, w32.DI_NORMAL)
+	gdiFlushProc.Call()
+	w32.SelectObject(dc, old)
+	return append([]byte(nil), pixels...)
+}
+
+func minByte(a, b byte) byte {
+	if a < b {
+		return a
+	}
+	return b
+}
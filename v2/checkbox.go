@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewCheckBox() *CheckBox {
 	return &CheckBox{}
@@ -9,7 +9,7 @@ func NewCheckBox() *CheckBox {
 type CheckBox struct {
 	textControl
 	checked  bool
-	onChange func(bool)
+	onChange BoolEvent
 }
 
 var _ Control = (*CheckBox)(nil)
@@ -19,11 +19,15 @@ func (*CheckBox) canFocus() bool {
 }
 
 func (c *CheckBox) OnTabFocus() func() {
-	return c.onTabFocus
+	return c.onTabFocus.primary
 }
 
 func (c *CheckBox) SetOnTabFocus(f func()) {
-	c.onTabFocus = f
+	c.onTabFocus.primary = f
+}
+
+func (c *CheckBox) TabFocusEvent() *Event {
+	return &c.onTabFocus
 }
 
 func (*CheckBox) eatsTabs() bool {
@@ -31,8 +35,8 @@ func (*CheckBox) eatsTabs() bool {
 }
 
 func (c *CheckBox) create(id int) {
-	c.textControl.create(id, 0, "BUTTON", w32.WS_TABSTOP|w32.BS_AUTOCHECKBOX)
-	w32.SendMessage(c.handle, w32.BM_SETCHECK, toCheckState(c.checked), 0)
+	c.textControl.create(id, 0, "BUTTON", win.WS_TABSTOP|win.BS_AUTOCHECKBOX)
+	ui.setChecked(c.handle, c.checked)
 }
 
 func (c *CheckBox) Checked() bool {
@@ -45,30 +49,27 @@ func (c *CheckBox) SetChecked(checked bool) {
 	}
 	c.checked = checked
 	if c.handle != 0 {
-		w32.SendMessage(c.handle, w32.BM_SETCHECK, toCheckState(c.checked), 0)
-	}
-	if c.onChange != nil {
-		c.onChange(c.checked)
+		ui.setChecked(c.handle, c.checked)
 	}
+	c.onChange.fire(c.checked)
 	return
 }
 
-func toCheckState(checked bool) uintptr {
-	if checked {
-		return w32.BST_CHECKED
-	}
-	return w32.BST_UNCHECKED
+func (c *CheckBox) OnChange() func(checked bool) {
+	return c.onChange.primary
 }
 
 func (c *CheckBox) SetOnChange(f func(checked bool)) {
-	c.onChange = f
+	c.onChange.primary = f
+}
+
+func (c *CheckBox) ChangeEvent() *BoolEvent {
+	return &c.onChange
 }
 
 func (c *CheckBox) handleNotification(cmd uintptr) {
-	if cmd == w32.BN_CLICKED {
-		c.checked = w32.SendMessage(c.handle, w32.BM_GETCHECK, 0, 0) == w32.BST_CHECKED
-		if c.onChange != nil {
-			c.onChange(c.checked)
-		}
+	if cmd == win.BN_CLICKED {
+		c.checked = ui.checked(c.handle)
+		c.onChange.fire(c.checked)
 	}
 }
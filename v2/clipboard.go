@@ -0,0 +1,280 @@
+package wui
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"unicode/utf16"
+)
+
+// Clipboard is the system clipboard. Its functions must be called on the UI
+// thread, like all functions of windows and controls.
+//
+// Getters return a nil or empty value and no error if the clipboard does not
+// contain the requested format. Setters replace all contents of the
+// clipboard.
+var Clipboard = &SystemClipboard{}
+
+// SystemClipboard is the type of Clipboard, there is only this one instance.
+type SystemClipboard struct {
+	onChange Event
+}
+
+// ClipboardFormat identifies a type of data on the clipboard. Use the
+// predefined formats or register your own with Clipboard.RegisterFormat.
+type ClipboardFormat uint
+
+const (
+	// ClipboardText is UTF-16 text, CF_UNICODETEXT in the Windows API.
+	ClipboardText ClipboardFormat = 13
+	// ClipboardDIB is a device-independent bitmap, CF_DIB in the Windows API.
+	ClipboardDIB ClipboardFormat = 8
+	// ClipboardDIBV5 is a device-independent bitmap with alpha, CF_DIBV5 in
+	// the Windows API.
+	ClipboardDIBV5 ClipboardFormat = 17
+	// ClipboardFiles is a list of file paths, CF_HDROP in the Windows API.
+	ClipboardFiles ClipboardFormat = 15
+)
+
+// clipboardItem is the data of one format, in the encoding that the Windows
+// API uses.
+type clipboardItem struct {
+	format ClipboardFormat
+	data   []byte
+}
+
+// Has returns true if the clipboard contains data in the given format.
+func (*SystemClipboard) Has(f ClipboardFormat) bool {
+	return ui.hasClipboardFormat(f)
+}
+
+// Data returns the raw bytes of the given format.
+func (*SystemClipboard) Data(f ClipboardFormat) ([]byte, error) {
+	data, err := ui.clipboardData(f)
+	if err != nil {
+		return nil, errors.New("wui.Clipboard.Data: " + err.Error())
+	}
+	return data, nil
+}
+
+// SetData puts data in the given format on the clipboard. Usually the format
+// is one that you registered with RegisterFormat.
+func (*SystemClipboard) SetData(f ClipboardFormat, data []byte) error {
+	err := ui.setClipboardData([]clipboardItem{{format: f, data: data}})
+	if err != nil {
+		return errors.New("wui.Clipboard.SetData: " + err.Error())
+	}
+	return nil
+}
+
+// RegisterFormat returns the format for the given name. Programs that
+// register the same name get the same format, this way they can exchange
+// custom data.
+func (*SystemClipboard) RegisterFormat(name string) (ClipboardFormat, error) {
+	if name == "" {
+		return 0, errors.New("wui.Clipboard.RegisterFormat: empty name")
+	}
+	f, err := ui.registerClipboardFormat(name)
+	if err != nil {
+		return 0, errors.New("wui.Clipboard.RegisterFormat: " + err.Error())
+	}
+	return f, nil
+}
+
+// Text returns the text on the clipboard.
+func (*SystemClipboard) Text() (string, error) {
+	data, err := ui.clipboardData(ClipboardText)
+	if err != nil {
+		return "", errors.New("wui.Clipboard.Text: " + err.Error())
+	}
+	return decodeClipboardText(data), nil
+}
+
+// SetText puts the text on the clipboard.
+func (*SystemClipboard) SetText(text string) error {
+	err := ui.setClipboardData([]clipboardItem{{
+		format: ClipboardText,
+		data:   encodeClipboardText(text),
+	}})
+	if err != nil {
+		return errors.New("wui.Clipboard.SetText: " + err.Error())
+	}
+	return nil
+}
+
+// Image returns the image on the clipboard. It prefers the DIBV5 format,
+// which can have an alpha channel, over the DIB format.
+func (*SystemClipboard) Image() (image.Image, error) {
+	for _, f := range []ClipboardFormat{ClipboardDIBV5, ClipboardDIB} {
+		if !ui.hasClipboardFormat(f) {
+			continue
+		}
+		data, err := ui.clipboardData(f)
+		if err != nil {
+			return nil, errors.New("wui.Clipboard.Image: " + err.Error())
+		}
+		if data == nil {
+			continue
+		}
+		img, err := decodeDIB(data)
+		if err != nil {
+			return nil, errors.New("wui.Clipboard.Image: " + err.Error())
+		}
+		return img, nil
+	}
+	return nil, nil
+}
+
+// SetImage puts the image on the clipboard, both as DIBV5 with alpha channel
+// and as DIB for programs that do not understand DIBV5.
+func (*SystemClipboard) SetImage(img image.Image) error {
+	if img == nil {
+		return errors.New("wui.Clipboard.SetImage: image is nil")
+	}
+	err := ui.setClipboardData([]clipboardItem{
+		{format: ClipboardDIBV5, data: encodeDIBV5(img)},
+		{format: ClipboardDIB, data: encodeDIB(img)},
+	})
+	if err != nil {
+		return errors.New("wui.Clipboard.SetImage: " + err.Error())
+	}
+	return nil
+}
+
+// Files returns the paths of the files that were copied, e.g. in the Windows
+// Explorer.
+func (*SystemClipboard) Files() ([]string, error) {
+	data, err := ui.clipboardData(ClipboardFiles)
+	if err != nil {
+		return nil, errors.New("wui.Clipboard.Files: " + err.Error())
+	}
+	if data == nil {
+		return nil, nil
+	}
+	files, err := decodeDropFiles(data)
+	if err != nil {
+		return nil, errors.New("wui.Clipboard.Files: " + err.Error())
+	}
+	return files, nil
+}
+
+// SetFiles puts the file paths on the clipboard so they can be pasted, e.g. in
+// the Windows Explorer.
+func (*SystemClipboard) SetFiles(paths []string) error {
+	err := ui.setClipboardData([]clipboardItem{{
+		format: ClipboardFiles,
+		data:   encodeDropFiles(paths),
+	}})
+	if err != nil {
+		return errors.New("wui.Clipboard.SetFiles: " + err.Error())
+	}
+	return nil
+}
+
+// OnChange returns the function that is called when the contents of the
+// clipboard change, in this or any other program.
+func (c *SystemClipboard) OnChange() func() {
+	return c.onChange.primary
+}
+
+// SetOnChange sets the function that is called when the contents of the
+// clipboard change, in this or any other program. Changes are only reported
+// while a window is open.
+func (c *SystemClipboard) SetOnChange(f func()) {
+	c.onChange.primary = f
+}
+
+func (c *SystemClipboard) ChangeEvent() *Event {
+	return &c.onChange
+}
+
+func (c *SystemClipboard) changed() {
+	c.onChange.fire()
+}
+
+// encodeClipboardText returns the text as 0-terminated UTF-16.
+func encodeClipboardText(text string) []byte {
+	u := utf16.Encode([]rune(text))
+	b := make([]byte, 2*len(u)+2)
+	for i, c := range u {
+		binary.LittleEndian.PutUint16(b[2*i:], c)
+	}
+	return b
+}
+
+// decodeClipboardText reads UTF-16 text up to the first 0.
+func decodeClipboardText(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u))
+}
+
+// dropFilesSize is the size of the DROPFILES struct that precedes the file
+// list in CF_HDROP data.
+const dropFilesSize = 20
+
+// encodeDropFiles returns the paths as a DROPFILES struct followed by the
+// 0-terminated UTF-16 paths and another 0.
+func encodeDropFiles(paths []string) []byte {
+	var u []uint16
+	for _, path := range paths {
+		u = append(u, utf16.Encode([]rune(path))...)
+		u = append(u, 0)
+	}
+	u = append(u, 0)
+	b := make([]byte, dropFilesSize+2*len(u))
+	binary.LittleEndian.PutUint32(b[0:], dropFilesSize) // Offset of the files.
+	binary.LittleEndian.PutUint32(b[16:], 1)            // Wide characters.
+	for i, c := range u {
+		binary.LittleEndian.PutUint16(b[dropFilesSize+2*i:], c)
+	}
+	return b
+}
+
+func decodeDropFiles(b []byte) ([]string, error) {
+	if len(b) < dropFilesSize {
+		return nil, errors.New("DROPFILES too short")
+	}
+	offset := int(binary.LittleEndian.Uint32(b[0:]))
+	wide := binary.LittleEndian.Uint32(b[16:]) != 0
+	if offset < dropFilesSize || offset > len(b) {
+		return nil, errors.New("invalid DROPFILES offset")
+	}
+	var paths []string
+	if wide {
+		var path []uint16
+		for i := offset; i+1 < len(b); i += 2 {
+			c := binary.LittleEndian.Uint16(b[i:])
+			if c == 0 {
+				if len(path) == 0 {
+					break
+				}
+				paths = append(paths, string(utf16.Decode(path)))
+				path = path[:0]
+			} else {
+				path = append(path, c)
+			}
+		}
+	} else {
+		// ANSI paths, we decode them as Latin-1.
+		var path []rune
+		for i := offset; i < len(b); i++ {
+			if b[i] == 0 {
+				if len(path) == 0 {
+					break
+				}
+				paths = append(paths, string(path))
+				path = path[:0]
+			} else {
+				path = append(path, rune(b[i]))
+			}
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,91 @@
+package wui
+
+import (
+	"image"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestClipboardHoldsOneThingAtATime(t *testing.T) {
+	UseHeadless()
+
+	text, err := Clipboard.Text()
+	check.Eq(t, err, nil)
+	check.Eq(t, text, "")
+
+	check.Eq(t, Clipboard.SetText("Hello, 世界 😀"), nil)
+	check.Eq(t, Clipboard.Has(ClipboardText), true)
+	text, err = Clipboard.Text()
+	check.Eq(t, err, nil)
+	check.Eq(t, text, "Hello, 世界 😀")
+
+	check.Eq(t, Clipboard.SetFiles([]string{`C:\a.txt`, `C:\ß\b`}), nil)
+	check.Eq(t, Clipboard.Has(ClipboardText), false)
+	files, err := Clipboard.Files()
+	check.Eq(t, err, nil)
+	check.Eq(t, files, []string{`C:\a.txt`, `C:\ß\b`})
+
+	check.Eq(t, Clipboard.SetImage(testImage()), nil)
+	check.Eq(t, Clipboard.Has(ClipboardDIB), true)
+	check.Eq(t, Clipboard.Has(ClipboardDIBV5), true)
+	img, err := Clipboard.Image()
+	check.Eq(t, err, nil)
+	check.Eq(t, img, testImage())
+	files, err = Clipboard.Files()
+	check.Eq(t, err, nil)
+	check.Eq(t, files, nil)
+}
+
+func TestClipboardImageFallsBackToDIB(t *testing.T) {
+	UseHeadless()
+	opaque := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	opaque.Pix = []uint8{1, 2, 3, 255}
+	check.Eq(t, Clipboard.SetData(ClipboardDIB, encodeDIB(opaque)), nil)
+	img, err := Clipboard.Image()
+	check.Eq(t, err, nil)
+	check.Eq(t, img, opaque)
+}
+
+func TestCustomClipboardFormats(t *testing.T) {
+	UseHeadless()
+
+	a, err := Clipboard.RegisterFormat("wui test a")
+	check.Eq(t, err, nil)
+	b, err := Clipboard.RegisterFormat("wui test b")
+	check.Eq(t, err, nil)
+	check.Neq(t, a, b)
+	a2, err := Clipboard.RegisterFormat("wui test a")
+	check.Eq(t, err, nil)
+	check.Eq(t, a2, a)
+	_, err = Clipboard.RegisterFormat("")
+	check.Neq(t, err, nil)
+
+	check.Eq(t, Clipboard.SetData(a, []byte{1, 2, 3}), nil)
+	data, err := Clipboard.Data(a)
+	check.Eq(t, err, nil)
+	check.Eq(t, data, []byte{1, 2, 3})
+	data, err = Clipboard.Data(b)
+	check.Eq(t, err, nil)
+	check.Eq(t, data, nil)
+}
+
+func TestClipboardNotifiesChanges(t *testing.T) {
+	UseHeadless()
+	changes := 0
+	unsubscribe := Clipboard.ChangeEvent().Subscribe(func() { changes++ })
+	defer unsubscribe()
+
+	Clipboard.SetText("a")
+	Clipboard.SetImage(testImage())
+	check.Eq(t, changes, 2)
+}
+
+func TestDecodeANSIDropFiles(t *testing.T) {
+	b := make([]byte, dropFilesSize)
+	b[0] = dropFilesSize
+	b = append(b, "C:\\x\x00D:\\\xE4\x00\x00"...)
+	files, err := decodeDropFiles(b)
+	check.Eq(t, err, nil)
+	check.Eq(t, files, []string{`C:\x`, `D:\ä`})
+}
@@ -0,0 +1,116 @@
+package wui
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gonutz/w32/v2"
+)
+
+func (*winAPI) registerClipboardFormat(name string) (ClipboardFormat, error) {
+	f := registerClipboardFormat(name)
+	if f == 0 {
+		return 0, errors.New("RegisterClipboardFormat failed")
+	}
+	return ClipboardFormat(f), nil
+}
+
+func (*winAPI) hasClipboardFormat(f ClipboardFormat) bool {
+	return w32.IsClipboardFormatAvailable(uint(f))
+}
+
+func (a *winAPI) clipboardData(f ClipboardFormat) ([]byte, error) {
+	if !w32.IsClipboardFormatAvailable(uint(f)) {
+		return nil, nil
+	}
+	if err := a.openClipboard(); err != nil {
+		return nil, err
+	}
+	defer w32.CloseClipboard()
+
+	mem := w32.HGLOBAL(w32.GetClipboardData(uint(f)))
+	if mem == 0 {
+		return nil, errors.New("GetClipboardData failed")
+	}
+	size := globalSize(uintptr(mem))
+	p := w32.GlobalLock(mem)
+	if p == nil {
+		return nil, errors.New("GlobalLock failed")
+	}
+	defer w32.GlobalUnlock(mem)
+	data := make([]byte, size)
+	copy(data, (*[1 << 30]byte)(p)[:size:size])
+	return data, nil
+}
+
+func (a *winAPI) setClipboardData(items []clipboardItem) error {
+	if err := a.openClipboard(); err != nil {
+		return err
+	}
+	defer w32.CloseClipboard()
+
+	if !w32.EmptyClipboard() {
+		return errors.New("EmptyClipboard failed")
+	}
+	for _, item := range items {
+		size := len(item.data)
+		if size == 0 {
+			// GlobalAlloc does not give us memory for 0 bytes.
+			size = 1
+		}
+		mem := w32.GlobalAlloc(w32.GMEM_MOVEABLE, uint32(size))
+		if mem == 0 {
+			return errors.New("GlobalAlloc failed")
+		}
+		p := w32.GlobalLock(mem)
+		if p == nil {
+			w32.GlobalFree(mem)
+			return errors.New("GlobalLock failed")
+		}
+		copy((*[1 << 30]byte)(p)[:size:size], item.data)
+		w32.GlobalUnlock(mem)
+		// On success the clipboard owns the memory, we must not free it.
+		if w32.SetClipboardData(uint(item.format), w32.HANDLE(mem)) == 0 {
+			w32.GlobalFree(mem)
+			return errors.New("SetClipboardData failed")
+		}
+	}
+	return nil
+}
+
+// openClipboard retries for a short time because other programs might have
+// the clipboard open right now.
+func (a *winAPI) openClipboard() error {
+	for i := 0; i < 10; i++ {
+		if w32.OpenClipboard(a.clipboardListener) {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return errors.New("OpenClipboard failed")
+}
+
+// listenToClipboard makes the window receive WM_CLIPBOARDUPDATE, unless
+// another window already does. One window is enough to notify Clipboard.
+func (a *winAPI) listenToClipboard(window uintptr) {
+	if a.clipboardListener == 0 &&
+		w32.AddClipboardFormatListener(w32.HWND(window)) {
+		a.clipboardListener = w32.HWND(window)
+	}
+}
+
+// stopListeningToClipboard hands the clipboard notifications over to another
+// open window if the given window was the one receiving them.
+func (a *winAPI) stopListeningToClipboard(window uintptr) {
+	if a.clipboardListener != w32.HWND(window) {
+		return
+	}
+	w32.RemoveClipboardFormatListener(a.clipboardListener)
+	a.clipboardListener = 0
+	for _, w := range app.windows {
+		if w.handle != 0 && w.handle != window {
+			a.listenToClipboard(w.handle)
+			return
+		}
+	}
+}
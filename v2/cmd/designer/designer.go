@@ -15,7 +15,6 @@ import (
 	"unicode"
 	"unicode/utf8"
 
-	"github.com/gonutz/w32/v2"
 	"github.com/gonutz/wui/v2"
 )
 
@@ -112,14 +111,22 @@ func main() {
 	menu := wui.NewMainMenu()
 	fileMenu := wui.NewMenu("&File")
 	editMenu := wui.NewMenu("&Edit")
-	fileOpenMenu := wui.NewMenuString("&Open File...\tCtrl+O")
-	fileSaveMenu := wui.NewMenuString("&Save File\tCtrl+S")
-	fileSaveAsMenu := wui.NewMenuString("Save File &As...\tCtrl+Shift+S")
-	previewMenu := wui.NewMenuString("&Run Preview\tCtrl+R")
+	fileOpenMenu := wui.NewMenuString("&Open File...")
+	fileSaveMenu := wui.NewMenuString("&Save File")
+	fileSaveAsMenu := wui.NewMenuString("Save File &As...")
+	previewMenu := wui.NewMenuString("&Run Preview")
 	exitMenu := wui.NewMenuString("E&xit\tAlt+F4")
-	undoMenu := wui.NewMenuString("&Undo\tCtrl+Z")
-	redoMenu := wui.NewMenuString("&Redo\tCtrl+Shift+Z")
-	deleteMenu := wui.NewMenuString("&Delete\tCtrl+Del")
+	undoMenu := wui.NewMenuString("&Undo")
+	redoMenu := wui.NewMenuString("&Redo")
+	deleteMenu := wui.NewMenuString("&Delete")
+	addTabPageMenu := wui.NewMenuString("Add &Tab Page")
+	fileOpenMenu.SetShortcut(wui.KeyControl, wui.KeyO)
+	fileSaveMenu.SetShortcut(wui.KeyControl, wui.KeyS)
+	fileSaveAsMenu.SetShortcut(wui.KeyControl, wui.KeyShift, wui.KeyS)
+	previewMenu.SetShortcut(wui.KeyControl, wui.KeyR)
+	undoMenu.SetShortcut(wui.KeyControl, wui.KeyZ)
+	redoMenu.SetShortcut(wui.KeyControl, wui.KeyShift, wui.KeyZ)
+	deleteMenu.SetShortcut(wui.KeyControl, wui.KeyDelete)
 	fileMenu.Add(fileOpenMenu)
 	fileMenu.Add(fileSaveMenu)
 	fileMenu.Add(fileSaveAsMenu)
@@ -131,6 +138,7 @@ func main() {
 	editMenu.Add(redoMenu)
 	editMenu.Add(wui.NewMenuSeparator())
 	editMenu.Add(deleteMenu)
+	editMenu.Add(addTabPageMenu)
 	menu.Add(fileMenu)
 	menu.Add(editMenu)
 	w.SetMenu(menu)
@@ -150,6 +158,21 @@ func main() {
 
 	const propMargin = 2
 
+	// changes records all edits to theWindow for undo and redo.
+	var changes history
+	// changeActive calls change, which is supposed to modify the active
+	// control, and records the modification. If the change comes from the user
+	// typing a property, pass that property's name as typing, this merges
+	// consecutive changes into one.
+	changeActive := func(typing string, change func()) {
+		before := stateOf(active)
+		change()
+		if c := newChangeControl(active, before, stateOf(active)); c != nil {
+			c.typing = typing
+			changes.record(c)
+		}
+	}
+
 	boolPanel := func(parent wui.Container, name string) (*wui.CheckBox, *wui.Panel) {
 		c := wui.NewCheckBox()
 		c.SetText(name)
@@ -165,9 +188,11 @@ func main() {
 		c, p := boolPanel(w, name)
 		setterFunc := "Set" + getterFunc // By convention.
 		c.SetOnChange(func(on bool) {
-			reflect.ValueOf(active).MethodByName(setterFunc).Call(
-				[]reflect.Value{reflect.ValueOf(on)},
-			)
+			changeActive("", func() {
+				reflect.ValueOf(active).MethodByName(setterFunc).Call(
+					[]reflect.Value{reflect.ValueOf(on)},
+				)
+			})
 			updateProperties()
 			preview.Paint()
 		})
@@ -220,9 +245,11 @@ func main() {
 				return
 			}
 			if m, ok := reflect.TypeOf(active).MethodByName(setterFunc); ok {
-				reflect.ValueOf(active).MethodByName(setterFunc).Call(
-					[]reflect.Value{reflect.ValueOf(v).Convert(m.Type.In(1))},
-				)
+				changeActive("", func() {
+					reflect.ValueOf(active).MethodByName(setterFunc).Call(
+						[]reflect.Value{reflect.ValueOf(v).Convert(m.Type.In(1))},
+					)
+				})
 				updateProperties()
 				preview.Paint()
 			}
@@ -274,9 +301,11 @@ func main() {
 				return
 			}
 			if m, ok := reflect.TypeOf(active).MethodByName(setterFunc); ok {
-				reflect.ValueOf(active).MethodByName(setterFunc).Call(
-					[]reflect.Value{reflect.ValueOf(v).Convert(m.Type.In(1))},
-				)
+				changeActive("", func() {
+					reflect.ValueOf(active).MethodByName(setterFunc).Call(
+						[]reflect.Value{reflect.ValueOf(v).Convert(m.Type.In(1))},
+					)
+				})
 				updateProperties()
 				preview.Paint()
 			}
@@ -323,9 +352,11 @@ func main() {
 				return
 			}
 			if _, ok := reflect.TypeOf(active).MethodByName(setterFunc); ok {
-				reflect.ValueOf(active).MethodByName(setterFunc).Call(
-					[]reflect.Value{reflect.ValueOf(t.Text())},
-				)
+				changeActive(setterFunc, func() {
+					reflect.ValueOf(active).MethodByName(setterFunc).Call(
+						[]reflect.Value{reflect.ValueOf(t.Text())},
+					)
+				})
 				updateProperties()
 				preview.Paint()
 			}
@@ -369,9 +400,11 @@ func main() {
 				items := strings.Split(list.Text(), "\r\n")
 				items = removeEmptyStrings(items)
 				l.SetText(fmt.Sprintf("%s (%d)", name, len(items)))
-				reflect.ValueOf(active).MethodByName(setterFunc).Call(
-					[]reflect.Value{reflect.ValueOf(items)},
-				)
+				changeActive(setterFunc, func() {
+					reflect.ValueOf(active).MethodByName(setterFunc).Call(
+						[]reflect.Value{reflect.ValueOf(items)},
+					)
+				})
 				start, end := list.CursorPosition()
 				updateProperties()
 				list.SetSelection(start, end)
@@ -421,9 +454,11 @@ func main() {
 		c.SetOnChange(func(index int) {
 			m, ok := reflect.TypeOf(active).MethodByName(setterFunc)
 			if ok {
-				reflect.ValueOf(active).MethodByName(setterFunc).Call(
-					[]reflect.Value{reflect.ValueOf(index).Convert(m.Type.In(1))},
-				)
+				changeActive("", func() {
+					reflect.ValueOf(active).MethodByName(setterFunc).Call(
+						[]reflect.Value{reflect.ValueOf(index).Convert(m.Type.In(1))},
+					)
+				})
 				updateProperties()
 				preview.Paint()
 			}
@@ -474,6 +509,7 @@ func main() {
 		intProp("Inner Y", "InnerY"),
 		intProp("Inner Width", "InnerWidth"),
 		intProp("Inner Height", "InnerHeight"),
+		stringProp("Tool Tip", "ToolTip"),
 		enumProp("Alignment", "Alignment",
 			"Left", "Center", "Right",
 		),
@@ -508,6 +544,9 @@ func main() {
 		boolProp("Vertical", "Vertical"),
 		boolProp("Moves Forever", "MovesForever"),
 		boolProp("Word Wrap", "WordWrap"),
+		boolProp("Check Boxes", "HasCheckBoxes"),
+		boolProp("Edit Labels", "EditableLabels"),
+		boolProp("Closable Tabs", "ClosableTabs"),
 	}
 
 	fontProps := wui.NewPanel()
@@ -561,9 +600,13 @@ func main() {
 		fontProps.SetBounds(15, 0, 175, y+5)
 		fontLabel.SetWidth(fontProps.InnerWidth())
 	}
-	updateFont := func() {
+	// showingFont is true while the font properties are filled with the
+	// active control's font. Changing the font UI will not change the font
+	// then.
+	showingFont := false
+	updateFont := func(typing string) {
 		f, ok := active.(fonter)
-		if !ok {
+		if !ok || showingFont {
 			return
 		}
 		useParent := useParentFont.Checked()
@@ -573,35 +616,34 @@ func main() {
 		fontItalic.SetEnabled(!useParent)
 		fontUnderlined.SetEnabled(!useParent)
 		fontStrikedOut.SetEnabled(!useParent)
-		if useParent {
-			f.SetFont(nil)
-		} else {
-			font, err := wui.NewFont(wui.FontDesc{
-				Name:       fontName.Text(),
-				Height:     fontHeight.Value(),
-				Bold:       fontBold.Checked(),
-				Italic:     fontItalic.Checked(),
-				Underlined: fontUnderlined.Checked(),
-				StrikedOut: fontStrikedOut.Checked(),
-			})
-			if err == nil {
-				f.SetFont(font)
+		changeActive(typing, func() {
+			if useParent {
+				f.SetFont(nil)
+			} else {
+				font, err := wui.NewFont(wui.FontDesc{
+					Name:       fontName.Text(),
+					Height:     fontHeight.Value(),
+					Bold:       fontBold.Checked(),
+					Italic:     fontItalic.Checked(),
+					Underlined: fontUnderlined.Checked(),
+					StrikedOut: fontStrikedOut.Checked(),
+				})
+				if err == nil {
+					f.SetFont(font)
+				}
 			}
-		}
+		})
 		preview.Paint()
 	}
-	useParentFont.SetOnChange(func(disable bool) { updateFont() })
-	fontName.SetOnTextChange(func() { updateFont() })
-	fontHeight.SetOnValueChange(func(int) { updateFont() })
-	fontBold.SetOnChange(func(bool) { updateFont() })
-	fontItalic.SetOnChange(func(bool) { updateFont() })
-	fontUnderlined.SetOnChange(func(bool) { updateFont() })
-	fontStrikedOut.SetOnChange(func(bool) { updateFont() })
+	useParentFont.SetOnChange(func(disable bool) { updateFont("") })
+	fontName.SetOnTextChange(func() { updateFont("FontName") })
+	fontHeight.SetOnValueChange(func(int) { updateFont("") })
+	fontBold.SetOnChange(func(bool) { updateFont("") })
+	fontItalic.SetOnChange(func(bool) { updateFont("") })
+	fontUnderlined.SetOnChange(func(bool) { updateFont("") })
+	fontStrikedOut.SetOnChange(func(bool) { updateFont("") })
 
-	appIcon := w32.LoadIcon(0, w32.MakeIntResource(w32.IDI_APPLICATION))
-	appIconWidth := w32.GetSystemMetrics(w32.SM_CXICON)
-	appIconHeight := w32.GetSystemMetrics(w32.SM_CYICON)
-	appIconWidth, appIconHeight = 17, 17
+	appIconWidth, appIconHeight := 17, 17
 
 	defaultCursor := w.Cursor()
 
@@ -674,6 +716,18 @@ func main() {
 	labelTemplate.SetText("Text Label")
 	labelTemplate.SetBounds(20, 473, 150, 13)
 
+	treeViewTemplate := wui.NewTreeView()
+	treeViewTemplate.SetBounds(20, 495, 72, 75)
+	treeRoot := treeViewTemplate.AddNode("Tree View")
+	treeRoot.AddNode("Node 1")
+	treeRoot.AddNode("Node 2")
+	treeRoot.Expand()
+
+	tabControlTemplate := wui.NewTabControl()
+	tabControlTemplate.SetBounds(98, 495, 72, 75)
+	tabControlTemplate.AddPage("Tab 1")
+	tabControlTemplate.AddPage("Tab 2")
+
 	allTemplates := []wui.Control{
 		panelTemplate,
 		paintBoxTemplate,
@@ -688,6 +742,8 @@ func main() {
 		checkBoxTemplate,
 		radioButtonTemplate,
 		labelTemplate,
+		treeViewTemplate,
+		tabControlTemplate,
 	}
 
 	var highlightedTemplate, controlToAdd wui.Control
@@ -755,7 +811,7 @@ func main() {
 		}
 
 		dlg := wui.NewWindow()
-		dlg.SetPosition(w32.ClientToScreen(w32.HWND(preview.Handle()), 0, 0))
+		dlg.SetPosition(clientToScreen(preview.Handle(), 0, 0))
 		dlg.SetSize(preview.Size())
 
 		code := wui.NewTextEdit()
@@ -827,6 +883,7 @@ func main() {
 			fontProps.SetY(y)
 			y += fontProps.Height()
 			font := f.Font()
+			showingFont = true
 			if _, isWindow := active.(*wui.Window); isWindow {
 				useParentFont.SetEnabled(false)
 				useParentFont.SetChecked(false)
@@ -842,6 +899,7 @@ func main() {
 				fontUnderlined.SetChecked(font.Desc.Underlined)
 				fontStrikedOut.SetChecked(font.Desc.StrikedOut)
 			}
+			showingFont = false
 		}
 	}
 	activate(theWindow)
@@ -951,13 +1009,11 @@ func main() {
 			c.Line(cx, cy, cx+iconSize, cy+iconSize, color)
 			c.Line(cx, cy+iconSize-1, cx+iconSize, cy-1, color)
 
-			w32.DrawIconEx(
-				w32.HDC(c.Handle()),
+			drawAppIcon(
+				c,
 				xOffset+borderSize,
 				yOffset+(topBorderSize-appIconHeight)/2,
-				appIcon,
 				appIconWidth, appIconHeight,
-				0, 0, w32.DI_NORMAL,
 			)
 		}
 
@@ -987,6 +1043,7 @@ func main() {
 	var (
 		dragStartX, dragStartY                                  int
 		preResizeX, preResizeY, preResizeWidth, preResizeHeight int
+		preDragState                                            controlState
 	)
 
 	lastX, lastY := -999999, -999999
@@ -1183,7 +1240,12 @@ func main() {
 				addToThis, x, y := findContainerAt(theWindow, relX+w/2, relY+h/2)
 				controlToAdd.SetBounds(x-w/2, y-h/2, w, h)
 				names[controlToAdd] = defaultName(controlToAdd)
-				addToThis.Add(controlToAdd)
+				if tab, ok := controlToAdd.(*wui.TabControl); ok {
+					for _, page := range tab.Pages() {
+						names[page] = defaultName(page)
+					}
+				}
+				changes.do(newAddControl(addToThis, controlToAdd))
 				activate(controlToAdd)
 				controlToAdd = nil
 				mouseMode = idleMouse
@@ -1195,12 +1257,33 @@ func main() {
 				dragStartY = y
 				preResizeX, preResizeY, preResizeWidth, preResizeHeight = nextToDrag.Bounds()
 				mouseMode = nextDragMouseMode
+				if dragging() {
+					preDragState = stateOf(nextToDrag)
+				}
 				if mouseMode == idleMouse && contains(preview, x, y) {
 					newActive := findControlAt(
 						theWindow,
 						x-preview.X()-innerX,
 						y-preview.Y()-innerY,
 					)
+					if tab, ok := newActive.(*wui.TabControl); ok {
+						// Clicking a tab shows its page so the user can place
+						// controls on it.
+						tabX, tabY, _, _ := relativeBounds(tab, theWindow)
+						i := tabAt(
+							tab,
+							x-preview.X()-xOffset-tabX,
+							y-preview.Y()-yOffset-tabY,
+						)
+						if i != -1 {
+							before := stateOf(tab)
+							tab.SetSelectedIndex(i)
+							if c := newChangeControl(tab, before, stateOf(tab)); c != nil {
+								changes.record(c)
+							}
+							newActive = tab.Pages()[i]
+						}
+					}
 					if newActive != active {
 						activate(newActive)
 					}
@@ -1212,6 +1295,12 @@ func main() {
 
 	w.SetOnMouseUp(func(button wui.MouseButton, x, y int) {
 		if button == wui.MouseButtonLeft {
+			if dragging() {
+				change := newChangeControl(nextToDrag, preDragState, stateOf(nextToDrag))
+				if change != nil {
+					changes.record(change)
+				}
+			}
 			if mouseMode != addControl {
 				mouseMode = idleMouse
 			}
@@ -1239,8 +1328,23 @@ func main() {
 		open.SetTitle("Select a Go file containing one or more wui.Windows")
 		open.AddFilter("Go file", ".go")
 		if accept, path := open.ExecuteSingleSelection(w); accept {
+			code, err := ioutil.ReadFile(path)
+			if err != nil {
+				wui.MessageBoxError("Error", err.Error())
+				return
+			}
+			window, controlNames, controlEvents, err := parseCode(code)
+			if err != nil {
+				wui.MessageBoxError("Error", path+": "+err.Error())
+				return
+			}
+			theWindow = window
+			names = controlNames
+			events = controlEvents
+			changes.clear()
+			activate(theWindow)
+			preview.Paint()
 			setWorkingPath(path)
-			wui.MessageBoxError("TODO", "Open is not yet implemented")
 		}
 	})
 
@@ -1260,7 +1364,7 @@ func main() {
 		save.AddFilter("Go file", ".go")
 		if accept, path := save.Execute(w); accept {
 			saveCodeTo(path)
-			w32.ShellExecute(0, "open", path, "", "", w32.SW_SHOWNORMAL)
+			openWithDefaultProgram(path)
 		}
 	})
 
@@ -1274,33 +1378,58 @@ func main() {
 
 	previewMenu.SetOnClick(func() {
 		// We place the window such that it lies exactly over our drawing.
-		x, y := w32.ClientToScreen(w32.HWND(w.Handle()), preview.X(), preview.Y())
+		x, y := clientToScreen(w.Handle(), preview.X(), preview.Y())
 		showPreview(w, theWindow, x+xOffset, y+yOffset)
 	})
 
 	exitMenu.SetOnClick(w.Close)
 
-	// TODO Build undo/redo.
-	//undoMenu.SetOnClick(func() {})
-	//redoMenu.SetOnClick(func() {})
+	// afterHistoryChange shows the state after an undo or redo. The active
+	// control might have been removed, in that case we activate the window.
+	afterHistoryChange := func() {
+		if isIn(active, theWindow) {
+			activate(active)
+		} else {
+			activate(theWindow)
+		}
+		preview.Paint()
+	}
+
+	undoMenu.SetOnClick(func() {
+		if changes.undo() {
+			afterHistoryChange()
+		}
+	})
+
+	redoMenu.SetOnClick(func() {
+		if changes.redo() {
+			afterHistoryChange()
+		}
+	})
 
 	deleteMenu.SetOnClick(func() {
 		if active != nil && active != theWindow {
 			c := active.(wui.Control)
 			p := active.Parent()
 			activate(p)
-			p.Remove(c)
+			changes.do(newRemoveControl(p, c))
 			preview.Paint()
 		}
 	})
 
-	w.SetShortcut(fileOpenMenu.OnClick(), wui.KeyControl, wui.KeyO)
-	w.SetShortcut(fileSaveMenu.OnClick(), wui.KeyControl, wui.KeyS)
-	w.SetShortcut(fileSaveAsMenu.OnClick(), wui.KeyControl, wui.KeyShift, wui.KeyS)
-	w.SetShortcut(previewMenu.OnClick(), wui.KeyControl, wui.KeyR)
-	w.SetShortcut(undoMenu.OnClick(), wui.KeyControl, wui.KeyZ)
-	w.SetShortcut(redoMenu.OnClick(), wui.KeyControl, wui.KeyShift, wui.KeyZ)
-	w.SetShortcut(deleteMenu.OnClick(), wui.KeyControl, wui.KeyDelete)
+	addTabPageMenu.SetOnClick(func() {
+		tab, ok := active.(*wui.TabControl)
+		if page, isPage := active.(*wui.TabPage); isPage {
+			tab, ok = page.Parent().(*wui.TabControl)
+		}
+		if ok {
+			page := wui.NewTabPage()
+			page.SetTitle("Tab " + strconv.Itoa(len(tab.Pages())+1))
+			names[page] = defaultName(page)
+			changes.do(newAddControl(tab, page))
+			preview.Paint()
+		}
+	})
 
 	//w.SetShortcut(w.Close, wui.KeyEscape) // TODO ESC for debugging
 
@@ -1329,7 +1458,7 @@ func defaultWindow() *wui.Window {
 }
 
 func findControlAt(parent wui.Container, x, y int) node {
-	for _, child := range parent.Children() {
+	for _, child := range designedChildren(parent) {
 		if contains(child, x, y) {
 			if container, ok := child.(wui.Container); ok {
 				dx, dy, _, _ := container.Bounds()
@@ -1477,6 +1606,12 @@ func drawControl(c wui.Control, d drawer) {
 		drawFloatUpDown(x, d)
 	case *wui.TextEdit:
 		drawTextEdit(x, d)
+	case *wui.TreeView:
+		drawTreeView(x, d)
+	case *wui.TabControl:
+		drawTabControl(x, d)
+	case *wui.TabPage:
+		drawTabPage(x, d)
 	default:
 		panic("unhandled control type")
 	}
@@ -1621,9 +1756,9 @@ func drawSlider(s *wui.Slider, d drawer) {
 		}
 		drawCursorArrow = func(offset int) {
 			d.Polygon([]wui.Point{
-				{int32(cursorCenter - 5), int32(y + 15)},
-				{int32(cursorCenter), int32(y + 15 + offset)},
-				{int32(cursorCenter + 5), int32(y + 15)},
+				{X: int32(cursorCenter - 5), Y: int32(y + 15)},
+				{X: int32(cursorCenter), Y: int32(y + 15 + offset)},
+				{X: int32(cursorCenter + 5), Y: int32(y + 15)},
 			}, cursorColor)
 		}
 
@@ -1661,9 +1796,9 @@ func drawSlider(s *wui.Slider, d drawer) {
 		}
 		drawCursorArrow = func(offset int) {
 			d.Polygon([]wui.Point{
-				{int32(x + 15), int32(cursorCenter - 5)},
-				{int32(x + 15 + offset), int32(cursorCenter)},
-				{int32(x + 15), int32(cursorCenter + 5)},
+				{X: int32(x + 15), Y: int32(cursorCenter - 5)},
+				{X: int32(x + 15 + offset), Y: int32(cursorCenter)},
+				{X: int32(x + 15), Y: int32(cursorCenter + 5)},
 			}, cursorColor)
 		}
 
@@ -1886,6 +2021,140 @@ func drawTextEdit(t *wui.TextEdit, d drawer) {
 	}
 }
 
+func drawTreeView(t *wui.TreeView, d drawer) {
+	x, y, w, h := t.Bounds()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	d.PushDrawRegion(x, y, w, h)
+	d.DrawRect(x, y, w, h, wui.RGB(130, 135, 144))
+	d.FillRect(x+1, y+1, w-2, h-2, wui.RGB(255, 255, 255))
+	color := wui.RGB(0, 0, 0)
+	if !t.Enabled() {
+		color = wui.RGB(109, 109, 109)
+	}
+	lineY := y + 3
+	var drawNodes func(nodes []*wui.TreeNode, indent int)
+	drawNodes = func(nodes []*wui.TreeNode, indent int) {
+		for _, n := range nodes {
+			nodeX := x + 5 + indent*19
+			if len(n.Children()) > 0 || n.HasLazyChildren() {
+				// Draw the expand button, a minus for expanded nodes and a
+				// plus for collapsed ones.
+				d.DrawRect(nodeX, lineY+4, 9, 9, wui.RGB(150, 150, 150))
+				d.Line(nodeX+2, lineY+8, nodeX+7, lineY+8, color)
+				if !n.Expanded() {
+					d.Line(nodeX+4, lineY+6, nodeX+4, lineY+11, color)
+				}
+			}
+			textX := nodeX + 14
+			if t.HasCheckBoxes() {
+				d.DrawRect(textX, lineY+2, 13, 13, wui.RGB(0, 0, 0))
+				if n.Checked() {
+					d.Line(textX+2, lineY+8, textX+5, lineY+11, color)
+					d.Line(textX+5, lineY+10, textX+11, lineY+4, color)
+				}
+				textX += 16
+			}
+			d.TextOut(textX, lineY+1, n.Text(), color)
+			lineY += 18
+			if n.Expanded() {
+				drawNodes(n.Children(), indent+1)
+			}
+		}
+	}
+	drawNodes(t.Nodes(), 0)
+	d.PopDrawRegion()
+}
+
+// designTabHeight is the height of the tabs that the designer draws on top of
+// TabControls.
+const designTabHeight = 19
+
+// designTabs returns the tabs of the TabControl, relative to the control. The
+// designer does not know the fonts of the real tabs, their widths are only
+// estimated from the lengths of the titles.
+func designTabs(t *wui.TabControl) []rectangle {
+	var tabs []rectangle
+	x := 2
+	for _, p := range t.Pages() {
+		width := 6 + 5*utf8.RuneCountInString(p.Title())
+		if t.ClosableTabs() {
+			width += 12
+		}
+		tabs = append(tabs, rect(x, 2, width, designTabHeight))
+		x += width
+	}
+	return tabs
+}
+
+// tabAt returns the index of the tab at x,y, relative to the TabControl, or -1
+// if there is no tab.
+func tabAt(t *wui.TabControl, x, y int) int {
+	for i, tab := range designTabs(t) {
+		if tab.contains(x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+func drawTabControl(t *wui.TabControl, d drawer) {
+	x, y, w, h := t.Bounds()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	d.PushDrawRegion(x, y, w, h)
+	white := wui.RGB(255, 255, 255)
+	border := wui.RGB(137, 140, 149)
+	color := wui.RGB(0, 0, 0)
+	if !t.Enabled() {
+		color = wui.RGB(109, 109, 109)
+	}
+	top := 2 + designTabHeight
+	d.FillRect(x, y+top, w, h-top, white)
+	d.DrawRect(x, y+top, w, h-top, border)
+	drawTab := func(i int, tab rectangle, selected bool) {
+		tab.x += x
+		tab.y += y
+		fill := wui.RGB(240, 240, 240)
+		if selected {
+			// The selected tab is a bit larger and merges with the page.
+			tab = rect(tab.x-2, tab.y-2, tab.w+4, tab.h+3)
+			fill = white
+		}
+		d.FillRect(tab.x, tab.y, tab.w, tab.h, fill)
+		d.DrawRect(tab.x, tab.y, tab.w, tab.h, border)
+		if selected {
+			d.Line(tab.x+1, tab.y+tab.h-1, tab.x+tab.w-1, tab.y+tab.h-1, white)
+		}
+		if t.ClosableTabs() {
+			tab.w -= 12
+			d.TextRectFormat(tab.x+tab.w, tab.y, 12, tab.h, "×", wui.FormatCenter, color)
+		}
+		title := t.Pages()[i].Title()
+		d.TextRectFormat(tab.x, tab.y, tab.w, tab.h, title, wui.FormatCenter, color)
+	}
+	tabs := designTabs(t)
+	for i, tab := range tabs {
+		if i != t.SelectedIndex() {
+			drawTab(i, tab, false)
+		}
+	}
+	if page := t.SelectedPage(); page != nil {
+		drawTab(t.SelectedIndex(), tabs[t.SelectedIndex()], true)
+	}
+	d.PopDrawRegion()
+	if page := t.SelectedPage(); page != nil {
+		drawTabPage(page, makeOffsetDrawer(d, x, y))
+	}
+}
+
+func drawTabPage(p *wui.TabPage, d drawer) {
+	x, y, _, _ := p.Bounds()
+	drawContainer(p, makeOffsetDrawer(d, x, y))
+}
+
 type node interface {
 	Parent() wui.Container
 	Bounds() (x, y, width, height int)
@@ -2161,6 +2430,21 @@ func cloneControl(c wui.Control) wui.Control {
 		t.SetWordWrap(x.WordWrap())
 		t.SetText(x.Text())
 		return t
+	case *wui.TreeView:
+		t := wui.NewTreeView()
+		t.SetBounds(0, 0, x.Width(), x.Height())
+		t.SetHasCheckBoxes(x.HasCheckBoxes())
+		t.SetEditableLabels(x.EditableLabels())
+		return t
+	case *wui.TabControl:
+		t := wui.NewTabControl()
+		t.SetBounds(0, 0, x.Width(), x.Height())
+		t.SetClosableTabs(x.ClosableTabs())
+		t.SetSelectedIndex(x.SelectedIndex())
+		for _, p := range x.Pages() {
+			t.AddPage(p.Title())
+		}
+		return t
 	default:
 		panic("unhandled control type in cloneControl")
 	}
@@ -2181,18 +2465,50 @@ type fonter interface {
 	SetFont(*wui.Font)
 }
 
+// isIn returns true if n is the window or one of its (grand-)children.
+func isIn(n node, w *wui.Window) bool {
+	for n != nil {
+		if n == node(w) {
+			return true
+		}
+		parent := n.Parent()
+		if parent == nil {
+			return false
+		}
+		n = parent
+	}
+	return false
+}
+
 func findContainerAt(c wui.Container, x, y int) (innerMost wui.Container, atX, atY int) {
-	for _, child := range c.Children() {
+	for _, child := range designedChildren(c) {
 		if container, ok := child.(wui.Container); ok {
 			if innerContains(container, x, y) {
 				dx, dy, _, _ := container.InnerBounds()
-				return findContainerAt(container, x-dx, y-dy)
+				inner, innerX, innerY := findContainerAt(container, x-dx, y-dy)
+				// Controls are placed on the pages of a TabControl, not on
+				// the TabControl itself.
+				if _, isTabs := inner.(*wui.TabControl); !isTabs {
+					return inner, innerX, innerY
+				}
 			}
 		}
 	}
 	return c, x, y
 }
 
+// designedChildren returns the children of the container that are shown in the
+// designer. Only the selected page of a TabControl is shown.
+func designedChildren(c wui.Container) []wui.Control {
+	if tab, ok := c.(*wui.TabControl); ok {
+		if page := tab.SelectedPage(); page != nil {
+			return []wui.Control{page}
+		}
+		return nil
+	}
+	return c.Children()
+}
+
 // removeEmptyStrings changes the given input slice.
 func removeEmptyStrings(items []string) []string {
 	n := 0
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gonutz/wui/v2"
+)
+
+// constructors creates the controls for the wui.New* calls in the generated
+// code, indexed by their type names.
+var constructors = map[string]func() interface{}{
+	"Window":      func() interface{} { return wui.NewWindow() },
+	"Button":      func() interface{} { return wui.NewButton() },
+	"Label":       func() interface{} { return wui.NewLabel() },
+	"CheckBox":    func() interface{} { return wui.NewCheckBox() },
+	"RadioButton": func() interface{} { return wui.NewRadioButton() },
+	"Slider":      func() interface{} { return wui.NewSlider() },
+	"Panel":       func() interface{} { return wui.NewPanel() },
+	"PaintBox":    func() interface{} { return wui.NewPaintBox() },
+	"EditLine":    func() interface{} { return wui.NewEditLine() },
+	"IntUpDown":   func() interface{} { return wui.NewIntUpDown() },
+	"ComboBox":    func() interface{} { return wui.NewComboBox() },
+	"ProgressBar": func() interface{} { return wui.NewProgressBar() },
+	"FloatUpDown": func() interface{} { return wui.NewFloatUpDown() },
+	"TextEdit":    func() interface{} { return wui.NewTextEdit() },
+	"TreeView":    func() interface{} { return wui.NewTreeView() },
+	"TabControl":  func() interface{} { return wui.NewTabControl() },
+	"TabPage":     func() interface{} { return wui.NewTabPage() },
+}
+
+// parseCode is the inverse of generateCode. It reads the main function of the
+// given Go code and builds the window that it describes. It also returns the
+// variable names of the controls and the code of their events so generating
+// code for the parsed window results in the original code.
+func parseCode(code []byte) (*wui.Window, map[interface{}]string, map[event]string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", code, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var mainFunc *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if f, ok := decl.(*ast.FuncDecl); ok && f.Recv == nil && f.Name.Name == "main" {
+			mainFunc = f
+		}
+	}
+	if mainFunc == nil || mainFunc.Body == nil {
+		return nil, nil, nil, errors.New("no main function found")
+	}
+
+	p := codeParser{
+		fset:     fset,
+		code:     code,
+		controls: make(map[string]interface{}),
+		fonts:    make(map[string]*wui.Font),
+		names:    make(map[interface{}]string),
+		events:   make(map[event]string),
+	}
+	for _, stmt := range mainFunc.Body.List {
+		if err := p.statement(stmt); err != nil {
+			line := fset.Position(stmt.Pos()).Line
+			return nil, nil, nil, fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+	if p.window == nil {
+		return nil, nil, nil, errors.New("no wui.Window found in main function")
+	}
+	return p.window, p.names, p.events, nil
+}
+
+type codeParser struct {
+	fset     *token.FileSet
+	code     []byte
+	window   *wui.Window
+	controls map[string]interface{}
+	fonts    map[string]*wui.Font
+	names    map[interface{}]string
+	events   map[event]string
+}
+
+func (p *codeParser) statement(stmt ast.Stmt) error {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok != token.DEFINE || len(s.Rhs) != 1 {
+			return errors.New("unexpected assignment")
+		}
+		call, ok := s.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			return errors.New("unexpected assignment")
+		}
+		fun, ok := wuiSelector(call.Fun)
+		if !ok {
+			return errors.New("only wui functions can be called")
+		}
+		name, ok := s.Lhs[0].(*ast.Ident)
+		if !ok {
+			return errors.New("unexpected assignment")
+		}
+		if _, ok := p.controls[name.Name]; ok || p.fonts[name.Name] != nil {
+			return errors.New(name.Name + " redeclared")
+		}
+		if fun == "NewFont" {
+			return p.font(name.Name, call.Args)
+		}
+		newControl, ok := constructors[strings.TrimPrefix(fun, "New")]
+		if !ok || len(call.Args) != 0 || len(s.Lhs) != 1 {
+			return errors.New("unknown function wui." + fun)
+		}
+		c := newControl()
+		if w, ok := c.(*wui.Window); ok && p.window == nil {
+			p.window = w
+		}
+		p.controls[name.Name] = c
+		p.names[c] = name.Name
+		return nil
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return errors.New("unexpected expression")
+		}
+		return p.methodCall(call)
+	default:
+		return errors.New("unexpected statement")
+	}
+}
+
+func (p *codeParser) font(name string, args []ast.Expr) error {
+	if len(args) != 1 {
+		return errors.New("wui.NewFont needs one argument")
+	}
+	lit, ok := args[0].(*ast.CompositeLit)
+	if !ok {
+		return errors.New("wui.NewFont needs a wui.FontDesc literal")
+	}
+	if typ, _ := wuiSelector(lit.Type); typ != "FontDesc" {
+		return errors.New("wui.NewFont needs a wui.FontDesc literal")
+	}
+	var desc wui.FontDesc
+	d := reflect.ValueOf(&desc).Elem()
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return errors.New("wui.FontDesc fields must be named")
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return errors.New("invalid wui.FontDesc field")
+		}
+		field := d.FieldByName(key.Name)
+		if !field.IsValid() {
+			return errors.New("unknown wui.FontDesc field " + key.Name)
+		}
+		v, err := p.value(kv.Value, field.Type())
+		if err != nil {
+			return err
+		}
+		field.Set(v)
+	}
+	font, err := wui.NewFont(desc)
+	if err != nil {
+		return err
+	}
+	p.fonts[name] = font
+	return nil
+}
+
+func (p *codeParser) methodCall(call *ast.CallExpr) error {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return errors.New("unexpected function call")
+	}
+	recv, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return errors.New("unexpected function call")
+	}
+	c, ok := p.controls[recv.Name]
+	if !ok {
+		return errors.New("unknown variable " + recv.Name)
+	}
+	method := sel.Sel.Name
+
+	switch {
+	case method == "Add":
+		container, ok := c.(wui.Container)
+		if !ok || len(call.Args) != 1 {
+			return errors.New("invalid call to Add")
+		}
+		arg, ok := call.Args[0].(*ast.Ident)
+		if !ok {
+			return errors.New("invalid call to Add")
+		}
+		child, ok := p.controls[arg.Name].(wui.Control)
+		if !ok {
+			return errors.New("unknown control " + arg.Name)
+		}
+		if _, isTabs := container.(*wui.TabControl); isTabs {
+			if _, isPage := child.(*wui.TabPage); !isPage {
+				return errors.New("a TabControl can only have TabPages as children")
+			}
+		}
+		container.Add(child)
+		return nil
+	case method == "Show" || method == "SetShortcut":
+		// These are the last lines in generated code, they do not define the
+		// window. SetShortcut is only generated for previews.
+		return nil
+	case strings.HasPrefix(method, "SetOn"):
+		if len(call.Args) != 1 {
+			return errors.New("invalid call to " + method)
+		}
+		p.events[event{c, strings.TrimPrefix(method, "Set")}] = p.source(call.Args[0])
+		return nil
+	}
+
+	m := reflect.ValueOf(c).MethodByName(method)
+	if !m.IsValid() {
+		return fmt.Errorf("%s has no method %s", p.names[c], method)
+	}
+	// Only the setters that we generate may be called, other methods, like
+	// Close or Destroy, would act on the designer's own controls.
+	if !isGeneratedSetter(c, method) {
+		return fmt.Errorf("%s.%s is not a property setter", p.names[c], method)
+	}
+	t := m.Type()
+	n := t.NumIn()
+	if t.IsVariadic() && len(call.Args) < n-1 ||
+		!t.IsVariadic() && len(call.Args) != n {
+		return fmt.Errorf("%s needs %d arguments", method, n)
+	}
+	args := make([]reflect.Value, len(call.Args))
+	for i := range args {
+		argType := t.In(min(i, n-1))
+		if t.IsVariadic() && i >= n-1 {
+			argType = argType.Elem()
+		}
+		v, err := p.value(call.Args[i], argType)
+		if err != nil {
+			return err
+		}
+		args[i] = v
+	}
+	m.Call(args)
+	return nil
+}
+
+// isGeneratedSetter returns true if generated code can call the method on the
+// control, i.e. it is SetFont or the setter of one of the control's properties.
+func isGeneratedSetter(c interface{}, method string) bool {
+	if method == "SetFont" {
+		return true
+	}
+	for _, prop := range propertiesOf(c) {
+		if method == "Set"+prop.name {
+			return true
+		}
+	}
+	return false
+}
+
+// value evaluates the expression which was generated by toGo, as a value of
+// the given type.
+func (p *codeParser) value(expr ast.Expr, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	fail := func() (reflect.Value, error) {
+		return v, fmt.Errorf("%s is not a valid %v", p.source(expr), t)
+	}
+
+	if t == reflect.TypeOf((*wui.Font)(nil)) {
+		if id, ok := expr.(*ast.Ident); ok && p.fonts[id.Name] != nil {
+			return reflect.ValueOf(p.fonts[id.Name]), nil
+		}
+		return fail()
+	}
+
+	if name, ok := wuiSelector(expr); ok {
+		// This is a named constant. Its String method returns the Go code for
+		// it, see toGo.
+		if _, ok := v.Interface().(fmt.Stringer); !ok {
+			return fail()
+		}
+		for i := 0; i < 256; i++ {
+			if v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uintptr {
+				v.SetUint(uint64(i))
+			} else {
+				v.SetInt(int64(i))
+			}
+			if v.Interface().(fmt.Stringer).String() == "wui."+name {
+				return v, nil
+			}
+		}
+		return fail()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if id, ok := expr.(*ast.Ident); ok && (id.Name == "true" || id.Name == "false") {
+			v.SetBool(id.Name == "true")
+			return v, nil
+		}
+	case reflect.String:
+		if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			s, err := strconv.Unquote(lit.Value)
+			if err == nil {
+				v.SetString(s)
+				return v, nil
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := number(expr); ok {
+			i, err := strconv.ParseInt(n, 0, t.Bits())
+			if err == nil {
+				v.SetInt(i)
+				return v, nil
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := number(expr); ok {
+			i, err := strconv.ParseUint(n, 0, t.Bits())
+			if err == nil {
+				v.SetUint(i)
+				return v, nil
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := number(expr); ok {
+			f, err := strconv.ParseFloat(n, t.Bits())
+			if err == nil {
+				v.SetFloat(f)
+				return v, nil
+			}
+		}
+	case reflect.Slice:
+		if lit, ok := expr.(*ast.CompositeLit); ok {
+			v = reflect.MakeSlice(t, 0, len(lit.Elts))
+			for _, elt := range lit.Elts {
+				e, err := p.value(elt, t.Elem())
+				if err != nil {
+					return e, err
+				}
+				v = reflect.Append(v, e)
+			}
+			return v, nil
+		}
+	}
+	return fail()
+}
+
+// source returns the Go code of the given expression. Generated code is
+// indented by one tab inside the main function, this indentation is removed
+// from all but the first line.
+func (p *codeParser) source(expr ast.Expr) string {
+	start := p.fset.Position(expr.Pos()).Offset
+	end := p.fset.Position(expr.End()).Offset
+	return strings.Replace(string(p.code[start:end]), "\n\t", "\n", -1)
+}
+
+// number returns the text of a number literal, possibly negative.
+func number(expr ast.Expr) (string, bool) {
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.SUB {
+		n, ok := number(u.X)
+		return "-" + n, ok
+	}
+	if lit, ok := expr.(*ast.BasicLit); ok {
+		return lit.Value, lit.Kind == token.INT || lit.Kind == token.FLOAT
+	}
+	return "", false
+}
+
+// wuiSelector returns X if the expression is of the form wui.X.
+func wuiSelector(expr ast.Expr) (string, bool) {
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "wui" {
+			return sel.Sel.Name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/wui/v2"
+)
+
+func TestGeneratedCodeCanBeParsedBack(t *testing.T) {
+	tests := []struct {
+		name   string
+		window func() *wui.Window
+	}{
+		{
+			name:   "default window",
+			window: wui.NewWindow,
+		},
+		{
+			name: "window properties",
+			window: func() *wui.Window {
+				w := wui.NewWindow()
+				w.SetTitle("A \"quoted\" title\n")
+				w.SetInnerBounds(10, -20, 300, 200)
+				w.SetAlpha(128)
+				w.SetHasMaxButton(false)
+				w.SetResizable(false)
+				w.SetState(wui.WindowMaximized)
+				return w
+			},
+		},
+		{
+			name: "fonts",
+			window: func() *wui.Window {
+				w := wui.NewWindow()
+				f, _ := wui.NewFont(wui.FontDesc{Name: "Tahoma", Height: -11})
+				w.SetFont(f)
+				b := wui.NewButton()
+				f, _ = wui.NewFont(wui.FontDesc{Bold: true, StrikedOut: true})
+				b.SetFont(f)
+				w.Add(b)
+				return w
+			},
+		},
+		{
+			name: "all controls",
+			window: func() *wui.Window {
+				w := wui.NewWindow()
+
+				b := wui.NewButton()
+				b.SetText("OK")
+				b.SetBounds(1, 2, 3, 4)
+				b.SetAnchors(wui.AnchorMinAndMax, wui.AnchorCenter)
+				b.SetEnabled(false)
+				w.Add(b)
+
+				l := wui.NewLabel()
+				l.SetAlignment(wui.AlignRight)
+				l.SetVisible(false)
+				w.Add(l)
+
+				c := wui.NewCheckBox()
+				c.SetChecked(true)
+				w.Add(c)
+
+				r := wui.NewRadioButton()
+				r.SetText("radio")
+				w.Add(r)
+
+				s := wui.NewSlider()
+				s.SetMinMax(-50, 200)
+				s.SetCursorPosition(150)
+				s.SetOrientation(wui.VerticalSlider)
+				s.SetTickPosition(wui.TicksOnBothSides)
+				w.Add(s)
+
+				e := wui.NewEditLine()
+				e.SetText("text")
+				e.SetIsPassword(true)
+				e.SetCharacterLimit(10)
+				w.Add(e)
+
+				i := wui.NewIntUpDown()
+				i.SetMinMax(100, 200)
+				i.SetValue(150)
+				w.Add(i)
+
+				f := wui.NewFloatUpDown()
+				f.SetMinMax(-1.5, 1e6)
+				f.SetPrecision(3)
+				f.SetValue(0.125)
+				w.Add(f)
+
+				combo := wui.NewComboBox()
+				combo.SetItems([]string{"one", "two", "three"})
+				combo.SetSelectedIndex(1)
+				w.Add(combo)
+
+				single := wui.NewComboBox()
+				single.SetItems([]string{"only"})
+				w.Add(single)
+
+				p := wui.NewProgressBar()
+				p.SetValue(0.5)
+				p.SetVertical(true)
+				w.Add(p)
+
+				text := wui.NewTextEdit()
+				text.SetText("line 1\r\nline 2")
+				text.SetWordWrap(true)
+				w.Add(text)
+
+				tree := wui.NewTreeView()
+				tree.SetHasCheckBoxes(true)
+				tree.SetEditableLabels(true)
+				w.Add(tree)
+
+				tabs := wui.NewTabControl()
+				tabs.SetSelectedIndex(1)
+				tabs.SetClosableTabs(true)
+				w.Add(tabs)
+				tabs.AddPage("General")
+				page := tabs.AddPage("Advanced")
+				page.SetEnabled(false)
+				page.Add(wui.NewCheckBox())
+
+				return w
+			},
+		},
+		{
+			name: "nested panels",
+			window: func() *wui.Window {
+				w := wui.NewWindow()
+				outer := wui.NewPanel()
+				outer.SetBorderStyle(wui.PanelBorderSunken)
+				outer.SetBounds(10, 10, 200, 200)
+				w.Add(outer)
+				inner := wui.NewPanel()
+				inner.SetBounds(5, 5, 50, 50)
+				outer.Add(inner)
+				inner.Add(wui.NewButton())
+				w.Add(wui.NewLabel())
+				return w
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names = make(map[interface{}]string)
+			events = make(map[event]string)
+			w := tt.window()
+			nameAll(w)
+			code := string(generateCode(w, false))
+
+			parsed, parsedNames, parsedEvents, err := parseCode([]byte(code))
+			check.Eq(t, err, nil)
+			names, events = parsedNames, parsedEvents
+			check.Eq(t, string(generateCode(parsed, false)), code)
+		})
+	}
+}
+
+func TestFormFilesKeepDesignerPropertiesAndNames(t *testing.T) {
+	w := wui.NewWindow()
+	w.SetTitle("Form")
+	w.SetInnerBounds(10, 20, 300, 200)
+	f, _ := wui.NewFont(wui.FontDesc{Name: "Tahoma", Height: -11})
+	w.SetFont(f)
+
+	ok := wui.NewButton()
+	ok.SetText("OK")
+	ok.SetBounds(1, 2, 3, 4)
+	ok.SetAnchors(wui.AnchorMinAndMax, wui.AnchorCenter)
+	w.Add(ok)
+
+	panel := wui.NewPanel()
+	panel.SetBorderStyle(wui.PanelBorderSunken)
+	w.Add(panel)
+	nameEdit := wui.NewEditLine()
+	nameEdit.SetText("name")
+	nameEdit.SetCharacterLimit(10)
+	panel.Add(nameEdit)
+
+	tabs := wui.NewTabControl()
+	tabs.SetClosableTabs(true)
+	w.Add(tabs)
+	general := tabs.AddPage("General")
+	advanced := tabs.AddPage("Advanced")
+	advanced.SetEnabled(false)
+	verbose := wui.NewCheckBox()
+	verbose.SetChecked(true)
+	advanced.Add(verbose)
+
+	formNames := map[string]wui.Control{
+		"okButton": ok,
+		"panel":    panel,
+		"nameEdit": nameEdit,
+		"tabs":     tabs,
+		"general":  general,
+		"advanced": advanced,
+		"verbose":  verbose,
+	}
+	names = map[interface{}]string{w: "mainWindow"}
+	events = make(map[event]string)
+	for name, c := range formNames {
+		names[c] = name
+	}
+	code := string(generateCode(w, false))
+
+	var form bytes.Buffer
+	check.Eq(t, wui.SaveWindow(&form, w, formNames), nil)
+	loaded, loadedNames, err := wui.LoadWindow(&form)
+	check.Eq(t, err, nil)
+
+	check.Eq(t, len(loadedNames), len(formNames))
+	check.Eq(t, loadedNames["okButton"].(*wui.Button).Text(), "OK")
+	check.Eq(t, loadedNames["nameEdit"].Parent(), loadedNames["panel"])
+	check.Eq(t, loadedNames["verbose"].Parent(), loadedNames["advanced"])
+	check.Eq(t, loadedNames["advanced"].Parent(), loadedNames["tabs"])
+
+	names = map[interface{}]string{loaded: "mainWindow"}
+	for name, c := range loadedNames {
+		names[c] = name
+	}
+	check.Eq(t, string(generateCode(loaded, false)), code)
+}
+
+// nameAll gives all controls default names, like the designer does when they
+// are placed.
+func nameAll(c interface{}) {
+	names[c] = defaultName(c)
+	if con, ok := c.(wui.Container); ok {
+		for _, child := range con.Children() {
+			nameAll(child)
+		}
+	}
+}
+
+func TestParsingKeepsNamesAndEvents(t *testing.T) {
+	code := `package main
+
+import "github.com/gonutz/wui/v2"
+
+func main() {
+	mainWindow := wui.NewWindow()
+	mainWindow.SetTitle("Paint")
+
+	canvas := wui.NewPaintBox()
+	canvas.SetSize(100, 50)
+	mainWindow.Add(canvas)
+
+	canvas.SetOnPaint(func(canvas *wui.Canvas) {
+		canvas.FillRect(0, 0, 10, 10, wui.RGB(255, 0, 0))
+	})
+
+	mainWindow.Show()
+}
+`
+	w, parsedNames, parsedEvents, err := parseCode([]byte(code))
+	check.Eq(t, err, nil)
+	check.Eq(t, w.Title(), "Paint")
+	check.Eq(t, len(w.Children()), 1)
+	check.Eq(t, parsedNames[w], "mainWindow")
+	canvas := w.Children()[0]
+	check.Eq(t, parsedNames[canvas], "canvas")
+	check.Eq(
+		t,
+		parsedEvents[event{canvas, "OnPaint"}],
+		"func(canvas *wui.Canvas) {\n\tcanvas.FillRect(0, 0, 10, 10, wui.RGB(255, 0, 0))\n}",
+	)
+
+	names, events = parsedNames, parsedEvents
+	check.Eq(t, string(generateCode(w, false)), code)
+}
+
+func TestParsingInvalidCodeFails(t *testing.T) {
+	parseErr := func(code string) string {
+		t.Helper()
+		_, _, _, err := parseCode([]byte(code))
+		if err == nil {
+			t.Fatal("error expected")
+		}
+		return err.Error()
+	}
+
+	check.Eq(t, parseErr(`package main`), "no main function found")
+	check.Eq(t, parseErr(`package main
+func main() {}`), "no wui.Window found in main function")
+	check.Eq(t, parseErr(`package main
+func main() {
+	w := wui.NewWindow()
+	w.SetTitle(123)
+}`), "line 4: 123 is not a valid string")
+	check.Eq(t, parseErr(`package main
+func main() {
+	w := wui.NewWindow()
+	w.SetFoo(1)
+}`), "line 4: w has no method SetFoo")
+	check.Eq(t, parseErr(`package main
+func main() {
+	w := wui.NewWindow()
+	w.Close()
+}`), "line 4: w.Close is not a property setter")
+	check.Eq(t, parseErr(`package main
+func main() {
+	w := wui.NewWindow()
+	w.SetTitle("a", "b")
+}`), "line 4: SetTitle needs 1 arguments")
+	check.Eq(t, parseErr(`package main
+func main() {
+	tabs := wui.NewTabControl()
+	b := wui.NewButton()
+	tabs.Add(b)
+}`), "line 5: a TabControl can only have TabPages as children")
+	check.Eq(t, parseErr(`package main
+func main() {
+	b := wui.NewUnknown()
+}`), "line 3: unknown function wui.NewUnknown")
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import "github.com/gonutz/wui/v2"
+
+// Without the Win32 API the designer only runs headless, e.g. in tests, so
+// these are no-ops.
+
+func drawAppIcon(c *wui.Canvas, x, y, width, height int) {}
+
+func clientToScreen(window uintptr, x, y int) (int, int) {
+	return x, y
+}
+
+func openWithDefaultProgram(path string) {}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2"
+)
+
+var appIcon = w32.LoadIcon(0, w32.MakeIntResource(w32.IDI_APPLICATION))
+
+func drawAppIcon(c *wui.Canvas, x, y, width, height int) {
+	w32.DrawIconEx(
+		w32.HDC(c.Handle()),
+		x, y,
+		appIcon,
+		width, height,
+		0, 0, w32.DI_NORMAL,
+	)
+}
+
+func clientToScreen(window uintptr, x, y int) (int, int) {
+	return w32.ClientToScreen(w32.HWND(window), x, y)
+}
+
+func openWithDefaultProgram(path string) {
+	w32.ShellExecute(0, "open", path, "", "", w32.SW_SHOWNORMAL)
+}
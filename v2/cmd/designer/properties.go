@@ -32,6 +32,7 @@ func commonPropertiesPlus(plus ...property) []property {
 			prop("Height"),
 			prop("Size", "Width", "Height"),
 			prop("Bounds", "Position", "Size"),
+			prop("ToolTip"),
 		},
 		plus...)
 }
@@ -81,10 +82,12 @@ var properties = map[interface{}][]property{
 	wui.NewSlider(): commonPropertiesPlus(
 		prop("ArrowIncrement"),
 		prop("MouseIncrement"),
-		prop("CursorPosition"),
+		// The range must be set before the cursor position because the
+		// position is clamped to the range.
 		prop("Min"),
 		prop("Max"),
 		prop("MinMax", "Min", "Max"),
+		prop("CursorPosition"),
 		prop("Orientation"),
 		prop("TickFrequency"),
 		prop("TickPosition"),
@@ -105,10 +108,10 @@ var properties = map[interface{}][]property{
 	),
 
 	wui.NewIntUpDown(): commonPropertiesPlus(
-		prop("Value"),
 		prop("Min"),
 		prop("Max"),
 		prop("MinMax", "Min", "Max"),
+		prop("Value"),
 	),
 
 	wui.NewComboBox(): commonPropertiesPlus(
@@ -123,11 +126,11 @@ var properties = map[interface{}][]property{
 	),
 
 	wui.NewFloatUpDown(): commonPropertiesPlus(
-		prop("Value"),
 		prop("Min"),
 		prop("Max"),
 		prop("MinMax", "Min", "Max"),
 		prop("Precision"),
+		prop("Value"),
 	),
 
 	wui.NewTextEdit(): commonPropertiesPlus(
@@ -136,16 +139,42 @@ var properties = map[interface{}][]property{
 		prop("CharacterLimit"),
 		prop("WritesTabs"),
 	),
+
+	wui.NewTreeView(): commonPropertiesPlus(
+		prop("HasCheckBoxes"),
+		prop("EditableLabels"),
+	),
+
+	wui.NewTabControl(): commonPropertiesPlus(
+		prop("SelectedIndex"),
+		prop("ClosableTabs"),
+	),
+
+	// The TabControl places its pages, they have no bounds of their own.
+	wui.NewTabPage(): []property{
+		prop("Enabled"),
+		prop("Title"),
+	},
 }
 
 func generateProperties(variable string, control interface{}) []string {
-	for def, props := range properties {
+	def := defaultOf(control)
+	return genProps(variable, control, def, properties[def])
+}
+
+func propertiesOf(control interface{}) []property {
+	return properties[defaultOf(control)]
+}
+
+// defaultOf returns the default control of the same type as the given control.
+// It is the key into the properties map.
+func defaultOf(control interface{}) interface{} {
+	for def := range properties {
 		if reflect.TypeOf(control) == reflect.TypeOf(def) {
-			return genProps(variable, control, def, props)
+			return def
 		}
 	}
 	panic("no properties found for type " + reflect.TypeOf(control).String())
-
 }
 
 func genProps(variable string, c, def interface{}, props []property) []string {
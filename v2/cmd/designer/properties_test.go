@@ -53,6 +53,10 @@ func TestButtonPropertyGeneration(t *testing.T) {
 	b = wui.NewButton()
 	b.SetAnchors(wui.AnchorCenter, wui.AnchorMaxAndCenter)
 	checkProperties(`b.SetAnchors(wui.AnchorCenter, wui.AnchorMaxAndCenter)`)
+
+	b = wui.NewButton()
+	b.SetToolTip("Saves the file\nCtrl+S")
+	checkProperties(`b.SetToolTip("Saves the file\nCtrl+S")`)
 }
 
 func TestCheckBoxPropertyGeneration(t *testing.T) {
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/gonutz/wui/v2"
+)
+
+// command is a reversible change to the designed window.
+type command interface {
+	do()
+	undo()
+}
+
+// history keeps the commands that were executed so they can be undone and
+// redone.
+type history struct {
+	done   []command
+	undone []command
+}
+
+// do executes the command and records it.
+func (h *history) do(c command) {
+	c.do()
+	h.record(c)
+}
+
+// record adds a command that was already executed to the history. Recording a
+// new command clears the redo list.
+func (h *history) record(c command) {
+	h.undone = nil
+	if len(h.done) > 0 {
+		if m, ok := h.done[len(h.done)-1].(merger); ok && m.merge(c) {
+			return
+		}
+	}
+	h.done = append(h.done, c)
+}
+
+// undo reverts the last command. It returns false if there is nothing to undo.
+func (h *history) undo() bool {
+	if len(h.done) == 0 {
+		return false
+	}
+	c := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	c.undo()
+	h.undone = append(h.undone, c)
+	return true
+}
+
+// redo executes the last undone command again. It returns false if there is
+// nothing to redo.
+func (h *history) redo() bool {
+	if len(h.undone) == 0 {
+		return false
+	}
+	c := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	c.do()
+	h.done = append(h.done, c)
+	return true
+}
+
+// clear forgets all commands, e.g. after loading a new window.
+func (h *history) clear() {
+	h.done = nil
+	h.undone = nil
+}
+
+// merger is implemented by commands that can combine with the command that
+// follows them. This way typing a text creates one step in the history instead
+// of one per character.
+type merger interface {
+	merge(next command) bool
+}
+
+// addControl places a new control in a container.
+type addControl struct {
+	parent wui.Container
+	child  wui.Control
+	index  int
+}
+
+func newAddControl(parent wui.Container, child wui.Control) *addControl {
+	return &addControl{
+		parent: parent,
+		child:  child,
+		index:  len(parent.Children()),
+	}
+}
+
+func (c *addControl) do() {
+	insertChild(c.parent, c.child, c.index)
+}
+
+func (c *addControl) undo() {
+	c.parent.Remove(c.child)
+}
+
+// removeControl deletes a control from its container.
+type removeControl struct {
+	parent wui.Container
+	child  wui.Control
+	index  int
+}
+
+func newRemoveControl(parent wui.Container, child wui.Control) *removeControl {
+	index := 0
+	for i, c := range parent.Children() {
+		if c == child {
+			index = i
+		}
+	}
+	return &removeControl{
+		parent: parent,
+		child:  child,
+		index:  index,
+	}
+}
+
+func (c *removeControl) do() {
+	c.parent.Remove(c.child)
+}
+
+func (c *removeControl) undo() {
+	insertChild(c.parent, c.child, c.index)
+}
+
+// insertChild adds the child to the parent so that it ends up at the given
+// index in the parent's children. The order of the children is the order of
+// the generated code.
+func insertChild(parent wui.Container, child wui.Control, index int) {
+	children := parent.Children()
+	if index > len(children) {
+		index = len(children)
+	}
+	after := append([]wui.Control{}, children[index:]...)
+	for _, c := range after {
+		parent.Remove(c)
+	}
+	parent.Add(child)
+	for _, c := range after {
+		parent.Add(c)
+	}
+}
+
+// changeControl restores the properties of a control. Some properties
+// influence others, e.g. setting a Slider's Min can change its cursor
+// position, so we always keep the whole state of the control.
+type changeControl struct {
+	control       interface{}
+	before, after controlState
+	// typing is the name of the property that the user is typing into. These
+	// changes are merged into one.
+	typing string
+}
+
+// newChangeControl returns nil if before and after are the same.
+func newChangeControl(control interface{}, before, after controlState) *changeControl {
+	if before.equals(after) {
+		return nil
+	}
+	return &changeControl{
+		control: control,
+		before:  before,
+		after:   after,
+	}
+}
+
+func (c *changeControl) do() {
+	c.after.restore(c.control)
+}
+
+func (c *changeControl) undo() {
+	c.before.restore(c.control)
+}
+
+func (c *changeControl) merge(next command) bool {
+	n, ok := next.(*changeControl)
+	if ok && c.typing != "" && c.typing == n.typing && c.control == n.control {
+		c.after = n.after
+		return true
+	}
+	return false
+}
+
+// controlState is a snapshot of all properties of a control that the designer
+// can edit.
+type controlState struct {
+	values [][]reflect.Value
+	font   *wui.Font
+}
+
+func stateOf(control interface{}) controlState {
+	var s controlState
+	v := reflect.ValueOf(control)
+	for _, p := range editableProperties(control) {
+		s.values = append(s.values, v.MethodByName(p).Call(nil))
+	}
+	if f, ok := control.(fonter); ok {
+		s.font = f.Font()
+	}
+	return s
+}
+
+func (s controlState) restore(control interface{}) {
+	v := reflect.ValueOf(control)
+	for i, p := range editableProperties(control) {
+		v.MethodByName("Set" + p).Call(s.values[i])
+	}
+	if f, ok := control.(fonter); ok {
+		f.SetFont(s.font)
+	}
+}
+
+func (s controlState) equals(other controlState) bool {
+	if len(s.values) != len(other.values) || !sameFont(s.font, other.font) {
+		return false
+	}
+	for i := range s.values {
+		if !equal(s.values[i], other.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameFont compares the font descriptions, the designer creates a new font for
+// every change in the font properties.
+func sameFont(a, b *wui.Font) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Desc == b.Desc
+}
+
+// editableProperties returns the names of the properties of the control,
+// leaving out those that only combine other properties, e.g. Bounds.
+func editableProperties(control interface{}) []string {
+	var names []string
+	for _, p := range propertiesOf(control) {
+		if len(p.combines) == 0 {
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
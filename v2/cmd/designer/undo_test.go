@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/wui/v2"
+)
+
+func TestUndoAndRedoAddingControls(t *testing.T) {
+	var h history
+	w := wui.NewWindow()
+	a := wui.NewButton()
+	b := wui.NewButton()
+
+	h.do(newAddControl(w, a))
+	h.do(newAddControl(w, b))
+	check.Eq(t, w.Children(), []wui.Control{a, b})
+
+	check.Eq(t, h.undo(), true)
+	check.Eq(t, w.Children(), []wui.Control{a})
+	check.Eq(t, h.undo(), true)
+	check.Eq(t, w.Children(), []wui.Control{})
+	check.Eq(t, h.undo(), false)
+
+	check.Eq(t, h.redo(), true)
+	check.Eq(t, h.redo(), true)
+	check.Eq(t, w.Children(), []wui.Control{a, b})
+	check.Eq(t, h.redo(), false)
+}
+
+func TestUndoingRemoveKeepsChildOrder(t *testing.T) {
+	var h history
+	p := wui.NewPanel()
+	a, b, c := wui.NewButton(), wui.NewLabel(), wui.NewCheckBox()
+	p.Add(a)
+	p.Add(b)
+	p.Add(c)
+
+	h.do(newRemoveControl(p, b))
+	check.Eq(t, p.Children(), []wui.Control{a, c})
+	check.Eq(t, b.Parent(), nil)
+
+	h.undo()
+	check.Eq(t, p.Children(), []wui.Control{a, b, c})
+	check.Eq(t, b.Parent(), p)
+}
+
+func TestRecordingClearsRedo(t *testing.T) {
+	var h history
+	w := wui.NewWindow()
+	h.do(newAddControl(w, wui.NewButton()))
+	h.undo()
+	h.do(newAddControl(w, wui.NewLabel()))
+	check.Eq(t, h.redo(), false)
+}
+
+func TestUndoPropertyChange(t *testing.T) {
+	var h history
+	b := wui.NewButton()
+	b.SetBounds(10, 20, 30, 40)
+
+	before := stateOf(b)
+	b.SetText("changed")
+	b.SetX(100)
+	h.record(newChangeControl(b, before, stateOf(b)))
+
+	h.undo()
+	check.Eq(t, b.Text(), "")
+	check.Eq(t, b.X(), 10)
+	h.redo()
+	check.Eq(t, b.Text(), "changed")
+	check.Eq(t, b.X(), 100)
+}
+
+func TestUnchangedStateCreatesNoCommand(t *testing.T) {
+	b := wui.NewButton()
+	check.Eq(t, newChangeControl(b, stateOf(b), stateOf(b)) == nil, true)
+
+	f1, _ := wui.NewFont(wui.FontDesc{Name: "Tahoma"})
+	f2, _ := wui.NewFont(wui.FontDesc{Name: "Tahoma"})
+	b.SetFont(f1)
+	before := stateOf(b)
+	b.SetFont(f2)
+	check.Eq(t, newChangeControl(b, before, stateOf(b)) == nil, true)
+}
+
+func TestUndoRestoresDependentProperties(t *testing.T) {
+	var h history
+	s := wui.NewSlider()
+	s.SetMinMax(0, 100)
+	s.SetCursorPosition(80)
+
+	// Lowering the max clamps the cursor, undo must bring both back.
+	before := stateOf(s)
+	s.SetMax(50)
+	s.SetCursorPosition(50)
+	h.record(newChangeControl(s, before, stateOf(s)))
+
+	h.undo()
+	check.Eq(t, s.Max(), 100)
+	check.Eq(t, s.CursorPosition(), 80)
+}
+
+func TestTypingIsMergedIntoOneStep(t *testing.T) {
+	var h history
+	l := wui.NewLabel()
+	typeText := func(text string) {
+		before := stateOf(l)
+		l.SetText(text)
+		c := newChangeControl(l, before, stateOf(l))
+		c.typing = "SetText"
+		h.record(c)
+	}
+	typeText("a")
+	typeText("ab")
+	typeText("abc")
+
+	before := stateOf(l)
+	l.SetX(5)
+	h.record(newChangeControl(l, before, stateOf(l)))
+
+	h.undo()
+	check.Eq(t, l.Text(), "abc")
+	check.Eq(t, l.X(), 0)
+	h.undo()
+	check.Eq(t, l.Text(), "")
+	check.Eq(t, h.undo(), false)
+}
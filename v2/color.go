@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 // Color is a 24 bit color in BGR form. The alpha channel is always 0 and has no
 // relevance.
@@ -26,138 +26,138 @@ func RGB(r, g, b uint8) Color {
 // These are predefined colors defined by the current Windows theme.
 var (
 	// Scroll bar gray area.
-	ColorScrollBar = sysColor(w32.COLOR_SCROLLBAR)
+	ColorScrollBar = sysColor(win.COLOR_SCROLLBAR)
 
 	// Desktop.
-	ColorBackground = sysColor(w32.COLOR_BACKGROUND)
+	ColorBackground = sysColor(win.COLOR_BACKGROUND)
 
 	// Desktop.
-	ColorDesktop = sysColor(w32.COLOR_DESKTOP)
+	ColorDesktop = sysColor(win.COLOR_DESKTOP)
 
 	// Active window title bar. The associated foreground color is
 	// COLOR_CAPTIONTEXT. Specifies the left side color in the color gradient of
 	// an active window's title bar if the gradient effect is enabled.
-	ColorActiveCaption = sysColor(w32.COLOR_ACTIVECAPTION)
+	ColorActiveCaption = sysColor(win.COLOR_ACTIVECAPTION)
 
 	// Inactive window caption. The associated foreground color is
 	// COLOR_INACTIVECAPTIONTEXT. Specifies the left side color in the color
 	// gradient of an inactive window's title bar if the gradient effect is
 	// enabled.
-	ColorInactiveCaption = sysColor(w32.COLOR_INACTIVECAPTION)
+	ColorInactiveCaption = sysColor(win.COLOR_INACTIVECAPTION)
 
 	// Menu background. The associated foreground color is COLOR_MENUTEXT.
-	ColorMenu = sysColor(w32.COLOR_MENU)
+	ColorMenu = sysColor(win.COLOR_MENU)
 
 	// Window background. The associated foreground colors are COLOR_WINDOWTEXT
 	// and COLOR_HOTLITE.
-	ColorWindow = sysColor(w32.COLOR_WINDOW)
+	ColorWindow = sysColor(win.COLOR_WINDOW)
 
 	// Window frame.
-	ColorWindowFrame = sysColor(w32.COLOR_WINDOWFRAME)
+	ColorWindowFrame = sysColor(win.COLOR_WINDOWFRAME)
 
 	// Text in menus. The associated background color is COLOR_MENU.
-	ColorMenuText = sysColor(w32.COLOR_MENUTEXT)
+	ColorMenuText = sysColor(win.COLOR_MENUTEXT)
 
 	// Text in windows. The associated background color is COLOR_WINDOW.
-	ColorWindowText = sysColor(w32.COLOR_WINDOWTEXT)
+	ColorWindowText = sysColor(win.COLOR_WINDOWTEXT)
 
 	// Text in caption, size box, and scroll bar arrow box. The associated
 	// background color is COLOR_ACTIVECAPTION.
-	ColorCaptionText = sysColor(w32.COLOR_CAPTIONTEXT)
+	ColorCaptionText = sysColor(win.COLOR_CAPTIONTEXT)
 
 	// Active window border.
-	ColorActiveBorder = sysColor(w32.COLOR_ACTIVEBORDER)
+	ColorActiveBorder = sysColor(win.COLOR_ACTIVEBORDER)
 
 	// Inactive window border.
-	ColorInactiveBorder = sysColor(w32.COLOR_INACTIVEBORDER)
+	ColorInactiveBorder = sysColor(win.COLOR_INACTIVEBORDER)
 
 	// Background color of multiple document interface (MDI) applications.
-	ColorAppWorkspace = sysColor(w32.COLOR_APPWORKSPACE)
+	ColorAppWorkspace = sysColor(win.COLOR_APPWORKSPACE)
 
 	// Item(s) selected in a control. The associated foreground color is
 	// COLOR_HIGHLIGHTTEXT.
-	ColorHighlight = sysColor(w32.COLOR_HIGHLIGHT)
+	ColorHighlight = sysColor(win.COLOR_HIGHLIGHT)
 
 	// Text of item(s) selected in a control. The associated background color is
 	// COLOR_HIGHLIGHT.
-	ColorHighlightText = sysColor(w32.COLOR_HIGHLIGHTTEXT)
+	ColorHighlightText = sysColor(win.COLOR_HIGHLIGHTTEXT)
 
 	// Face color for three-dimensional display elements and for dialog box
 	// backgrounds.
-	Color3DFace = sysColor(w32.COLOR_3DFACE)
+	Color3DFace = sysColor(win.COLOR_3DFACE)
 
 	// Face color for three-dimensional display elements and for dialog box
 	// backgrounds. The associated foreground color is COLOR_BTNTEXT.
-	ColorButtonFace = sysColor(w32.COLOR_BTNFACE)
+	ColorButtonFace = sysColor(win.COLOR_BTNFACE)
 
 	// Shadow color for three-dimensional display elements (for edges facing
 	// away from the light source).
-	Color3DShadow = sysColor(w32.COLOR_3DSHADOW)
+	Color3DShadow = sysColor(win.COLOR_3DSHADOW)
 
 	// Shadow color for three-dimensional display elements (for edges facing
 	// away from the light source).
-	ColorButtonShadow = sysColor(w32.COLOR_BTNSHADOW)
+	ColorButtonShadow = sysColor(win.COLOR_BTNSHADOW)
 
 	// Grayed (disabled) text. This color is set to 0 if the current display
 	// driver does not support a solid gray color.
-	ColorGrayText = sysColor(w32.COLOR_GRAYTEXT)
+	ColorGrayText = sysColor(win.COLOR_GRAYTEXT)
 
 	// Text on push buttons. The associated background color is COLOR_BTNFACE.
-	ColorButtonText = sysColor(w32.COLOR_BTNTEXT)
+	ColorButtonText = sysColor(win.COLOR_BTNTEXT)
 
 	// Color of text in an inactive caption. The associated background color is
 	// COLOR_INACTIVECAPTION.
-	ColorInactiveCaptionText = sysColor(w32.COLOR_INACTIVECAPTIONTEXT)
+	ColorInactiveCaptionText = sysColor(win.COLOR_INACTIVECAPTIONTEXT)
 
 	// Highlight color for three-dimensional display elements (for edges facing
 	// the light source.)
-	Color3DHighlight = sysColor(w32.COLOR_3DHIGHLIGHT)
+	Color3DHighlight = sysColor(win.COLOR_3DHIGHLIGHT)
 
 	// Highlight color for three-dimensional display elements (for edges facing
 	// the light source.)
-	ColorButtonHighlight = sysColor(w32.COLOR_BTNHIGHLIGHT)
+	ColorButtonHighlight = sysColor(win.COLOR_BTNHIGHLIGHT)
 
 	// Dark shadow for three-dimensional display elements.
-	Color3DDarkShadow = sysColor(w32.COLOR_3DDKSHADOW)
+	Color3DDarkShadow = sysColor(win.COLOR_3DDKSHADOW)
 
 	// Light color for three-dimensional display elements (for edges facing the
 	// light source.)
-	Color3DLight = sysColor(w32.COLOR_3DLIGHT)
+	Color3DLight = sysColor(win.COLOR_3DLIGHT)
 
 	// Text color for tooltip controls. The associated background color is
 	// COLOR_INFOBK.
-	ColorInfoText = sysColor(w32.COLOR_INFOTEXT)
+	ColorInfoText = sysColor(win.COLOR_INFOTEXT)
 
 	// Background color for tooltip controls. The associated foreground color is
 	// COLOR_INFOTEXT.
-	ColorInfoBackground = sysColor(w32.COLOR_INFOBK)
+	ColorInfoBackground = sysColor(win.COLOR_INFOBK)
 
 	// Color for a hyperlink or hot-tracked item. The associated background
 	// color is COLOR_WINDOW.
-	ColorHotlight = sysColor(w32.COLOR_HOTLIGHT)
+	ColorHotlight = sysColor(win.COLOR_HOTLIGHT)
 
 	// Right side color in the color gradient of an active window's title bar.
 	// COLOR_ACTIVECAPTION specifies the left side color. Use
 	// SPI_GETGRADIENTCAPTIONS with the SystemParametersInfo function to
 	// determine whether the gradient effect is enabled.
-	ColorGradientActiveCaption = sysColor(w32.COLOR_GRADIENTACTIVECAPTION)
+	ColorGradientActiveCaption = sysColor(win.COLOR_GRADIENTACTIVECAPTION)
 
 	// Right side color in the color gradient of an inactive window's title bar.
 	// COLOR_INACTIVECAPTION specifies the left side color.
-	ColorGradientInactiveCaption = sysColor(w32.COLOR_GRADIENTINACTIVECAPTION)
+	ColorGradientInactiveCaption = sysColor(win.COLOR_GRADIENTINACTIVECAPTION)
 
 	// The color used to highlight menu items when the menu appears as a flat
 	// menu (see SystemParametersInfo). The highlighted menu item is outlined
 	// with COLOR_HIGHLIGHT. Windows 2000: This value is not supported.
-	ColorMenuHighlight = sysColor(w32.COLOR_MENUHILIGHT)
+	ColorMenuHighlight = sysColor(win.COLOR_MENUHILIGHT)
 
 	// The background color for the menu bar when menus appear as flat menus
 	// (see SystemParametersInfo). However, COLOR_MENU continues to specify the
 	// background color of the menu popup. Windows 2000: This value is not
 	// supported.
-	ColorMenuBar = sysColor(w32.COLOR_MENUBAR)
+	ColorMenuBar = sysColor(win.COLOR_MENUBAR)
 )
 
 func sysColor(index int) Color {
-	return Color(w32.GetSysColor(index))
+	return ui.sysColor(index)
 }
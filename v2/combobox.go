@@ -1,11 +1,6 @@
 package wui
 
-import (
-	"syscall"
-	"unsafe"
-
-	"github.com/gonutz/w32/v2"
-)
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewComboBox() *ComboBox {
 	return &ComboBox{selected: -1}
@@ -15,7 +10,7 @@ type ComboBox struct {
 	textControl
 	items    []string
 	selected int
-	onChange func(newIndex int)
+	onChange IntEvent
 }
 
 var _ Control = (*ComboBox)(nil)
@@ -29,11 +24,15 @@ func (*ComboBox) canFocus() bool {
 }
 
 func (c *ComboBox) OnTabFocus() func() {
-	return c.onTabFocus
+	return c.onTabFocus.primary
 }
 
 func (c *ComboBox) SetOnTabFocus(f func()) {
-	c.onTabFocus = f
+	c.onTabFocus.primary = f
+}
+
+func (c *ComboBox) TabFocusEvent() *Event {
+	return &c.onTabFocus
 }
 
 func (*ComboBox) eatsTabs() bool {
@@ -43,9 +42,9 @@ func (*ComboBox) eatsTabs() bool {
 func (e *ComboBox) create(id int) {
 	e.textControl.create(
 		id,
-		w32.WS_EX_CLIENTEDGE,
+		win.WS_EX_CLIENTEDGE,
 		"COMBOBOX",
-		w32.WS_TABSTOP|w32.CBS_DROPDOWNLIST,
+		win.WS_TABSTOP|win.CBS_DROPDOWNLIST,
 	)
 	for _, s := range e.items {
 		e.addItem(s)
@@ -61,14 +60,13 @@ func (e *ComboBox) AddItem(s string) {
 }
 
 func (e *ComboBox) addItem(s string) {
-	ptr, _ := syscall.UTF16PtrFromString(s)
-	w32.SendMessage(e.handle, w32.CB_ADDSTRING, 0, uintptr(unsafe.Pointer(ptr)))
+	ui.addItem(e.handle, s)
 }
 
 func (e *ComboBox) Clear() {
 	e.items = nil
 	if e.handle != 0 {
-		w32.SendMessage(e.handle, w32.CB_RESETCONTENT, 0, 0)
+		ui.clearItems(e.handle)
 	}
 }
 
@@ -79,7 +77,7 @@ func (e *ComboBox) Items() []string {
 func (e *ComboBox) SetItems(items []string) {
 	e.items = items
 	if e.handle != 0 {
-		w32.SendMessage(e.handle, w32.CB_RESETCONTENT, 0, 0)
+		ui.clearItems(e.handle)
 		for _, s := range e.items {
 			e.addItem(s)
 		}
@@ -88,7 +86,7 @@ func (e *ComboBox) SetItems(items []string) {
 
 func (e *ComboBox) SelectedIndex() int {
 	if e.handle != 0 {
-		e.selected = int(w32.SendMessage(e.handle, w32.CB_GETCURSEL, 0, 0))
+		e.selected = ui.selectedItem(e.handle)
 	}
 	return e.selected
 }
@@ -105,16 +103,24 @@ func (e *ComboBox) SetSelectedIndex(i int) {
 	}
 	e.selected = i
 	if e.handle != 0 {
-		w32.SendMessage(e.handle, w32.CB_SETCURSEL, uintptr(i), 0)
+		ui.selectItem(e.handle, i)
 	}
 }
 
+func (e *ComboBox) OnChange() func(newIndex int) {
+	return e.onChange.primary
+}
+
 func (e *ComboBox) SetOnChange(f func(newIndex int)) {
-	e.onChange = f
+	e.onChange.primary = f
+}
+
+func (e *ComboBox) ChangeEvent() *IntEvent {
+	return &e.onChange
 }
 
 func (e *ComboBox) handleNotification(cmd uintptr) {
-	if cmd == w32.CBN_SELCHANGE && e.onChange != nil {
-		e.onChange(e.SelectedIndex())
+	if cmd == win.CBN_SELCHANGE && !e.onChange.empty() {
+		e.onChange.fire(e.SelectedIndex())
 	}
 }
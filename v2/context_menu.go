@@ -0,0 +1,65 @@
+package wui
+
+// Popup shows the menu at x, y in the window's client area and calls OnClick
+// of the item that the user clicks. It returns when the menu is closed. The
+// menu's name is not shown, only its items. Nothing happens if the window is
+// not shown.
+//
+// x and y are in pixels, not logical units, like the positions in mouse events.
+// This way you can pass on the mouse position of a click. To place the menu
+// relative to a control, use Scale(x, w.DPI()).
+func (m *Menu) Popup(w *Window, x, y int) {
+	if w.handle == 0 {
+		return
+	}
+	left, top, _, _ := ui.clientBounds(w.handle)
+	m.popup(w, left+x, top+y)
+}
+
+func (c *control) ContextMenu() *Menu {
+	return c.contextMenu
+}
+
+// SetContextMenu sets the menu that pops up when the user right-clicks the
+// control, or presses the menu key or Shift+F10 while it has the focus. Use
+// NewMenu to create it, its name is not shown. Without a context menu, the
+// control's parent shows its context menu, if it has one.
+func (c *control) SetContextMenu(m *Menu) {
+	c.contextMenu = m
+}
+
+// contextMenuRequested is called by the backend when the user asks for the
+// control's context menu. x and y are screen coordinates. It returns false if
+// the control has no context menu so the request goes to its parent.
+func (c *control) contextMenuRequested(x, y int) bool {
+	if c.contextMenu == nil {
+		return false
+	}
+	if w := windowOf(c.parent); w != nil && w.handle != 0 {
+		c.contextMenu.popup(w, x, y)
+	}
+	return true
+}
+
+func (w *Window) ContextMenu() *Menu {
+	return w.contextMenu
+}
+
+// SetContextMenu sets the menu that pops up when the user right-clicks the
+// window's client area, or presses the menu key or Shift+F10. This includes
+// clicks on controls that have no context menu of their own. Use NewMenu to
+// create it, its name is not shown.
+func (w *Window) SetContextMenu(m *Menu) {
+	w.contextMenu = m
+}
+
+// contextMenuRequested is called by the backend when the user asks for the
+// window's context menu. x and y are screen coordinates. It returns false if
+// the window has no context menu.
+func (w *Window) contextMenuRequested(x, y int) bool {
+	if w.contextMenu == nil {
+		return false
+	}
+	w.contextMenu.popup(w, x, y)
+	return true
+}
@@ -0,0 +1,141 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestContextMenuGoesToClosestParentWithMenu(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	panel := NewPanel()
+	panel.SetBounds(10, 20, 200, 100)
+	w.Add(panel)
+	withMenu := NewButton()
+	withMenu.SetBounds(5, 6, 50, 25)
+	panel.Add(withMenu)
+	withoutMenu := NewButton()
+	withoutMenu.SetBounds(60, 6, 50, 25)
+	panel.Add(withoutMenu)
+
+	var clicked []string
+	item := func(text string) *MenuString {
+		return NewMenuString(text).SetOnClick(func() {
+			clicked = append(clicked, text)
+		})
+	}
+	withMenu.SetContextMenu(NewMenu("").Add(item("Button")))
+	w.SetContextMenu(NewMenu("").Add(item("Window")))
+	check.Eq(t, panel.ContextMenu() == nil, true)
+	check.Eq(t, w.ContextMenu() != nil, true)
+
+	var shown [][]string
+	h.SetPopupMenuChoice(func(items []string) string {
+		shown = append(shown, items)
+		return items[0]
+	})
+
+	w.SetOnShow(func() {
+		left, top, _, _ := h.clientBounds(w.Handle())
+
+		h.OpenContextMenu(withMenu, 1, 2)
+		check.Eq(t, h.popupX, left+10+5+1)
+		check.Eq(t, h.popupY, top+20+6+2)
+
+		h.OpenContextMenu(withoutMenu, 3, 4)
+		check.Eq(t, h.popupX, left+10+60+3)
+		check.Eq(t, h.popupY, top+20+6+4)
+
+		h.PressContextMenuKey(withMenu)
+		check.Eq(t, h.popupX, left+10+5)
+		check.Eq(t, h.popupY, top+20+6)
+
+		h.OpenContextMenu(w, 7, 8)
+		check.Eq(t, h.popupX, left+7)
+		check.Eq(t, h.popupY, top+8)
+
+		w.SetContextMenu(nil)
+		h.OpenContextMenu(withoutMenu, 0, 0)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, shown, [][]string{{"Button"}, {"Window"}, {"Button"}, {"Window"}})
+	check.Eq(t, clicked, []string{"Button", "Window", "Button", "Window"})
+}
+
+func TestContextMenuOfDisabledControlDoesNotOpen(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	b := NewButton()
+	b.SetEnabled(false)
+	b.SetContextMenu(NewMenu("").Add(NewMenuString("Item")))
+	w.Add(b)
+	opened := false
+	h.SetPopupMenuChoice(func([]string) string {
+		opened = true
+		return ""
+	})
+	w.SetOnShow(func() {
+		h.OpenContextMenu(b, 0, 0)
+		w.Close()
+	})
+	w.Show()
+	check.Eq(t, opened, false)
+}
+
+func TestMenuStringsKeepTheirStateInPopups(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	wrap := NewMenuString("Wrap lines")
+	wrap.SetOnClick(func() {
+		wrap.SetChecked(!wrap.Checked())
+		if wrap.Checked() {
+			wrap.SetText("Unwrap lines")
+		} else {
+			wrap.SetText("Wrap lines")
+		}
+	})
+	// The same MenuString may be in the menu bar and in a popup.
+	w.SetMenu(NewMainMenu().Add(NewMenu("View").Add(wrap)))
+	popup := NewMenu("").Add(wrap)
+
+	var shown []string
+	var checked []bool
+	h.SetPopupMenuChoice(func(items []string) string {
+		shown = append(shown, items...)
+		checked = append(checked, h.menus[w.popupMenu.handle].items[0].checked)
+		return items[0]
+	})
+
+	w.SetOnShow(func() {
+		popup.Popup(w, 10, 10)
+		check.Eq(t, wrap.Checked(), true)
+		check.Eq(t, menuBarItem(h, wrap).checked, true)
+		check.Eq(t, menuBarItem(h, wrap).text, "Unwrap lines")
+
+		popup.Popup(w, 10, 10)
+		check.Eq(t, wrap.Checked(), false)
+
+		h.ClickMenu(wrap)
+		check.Eq(t, wrap.Checked(), true)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, shown, []string{"Wrap lines", "Unwrap lines"})
+	check.Eq(t, checked, []bool{false, true})
+}
+
+func TestPopupDoesNothingBeforeWindowIsShown(t *testing.T) {
+	h := UseHeadless()
+	opened := false
+	h.SetPopupMenuChoice(func([]string) string {
+		opened = true
+		return ""
+	})
+	NewMenu("").Add(NewMenuString("Item")).Popup(NewWindow(), 0, 0)
+	check.Eq(t, opened, false)
+}
@@ -1,12 +1,10 @@
 package wui
 
 import (
-	"syscall"
 	"unicode"
 	"unicode/utf8"
-	"unsafe"
 
-	"github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2/internal/win"
 )
 
 // Anchor defines how a child control is resized when its parent changes size.
@@ -67,18 +65,25 @@ func (a Anchor) String() string {
 }
 
 type control struct {
-	handle     w32.HWND
-	x          int
-	y          int
-	width      int
-	height     int
-	hAnchor    Anchor
-	vAnchor    Anchor
-	parent     Container
-	disabled   bool
-	hidden     bool
-	onResize   func()
-	onTabFocus func()
+	handle      uintptr
+	x           int
+	y           int
+	width       int
+	height      int
+	hAnchor     Anchor
+	vAnchor     Anchor
+	parent      Container
+	disabled    bool
+	hidden      bool
+	onResize    Event
+	onTabFocus  Event
+	tabIndex    int
+	noTabStop   bool
+	toolTip     string
+	toolTipFunc func() string
+	contextMenu *Menu
+	controlEvents
+	dropHandlers
 }
 
 // closing defaults to nothing, the base control has no properties that are
@@ -87,13 +92,18 @@ func (c *control) closing() {}
 
 func (c *control) destroy() {
 	if c.handle != 0 {
-		w32.DestroyWindow(c.handle)
+		if c.hasToolTip() {
+			if w := windowOf(c.parent); w != nil {
+				w.setToolTipFor(c.handle, false)
+			}
+		}
+		ui.destroy(c.handle)
 		c.handle = 0
 	}
 }
 
 func (c *control) Handle() uintptr {
-	return uintptr(c.handle)
+	return c.handle
 }
 
 func (c *control) Parent() Container {
@@ -107,29 +117,55 @@ func (c *control) setParent(parent Container) {
 func (c *control) create(id int, exStyle uint, className string, style uint) {
 	var visible uint
 	if !c.hidden {
-		visible = w32.WS_VISIBLE
+		visible = win.WS_VISIBLE
 	}
-	c.handle = w32.CreateWindowExStr(
+	x, y, width, height := c.pixelBounds()
+	c.handle = ui.createChild(
+		c.parent.getHandle(),
+		id,
 		exStyle,
 		className,
-		"",
-		visible|w32.WS_CHILD|style,
-		c.x, c.y, c.width, c.height,
-		c.parent.getHandle(), w32.HMENU(id), c.parent.getInstance(), nil,
+		visible|win.WS_CHILD|style,
+		x, y, width, height,
 	)
 	if c.disabled {
-		w32.EnableWindow(c.handle, false)
+		ui.setEnabled(c.handle, false)
+	}
+	hooks := c.controlEvents.hooks()
+	// Controls that are transparent to the mouse need it for their tooltip.
+	hooks.wantsMouse = func() bool {
+		return c.controlEvents.wantsMouse() || c.hasToolTip()
 	}
+	hooks.contextMenu = c.contextMenuRequested
+	ui.hookControl(c.handle, hooks)
+	c.updateToolTip()
 }
 
 func (c *control) parentFontChanged() {}
 
+// dpi returns the DPI of the window that the control is on.
+func (c *control) dpi() int {
+	if c.parent == nil {
+		return DefaultDPI
+	}
+	return c.parent.getDPI()
+}
+
+// pixelBounds returns the control's bounds scaled to its window's DPI.
+func (c *control) pixelBounds() (x, y, width, height int) {
+	return scaleBounds(c.x, c.y, c.width, c.height, c.dpi())
+}
+
 func (c *control) SetOnResize(f func()) {
-	c.onResize = f
+	c.onResize.primary = f
+}
+
+func (c *control) ResizeEvent() *Event {
+	return &c.onResize
 }
 
 func (c *control) OnResize() func() {
-	return c.onResize
+	return c.onResize.primary
 }
 
 func (c *control) X() int {
@@ -191,14 +227,11 @@ func (c *control) SetBounds(x, y, width, height int) {
 	}
 	c.x, c.y, c.width, c.height = x, y, width, height
 	if c.handle != 0 {
-		w32.SetWindowPos(
-			c.handle, 0,
-			c.x, c.y, c.width, c.height,
-			w32.SWP_NOOWNERZORDER|w32.SWP_NOZORDER,
-		)
+		x, y, width, height := c.pixelBounds()
+		ui.setBounds(c.handle, x, y, width, height)
 	}
-	if resize && c.onResize != nil {
-		c.onResize()
+	if resize {
+		c.onResize.fire()
 	}
 }
 
@@ -234,7 +267,7 @@ func (c *control) Enabled() bool {
 func (c *control) SetEnabled(e bool) {
 	c.disabled = !e
 	if c.handle != 0 {
-		w32.EnableWindow(c.handle, e)
+		ui.setEnabled(c.handle, e)
 	}
 }
 
@@ -243,23 +276,21 @@ func (c *control) Visible() bool {
 }
 
 func (c *control) SetVisible(v bool) {
+	changed := c.hidden == v
 	c.hidden = !v
 	if c.handle != 0 {
-		if v {
-			w32.ShowWindow(c.handle, w32.SW_SHOW)
-		} else {
-			w32.ShowWindow(c.handle, w32.SW_HIDE)
-		}
-		w32.InvalidateRect(c.handle, nil, true)
+		ui.setVisible(c.handle, v)
+	}
+	// Layouts leave out invisible controls.
+	if changed && c.parent != nil {
+		relayout(c.parent)
 	}
 }
 
 func (c *control) handleNotification(cmd uintptr) {}
 
 func (c *control) wasFocussedWithTab() {
-	if c.onTabFocus != nil {
-		c.onTabFocus()
-	}
+	c.onTabFocus.fire()
 }
 
 type textControl struct {
@@ -270,7 +301,7 @@ type textControl struct {
 
 func (c *textControl) create(id int, exStyle uint, className string, style uint) {
 	c.control.create(id, exStyle, className, style)
-	w32.SetWindowText(c.handle, c.text)
+	ui.setText(c.handle, c.text)
 	c.SetFont(c.font)
 }
 
@@ -302,7 +333,7 @@ func deleteWordBeforeCursor(text []rune, cursor int) (newText string, newCursor
 
 func (c *textControl) Text() string {
 	if c.handle != 0 {
-		c.text = w32.GetWindowText(c.handle)
+		c.text = ui.text(c.handle)
 	}
 	return c.text
 }
@@ -310,7 +341,7 @@ func (c *textControl) Text() string {
 func (c *textControl) SetText(text string) {
 	c.text = text
 	if c.handle != 0 {
-		w32.SetWindowText(c.handle, text)
+		ui.setText(c.handle, text)
 	}
 }
 
@@ -323,20 +354,24 @@ func (c *textControl) parentFontChanged() {
 }
 
 func (c *textControl) SetFont(font *Font) {
+	old := c.font
 	c.font = font
 	if c.handle != 0 {
-		w32.SendMessage(c.handle, w32.WM_SETFONT, uintptr(c.fontHandle()), 1)
+		ui.setFont(c.handle, c.fontHandle())
+	}
+	if old != font {
+		releaseScaledFonts(old)
 	}
 }
 
-func (c *textControl) fontHandle() w32.HFONT {
+func (c *textControl) fontHandle() uintptr {
 	if c.font != nil {
-		return c.font.handle
+		return c.font.handleFor(c.dpi())
 	}
 	if c.parent != nil {
 		font := c.parent.Font()
 		if font != nil {
-			return font.handle
+			return font.handleFor(c.dpi())
 		}
 	}
 	return 0
@@ -345,18 +380,19 @@ func (c *textControl) fontHandle() w32.HFONT {
 func (c *textControl) Focus() {
 	// TODO Allow this before showing a window.
 	if c.handle != 0 {
-		w32.SetFocus(c.handle)
+		ui.focus(c.handle)
 	}
 }
 
 func (c *textControl) HasFocus() bool {
-	return c.handle != 0 && w32.GetFocus() == c.handle
+	return c.handle != 0 && ui.focused() == c.handle
 }
 
 type textEditControl struct {
 	textControl
 	cursorStart int
 	cursorEnd   int
+	errorText   string
 }
 
 func (c *textEditControl) create(id int, exStyle uint, className string, style uint) {
@@ -364,68 +400,74 @@ func (c *textEditControl) create(id int, exStyle uint, className string, style u
 	if c.cursorStart != 0 || c.cursorEnd != 0 {
 		c.setCursor(c.cursorStart, c.cursorEnd)
 	}
-	w32.SetWindowSubclass(c.handle, textEditSubclassProc, 0, uintptr(unsafe.Pointer(c)))
-}
-
-var textEditSubclassProc = syscall.NewCallback(func(
-	window w32.HWND,
-	msg uint32,
-	wParam, lParam uintptr,
-	subclassID uintptr,
-	refData uintptr,
-) uintptr {
-	c := (*textEditControl)(unsafe.Pointer(refData))
-	switch msg {
-	case w32.WM_CHAR:
-		shift := w32.GetKeyState(w32.VK_SHIFT)&0x8000 != 0
-		if wParam == 1 {
-			// Ctrl+A was pressed - select all text.
-			c.SelectAll()
-			return 0
-		}
-		if wParam == 26 && !shift {
-			// TODO Ctrl+Z was pressed - undo the last action.
-			//return 0
-		}
-		if wParam == 25 || wParam == 26 && shift {
-			// TODO Ctrl+Y of Ctrl+Shift+Z was pressed - redo the last action.
-			//return 0
+	ui.hookControl(c.handle, controlHooks{char: c.handleChar})
+	if c.errorText != "" {
+		ui.setErrorText(c.handle, c.errorText)
+	}
+}
+
+// ErrorText is the message of a failed validation, it is empty if the control
+// is valid.
+func (c *textEditControl) ErrorText() string {
+	return c.errorText
+}
+
+// SetErrorText marks the control as invalid, it gets a red border and shows
+// the text in a balloon when it gets the focus. Set the text to "" to mark the
+// control as valid again. Form.Validate calls this for you.
+func (c *textEditControl) SetErrorText(text string) {
+	if text == c.errorText {
+		return
+	}
+	c.errorText = text
+	if c.handle != 0 {
+		ui.setErrorText(c.handle, text)
+	}
+}
+
+// handleChar adds the keyboard shortcuts to the edit control that Windows does
+// not handle by default. It returns true if the character was handled.
+func (c *textEditControl) handleChar(char rune) bool {
+	shift := ui.keyDown(KeyShift)
+	if char == 1 {
+		// Ctrl+A was pressed - select all text.
+		c.SelectAll()
+		return true
+	}
+	if char == 26 && !shift {
+		// TODO Ctrl+Z was pressed - undo the last action.
+		//return true
+	}
+	if char == 25 || char == 26 && shift {
+		// TODO Ctrl+Y of Ctrl+Shift+Z was pressed - redo the last action.
+		//return true
+	}
+	if char == 127 {
+		// Ctrl+Backspace was pressed, if there is currently a selection
+		// active, delete it. If there is just the cursor, delete the
+		// last word before the cursor.
+		text := []rune(c.Text())
+		start, end := c.CursorPosition()
+		if start != end {
+			// There is a selection, delete it.
+			c.SetText(string(append(text[:start], text[end:]...)))
+			c.SetCursorPosition(start)
+		} else {
+			// No selection, delete the last word before the cursor.
+			newText, newCursor := deleteWordBeforeCursor(text, start)
+			c.SetText(newText)
+			c.SetCursorPosition(newCursor)
 		}
-		if wParam == 127 {
-			// Ctrl+Backspace was pressed, if there is currently a selection
-			// active, delete it. If there is just the cursor, delete the
-			// last word before the cursor.
-			text := []rune(c.Text())
-			start, end := c.CursorPosition()
-			if start != end {
-				// There is a selection, delete it.
-				c.SetText(string(append(text[:start], text[end:]...)))
-				c.SetCursorPosition(start)
-			} else {
-				// No selection, delete the last word before the cursor.
-				newText, newCursor := deleteWordBeforeCursor(text, start)
-				c.SetText(newText)
-				c.SetCursorPosition(newCursor)
-			}
-			// Since we will return 0 from this message, no EN_CHANGE will be
-			// sent for us as this is usually done by DefSubclassProc. We have
-			// to send it ourselves.
-			if c.parent != nil {
-				id := w32.GetDlgCtrlID(c.handle)
-				w32.SendMessage(
-					c.parent.getHandle(),
-					w32.WM_COMMAND,
-					uintptr(id)&0xFFFF|(w32.EN_CHANGE<<16),
-					uintptr(c.handle),
-				)
-			}
-			return 0
+		// Since we handle this character, no EN_CHANGE will be sent for us as
+		// this is usually done by the default window procedure. We have to
+		// send it ourselves.
+		if c.parent != nil {
+			ui.notifyParent(c.handle, win.EN_CHANGE)
 		}
-		return w32.DefSubclassProc(window, msg, wParam, lParam)
-	default:
-		return w32.DefSubclassProc(window, msg, wParam, lParam)
+		return true
 	}
-})
+	return false
+}
 
 // CursorPosition returns the current cursor position, respectively the current
 // selection.
@@ -441,14 +483,7 @@ var textEditSubclassProc = syscall.NewCallback(func(
 //     c.Text()[start:end]
 func (c *textEditControl) CursorPosition() (start, end int) {
 	if c.handle != 0 {
-		var start, end uint32
-		w32.SendMessage(
-			c.handle,
-			w32.EM_GETSEL,
-			uintptr(unsafe.Pointer(&start)),
-			uintptr(unsafe.Pointer(&end)),
-		)
-		c.cursorStart, c.cursorEnd = int(start), int(end)
+		c.cursorStart, c.cursorEnd = ui.selection(c.handle)
 	}
 	return c.cursorStart, c.cursorEnd
 }
@@ -470,13 +505,7 @@ func (c *textEditControl) setCursor(start, end int) {
 	c.cursorEnd = end
 
 	if c.handle != 0 {
-		w32.SendMessage(
-			c.handle,
-			w32.EM_SETSEL,
-			uintptr(uint32(c.cursorStart)),
-			uintptr(uint32(c.cursorEnd)),
-		)
-		w32.SendMessage(c.handle, w32.EM_SCROLLCARET, 0, 0)
+		ui.setSelection(c.handle, c.cursorStart, c.cursorEnd)
 	} else {
 		c.clampCursorToText()
 	}
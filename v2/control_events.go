@@ -0,0 +1,211 @@
+package wui
+
+// controlEvents are the mouse, keyboard and focus events that all controls
+// have. Mouse positions are in pixels, relative to the top-left corner of the
+// control.
+type controlEvents struct {
+	onMouseEnter  Event
+	onMouseLeave  Event
+	onMouseMove   PointEvent
+	onMouseDown   MouseEvent
+	onMouseUp     MouseEvent
+	onDoubleClick MouseEvent
+	onMouseWheel  WheelEvent
+	onKeyDown     IntEvent
+	onKeyUp       IntEvent
+	onChar        RuneEvent
+	onFocus       Event
+	onFocusLost   Event
+	mouseInside   bool
+}
+
+// hooks returns the controlHooks that fire the events.
+func (e *controlEvents) hooks() controlHooks {
+	return controlHooks{
+		char: func(r rune) bool {
+			e.onChar.fire(r)
+			return false
+		},
+		focusGained: e.onFocus.fire,
+		focusLost:   e.onFocusLost.fire,
+		mouseMove: func(x, y int) {
+			if !e.mouseInside {
+				e.mouseInside = true
+				e.onMouseEnter.fire()
+			}
+			e.onMouseMove.fire(x, y)
+		},
+		mouseLeave: func() {
+			e.mouseInside = false
+			e.onMouseLeave.fire()
+		},
+		mouseDown:   e.onMouseDown.fire,
+		mouseUp:     e.onMouseUp.fire,
+		doubleClick: e.onDoubleClick.fire,
+		mouseWheel:  e.onMouseWheel.fire,
+		keyDown:     e.onKeyDown.fire,
+		keyUp:       e.onKeyUp.fire,
+		wantsMouse:  e.wantsMouse,
+	}
+}
+
+// wantsMouse returns true if there are handlers for mouse events other than
+// movement. Labels, Panels and PaintBoxes let mouse clicks through to their
+// parent window unless they need them themselves.
+func (e *controlEvents) wantsMouse() bool {
+	return !e.onMouseEnter.empty() ||
+		!e.onMouseLeave.empty() ||
+		!e.onMouseDown.empty() ||
+		!e.onMouseUp.empty() ||
+		!e.onDoubleClick.empty() ||
+		!e.onMouseWheel.empty()
+}
+
+func (e *controlEvents) OnMouseEnter() func() {
+	return e.onMouseEnter.primary
+}
+
+// SetOnMouseEnter sets a function that is called when the mouse moves into the
+// control. OnMouseLeave is called when it leaves the control again.
+func (e *controlEvents) SetOnMouseEnter(f func()) {
+	e.onMouseEnter.primary = f
+}
+
+func (e *controlEvents) MouseEnterEvent() *Event {
+	return &e.onMouseEnter
+}
+
+func (e *controlEvents) OnMouseLeave() func() {
+	return e.onMouseLeave.primary
+}
+
+func (e *controlEvents) SetOnMouseLeave(f func()) {
+	e.onMouseLeave.primary = f
+}
+
+func (e *controlEvents) MouseLeaveEvent() *Event {
+	return &e.onMouseLeave
+}
+
+func (e *controlEvents) OnMouseMove() func(x, y int) {
+	return e.onMouseMove.primary
+}
+
+func (e *controlEvents) SetOnMouseMove(f func(x, y int)) {
+	e.onMouseMove.primary = f
+}
+
+func (e *controlEvents) MouseMoveEvent() *PointEvent {
+	return &e.onMouseMove
+}
+
+func (e *controlEvents) OnMouseDown() func(button MouseButton, x, y int) {
+	return e.onMouseDown.primary
+}
+
+func (e *controlEvents) SetOnMouseDown(f func(button MouseButton, x, y int)) {
+	e.onMouseDown.primary = f
+}
+
+func (e *controlEvents) MouseDownEvent() *MouseEvent {
+	return &e.onMouseDown
+}
+
+func (e *controlEvents) OnMouseUp() func(button MouseButton, x, y int) {
+	return e.onMouseUp.primary
+}
+
+func (e *controlEvents) SetOnMouseUp(f func(button MouseButton, x, y int)) {
+	e.onMouseUp.primary = f
+}
+
+func (e *controlEvents) MouseUpEvent() *MouseEvent {
+	return &e.onMouseUp
+}
+
+func (e *controlEvents) OnDoubleClick() func(button MouseButton, x, y int) {
+	return e.onDoubleClick.primary
+}
+
+func (e *controlEvents) SetOnDoubleClick(f func(button MouseButton, x, y int)) {
+	e.onDoubleClick.primary = f
+}
+
+func (e *controlEvents) DoubleClickEvent() *MouseEvent {
+	return &e.onDoubleClick
+}
+
+func (e *controlEvents) OnMouseWheel() func(x, y int, delta float64) {
+	return e.onMouseWheel.primary
+}
+
+func (e *controlEvents) SetOnMouseWheel(f func(x, y int, delta float64)) {
+	e.onMouseWheel.primary = f
+}
+
+func (e *controlEvents) MouseWheelEvent() *WheelEvent {
+	return &e.onMouseWheel
+}
+
+func (e *controlEvents) OnKeyDown() func(key int) {
+	return e.onKeyDown.primary
+}
+
+func (e *controlEvents) SetOnKeyDown(f func(key int)) {
+	e.onKeyDown.primary = f
+}
+
+func (e *controlEvents) KeyDownEvent() *IntEvent {
+	return &e.onKeyDown
+}
+
+func (e *controlEvents) OnKeyUp() func(key int) {
+	return e.onKeyUp.primary
+}
+
+func (e *controlEvents) SetOnKeyUp(f func(key int)) {
+	e.onKeyUp.primary = f
+}
+
+func (e *controlEvents) KeyUpEvent() *IntEvent {
+	return &e.onKeyUp
+}
+
+func (e *controlEvents) OnChar() func(r rune) {
+	return e.onChar.primary
+}
+
+func (e *controlEvents) SetOnChar(f func(r rune)) {
+	e.onChar.primary = f
+}
+
+func (e *controlEvents) CharEvent() *RuneEvent {
+	return &e.onChar
+}
+
+func (e *controlEvents) OnFocus() func() {
+	return e.onFocus.primary
+}
+
+// SetOnFocus sets a function that is called when the control gets the keyboard
+// focus, no matter if by mouse, the Tab key or a call to Focus. OnTabFocus is
+// only called for the Tab key.
+func (e *controlEvents) SetOnFocus(f func()) {
+	e.onFocus.primary = f
+}
+
+func (e *controlEvents) FocusEvent() *Event {
+	return &e.onFocus
+}
+
+func (e *controlEvents) OnFocusLost() func() {
+	return e.onFocusLost.primary
+}
+
+func (e *controlEvents) SetOnFocusLost(f func()) {
+	e.onFocusLost.primary = f
+}
+
+func (e *controlEvents) FocusLostEvent() *Event {
+	return &e.onFocusLost
+}
@@ -0,0 +1,91 @@
+package wui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestControlsReportMouseEvents(t *testing.T) {
+	h := UseHeadless()
+
+	var events []string
+	log := func(format string, args ...interface{}) {
+		events = append(events, fmt.Sprintf(format, args...))
+	}
+
+	w := NewWindow()
+	label := NewLabel()
+	w.Add(label)
+	label.SetOnMouseEnter(func() { log("enter label") })
+	label.SetOnMouseLeave(func() { log("leave label") })
+	label.SetOnMouseMove(func(x, y int) { log("move label %d,%d", x, y) })
+	table := NewStringTable("A")
+	w.Add(table)
+	table.MouseDownEvent().Subscribe(func(b MouseButton, x, y int) {
+		log("down %v %d,%d", b, x, y)
+	})
+	table.SetOnMouseUp(func(b MouseButton, x, y int) { log("up %v", b) })
+	table.SetOnDoubleClick(func(b MouseButton, x, y int) { log("double %v", b) })
+	table.SetOnMouseWheel(func(x, y int, delta float64) { log("wheel %v", delta) })
+
+	w.SetOnShow(func() {
+		h.MouseMove(label, 1, 2)
+		h.MouseMove(label, 3, 4)
+		h.MouseMove(table, 5, 6)
+		h.MouseDown(table, MouseButtonRight, 7, 8)
+		h.MouseUp(table, MouseButtonRight, 7, 8)
+		h.DoubleClick(table, MouseButtonLeft, 7, 8)
+		h.MouseWheel(table, 7, 8, -1)
+		h.MouseMove(label, 0, 0)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, events, []string{
+		"enter label",
+		"move label 1,2",
+		"move label 3,4",
+		"leave label",
+		"down wui.MouseButtonRight 7,8",
+		"up wui.MouseButtonRight",
+		"double wui.MouseButtonLeft",
+		"wheel -1",
+		"enter label",
+		"move label 0,0",
+	})
+}
+
+func TestControlsReportKeysAndFocus(t *testing.T) {
+	h := UseHeadless()
+
+	var events []string
+	w := NewWindow()
+	name := NewEditLine()
+	w.Add(name)
+	name.SetOnFocus(func() { events = append(events, "focus") })
+	name.SetOnFocusLost(func() { events = append(events, "commit "+name.Text()) })
+	name.SetOnKeyDown(func(key int) { events = append(events, fmt.Sprint("down ", key)) })
+	name.SetOnKeyUp(func(key int) { events = append(events, fmt.Sprint("up ", key)) })
+	name.SetOnChar(func(r rune) { events = append(events, "char "+string(r)) })
+	other := NewButton()
+	w.Add(other)
+
+	w.SetOnShow(func() {
+		h.Type(name, "a")
+		h.KeyDown(name, KeyReturn)
+		h.KeyUp(name, KeyReturn)
+		other.Focus()
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, events, []string{
+		"focus",
+		"char a",
+		"down 13",
+		"up 13",
+		"commit a",
+	})
+}
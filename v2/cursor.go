@@ -4,69 +4,69 @@ import (
 	"errors"
 	"image"
 
-	"github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2/internal/win"
 )
 
 // Cursor describes the mouse cursor image. You can use a pre-defined Cursor...
 // variable (see below) or create a custom cursor with NewCursorFromImage.
 type Cursor struct {
-	handle w32.HCURSOR
+	handle uintptr
 }
 
 var (
 	// CursorArrow is the standard, default arrow cursor.
-	CursorArrow = loadCursor(w32.IDC_ARROW)
+	CursorArrow = loadCursor(win.IDC_ARROW)
 
 	// CursorIBeam is the text cursor, it looks like the letter I.
-	CursorIBeam = loadCursor(w32.IDC_IBEAM)
+	CursorIBeam = loadCursor(win.IDC_IBEAM)
 
 	// CursorWait is the hour glass or rotating circle cursor that indicates
 	// that an action will take some more time.
-	CursorWait = loadCursor(w32.IDC_WAIT)
+	CursorWait = loadCursor(win.IDC_WAIT)
 
 	// CursorCross looks like a black + (plus) symbol.
-	CursorCross = loadCursor(w32.IDC_CROSS)
+	CursorCross = loadCursor(win.IDC_CROSS)
 
 	// CursorUpArrow is a vertical arrow pointing upwards.
-	CursorUpArrow = loadCursor(w32.IDC_UPARROW)
+	CursorUpArrow = loadCursor(win.IDC_UPARROW)
 
 	// CursorSizeNWSE is a diagonal line from top-left to bottom-right with
 	// arrows at both ends.
-	CursorSizeNWSE = loadCursor(w32.IDC_SIZENWSE)
+	CursorSizeNWSE = loadCursor(win.IDC_SIZENWSE)
 
 	// CursorSizeNESW is a diagonal line from top-right to bottom-left with
 	// arrows at both ends.
-	CursorSizeNESW = loadCursor(w32.IDC_SIZENESW)
+	CursorSizeNESW = loadCursor(win.IDC_SIZENESW)
 
 	// CursorSizeWE is a horizontal line from left to right with arrows at both
 	// ends.
-	CursorSizeWE = loadCursor(w32.IDC_SIZEWE)
+	CursorSizeWE = loadCursor(win.IDC_SIZEWE)
 
 	// CursorSizeNS is a vertical line from top to bottom with arrows at both
 	// ends.
-	CursorSizeNS = loadCursor(w32.IDC_SIZENS)
+	CursorSizeNS = loadCursor(win.IDC_SIZENS)
 
 	// CursorSizeAll is a white + (plus) symbol with arrows at all four ends.
-	CursorSizeAll = loadCursor(w32.IDC_SIZEALL)
+	CursorSizeAll = loadCursor(win.IDC_SIZEALL)
 
 	// CursorNo indicates that an action is not possible. It is a crossed-out
 	// red circle, like a stop sign.
-	CursorNo = loadCursor(w32.IDC_NO)
+	CursorNo = loadCursor(win.IDC_NO)
 
 	// CursorHand is a hand pointing its index finger upwards.
-	CursorHand = loadCursor(w32.IDC_HAND)
+	CursorHand = loadCursor(win.IDC_HAND)
 
 	// CursorAppStarting is a combination of CursorArrow and CursorWait, it has
 	// the arrow cursor but with an hour glass or rotating circle next to it.
-	CursorAppStarting = loadCursor(w32.IDC_APPSTARTING)
+	CursorAppStarting = loadCursor(win.IDC_APPSTARTING)
 
 	// CursorHelp is the default arrow cursor with a little question mark icon
 	// next to it.
-	CursorHelp = loadCursor(w32.IDC_HELP)
+	CursorHelp = loadCursor(win.IDC_HELP)
 )
 
 func loadCursor(id uint16) *Cursor {
-	return &Cursor{handle: w32.LoadCursor(0, w32.MakeIntResource(id))}
+	return &Cursor{handle: ui.loadCursor(id)}
 }
 
 // NewCursorFromImage creates a cursor with 4 possible colors: black, white,
@@ -118,9 +118,7 @@ func NewCursorFromImage(img image.Image, x, y int) (*Cursor, error) {
 			}
 		}
 	}
-	handle := w32.CreateCursor(
-		w32.GetModuleHandle(""), x, y, b.Dx(), b.Dy(), and, xor,
-	)
+	handle := ui.createCursor(x, y, b.Dx(), b.Dy(), and, xor)
 	if handle == 0 {
 		return nil, errors.New(
 			"wui.NewCursorFromImage: CreateCursor returned 0 handle",
@@ -0,0 +1,162 @@
+package wui
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/gonutz/w32/v2"
+)
+
+func (*winAPI) messageBox(owner uintptr, caption, text string, flags uint) int {
+	return w32.MessageBox(w32.HWND(owner), text, caption, flags)
+}
+
+func (*winAPI) selectFolder(owner uintptr, title string) (bool, string) {
+	t, _ := syscall.UTF16PtrFromString(title)
+	idl := w32.SHBrowseForFolder(&w32.BROWSEINFO{
+		Owner: w32.HWND(owner),
+		Title: t,
+		Flags: w32.BIF_NEWDIALOGSTYLE,
+	})
+	folder := w32.SHGetPathFromIDList(idl)
+	return idl != 0, folder
+}
+
+// filterString converts the file filters to the format that GetOpenFileName
+// and GetSaveFileName expect: a list of 0-terminated text and mask strings,
+// terminated by another 0.
+func filterString(filters []fileFilter) []uint16 {
+	var s []uint16
+	for _, f := range filters {
+		text, _ := syscall.UTF16FromString(f.text)
+		mask, _ := syscall.UTF16FromString(f.mask)
+		s = append(s, text...)
+		s = append(s, mask...)
+	}
+	return append(s, 0)
+}
+
+func utf16PtrOrNil(s string) *uint16 {
+	if s == "" {
+		return nil
+	}
+	p, err := syscall.UTF16PtrFromString(s)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+func (*winAPI) openFiles(
+	owner uintptr,
+	title, initPath string,
+	filters []fileFilter,
+	filterIndex int,
+	multiSelect bool,
+) (bool, []string) {
+	bufLen := w32.MAX_PATH + 2
+	var flags uint32
+	if multiSelect {
+		bufLen = 65535
+		flags = w32.OFN_ALLOWMULTISELECT
+	}
+
+	var initDir *uint16
+	filenameBuf := make([]uint16, bufLen)
+	if initPath != "" {
+		if info, err := os.Stat(initPath); err == nil && info.IsDir() {
+			initDir = utf16PtrOrNil(initPath)
+		} else {
+			path, err := syscall.UTF16FromString(initPath)
+			if err == nil {
+				copy(filenameBuf, path)
+			}
+		}
+	}
+
+	filter := filterString(filters)
+	ok := w32.GetOpenFileName(&w32.OPENFILENAME{
+		Owner:       w32.HWND(owner),
+		Filter:      &filter[0],
+		FilterIndex: uint32(filterIndex + 1), // NOTE one-indexed
+		File:        &filenameBuf[0],
+		MaxFile:     uint32(len(filenameBuf)),
+		InitialDir:  initDir,
+		Title:       utf16PtrOrNil(title),
+		Flags: w32.OFN_ENABLESIZING | w32.OFN_EXPLORER |
+			w32.OFN_FILEMUSTEXIST | w32.OFN_LONGNAMES | w32.OFN_PATHMUSTEXIST |
+			w32.OFN_HIDEREADONLY | flags,
+	})
+	if !ok {
+		return false, nil
+	}
+	if !multiSelect {
+		return true, []string{syscall.UTF16ToString(filenameBuf)}
+	}
+
+	// parse multiple files, the format is 0-separated UTF-16 strings, first
+	// comes the directory, then the file names, after the last file name there
+	// are two zeros
+	buf := filenameBuf
+	var dir string
+	var files []string
+	var start int
+	for i := range buf[:len(buf)-1] {
+		if buf[i] == 0 {
+			part := buf[start:i]
+			if start == 0 {
+				dir = syscall.UTF16ToString(part)
+			} else {
+				file := syscall.UTF16ToString(part)
+				files = append(files, filepath.Join(dir, file))
+			}
+			start = i + 1
+			if buf[i+1] == 0 {
+				break
+			}
+		}
+	}
+	if dir != "" && files == nil {
+		// in this case, only one file was selected
+		return true, []string{dir}
+	}
+	return true, files
+}
+
+func (*winAPI) saveFile(
+	owner uintptr,
+	title, initPath string,
+	filters []fileFilter,
+	filterIndex int,
+) (ok bool, path string, selectedFilter int) {
+	var initDir *uint16
+	filenameBuf := make([]uint16, w32.MAX_PATH+2)
+	if initPath != "" {
+		if info, err := os.Stat(initPath); err == nil && info.IsDir() {
+			initDir = utf16PtrOrNil(initPath)
+		} else {
+			dir, file := filepath.Split(initPath)
+			initDir = utf16PtrOrNil(dir)
+			path, err := syscall.UTF16FromString(file)
+			if err == nil {
+				copy(filenameBuf, path)
+			}
+		}
+	}
+
+	filter := filterString(filters)
+	ofn := &w32.OPENFILENAME{
+		Owner:       w32.HWND(owner),
+		Filter:      &filter[0],
+		FilterIndex: uint32(filterIndex + 1), // NOTE one-indexed
+		File:        &filenameBuf[0],
+		MaxFile:     uint32(len(filenameBuf)),
+		InitialDir:  initDir,
+		Title:       utf16PtrOrNil(title),
+		Flags: w32.OFN_ENABLESIZING | w32.OFN_EXPLORER | w32.OFN_LONGNAMES |
+			w32.OFN_OVERWRITEPROMPT,
+	}
+	ok = w32.GetSaveFileName(ofn)
+	return ok, syscall.UTF16ToString(filenameBuf), int(ofn.FilterIndex) - 1
+}
@@ -0,0 +1,247 @@
+package wui
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"math/bits"
+)
+
+// A DIB (device-independent bitmap) is the way Windows stores images on the
+// clipboard. It is a BITMAPINFOHEADER, BITMAPV4HEADER or BITMAPV5HEADER,
+// followed by the optional color masks and palette, followed by the pixels.
+// Rows are padded to 4 bytes and stored bottom-up unless the height is
+// negative.
+
+const (
+	biRGB       = 0
+	biBitFields = 3
+
+	bitmapInfoHeaderSize = 40
+	bitmapV2HeaderSize   = 52 // Adds the red, green and blue masks.
+	bitmapV3HeaderSize   = 56 // Adds the alpha mask.
+	bitmapV4HeaderSize   = 108
+	bitmapV5HeaderSize   = 124
+
+	lcsSRGB     = 0x73524742 // 'sRGB'
+	lcsGMImages = 4
+)
+
+// decodeDIB decodes uncompressed DIBs with 1, 2, 4, 8, 16, 24 and 32 bits per
+// pixel. 32 bit DIBs without an alpha mask are treated as opaque if all their
+// alpha bytes are 0, since most programs leave them unset.
+func decodeDIB(b []byte) (image.Image, error) {
+	le := binary.LittleEndian
+	if len(b) < bitmapInfoHeaderSize {
+		return nil, errors.New("DIB header too short")
+	}
+	headerSize := int(le.Uint32(b))
+	if headerSize < bitmapInfoHeaderSize || headerSize > len(b) {
+		return nil, errors.New("invalid DIB header size")
+	}
+	width := int(int32(le.Uint32(b[4:])))
+	height := int(int32(le.Uint32(b[8:])))
+	bitCount := int(le.Uint16(b[14:]))
+	compression := le.Uint32(b[16:])
+	colorsUsed := int(le.Uint32(b[32:]))
+	topDown := height < 0
+	if topDown {
+		height = -height
+	}
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("invalid DIB size")
+	}
+
+	offset := headerSize
+	var masks [4]uint32 // Red, green, blue, alpha.
+	switch compression {
+	case biRGB:
+		switch bitCount {
+		case 16:
+			masks = [4]uint32{0x7C00, 0x03E0, 0x001F, 0}
+		case 24:
+			masks = [4]uint32{0xFF0000, 0x00FF00, 0x0000FF, 0}
+		case 32:
+			masks = [4]uint32{0xFF0000, 0x00FF00, 0x0000FF, 0xFF000000}
+		}
+	case biBitFields:
+		if bitCount != 16 && bitCount != 32 {
+			return nil, errors.New("DIB bit fields need 16 or 32 bits per pixel")
+		}
+		if headerSize < bitmapV2HeaderSize {
+			// The masks follow the header.
+			if len(b) < offset+12 {
+				return nil, errors.New("DIB color masks missing")
+			}
+			masks[0] = le.Uint32(b[offset:])
+			masks[1] = le.Uint32(b[offset+4:])
+			masks[2] = le.Uint32(b[offset+8:])
+			offset += 12
+		} else {
+			masks[0] = le.Uint32(b[40:])
+			masks[1] = le.Uint32(b[44:])
+			masks[2] = le.Uint32(b[48:])
+			if headerSize >= bitmapV3HeaderSize {
+				masks[3] = le.Uint32(b[52:])
+			}
+		}
+	default:
+		return nil, errors.New("compressed DIBs are not supported")
+	}
+
+	var palette []color.NRGBA
+	switch bitCount {
+	case 1, 2, 4, 8:
+		n := colorsUsed
+		if n == 0 || n > 1<<uint(bitCount) {
+			n = 1 << uint(bitCount)
+		}
+		if len(b) < offset+4*n {
+			return nil, errors.New("DIB palette too short")
+		}
+		palette = make([]color.NRGBA, n)
+		for i := range palette {
+			c := b[offset+4*i:]
+			palette[i] = color.NRGBA{R: c[2], G: c[1], B: c[0], A: 255}
+		}
+		offset += 4 * n
+	case 16, 24, 32:
+	default:
+		return nil, errors.New("unsupported DIB bit count")
+	}
+
+	// The sizes must not overflow, not even on 32 bit platforms.
+	if width > (math.MaxInt32-31)/bitCount || height > math.MaxInt32/4/width {
+		return nil, errors.New("DIB too large")
+	}
+	stride := (width*bitCount + 31) / 32 * 4
+	if height > (len(b)-offset)/stride {
+		return nil, errors.New("DIB pixel data too short")
+	}
+	size := stride * height
+	if compression == biBitFields && headerSize >= bitmapV2HeaderSize &&
+		len(b)-offset == size+12 {
+		// Some programs write the color masks after a V4 or V5 header, too.
+		offset += 12
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	hasAlpha := false
+	for y := 0; y < height; y++ {
+		row := b[offset+y*stride:]
+		destY := height - 1 - y
+		if topDown {
+			destY = y
+		}
+		dest := img.Pix[destY*img.Stride:]
+		for x := 0; x < width; x++ {
+			var c color.NRGBA
+			if palette != nil {
+				bit := x * bitCount
+				shift := uint(8 - bitCount - bit%8)
+				i := int(row[bit/8]>>shift) & (1<<uint(bitCount) - 1)
+				if i < len(palette) {
+					c = palette[i]
+				}
+			} else {
+				var v uint32
+				switch bitCount {
+				case 16:
+					v = uint32(le.Uint16(row[2*x:]))
+				case 24:
+					v = uint32(row[3*x]) | uint32(row[3*x+1])<<8 | uint32(row[3*x+2])<<16
+				case 32:
+					v = le.Uint32(row[4*x:])
+				}
+				c.R = maskedValue(v, masks[0])
+				c.G = maskedValue(v, masks[1])
+				c.B = maskedValue(v, masks[2])
+				c.A = 255
+				if masks[3] != 0 {
+					c.A = maskedValue(v, masks[3])
+					hasAlpha = hasAlpha || c.A != 0
+				}
+			}
+			dest[4*x+0] = c.R
+			dest[4*x+1] = c.G
+			dest[4*x+2] = c.B
+			dest[4*x+3] = c.A
+		}
+	}
+
+	if compression == biRGB && masks[3] != 0 && !hasAlpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 255
+		}
+	}
+
+	return img, nil
+}
+
+// maskedValue extracts the bits of mask from v and scales them to 8 bits.
+func maskedValue(v, mask uint32) uint8 {
+	if mask == 0 {
+		return 0
+	}
+	shift := uint(bits.TrailingZeros32(mask))
+	n := uint(bits.OnesCount32(mask))
+	x := (v & mask) >> shift
+	if n >= 8 {
+		return uint8(x >> (n - 8))
+	}
+	return uint8(x * 255 / (1<<n - 1))
+}
+
+// encodeDIB returns img as a 32 bit, bottom-up DIB with a BITMAPINFOHEADER,
+// the format of CF_DIB. The alpha values go into the fourth byte of each
+// pixel, which most programs ignore in this format.
+func encodeDIB(img image.Image) []byte {
+	return encodeDIBWithHeader(img, bitmapInfoHeaderSize)
+}
+
+// encodeDIBV5 returns img as a 32 bit, bottom-up DIB with a BITMAPV5HEADER and
+// an alpha mask, the format of CF_DIBV5. The colors are not premultiplied by
+// alpha.
+func encodeDIBV5(img image.Image) []byte {
+	return encodeDIBWithHeader(img, bitmapV5HeaderSize)
+}
+
+func encodeDIBWithHeader(img image.Image, headerSize int) []byte {
+	le := binary.LittleEndian
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	b := make([]byte, headerSize+4*width*height)
+
+	le.PutUint32(b[0:], uint32(headerSize))
+	le.PutUint32(b[4:], uint32(width))
+	le.PutUint32(b[8:], uint32(height))
+	le.PutUint16(b[12:], 1)  // Planes.
+	le.PutUint16(b[14:], 32) // Bits per pixel.
+	le.PutUint32(b[20:], uint32(4*width*height))
+	if headerSize == bitmapV5HeaderSize {
+		le.PutUint32(b[16:], biBitFields)
+		le.PutUint32(b[40:], 0x00FF0000)
+		le.PutUint32(b[44:], 0x0000FF00)
+		le.PutUint32(b[48:], 0x000000FF)
+		le.PutUint32(b[52:], 0xFF000000)
+		le.PutUint32(b[56:], lcsSRGB)
+		le.PutUint32(b[108:], lcsGMImages)
+	}
+
+	pix := b[headerSize:]
+	for y := 0; y < height; y++ {
+		row := pix[(height-1-y)*4*width:]
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(
+				img.At(bounds.Min.X+x, bounds.Min.Y+y),
+			).(color.NRGBA)
+			row[4*x+0] = c.B
+			row[4*x+1] = c.G
+			row[4*x+2] = c.R
+			row[4*x+3] = c.A
+		}
+	}
+	return b
+}
@@ -0,0 +1,143 @@
+package wui
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+	img.SetNRGBA(1, 0, color.NRGBA{G: 255, A: 128})
+	img.SetNRGBA(2, 0, color.NRGBA{B: 255, A: 0})
+	img.SetNRGBA(0, 1, color.NRGBA{R: 1, G: 2, B: 3, A: 4})
+	img.SetNRGBA(1, 1, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+	img.SetNRGBA(2, 1, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	return img
+}
+
+func TestDIBV5KeepsAlpha(t *testing.T) {
+	img, err := decodeDIB(encodeDIBV5(testImage()))
+	check.Eq(t, err, nil)
+	check.Eq(t, img, testImage())
+}
+
+func TestDIBKeepsAlphaIfAnyIsSet(t *testing.T) {
+	img, err := decodeDIB(encodeDIB(testImage()))
+	check.Eq(t, err, nil)
+	check.Eq(t, img, testImage())
+}
+
+func TestDIBWithoutAlphaIsOpaque(t *testing.T) {
+	opaque := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	opaque.SetNRGBA(0, 0, color.NRGBA{R: 50, G: 60, B: 70, A: 0})
+	opaque.SetNRGBA(1, 0, color.NRGBA{R: 80, G: 90, B: 100, A: 0})
+	img, err := decodeDIB(encodeDIB(opaque))
+	check.Eq(t, err, nil)
+	check.Eq(t, img.At(0, 0), color.NRGBA{R: 50, G: 60, B: 70, A: 255})
+	check.Eq(t, img.At(1, 0), color.NRGBA{R: 80, G: 90, B: 100, A: 255})
+}
+
+// dibHeader returns a BITMAPINFOHEADER without the pixels.
+func dibHeader(width, height, bitCount, compression, colors int) []byte {
+	b := make([]byte, bitmapInfoHeaderSize)
+	binary.LittleEndian.PutUint32(b[0:], bitmapInfoHeaderSize)
+	binary.LittleEndian.PutUint32(b[4:], uint32(width))
+	binary.LittleEndian.PutUint32(b[8:], uint32(height))
+	binary.LittleEndian.PutUint16(b[12:], 1)
+	binary.LittleEndian.PutUint16(b[14:], uint16(bitCount))
+	binary.LittleEndian.PutUint32(b[16:], uint32(compression))
+	binary.LittleEndian.PutUint32(b[32:], uint32(colors))
+	return b
+}
+
+func TestDecodeTopDown24BitDIB(t *testing.T) {
+	b := dibHeader(1, -2, 24, biRGB, 0)
+	b = append(b,
+		3, 2, 1, 0, // Blue, green, red and padding.
+		6, 5, 4, 0,
+	)
+	img, err := decodeDIB(b)
+	check.Eq(t, err, nil)
+	check.Eq(t, img.Bounds(), image.Rect(0, 0, 1, 2))
+	check.Eq(t, img.At(0, 0), color.NRGBA{R: 1, G: 2, B: 3, A: 255})
+	check.Eq(t, img.At(0, 1), color.NRGBA{R: 4, G: 5, B: 6, A: 255})
+}
+
+func TestDecodePaletteDIB(t *testing.T) {
+	b := dibHeader(3, 1, 1, biRGB, 2)
+	b = append(b,
+		0, 0, 0, 0, // Black.
+		255, 128, 0, 0, // Blue-ish.
+		0xA0, 0, 0, 0, // Bits 1, 0, 1 and padding.
+	)
+	img, err := decodeDIB(b)
+	check.Eq(t, err, nil)
+	check.Eq(t, img.At(0, 0), color.NRGBA{R: 0, G: 128, B: 255, A: 255})
+	check.Eq(t, img.At(1, 0), color.NRGBA{A: 255})
+	check.Eq(t, img.At(2, 0), color.NRGBA{R: 0, G: 128, B: 255, A: 255})
+}
+
+func TestDecode16BitBitFieldsDIB(t *testing.T) {
+	b := dibHeader(2, 1, 16, biBitFields, 0)
+	b = append(b,
+		0x00, 0xF8, 0, 0, // 5 bits red.
+		0xE0, 0x07, 0, 0, // 6 bits green.
+		0x1F, 0x00, 0, 0, // 5 bits blue.
+		0x00, 0xF8, // Full red.
+		0x1F, 0x00, // Full blue.
+	)
+	img, err := decodeDIB(b)
+	check.Eq(t, err, nil)
+	check.Eq(t, img.At(0, 0), color.NRGBA{R: 255, A: 255})
+	check.Eq(t, img.At(1, 0), color.NRGBA{B: 255, A: 255})
+}
+
+func TestDecodeInvalidDIBs(t *testing.T) {
+	_, err := decodeDIB(nil)
+	check.Neq(t, err, nil)
+	_, err = decodeDIB(dibHeader(0, 1, 32, biRGB, 0))
+	check.Neq(t, err, nil)
+	_, err = decodeDIB(dibHeader(1, 1, 32, 1, 0)) // RLE8 compressed.
+	check.Neq(t, err, nil)
+	_, err = decodeDIB(dibHeader(10, 10, 32, biRGB, 0)) // No pixels.
+	check.Neq(t, err, nil)
+}
+
+func TestDecodeBitFieldsAfterShortHeader(t *testing.T) {
+	// A header between BITMAPINFOHEADER and BITMAPV3INFOHEADER does not hold
+	// all color masks.
+	b := dibHeader(1, 1, 32, biBitFields, 0)
+	binary.LittleEndian.PutUint32(b[0:], 48)
+	b = append(b, make([]byte, 8)...)
+	_, err := decodeDIB(b)
+	check.Eq(t, err.Error(), "DIB color masks missing")
+
+	b = append(b,
+		0, 0, 0xFF, 0, // Red.
+		0, 0xFF, 0, 0, // Green.
+		0xFF, 0, 0, 0, // Blue.
+		3, 2, 1, 0,
+	)
+	img, err := decodeDIB(b)
+	check.Eq(t, err, nil)
+	check.Eq(t, img.At(0, 0), color.NRGBA{R: 1, G: 2, B: 3, A: 255})
+}
+
+func TestDecodeHugeDIBs(t *testing.T) {
+	b := append(dibHeader(0x7FFFFFFF, 0x7FFFFFFF, 32, biRGB, 0), 0, 0, 0, 0)
+	_, err := decodeDIB(b)
+	check.Eq(t, err.Error(), "DIB too large")
+
+	b = append(dibHeader(1<<26, -(1<<26), 1, biRGB, 0), make([]byte, 16)...)
+	_, err = decodeDIB(b)
+	check.Eq(t, err.Error(), "DIB too large")
+
+	b = append(dibHeader(1<<20, 1<<8, 24, biRGB, 0), make([]byte, 64)...)
+	_, err = decodeDIB(b)
+	check.Eq(t, err.Error(), "DIB pixel data too short")
+}
@@ -0,0 +1,69 @@
+package wui
+
+// DefaultDPI is the resolution of a monitor at 100% scaling. All bounds and
+// font heights in wui are logical units at this resolution. When a window is
+// shown on a monitor with a higher resolution, e.g. 144 DPI at 150% scaling,
+// the window, its controls and their fonts are scaled accordingly. When the
+// window is moved to a monitor with a different resolution, it is scaled again.
+//
+// Drawing on a Canvas, the mouse positions in mouse events and the position
+// passed to Menu.Popup are not scaled, they are in pixels. Use Window.DPI and
+// Scale to convert logical units to pixels.
+const DefaultDPI = 96
+
+// Scale converts n logical units to pixels at the given DPI.
+func Scale(n, dpi int) int {
+	return mulDiv(n, dpi, DefaultDPI)
+}
+
+// Unscale converts n pixels at the given DPI to logical units.
+func Unscale(n, dpi int) int {
+	return mulDiv(n, DefaultDPI, dpi)
+}
+
+// mulDiv returns a*b/c rounded to the nearest integer, halves are rounded away
+// from 0. This is how the Win32 function MulDiv rounds.
+func mulDiv(a, b, c int) int {
+	if c == 0 {
+		return a
+	}
+	n := a * b
+	if (n < 0) != (c < 0) {
+		return (n - c/2) / c
+	}
+	return (n + c/2) / c
+}
+
+// scaleBounds converts logical bounds to pixels. We scale the edges and not the
+// sizes so that controls that touch each other in logical units also touch
+// after scaling.
+func scaleBounds(x, y, width, height, dpi int) (int, int, int, int) {
+	left, top := Scale(x, dpi), Scale(y, dpi)
+	right, bottom := Scale(x+width, dpi), Scale(y+height, dpi)
+	return left, top, right - left, bottom - top
+}
+
+// unscaleBounds converts bounds in pixels to logical units.
+func unscaleBounds(x, y, width, height, dpi int) (int, int, int, int) {
+	left, top := Unscale(x, dpi), Unscale(y, dpi)
+	right, bottom := Unscale(x+width, dpi), Unscale(y+height, dpi)
+	return left, top, right - left, bottom - top
+}
+
+// scaleFont returns the description of the font at the given DPI.
+func scaleFont(desc FontDesc, dpi int) FontDesc {
+	desc.Height = Scale(desc.Height, dpi)
+	return desc
+}
+
+// rescale applies the current DPI to the children of c after the DPI of their
+// window changed.
+func rescale(c Container) {
+	for _, child := range c.Children() {
+		child.SetBounds(child.Bounds())
+		child.parentFontChanged()
+		if con, ok := child.(Container); ok {
+			rescale(con)
+		}
+	}
+}
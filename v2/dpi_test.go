@@ -0,0 +1,133 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestScaleRoundsLikeMulDiv(t *testing.T) {
+	check.Eq(t, Scale(10, 96), 10)
+	check.Eq(t, Scale(10, 144), 15)
+	check.Eq(t, Scale(10, 192), 20)
+	check.Eq(t, Scale(11, 120), 14) // 13.75
+	check.Eq(t, Scale(2, 120), 3)   // 2.5 rounds up.
+	check.Eq(t, Scale(-2, 120), -3) // -2.5 rounds away from 0.
+	check.Eq(t, Scale(-11, 144), -17)
+
+	check.Eq(t, Unscale(15, 144), 10)
+	check.Eq(t, Unscale(14, 120), 11)
+	check.Eq(t, Unscale(-17, 144), -11)
+}
+
+func TestScaledControlsStillTouch(t *testing.T) {
+	// At 125% two controls of width 5 would both be 6 pixels wide (6.25) and
+	// overlap if we scaled their sizes.
+	x1, _, w1, _ := scaleBounds(0, 0, 5, 5, 120)
+	x2, _, w2, _ := scaleBounds(5, 0, 5, 5, 120)
+	check.Eq(t, x1+w1, x2)
+	check.Eq(t, w1+w2, Scale(10, 120))
+}
+
+func TestWindowIsScaledToMonitorDPI(t *testing.T) {
+	h := UseHeadless()
+	h.SetDPI(144)
+
+	w := NewWindow()
+	w.SetBounds(100, 100, 400, 300)
+	f, _ := NewFont(FontDesc{Name: "Tahoma", Height: -12})
+	w.SetFont(f)
+	b := NewButton()
+	b.SetBounds(10, 20, 100, 25)
+	w.Add(b)
+
+	w.SetOnShow(func() {
+		check.Eq(t, w.DPI(), 144)
+		check.Eq(t, rect(w), Rectangle{X: 100, Y: 100, Width: 400, Height: 300})
+		check.Eq(t, rect(b), Rectangle{X: 10, Y: 20, Width: 100, Height: 25})
+
+		native, _ := h.Control(w.Handle())
+		check.Eq(t, nativeRect(native), Rectangle{X: 150, Y: 150, Width: 600, Height: 450})
+		native, _ = h.Control(b.Handle())
+		check.Eq(t, nativeRect(native), Rectangle{X: 15, Y: 30, Width: 150, Height: 38})
+		check.Eq(t, native.Font.Height, -18)
+		check.Eq(t, native.Font.Name, "Tahoma")
+		w.Close()
+	})
+	w.Show()
+	check.Eq(t, w.DPI(), DefaultDPI)
+}
+
+func TestMovingWindowToOtherMonitorRescalesIt(t *testing.T) {
+	h := UseHeadless()
+
+	w := NewWindow()
+	w.SetBounds(0, 0, 200, 100)
+	p := NewPanel()
+	p.SetBounds(10, 10, 100, 50)
+	w.Add(p)
+	l := NewLabel()
+	l.SetBounds(5, 5, 40, 20)
+	p.Add(l)
+	dpiChanges := 0
+	w.SetOnDPIChange(func() { dpiChanges++ })
+
+	w.SetOnShow(func() {
+		native, _ := h.Control(l.Handle())
+		check.Eq(t, nativeRect(native), Rectangle{X: 5, Y: 5, Width: 40, Height: 20})
+
+		h.SetDPI(192)
+		check.Eq(t, dpiChanges, 1)
+		check.Eq(t, w.DPI(), 192)
+		check.Eq(t, rect(w), Rectangle{X: 0, Y: 0, Width: 200, Height: 100})
+		check.Eq(t, rect(l), Rectangle{X: 5, Y: 5, Width: 40, Height: 20})
+		native, _ = h.Control(w.Handle())
+		check.Eq(t, nativeRect(native), Rectangle{X: 0, Y: 0, Width: 400, Height: 200})
+		native, _ = h.Control(l.Handle())
+		check.Eq(t, nativeRect(native), Rectangle{X: 10, Y: 10, Width: 80, Height: 40})
+
+		h.SetDPI(192)
+		check.Eq(t, dpiChanges, 1)
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestScaledFontsAreDeletedWhenNoLongerNeeded(t *testing.T) {
+	h := UseHeadless()
+	h.SetDPI(144)
+
+	w := NewWindow()
+	f, _ := NewFont(FontDesc{Name: "Tahoma", Height: -12})
+	w.SetFont(f)
+	other, _ := NewFont(FontDesc{Name: "Arial", Height: -12})
+	b := NewButton()
+	w.Add(b)
+
+	w.SetOnShow(func() {
+		at144 := f.scaled[144]
+		check.Eq(t, h.fonts[at144].Height, -18)
+
+		h.SetDPI(192)
+		_, ok := h.fonts[at144]
+		check.Eq(t, ok, false)
+		check.Eq(t, len(f.scaled), 1)
+
+		// The button still uses the font.
+		w.SetFont(other)
+		b.SetFont(f)
+		check.Eq(t, len(f.scaled), 1)
+		b.SetFont(nil)
+		check.Eq(t, len(f.scaled), 0)
+		check.Eq(t, len(other.scaled), 1)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, len(other.scaled), 0)
+}
+
+func nativeRect(c HeadlessControl) Rectangle {
+	return Rectangle{X: c.X, Y: c.Y, Width: c.Width, Height: c.Height}
+}
@@ -0,0 +1,118 @@
+package wui
+
+// DragData is what the user drags over or drops onto a window or control.
+type DragData struct {
+	// Files are the paths of the dragged files, they are empty if the user
+	// drags something else.
+	Files []string
+	// Text is the dragged text, if the user drags text instead of files.
+	Text string
+	// X and Y are the mouse position in pixels, relative to the top-left
+	// corner of the window's client area or the control.
+	X, Y int
+}
+
+// dropHandlers are the drag and drop handlers of a window or control.
+type dropHandlers struct {
+	onDragOver     func(d DragData) bool
+	onFilesDropped FilesEvent
+	onTextDropped  TextEvent
+}
+
+// accepts returns true if the data can be dropped here.
+func (h *dropHandlers) accepts(d DragData) bool {
+	if len(d.Files) > 0 && h.onFilesDropped.empty() ||
+		len(d.Files) == 0 && (d.Text == "" || h.onTextDropped.empty()) {
+		return false
+	}
+	return h.onDragOver == nil || h.onDragOver(d)
+}
+
+func (h *dropHandlers) drop(d DragData) {
+	if len(d.Files) > 0 {
+		h.onFilesDropped.fire(d.Files, d.X, d.Y)
+	} else {
+		h.onTextDropped.fire(d.Text, d.X, d.Y)
+	}
+}
+
+func (h *dropHandlers) OnDragOver() func(d DragData) bool {
+	return h.onDragOver
+}
+
+// SetOnDragOver sets a function that decides whether the dragged data may be
+// dropped at its current position. It is called while the user drags data that
+// can be dropped here, i.e. files if OnFilesDropped is set or text if
+// OnTextDropped is set. If f returns false, the mouse cursor shows that the
+// data cannot be dropped. Without OnDragOver, all files or text are accepted.
+func (h *dropHandlers) SetOnDragOver(f func(d DragData) bool) {
+	h.onDragOver = f
+}
+
+func (h *dropHandlers) OnFilesDropped() func(paths []string, x, y int) {
+	return h.onFilesDropped.primary
+}
+
+// SetOnFilesDropped sets a function that is called when the user drops files
+// here, e.g. from the Windows Explorer. x and y are the drop position in
+// pixels. Files that are dropped onto a control without OnFilesDropped go to
+// its parent.
+func (h *dropHandlers) SetOnFilesDropped(f func(paths []string, x, y int)) {
+	h.onFilesDropped.primary = f
+}
+
+func (h *dropHandlers) FilesDroppedEvent() *FilesEvent {
+	return &h.onFilesDropped
+}
+
+func (h *dropHandlers) OnTextDropped() func(text string, x, y int) {
+	return h.onTextDropped.primary
+}
+
+// SetOnTextDropped sets a function that is called when the user drops text
+// here, e.g. from a text editor. Text is only accepted if this is set.
+func (h *dropHandlers) SetOnTextDropped(f func(text string, x, y int)) {
+	h.onTextDropped.primary = f
+}
+
+func (h *dropHandlers) TextDroppedEvent() *TextEvent {
+	return &h.onTextDropped
+}
+
+// dropTargetFor returns the drop handlers of the control with the given
+// handle or, if it does not accept d, those of its closest parent that does.
+// pos returns the mouse position relative to the control or window with the
+// given handle, the returned data has the position relative to the one that
+// accepts it. It returns nil handlers if neither the control nor its parents
+// accept d.
+func (w *Window) dropTargetFor(
+	handle uintptr,
+	d DragData,
+	pos func(target uintptr) (x, y int),
+) (*dropHandlers, DragData) {
+	var c Control
+	for _, control := range w.controls {
+		if control.Handle() == handle && handle != 0 {
+			c = control
+		}
+	}
+	accepts := func(h *dropHandlers, target uintptr) bool {
+		d.X, d.Y = pos(target)
+		return h.accepts(d)
+	}
+	for c != nil {
+		if h := c.getDropHandlers(); accepts(h, c.Handle()) {
+			return h, d
+		}
+		parent, _ := c.Parent().(Control)
+		c = parent
+	}
+	if accepts(&w.dropHandlers, w.handle) {
+		return &w.dropHandlers, d
+	}
+	return nil, d
+}
+
+func (c *control) getDropHandlers() *dropHandlers {
+	return &c.dropHandlers
+}
@@ -0,0 +1,103 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestFilesAreDroppedOnClosestTarget(t *testing.T) {
+	h := UseHeadless()
+
+	type drop struct {
+		target string
+		paths  []string
+		x, y   int
+	}
+	var drops []drop
+
+	w := NewWindow()
+	w.SetOnFilesDropped(func(paths []string, x, y int) {
+		drops = append(drops, drop{"window", paths, x, y})
+	})
+	panel := NewPanel()
+	panel.SetBounds(10, 20, 200, 100)
+	w.Add(panel)
+	label := NewLabel()
+	label.SetBounds(5, 5, 50, 20)
+	panel.Add(label)
+	paint := NewPaintBox()
+	paint.SetBounds(100, 0, 50, 50)
+	panel.Add(paint)
+	paint.FilesDroppedEvent().Subscribe(func(paths []string, x, y int) {
+		drops = append(drops, drop{"paint", paths, x, y})
+	})
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.Drop(paint, DragData{Files: []string{"a.txt"}, X: 1, Y: 2}), true)
+		// The label does not accept files, they go to the window.
+		check.Eq(t, h.Drop(label, DragData{Files: []string{"b", "c"}, X: 3, Y: 4}), true)
+		// Nobody accepts text.
+		check.Eq(t, h.Drop(paint, DragData{Text: "text"}), false)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, drops, []drop{
+		{"paint", []string{"a.txt"}, 1, 2},
+		{"window", []string{"b", "c"}, 18, 29},
+	})
+}
+
+func TestDragOverCanRejectDrops(t *testing.T) {
+	h := UseHeadless()
+
+	w := NewWindow()
+	table := NewStringTable("Name")
+	w.Add(table)
+	var dropped []string
+	table.SetOnFilesDropped(func(paths []string, x, y int) {
+		dropped = append(dropped, paths...)
+	})
+	table.SetOnTextDropped(func(text string, x, y int) {
+		dropped = append(dropped, "text: "+text)
+	})
+	table.SetOnDragOver(func(d DragData) bool {
+		return d.Text != "" || d.Files[0] != "forbidden"
+	})
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.Drop(table, DragData{Files: []string{"forbidden"}}), false)
+		check.Eq(t, h.Drop(table, DragData{Files: []string{"allowed"}}), true)
+		check.Eq(t, h.Drop(table, DragData{Text: "hello"}), true)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, dropped, []string{"allowed", "text: hello"})
+}
+
+func TestDataGoesToTheClosestTargetThatAcceptsIt(t *testing.T) {
+	h := UseHeadless()
+
+	var dropped []string
+	w := NewWindow()
+	w.SetOnFilesDropped(func(paths []string, x, y int) {
+		dropped = append(dropped, "window", paths[0])
+	})
+	edit := NewEditLine()
+	edit.SetBounds(10, 20, 100, 20)
+	w.Add(edit)
+	edit.SetOnTextDropped(func(text string, x, y int) {
+		dropped = append(dropped, "edit", text)
+	})
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.Drop(edit, DragData{Files: []string{"a.txt"}}), true)
+		check.Eq(t, h.Drop(edit, DragData{Text: "text"}), true)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, dropped, []string{"window", "a.txt", "edit", "text"})
+}
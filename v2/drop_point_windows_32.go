@@ -0,0 +1,36 @@
+//go:build windows && (386 || arm)
+// +build windows
+// +build 386 arm
+
+package wui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+// On 32 bit systems, a POINTL that is passed by value takes up two arguments.
+
+var dragEnterCallback = syscall.NewCallback(func(this, data, keys, x, y uintptr, effect *uint32) uintptr {
+	return (*dropTarget)(unsafe.Pointer(this)).dragEnter(data, int(int32(x)), int(int32(y)), effect)
+})
+
+var dragOverCallback = syscall.NewCallback(func(this, keys, x, y uintptr, effect *uint32) uintptr {
+	return (*dropTarget)(unsafe.Pointer(this)).dragOver(int(int32(x)), int(int32(y)), effect)
+})
+
+var dropCallback = syscall.NewCallback(func(this, data, keys, x, y uintptr, effect *uint32) uintptr {
+	return (*dropTarget)(unsafe.Pointer(this)).drop(int(int32(x)), int(int32(y)), effect)
+})
+
+func childWindowFromPoint(parent w32.HWND, x, y int, flags uint32) w32.HWND {
+	ret, _, _ := childWindowFromPointEx.Call(
+		uintptr(parent),
+		uintptr(int32(x)),
+		uintptr(int32(y)),
+		uintptr(flags),
+	)
+	return w32.HWND(ret)
+}
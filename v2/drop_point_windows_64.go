@@ -0,0 +1,42 @@
+//go:build windows && !386 && !arm
+// +build windows,!386,!arm
+
+package wui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+// On 64 bit systems, a POINTL that is passed by value fits into one argument,
+// x is in the lower and y in the upper 32 bits.
+
+func pointArg(pt uintptr) (x, y int) {
+	return int(int32(uint32(pt))), int(int32(uint32(pt >> 32)))
+}
+
+var dragEnterCallback = syscall.NewCallback(func(this, data, keys, pt uintptr, effect *uint32) uintptr {
+	x, y := pointArg(pt)
+	return (*dropTarget)(unsafe.Pointer(this)).dragEnter(data, x, y, effect)
+})
+
+var dragOverCallback = syscall.NewCallback(func(this, keys, pt uintptr, effect *uint32) uintptr {
+	x, y := pointArg(pt)
+	return (*dropTarget)(unsafe.Pointer(this)).dragOver(x, y, effect)
+})
+
+var dropCallback = syscall.NewCallback(func(this, data, keys, pt uintptr, effect *uint32) uintptr {
+	x, y := pointArg(pt)
+	return (*dropTarget)(unsafe.Pointer(this)).drop(x, y, effect)
+})
+
+func childWindowFromPoint(parent w32.HWND, x, y int, flags uint32) w32.HWND {
+	ret, _, _ := childWindowFromPointEx.Call(
+		uintptr(parent),
+		uintptr(uint32(int32(x)))|uintptr(uint32(int32(y)))<<32,
+		uintptr(flags),
+	)
+	return w32.HWND(ret)
+}
@@ -0,0 +1,211 @@
+package wui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+// dropTarget implements the COM interface IDropTarget for a top-level window.
+// The first field must be the pointer to the function table.
+type dropTarget struct {
+	vtbl   *dropTargetVtbl
+	window *Window
+	// files and text are read from the IDataObject when the drag enters the
+	// window.
+	files []string
+	text  string
+}
+
+type dropTargetVtbl struct {
+	queryInterface uintptr
+	addRef         uintptr
+	release        uintptr
+	dragEnter      uintptr
+	dragOver       uintptr
+	dragLeave      uintptr
+	drop           uintptr
+}
+
+var dropTargetFuncs = &dropTargetVtbl{
+	queryInterface: syscall.NewCallback(func(this uintptr, iid *w32.GUID, object *uintptr) uintptr {
+		if *iid == iidIUnknown || *iid == iidIDropTarget {
+			*object = this
+			return w32.S_OK
+		}
+		*object = 0
+		return eNoInterface
+	}),
+	// The drop targets live as long as their windows, we do not count
+	// references.
+	addRef:    syscall.NewCallback(func(this uintptr) uintptr { return 1 }),
+	release:   syscall.NewCallback(func(this uintptr) uintptr { return 1 }),
+	dragEnter: dragEnterCallback,
+	dragOver:  dragOverCallback,
+	dragLeave: syscall.NewCallback(func(this uintptr) uintptr {
+		t := (*dropTarget)(unsafe.Pointer(this))
+		t.files, t.text = nil, ""
+		return w32.S_OK
+	}),
+	drop: dropCallback,
+}
+
+var (
+	iidIUnknown = w32.GUID{
+		Data1: 0x00000000,
+		Data4: [8]byte{0xC0, 0, 0, 0, 0, 0, 0, 0x46},
+	}
+	iidIDropTarget = w32.GUID{
+		Data1: 0x00000122,
+		Data4: [8]byte{0xC0, 0, 0, 0, 0, 0, 0, 0x46},
+	}
+)
+
+const (
+	eNoInterface     = 0x80004002
+	dropEffectNone   = 0
+	dropEffectCopy   = 1
+	dvAspectContent  = 1
+	tymedHGlobal     = 1
+	cwpSkipInvisible = 1
+)
+
+func (t *dropTarget) dragEnter(data uintptr, x, y int, effect *uint32) uintptr {
+	t.files = dataFiles(data)
+	if len(t.files) == 0 {
+		t.text = dataText(data)
+	}
+	return t.dragOver(x, y, effect)
+}
+
+func (t *dropTarget) dragOver(x, y int, effect *uint32) uintptr {
+	if *effect&dropEffectCopy != 0 && t.handle(x, y, false) {
+		*effect = dropEffectCopy
+	} else {
+		*effect = dropEffectNone
+	}
+	return w32.S_OK
+}
+
+func (t *dropTarget) drop(x, y int, effect *uint32) uintptr {
+	if *effect&dropEffectCopy != 0 && t.handle(x, y, true) {
+		*effect = dropEffectCopy
+	} else {
+		*effect = dropEffectNone
+	}
+	t.files, t.text = nil, ""
+	return w32.S_OK
+}
+
+// handle finds the control under the screen position x,y that accepts the
+// dragged data. It returns false if there is none. If drop is true, the data
+// is dropped on it.
+func (t *dropTarget) handle(x, y int, drop bool) bool {
+	window := w32.HWND(t.window.handle)
+	child := window
+	for {
+		cx, cy, _ := w32.ScreenToClient(child, x, y)
+		next := childWindowFromPoint(child, cx, cy, cwpSkipInvisible)
+		if next == 0 || next == child {
+			break
+		}
+		child = next
+	}
+	d := DragData{Files: t.files, Text: t.text}
+	h, d := t.window.dropTargetFor(uintptr(child), d, func(target uintptr) (int, int) {
+		tx, ty, _ := w32.ScreenToClient(w32.HWND(target), x, y)
+		return tx, ty
+	})
+	if h == nil {
+		return false
+	}
+	if drop {
+		h.drop(d)
+	}
+	return true
+}
+
+// dataFiles returns the file paths in an IDataObject.
+func dataFiles(data uintptr) []string {
+	var medium stgMedium
+	if !getData(data, w32.CF_HDROP, &medium) {
+		return nil
+	}
+	defer releaseStgMedium(&medium)
+	n := dragQueryFile(medium.handle, 0xFFFFFFFF, nil)
+	files := make([]string, n)
+	for i := range files {
+		buf := make([]uint16, dragQueryFile(medium.handle, uint32(i), nil)+1)
+		dragQueryFile(medium.handle, uint32(i), buf)
+		files[i] = syscall.UTF16ToString(buf)
+	}
+	return files
+}
+
+// dataText returns the text in an IDataObject.
+func dataText(data uintptr) string {
+	var medium stgMedium
+	if !getData(data, w32.CF_UNICODETEXT, &medium) {
+		return ""
+	}
+	defer releaseStgMedium(&medium)
+	p := w32.GlobalLock(w32.HGLOBAL(medium.handle))
+	if p == nil {
+		return ""
+	}
+	defer w32.GlobalUnlock(w32.HGLOBAL(medium.handle))
+	return utf16PtrToString((*uint16)(p))
+}
+
+// getData calls IDataObject.GetData for the clipboard format.
+func getData(data uintptr, format uint16, medium *stgMedium) bool {
+	f := formatEtc{
+		format: format,
+		aspect: dvAspectContent,
+		index:  -1,
+		tymed:  tymedHGlobal,
+	}
+	vtbl := *(**[4]uintptr)(unsafe.Pointer(data))
+	ret, _, _ := syscall.Syscall(
+		vtbl[3], // GetData
+		3,
+		data,
+		uintptr(unsafe.Pointer(&f)),
+		uintptr(unsafe.Pointer(medium)),
+	)
+	return ret == w32.S_OK
+}
+
+// utf16PtrToString reads a zero-terminated UTF-16 string.
+func utf16PtrToString(p *uint16) string {
+	var s []uint16
+	for ptr := unsafe.Pointer(p); *(*uint16)(ptr) != 0; ptr = unsafe.Pointer(uintptr(ptr) + 2) {
+		s = append(s, *(*uint16)(ptr))
+	}
+	return syscall.UTF16ToString(s)
+}
+
+// registerDropTarget lets the window receive drag and drop data. The window
+// decides which of its controls get the data, so they do not need their own
+// targets.
+func (a *winAPI) registerDropTarget(w *Window) {
+	if !a.oleInitialized {
+		a.oleInitialized = oleInitialize()
+		if !a.oleInitialized {
+			return
+		}
+	}
+	t := &dropTarget{vtbl: dropTargetFuncs, window: w}
+	if registerDragDrop(w.handle, uintptr(unsafe.Pointer(t))) {
+		// Keep the target alive as long as Windows knows about it.
+		a.dropTargets[w32.HWND(w.handle)] = t
+	}
+}
+
+func (a *winAPI) revokeDropTarget(window uintptr) {
+	if _, ok := a.dropTargets[w32.HWND(window)]; ok {
+		revokeDragDrop(window)
+		delete(a.dropTargets, w32.HWND(window))
+	}
+}
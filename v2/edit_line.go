@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewEditLine() *EditLine {
 	return &EditLine{limit: 0x7FFFFFFE}
@@ -9,10 +9,10 @@ func NewEditLine() *EditLine {
 type EditLine struct {
 	textEditControl
 	isPassword   bool
-	passwordChar uintptr
+	passwordChar rune
 	limit        int
 	readOnly     bool
-	onTextChange func()
+	onTextChange Event
 }
 
 var _ Control = (*EditLine)(nil)
@@ -26,11 +26,15 @@ func (*EditLine) canFocus() bool {
 }
 
 func (e *EditLine) OnTabFocus() func() {
-	return e.onTabFocus
+	return e.onTabFocus.primary
 }
 
 func (e *EditLine) SetOnTabFocus(f func()) {
-	e.onTabFocus = f
+	e.onTabFocus.primary = f
+}
+
+func (e *EditLine) TabFocusEvent() *Event {
+	return &e.onTabFocus
 }
 
 func (*EditLine) eatsTabs() bool {
@@ -40,11 +44,11 @@ func (*EditLine) eatsTabs() bool {
 func (e *EditLine) create(id int) {
 	e.textEditControl.create(
 		id,
-		w32.WS_EX_CLIENTEDGE,
+		win.WS_EX_CLIENTEDGE,
 		"EDIT",
-		w32.WS_TABSTOP|w32.ES_AUTOHSCROLL|w32.ES_PASSWORD,
+		win.WS_TABSTOP|win.ES_AUTOHSCROLL|win.ES_PASSWORD,
 	)
-	e.passwordChar = w32.SendMessage(e.handle, w32.EM_GETPASSWORDCHAR, 0, 0)
+	e.passwordChar = ui.passwordChar(e.handle)
 	e.SetIsPassword(e.isPassword)
 	e.SetCharacterLimit(e.limit)
 	e.SetReadOnly(e.readOnly)
@@ -54,11 +58,11 @@ func (e *EditLine) SetIsPassword(isPassword bool) {
 	e.isPassword = isPassword
 	if e.handle != 0 {
 		if e.isPassword {
-			w32.SendMessage(e.handle, w32.EM_SETPASSWORDCHAR, e.passwordChar, 0)
+			ui.setPasswordChar(e.handle, e.passwordChar)
 		} else {
-			w32.SendMessage(e.handle, w32.EM_SETPASSWORDCHAR, 0, 0)
+			ui.setPasswordChar(e.handle, 0)
 		}
-		w32.InvalidateRect(e.parent.getHandle(), nil, true)
+		ui.repaint(e.parent.getHandle())
 	}
 }
 
@@ -72,13 +76,13 @@ func (e *EditLine) SetCharacterLimit(count int) {
 	}
 	e.limit = count
 	if e.handle != 0 {
-		w32.SendMessage(e.handle, w32.EM_SETLIMITTEXT, uintptr(e.limit), 0)
+		ui.setTextLimit(e.handle, e.limit)
 	}
 }
 
 func (e *EditLine) CharacterLimit() int {
 	if e.handle != 0 {
-		e.limit = int(w32.SendMessage(e.handle, w32.EM_GETLIMITTEXT, 0, 0))
+		e.limit = ui.textLimit(e.handle)
 	}
 	return e.limit
 }
@@ -86,11 +90,7 @@ func (e *EditLine) CharacterLimit() int {
 func (e *EditLine) SetReadOnly(readOnly bool) {
 	e.readOnly = readOnly
 	if e.handle != 0 {
-		if readOnly {
-			w32.SendMessage(e.handle, w32.EM_SETREADONLY, 1, 0)
-		} else {
-			w32.SendMessage(e.handle, w32.EM_SETREADONLY, 0, 0)
-		}
+		ui.setReadOnly(e.handle, readOnly)
 	}
 }
 
@@ -99,15 +99,19 @@ func (e *EditLine) ReadOnly() bool {
 }
 
 func (e *EditLine) SetOnTextChange(f func()) {
-	e.onTextChange = f
+	e.onTextChange.primary = f
+}
+
+func (e *EditLine) TextChangeEvent() *Event {
+	return &e.onTextChange
 }
 
 func (e *EditLine) OnTextChange() func() {
-	return e.onTextChange
+	return e.onTextChange.primary
 }
 
 func (e *EditLine) handleNotification(cmd uintptr) {
-	if cmd == w32.EN_CHANGE && e.onTextChange != nil {
-		e.onTextChange()
+	if cmd == win.EN_CHANGE {
+		e.onTextChange.fire()
 	}
 }
@@ -0,0 +1,444 @@
+package wui
+
+// The Set/On functions of controls and windows, e.g. Button.SetOnClick, set
+// the primary handler of an event. Independent parts of a program, like a data
+// binding and a logger, can subscribe additional handlers to the same event,
+// e.g. with Button.ClickEvent().Subscribe(f). The primary handler is called
+// first, then the subscribed handlers in the order that they were subscribed.
+// Subscribe returns a function that removes the handler again, calling it more
+// than once does no harm. Subscribing nil does nothing.
+
+// handlers is the list of subscribed functions that all event types share.
+type handlers struct {
+	list   []subscription
+	nextID int
+}
+
+type subscription struct {
+	id int
+	f  interface{}
+}
+
+// subscribe appends f to the list. The returned function removes it again, it
+// may be called more than once.
+func (h *handlers) subscribe(f interface{}) (unsubscribe func()) {
+	h.nextID++
+	id := h.nextID
+	h.list = append(h.list, subscription{id: id, f: f})
+	return func() {
+		for i := range h.list {
+			if h.list[i].id == id {
+				// Copy the list so a dispatch that is currently running
+				// continues with the old list.
+				list := make([]subscription, 0, len(h.list)-1)
+				list = append(list, h.list[:i]...)
+				h.list = append(list, h.list[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// funcs returns the subscribed functions at the time of the call. Handlers
+// that subscribe or unsubscribe during dispatch take effect the next time.
+func (h *handlers) funcs() []interface{} {
+	fs := make([]interface{}, len(h.list))
+	for i := range h.list {
+		fs[i] = h.list[i].f
+	}
+	return fs
+}
+
+// Event is an event without arguments, e.g. a Button click.
+type Event struct {
+	primary func()
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *Event) Subscribe(f func()) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *Event) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *Event) fire() {
+	if e.primary != nil {
+		e.primary()
+	}
+	for _, f := range e.funcs() {
+		f.(func())()
+	}
+}
+
+// BoolEvent is an event with a bool argument, e.g. a CheckBox being checked or
+// unchecked.
+type BoolEvent struct {
+	primary func(bool)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *BoolEvent) Subscribe(f func(bool)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *BoolEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *BoolEvent) fire(b bool) {
+	if e.primary != nil {
+		e.primary(b)
+	}
+	for _, f := range e.funcs() {
+		f.(func(bool))(b)
+	}
+}
+
+// IntEvent is an event with an int argument, e.g. the new index of a
+// ComboBox or the key of a key press.
+type IntEvent struct {
+	primary func(int)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *IntEvent) Subscribe(f func(int)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *IntEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *IntEvent) fire(n int) {
+	if e.primary != nil {
+		e.primary(n)
+	}
+	for _, f := range e.funcs() {
+		f.(func(int))(n)
+	}
+}
+
+// FloatEvent is an event with a float64 argument, e.g. the new value of a
+// FloatUpDown.
+type FloatEvent struct {
+	primary func(float64)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *FloatEvent) Subscribe(f func(float64)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *FloatEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *FloatEvent) fire(x float64) {
+	if e.primary != nil {
+		e.primary(x)
+	}
+	for _, f := range e.funcs() {
+		f.(func(float64))(x)
+	}
+}
+
+// RuneEvent is an event with a character argument, e.g. a typed character.
+type RuneEvent struct {
+	primary func(rune)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *RuneEvent) Subscribe(f func(rune)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *RuneEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *RuneEvent) fire(r rune) {
+	if e.primary != nil {
+		e.primary(r)
+	}
+	for _, f := range e.funcs() {
+		f.(func(rune))(r)
+	}
+}
+
+// PointEvent is an event with a position argument, e.g. the mouse moving.
+type PointEvent struct {
+	primary func(x, y int)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *PointEvent) Subscribe(f func(x, y int)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *PointEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *PointEvent) fire(x, y int) {
+	if e.primary != nil {
+		e.primary(x, y)
+	}
+	for _, f := range e.funcs() {
+		f.(func(x, y int))(x, y)
+	}
+}
+
+// MouseEvent is an event for a mouse button being pressed or released.
+type MouseEvent struct {
+	primary func(button MouseButton, x, y int)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *MouseEvent) Subscribe(f func(button MouseButton, x, y int)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *MouseEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *MouseEvent) fire(button MouseButton, x, y int) {
+	if e.primary != nil {
+		e.primary(button, x, y)
+	}
+	for _, f := range e.funcs() {
+		f.(func(MouseButton, int, int))(button, x, y)
+	}
+}
+
+// WheelEvent is an event for the mouse wheel being turned.
+type WheelEvent struct {
+	primary func(x, y int, delta float64)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *WheelEvent) Subscribe(f func(x, y int, delta float64)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *WheelEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *WheelEvent) fire(x, y int, delta float64) {
+	if e.primary != nil {
+		e.primary(x, y, delta)
+	}
+	for _, f := range e.funcs() {
+		f.(func(int, int, float64))(x, y, delta)
+	}
+}
+
+// CanvasEvent is the event for painting a PaintBox.
+type CanvasEvent struct {
+	primary func(*Canvas)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *CanvasEvent) Subscribe(f func(*Canvas)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *CanvasEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *CanvasEvent) fire(c *Canvas) {
+	if e.primary != nil {
+		e.primary(c)
+	}
+	for _, f := range e.funcs() {
+		f.(func(*Canvas))(c)
+	}
+}
+
+// VetoEvent is an event that any handler can cancel, e.g. closing a Window.
+// Its handlers return false to cancel it. The handlers after the first one
+// that returns false are not called.
+type VetoEvent struct {
+	primary func() bool
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *VetoEvent) Subscribe(f func() bool) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *VetoEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+// fire returns false if a handler cancelled the event.
+func (e *VetoEvent) fire() bool {
+	if e.primary != nil && !e.primary() {
+		return false
+	}
+	for _, f := range e.funcs() {
+		if !f.(func() bool)() {
+			return false
+		}
+	}
+	return true
+}
+
+// FilesEvent is the event for files being dropped onto a window or control.
+type FilesEvent struct {
+	primary func(paths []string, x, y int)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *FilesEvent) Subscribe(f func(paths []string, x, y int)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *FilesEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *FilesEvent) fire(paths []string, x, y int) {
+	if e.primary != nil {
+		e.primary(paths, x, y)
+	}
+	for _, f := range e.funcs() {
+		f.(func([]string, int, int))(paths, x, y)
+	}
+}
+
+// TextEvent is the event for text being dropped onto a window or control.
+type TextEvent struct {
+	primary func(text string, x, y int)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *TextEvent) Subscribe(f func(text string, x, y int)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *TextEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *TextEvent) fire(text string, x, y int) {
+	if e.primary != nil {
+		e.primary(text, x, y)
+	}
+	for _, f := range e.funcs() {
+		f.(func(string, int, int))(text, x, y)
+	}
+}
+
+// TreeNodeEvent is an event that concerns a node of a TreeView, e.g. the node
+// being expanded.
+type TreeNodeEvent struct {
+	primary func(*TreeNode)
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *TreeNodeEvent) Subscribe(f func(*TreeNode)) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *TreeNodeEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+func (e *TreeNodeEvent) fire(n *TreeNode) {
+	if e.primary != nil {
+		e.primary(n)
+	}
+	for _, f := range e.funcs() {
+		f.(func(*TreeNode))(n)
+	}
+}
+
+// LabelEditEvent is the event for the user renaming a node of a TreeView. Its
+// handlers return false to reject the new text. The handlers after the first
+// one that returns false are not called.
+type LabelEditEvent struct {
+	primary func(n *TreeNode, text string) bool
+	handlers
+}
+
+// Subscribe adds f to the event and returns the function that removes it.
+func (e *LabelEditEvent) Subscribe(f func(n *TreeNode, text string) bool) (unsubscribe func()) {
+	if f == nil {
+		return func() {}
+	}
+	return e.subscribe(f)
+}
+
+func (e *LabelEditEvent) empty() bool {
+	return e.primary == nil && len(e.list) == 0
+}
+
+// fire returns false if a handler rejected the text.
+func (e *LabelEditEvent) fire(n *TreeNode, text string) bool {
+	if e.primary != nil && !e.primary(n, text) {
+		return false
+	}
+	for _, f := range e.funcs() {
+		if !f.(func(*TreeNode, string) bool)(n, text) {
+			return false
+		}
+	}
+	return true
+}
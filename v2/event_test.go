@@ -0,0 +1,80 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestPrimaryHandlerIsCalledBeforeSubscribers(t *testing.T) {
+	h := UseHeadless()
+
+	var calls []string
+	w := NewWindow()
+	b := NewButton()
+	w.Add(b)
+	b.ClickEvent().Subscribe(func() { calls = append(calls, "first") })
+	b.SetOnClick(func() { calls = append(calls, "primary") })
+	unsubscribe := b.ClickEvent().Subscribe(func() { calls = append(calls, "second") })
+	b.ClickEvent().Subscribe(func() { calls = append(calls, "third") })
+
+	w.SetOnShow(func() {
+		h.Click(b)
+		unsubscribe()
+		unsubscribe()
+		h.Click(b)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, calls, []string{
+		"primary", "first", "second", "third",
+		"primary", "first", "third",
+	})
+}
+
+func TestUnsubscribingDuringDispatchTakesEffectNextTime(t *testing.T) {
+	var e Event
+	calls := 0
+	var unsubscribe func()
+	e.Subscribe(func() { unsubscribe() })
+	unsubscribe = e.Subscribe(func() { calls++ })
+	e.fire()
+	e.fire()
+	check.Eq(t, calls, 1)
+}
+
+func TestAnyHandlerCanPreventClosing(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	var asked []string
+	allow := false
+	w.SetOnCanClose(func() bool {
+		asked = append(asked, "primary")
+		return true
+	})
+	w.CanCloseEvent().Subscribe(func() bool {
+		asked = append(asked, "editor")
+		return allow
+	})
+	w.CanCloseEvent().Subscribe(func() bool {
+		asked = append(asked, "never asked while editor vetoes")
+		return true
+	})
+	closed := 0
+	w.CloseEvent().Subscribe(func() { closed++ })
+	w.SetOnShow(func() {
+		w.Close()
+		check.Eq(t, closed, 0)
+		allow = true
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, closed, 1)
+	check.Eq(t, asked, []string{
+		"primary", "editor",
+		"primary", "editor", "never asked while editor vetoes",
+	})
+}
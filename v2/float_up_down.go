@@ -4,9 +4,8 @@ import (
 	"math"
 	"strconv"
 	"strings"
-	"syscall"
 
-	"github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2/internal/win"
 )
 
 func NewFloatUpDown() *FloatUpDown {
@@ -19,12 +18,12 @@ func NewFloatUpDown() *FloatUpDown {
 
 type FloatUpDown struct {
 	textEditControl
-	upDownHandle  w32.HWND
+	upDownHandle  uintptr
 	value         float64
 	min           float64
 	max           float64
 	precision     int
-	onValueChange func(value float64)
+	onValueChange FloatEvent
 }
 
 var _ Control = (*FloatUpDown)(nil)
@@ -32,7 +31,7 @@ var _ Control = (*FloatUpDown)(nil)
 func (n *FloatUpDown) destroy() {
 	n.textEditControl.destroy()
 	if n.upDownHandle != 0 {
-		w32.DestroyWindow(n.upDownHandle)
+		ui.destroy(n.upDownHandle)
 		n.upDownHandle = 0
 	}
 }
@@ -46,11 +45,15 @@ func (*FloatUpDown) canFocus() bool {
 }
 
 func (n *FloatUpDown) OnTabFocus() func() {
-	return n.onTabFocus
+	return n.onTabFocus.primary
 }
 
 func (n *FloatUpDown) SetOnTabFocus(f func()) {
-	n.onTabFocus = f
+	n.onTabFocus.primary = f
+}
+
+func (n *FloatUpDown) TabFocusEvent() *Event {
+	return &n.onTabFocus
 }
 
 func (*FloatUpDown) eatsTabs() bool {
@@ -93,26 +96,19 @@ func (n *FloatUpDown) create(id int) {
 	n.text = strconv.FormatFloat(n.value, 'f', n.precision, 64)
 	n.textEditControl.create(
 		id,
-		w32.WS_EX_CLIENTEDGE,
+		win.WS_EX_CLIENTEDGE,
 		"EDIT",
-		w32.WS_TABSTOP,
+		win.WS_TABSTOP,
 	)
-	w32.SetWindowSubclass(n.textEditControl.handle, syscall.NewCallback(func(
-		window w32.HWND,
-		msg uint32,
-		wParam, lParam uintptr,
-		subclassID uintptr,
-		refData uintptr,
-	) uintptr {
-		switch msg {
-		case w32.WM_KILLFOCUS:
+	ui.hookControl(n.textEditControl.handle, controlHooks{
+		focusLost: func() {
 			text := n.textEditControl.Text()
 			newText := sanitize(text)
 			if newText != text {
 				n.textEditControl.SetText(newText)
 			}
-			return w32.DefSubclassProc(window, msg, wParam, lParam)
-		case w32.WM_CHAR:
+		},
+		char: func(r rune) bool {
 			// these are the codes sent for the respective edit operations
 			const (
 				selectAll = 1  // for Ctrl+A
@@ -120,37 +116,36 @@ func (n *FloatUpDown) create(id int) {
 				paste     = 22 // Ctrl+V
 				cut       = 24 // Ctrl+X
 			)
-			if '0' <= wParam && wParam <= '9' ||
-				wParam == '+' || wParam == '-' ||
-				wParam == '.' || wParam == ',' ||
-				wParam == 'i' || wParam == 'I' ||
-				wParam == 'n' || wParam == 'N' ||
-				wParam == 'f' || wParam == 'F' ||
-				wParam == w32.VK_RETURN ||
-				wParam == w32.VK_BACK ||
-				wParam == w32.VK_DELETE ||
-				wParam == selectAll ||
-				wParam == copy || wParam == cut || wParam == paste {
-				return w32.DefSubclassProc(window, msg, wParam, lParam)
-			}
-			return 0
-		default:
-			return w32.DefSubclassProc(window, msg, wParam, lParam)
-		}
-	}), 0, 0)
+			allowed := '0' <= r && r <= '9' ||
+				r == '+' || r == '-' ||
+				r == '.' || r == ',' ||
+				r == 'i' || r == 'I' ||
+				r == 'n' || r == 'N' ||
+				r == 'f' || r == 'F' ||
+				r == KeyReturn ||
+				r == KeyBack ||
+				r == KeyDelete ||
+				r == selectAll ||
+				r == copy || r == cut || r == paste
+			// All other characters are swallowed.
+			return !allowed
+		},
+	})
 	var visible uint
 	if !n.hidden {
-		visible = w32.WS_VISIBLE
+		visible = win.WS_VISIBLE
 	}
-	upDown := w32.CreateWindowStr(
-		w32.UPDOWN_CLASS,
-		"",
-		visible|w32.WS_CHILD|
-			w32.UDS_ALIGNRIGHT|w32.UDS_NOTHOUSANDS|w32.UDS_ARROWKEYS,
-		n.x, n.y, n.width, n.height,
-		n.parent.getHandle(), w32.HMENU(id), n.parent.getInstance(), nil,
+	x, y, width, height := n.pixelBounds()
+	upDown := ui.createChild(
+		n.parent.getHandle(),
+		id,
+		0,
+		win.UPDOWN_CLASS,
+		visible|win.WS_CHILD|
+			win.UDS_ALIGNRIGHT|win.UDS_NOTHOUSANDS|win.UDS_ARROWKEYS,
+		x, y, width, height,
 	)
-	w32.SendMessage(upDown, w32.UDM_SETBUDDY, uintptr(n.handle), 0)
+	ui.setUpDownBuddy(upDown, n.handle)
 	n.upDownHandle = upDown
 }
 
@@ -181,12 +176,9 @@ func (n *FloatUpDown) SetSize(width, height int) {
 func (n *FloatUpDown) SetBounds(x, y, width, height int) {
 	n.textEditControl.SetBounds(x, y, width, height)
 	if n.upDownHandle != 0 {
-		w32.SetWindowPos(
-			n.upDownHandle, 0,
-			n.x, n.y, n.width, n.height,
-			w32.SWP_NOOWNERZORDER|w32.SWP_NOZORDER,
-		)
-		w32.SendMessage(n.upDownHandle, w32.UDM_SETBUDDY, uintptr(n.handle), 0)
+		x, y, width, height := n.pixelBounds()
+		ui.setBounds(n.upDownHandle, x, y, width, height)
+		ui.setUpDownBuddy(n.upDownHandle, n.handle)
 	}
 }
 
@@ -226,7 +218,7 @@ func (n *FloatUpDown) SetValue(f float64) {
 		n.value = n.max
 	}
 	if n.textEditControl.handle != 0 {
-		w32.SetWindowText(
+		ui.setText(
 			n.textEditControl.handle,
 			strconv.FormatFloat(n.value, 'f', n.precision, 64),
 		)
@@ -284,15 +276,19 @@ func (n *FloatUpDown) SetPrecision(p int) {
 }
 
 func (n *FloatUpDown) OnValueChange() func(value float64) {
-	return n.onValueChange
+	return n.onValueChange.primary
 }
 
 func (n *FloatUpDown) SetOnValueChange(f func(value float64)) {
-	n.onValueChange = f
+	n.onValueChange.primary = f
+}
+
+func (n *FloatUpDown) ValueChangeEvent() *FloatEvent {
+	return &n.onValueChange
 }
 
 func (n *FloatUpDown) handleNotification(cmd uintptr) {
-	if cmd == w32.EN_CHANGE && n.onValueChange != nil {
-		n.onValueChange(n.Value())
+	if cmd == win.EN_CHANGE && !n.onValueChange.empty() {
+		n.onValueChange.fire(n.Value())
 	}
 }
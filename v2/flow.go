@@ -0,0 +1,72 @@
+package wui
+
+// NewFlow creates a layout that places controls next to each other and wraps
+// them into a new line when a line is full, like words in a text.
+func NewFlow() *Flow {
+	return &Flow{}
+}
+
+// Flow is a Layout that places controls from left to right in the order in
+// which they were added to the container. When the next control does not fit
+// into the current line anymore, it starts a new line below. All controls
+// keep their preferred sizes and are aligned at the top of their line.
+type Flow struct {
+	layoutBase
+	spacing int
+}
+
+var _ Layout = (*Flow)(nil)
+
+// Spacing returns the space in pixels between two controls and between two
+// lines.
+func (f *Flow) Spacing() int {
+	return f.spacing
+}
+
+// SetSpacing sets the space in pixels between two controls and between two
+// lines.
+func (f *Flow) SetSpacing(s int) {
+	f.spacing = s
+	f.update()
+}
+
+func (f *Flow) arrange(width, height int, items []layoutItem) []Rectangle {
+	x0, y0, width, _ := shrink(width, height, f.margin)
+	bounds := make([]Rectangle, len(items))
+	x, y := 0, 0
+	lineHeight := 0
+	for i, item := range items {
+		w, h := item.preferred.width, item.preferred.height
+		// The first control in a line is always placed, even if it is too
+		// wide, otherwise we would never place it.
+		if x > 0 && x+w > width {
+			x = 0
+			y += lineHeight + f.spacing
+			lineHeight = 0
+		}
+		bounds[i] = Rectangle{X: x0 + x, Y: y0 + y, Width: w, Height: h}
+		x += w + f.spacing
+		lineHeight = maxInt(lineHeight, h)
+	}
+	return bounds
+}
+
+// measure returns as preferred size the size of all controls in one line. The
+// minimum size is the size of a single column of controls.
+func (f *Flow) measure(items []layoutItem) (preferred, min size) {
+	for _, item := range items {
+		preferred.width += item.preferred.width
+		preferred.height = maxInt(preferred.height, item.preferred.height)
+		min.width = maxInt(min.width, item.preferred.width)
+		min.height += item.preferred.height
+	}
+	s := spacingFor(len(items), f.spacing)
+	preferred.width += s
+	min.height += s
+	m := 2 * f.margin
+	preferred.width += m
+	preferred.height += m
+	min.width += m
+	min.height += m
+	return
+}
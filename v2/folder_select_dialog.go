@@ -1,11 +1,5 @@
 package wui
 
-import (
-	"syscall"
-
-	"github.com/gonutz/w32/v2"
-)
-
 type FolderSelectDialog struct {
 	title string
 }
@@ -19,20 +13,9 @@ func (dlg *FolderSelectDialog) SetTitle(title string) {
 }
 
 func (dlg *FolderSelectDialog) Execute(parent *Window) (bool, string) {
-	var owner w32.HWND
+	var owner uintptr
 	if parent != nil {
 		owner = parent.handle
 	}
-
-	title, _ := syscall.UTF16PtrFromString(dlg.title)
-
-	idl := w32.SHBrowseForFolder(&w32.BROWSEINFO{
-		Owner: owner,
-		Title: title,
-		Flags: w32.BIF_NEWDIALOGSTYLE,
-	})
-	folder := w32.SHGetPathFromIDList(idl)
-
-	return idl != 0, folder
-
+	return ui.selectFolder(owner, dlg.title)
 }
@@ -1,10 +1,6 @@
 package wui
 
-import (
-	"errors"
-
-	"github.com/gonutz/w32/v2"
-)
+import "errors"
 
 var NoExactFontMatch = errors.New("wui.NewFont: the desired font was not found in the system, a replacement is used")
 
@@ -13,48 +9,14 @@ var NoExactFontMatch = errors.New("wui.NewFont: the desired font was not found i
 // the system did not find an exact match. In case the creation fails, the
 // returned Font is nil and the error gives the reason.
 func NewFont(desc FontDesc) (*Font, error) {
-	var weight int32 = w32.FW_NORMAL
-	if desc.Bold {
-		weight = w32.FW_BOLD
-	}
-	byteBool := func(b bool) byte {
-		if b {
-			return 1
-		}
-		return 0
-	}
-	logfont := w32.LOGFONT{
-		Height:         int32(desc.Height),
-		Width:          0,
-		Escapement:     0,
-		Orientation:    0,
-		Weight:         weight,
-		Italic:         byteBool(desc.Italic),
-		Underline:      byteBool(desc.Underlined),
-		StrikeOut:      byteBool(desc.StrikedOut),
-		CharSet:        w32.DEFAULT_CHARSET,
-		OutPrecision:   w32.OUT_CHARACTER_PRECIS,
-		ClipPrecision:  w32.CLIP_CHARACTER_PRECIS,
-		Quality:        w32.DEFAULT_QUALITY,
-		PitchAndFamily: w32.DEFAULT_PITCH | w32.FF_DONTCARE,
+	handle, exactMatch := ui.createFont(desc)
+	if handle == 0 {
+		return nil, errors.New("wui.NewFont: unable to create font, please check your description")
 	}
-	logfont.SetFaceName(desc.Name)
-
-	found := false
-	w32.EnumFontFamiliesEx(w32.GetDC(0), logfont, func(*w32.ENUMLOGFONTEX, *w32.ENUMTEXTMETRIC, w32.FontType) bool {
-		found = true
-		return false
-	})
 	var err error
-	if !found {
+	if !exactMatch {
 		err = NoExactFontMatch
 	}
-
-	handle := w32.CreateFontIndirect(&logfont)
-	if handle == 0 {
-		return nil, errors.New("wui.NewFont: unable to create font, please check your description")
-	}
-
 	return &Font{Desc: desc, handle: handle}, err
 }
 
@@ -69,5 +31,70 @@ type FontDesc struct {
 
 type Font struct {
 	Desc   FontDesc
-	handle w32.HFONT
+	handle uintptr
+	// scaled holds the font handles for DPIs other than DefaultDPI. They are
+	// created when first needed.
+	scaled map[int]uintptr
+}
+
+// handleFor returns the font handle for a window with the given DPI.
+func (f *Font) handleFor(dpi int) uintptr {
+	if dpi == DefaultDPI || f.Desc.Height == 0 {
+		return f.handle
+	}
+	if h, ok := f.scaled[dpi]; ok {
+		return h
+	}
+	h, _ := ui.createFont(scaleFont(f.Desc, dpi))
+	if h == 0 {
+		return f.handle
+	}
+	if f.scaled == nil {
+		f.scaled = make(map[int]uintptr)
+	}
+	f.scaled[dpi] = h
+	return h
+}
+
+// releaseScaledFonts deletes the DPI-scaled handles of the fonts that no open
+// window needs anymore. It is called after fonts were replaced and after
+// windows were destroyed or moved to a monitor with another DPI.
+func releaseScaledFonts(fonts ...*Font) {
+	needed := make(map[*Font]map[int]bool)
+	for _, w := range app.windows {
+		if w.handle == 0 {
+			continue
+		}
+		dpi := w.DPI()
+		for _, f := range fontsOf(w) {
+			if needed[f] == nil {
+				needed[f] = make(map[int]bool)
+			}
+			needed[f][dpi] = true
+		}
+	}
+	for _, f := range fonts {
+		if f == nil {
+			continue
+		}
+		for dpi, h := range f.scaled {
+			if !needed[f][dpi] {
+				ui.deleteFont(h)
+				delete(f.scaled, dpi)
+			}
+		}
+	}
+}
+
+// fontsOf returns the fonts of the container and of all its descendants.
+func fontsOf(c Container) []*Font {
+	fonts := []*Font{c.Font()}
+	for _, child := range c.Children() {
+		if con, ok := child.(Container); ok {
+			fonts = append(fonts, fontsOf(con)...)
+		} else if f, ok := child.(interface{ Font() *Font }); ok {
+			fonts = append(fonts, f.Font())
+		}
+	}
+	return fonts
 }
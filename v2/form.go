@@ -0,0 +1,468 @@
+package wui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// LoadWindow creates a window and its controls from a form file. A form file
+// is a JSON document, the top-level object describes the window:
+//
+//	{
+//		"type": "Window",
+//		"properties": {"Title": "Login", "InnerWidth": 300},
+//		"font": {"Name": "Tahoma", "Height": -13},
+//		"children": [
+//			{
+//				"type": "Button",
+//				"name": "okButton",
+//				"properties": {"Text": "OK", "X": 10, "Y": 10}
+//			}
+//		]
+//	}
+//
+// Every object has a "type", the name of the control type, e.g. "Button" or
+// "Panel". Its "properties" are named like the getters and setters of the
+// control type. Enumerations are given as the constant's name, e.g.
+// "HorizontalAnchor": "AnchorMinAndMax". Properties that are left out keep
+// their default values. Containers, i.e. Window, Panel, TabControl and TabPage,
+// have "children". The children of a TabControl are its TabPages.
+//
+// Controls with a "name" are returned in the map, so you can set their event
+// handlers after loading. Use SaveWindow to create form files.
+func LoadWindow(r io.Reader) (*Window, map[string]Control, error) {
+	var root formControl
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		return nil, nil, errors.New("wui.LoadWindow: " + err.Error())
+	}
+	if root.Type != "Window" {
+		return nil, nil, fmt.Errorf(
+			"wui.LoadWindow: top-level type must be Window but is %q", root.Type,
+		)
+	}
+	names := make(map[string]Control)
+	w, err := root.build("window", names)
+	if err != nil {
+		return nil, nil, errors.New("wui.LoadWindow: " + err.Error())
+	}
+	return w.(*Window), names, nil
+}
+
+// SaveWindow writes the window and its controls as a form file, see
+// LoadWindow. names are the names of the controls. Only properties that differ
+// from their defaults are written. Event handlers are not saved.
+func SaveWindow(w io.Writer, window *Window, names map[string]Control) error {
+	nameOf := make(map[Control]string)
+	for name, c := range names {
+		nameOf[c] = name
+	}
+	root, err := formOf(window, nameOf)
+	if err != nil {
+		return errors.New("wui.SaveWindow: " + err.Error())
+	}
+	data, err := json.MarshalIndent(root, "", "\t")
+	if err != nil {
+		return errors.New("wui.SaveWindow: " + err.Error())
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// formControl is a window or control in a form file.
+type formControl struct {
+	Type       string                     `json:"type"`
+	Name       string                     `json:"name,omitempty"`
+	Properties map[string]json.RawMessage `json:"properties,omitempty"`
+	Font       *FontDesc                  `json:"font,omitempty"`
+	Children   []formControl              `json:"children,omitempty"`
+}
+
+// formType describes how a control type is stored in form files.
+type formType struct {
+	create func(f formControl) (interface{}, error)
+	// properties are the names of the properties, in the order in which they
+	// are set. Some properties depend on others, e.g. a Slider's
+	// CursorPosition is clamped to its Min and Max.
+	properties []string
+}
+
+var commonFormProperties = []string{
+	"Enabled",
+	"Visible",
+	"HorizontalAnchor",
+	"VerticalAnchor",
+	"TabIndex",
+	"TabStop",
+	"X",
+	"Y",
+	"Width",
+	"Height",
+	"ToolTip",
+}
+
+func commonFormPropertiesPlus(plus ...string) []string {
+	return append(append([]string{}, commonFormProperties...), plus...)
+}
+
+func creates(f func() interface{}) func(formControl) (interface{}, error) {
+	return func(formControl) (interface{}, error) {
+		return f(), nil
+	}
+}
+
+var formTypes = map[string]formType{
+	"Window": {
+		create: creates(func() interface{} { return NewWindow() }),
+		properties: []string{
+			"InnerX",
+			"InnerY",
+			"InnerWidth",
+			"InnerHeight",
+			"Title",
+			"Alpha",
+			"HasMinButton",
+			"HasMaxButton",
+			"HasCloseButton",
+			"HasBorder",
+			"Resizable",
+			"State",
+		},
+	},
+	"Button": {
+		create:     creates(func() interface{} { return NewButton() }),
+		properties: commonFormPropertiesPlus("Text"),
+	},
+	"Label": {
+		create:     creates(func() interface{} { return NewLabel() }),
+		properties: commonFormPropertiesPlus("Text", "Alignment"),
+	},
+	"CheckBox": {
+		create:     creates(func() interface{} { return NewCheckBox() }),
+		properties: commonFormPropertiesPlus("Text", "Checked"),
+	},
+	"RadioButton": {
+		create:     creates(func() interface{} { return NewRadioButton() }),
+		properties: commonFormPropertiesPlus("Text", "Checked"),
+	},
+	"Slider": {
+		create: creates(func() interface{} { return NewSlider() }),
+		properties: commonFormPropertiesPlus(
+			"ArrowIncrement",
+			"MouseIncrement",
+			"Min",
+			"Max",
+			"CursorPosition",
+			"Orientation",
+			"TickFrequency",
+			"TickPosition",
+			"TicksVisible",
+		),
+	},
+	"Panel": {
+		create:     creates(func() interface{} { return NewPanel() }),
+		properties: commonFormPropertiesPlus("BorderStyle"),
+	},
+	"PaintBox": {
+		create:     creates(func() interface{} { return NewPaintBox() }),
+		properties: commonFormPropertiesPlus(),
+	},
+	"EditLine": {
+		create: creates(func() interface{} { return NewEditLine() }),
+		properties: commonFormPropertiesPlus(
+			"Text",
+			"CharacterLimit",
+			"IsPassword",
+			"ReadOnly",
+		),
+	},
+	"IntUpDown": {
+		create:     creates(func() interface{} { return NewIntUpDown() }),
+		properties: commonFormPropertiesPlus("Min", "Max", "Value"),
+	},
+	"ComboBox": {
+		create:     creates(func() interface{} { return NewComboBox() }),
+		properties: commonFormPropertiesPlus("Items", "SelectedIndex"),
+	},
+	"ProgressBar": {
+		create: creates(func() interface{} { return NewProgressBar() }),
+		properties: commonFormPropertiesPlus(
+			"Vertical",
+			"MovesForever",
+			"Value",
+		),
+	},
+	"FloatUpDown": {
+		create: creates(func() interface{} { return NewFloatUpDown() }),
+		properties: commonFormPropertiesPlus(
+			"Min",
+			"Max",
+			"Precision",
+			"Value",
+		),
+	},
+	"TextEdit": {
+		create: creates(func() interface{} { return NewTextEdit() }),
+		properties: commonFormPropertiesPlus(
+			"Text",
+			"WordWrap",
+			"CharacterLimit",
+			"WritesTabs",
+		),
+	},
+	"StringList": {
+		create:     creates(func() interface{} { return NewStringList() }),
+		properties: commonFormPropertiesPlus("Items", "SelectedIndex"),
+	},
+	"StringTable": {
+		// The headers of a StringTable can only be set when creating it.
+		create: func(f formControl) (interface{}, error) {
+			headers := []string{""}
+			if data, ok := f.Properties["Headers"]; ok {
+				if err := json.Unmarshal(data, &headers); err != nil {
+					return nil, fmt.Errorf("property Headers: %v", err)
+				}
+				if len(headers) == 0 {
+					return nil, errors.New("property Headers: must not be empty")
+				}
+			}
+			return NewStringTable(headers[0], headers[1:]...), nil
+		},
+		properties: commonFormPropertiesPlus("Headers"),
+	},
+	"TreeView": {
+		create: creates(func() interface{} { return NewTreeView() }),
+		properties: commonFormPropertiesPlus(
+			"HasCheckBoxes",
+			"EditableLabels",
+		),
+	},
+	"TabControl": {
+		create: creates(func() interface{} { return NewTabControl() }),
+		properties: commonFormPropertiesPlus(
+			"SelectedIndex",
+			"ClosableTabs",
+		),
+	},
+	"TabPage": {
+		create:     creates(func() interface{} { return NewTabPage() }),
+		properties: []string{"Enabled", "Title"},
+	},
+}
+
+// formTypeName returns the name of the control's type, e.g. "Button" for a
+// *Button.
+func formTypeName(c interface{}) string {
+	return reflect.TypeOf(c).Elem().Name()
+}
+
+// build creates the control and its children. path says where the control is
+// in the file, it is used in error messages for unnamed controls.
+func (f formControl) build(path string, names map[string]Control) (interface{}, error) {
+	where := f.Type + " at " + path
+	if f.Name != "" {
+		where = fmt.Sprintf("%s %q", f.Type, f.Name)
+	}
+	fail := func(format string, a ...interface{}) error {
+		return errors.New(where + ": " + fmt.Sprintf(format, a...))
+	}
+
+	t, ok := formTypes[f.Type]
+	if !ok {
+		return nil, fail("unknown control type %q", f.Type)
+	}
+	c, err := t.create(f)
+	if err != nil {
+		return nil, fail("%v", err)
+	}
+
+	known := make(map[string]bool)
+	for _, p := range t.properties {
+		known[p] = true
+	}
+	var unknown []string
+	for p := range f.Properties {
+		if !known[p] {
+			unknown = append(unknown, p)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fail("unknown property %q", unknown[0])
+	}
+
+	v := reflect.ValueOf(c)
+	for _, p := range t.properties {
+		data, ok := f.Properties[p]
+		if !ok {
+			continue
+		}
+		setter := v.MethodByName("Set" + p)
+		if !setter.IsValid() {
+			continue // The property is only read when creating the control.
+		}
+		arg, err := decodeProperty(data, setter.Type().In(0))
+		if err != nil {
+			return nil, fail("property %s: %v", p, err)
+		}
+		setter.Call([]reflect.Value{arg})
+	}
+
+	if f.Font != nil {
+		fonter, ok := c.(interface{ SetFont(*Font) })
+		if !ok {
+			return nil, fail("%s has no font", f.Type)
+		}
+		font, err := NewFont(*f.Font)
+		if font == nil {
+			return nil, fail("font: %v", err)
+		}
+		fonter.SetFont(font)
+	}
+
+	if len(f.Children) > 0 {
+		parent, ok := c.(Container)
+		if !ok {
+			return nil, fail("%s cannot have children", f.Type)
+		}
+		for i, child := range f.Children {
+			built, err := child.build(fmt.Sprintf("%s.children[%d]", path, i), names)
+			if err != nil {
+				return nil, err
+			}
+			control, ok := built.(Control)
+			if !ok {
+				return nil, errors.New(where + ": a Window cannot be a child")
+			}
+			if _, ok := parent.(*TabControl); ok {
+				if _, ok := control.(*TabPage); !ok {
+					return nil, fail("a TabControl can only have TabPages as children")
+				}
+			}
+			parent.Add(control)
+		}
+	}
+
+	if f.Name != "" {
+		control, ok := c.(Control)
+		if ok {
+			if _, exists := names[f.Name]; exists {
+				return nil, fail("name is used more than once")
+			}
+			names[f.Name] = control
+		}
+	}
+	return c, nil
+}
+
+// isEnum returns true for the enumerations in this package, e.g. Anchor. They
+// are written as their constant names.
+func isEnum(t reflect.Type) bool {
+	_, isStringer := reflect.Zero(t).Interface().(fmt.Stringer)
+	return isStringer && t.Kind() == reflect.Int && t.PkgPath() == formPkgPath
+}
+
+var formPkgPath = reflect.TypeOf(Anchor(0)).PkgPath()
+
+func decodeProperty(data json.RawMessage, t reflect.Type) (reflect.Value, error) {
+	if isEnum(t) {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return reflect.Value{}, err
+		}
+		// Enumerations are consecutive and small, we find them by name.
+		for i := 0; i < 256; i++ {
+			v := reflect.New(t).Elem()
+			v.SetInt(int64(i))
+			if v.Interface().(fmt.Stringer).String() == "wui."+name {
+				return v, nil
+			}
+		}
+		return reflect.Value{}, fmt.Errorf("unknown %s %q", t.Name(), name)
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		if e, ok := err.(*json.UnmarshalTypeError); ok {
+			return reflect.Value{}, fmt.Errorf("%s is not a valid %s", e.Value, t)
+		}
+		return reflect.Value{}, err
+	}
+	return v.Elem(), nil
+}
+
+func encodeProperty(v reflect.Value) (json.RawMessage, error) {
+	if isEnum(v.Type()) {
+		name := strings.TrimPrefix(v.Interface().(fmt.Stringer).String(), "wui.")
+		return json.Marshal(name)
+	}
+	return json.Marshal(v.Interface())
+}
+
+// formOf describes the window or control c and its children.
+func formOf(c interface{}, names map[Control]string) (formControl, error) {
+	f := formControl{Type: formTypeName(c)}
+	t, ok := formTypes[f.Type]
+	if !ok {
+		return f, fmt.Errorf("%s cannot be saved", f.Type)
+	}
+	if control, ok := c.(Control); ok {
+		f.Name = names[control]
+	}
+
+	def, err := t.create(formControl{})
+	if err != nil {
+		return f, err
+	}
+	v := reflect.ValueOf(c)
+	defV := reflect.ValueOf(def)
+	for _, p := range t.properties {
+		value := v.MethodByName(p).Call(nil)[0]
+		if reflect.DeepEqual(value.Interface(), defV.MethodByName(p).Call(nil)[0].Interface()) {
+			continue
+		}
+		data, err := encodeProperty(value)
+		if err != nil {
+			return f, fmt.Errorf("%s property %s: %v", f.Type, p, err)
+		}
+		if f.Properties == nil {
+			f.Properties = make(map[string]json.RawMessage)
+		}
+		f.Properties[p] = data
+	}
+
+	if font := ownFont(c); font != nil {
+		desc := font.Desc
+		f.Font = &desc
+	}
+
+	if parent, ok := c.(Container); ok {
+		for _, child := range parent.Children() {
+			childForm, err := formOf(child, names)
+			if err != nil {
+				return f, err
+			}
+			f.Children = append(f.Children, childForm)
+		}
+	}
+	return f, nil
+}
+
+// ownFont returns the font that was set on c, not the one that it inherits from
+// its parent.
+func ownFont(c interface{}) *Font {
+	switch c := c.(type) {
+	case *Panel:
+		return c.font
+	case *TabControl:
+		return c.font
+	case *TabPage:
+		return c.font
+	case interface{ Font() *Font }:
+		return c.Font()
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package wui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestLoadWindowBuildsControlTree(t *testing.T) {
+	UseHeadless()
+
+	w, names, err := LoadWindow(strings.NewReader(`{
+		"type": "Window",
+		"properties": {"Title": "Login", "InnerWidth": 300, "State": "WindowMaximized"},
+		"font": {"Name": "Tahoma", "Height": -13},
+		"children": [
+			{
+				"type": "Panel",
+				"name": "panel",
+				"properties": {"BorderStyle": "PanelBorderSunken"},
+				"children": [
+					{
+						"type": "Slider",
+						"name": "volume",
+						"properties": {"CursorPosition": 150, "Max": 200}
+					}
+				]
+			},
+			{
+				"type": "Button",
+				"name": "ok",
+				"properties": {"Text": "OK", "HorizontalAnchor": "AnchorMax"}
+			},
+			{"type": "StringTable", "properties": {"Headers": ["a", "b"]}}
+		]
+	}`))
+	check.Eq(t, err, nil)
+
+	check.Eq(t, w.Title(), "Login")
+	check.Eq(t, w.InnerWidth(), 300)
+	check.Eq(t, w.State(), WindowMaximized)
+	check.Eq(t, w.Font().Desc, FontDesc{Name: "Tahoma", Height: -13})
+	check.Eq(t, len(names), 3)
+
+	panel := names["panel"].(*Panel)
+	check.Eq(t, panel.BorderStyle(), PanelBorderSunken)
+	check.Eq(t, panel.Parent(), w)
+	// CursorPosition is applied after Max, no matter the order in the file.
+	check.Eq(t, names["volume"].(*Slider).CursorPosition(), 150)
+	check.Eq(t, names["volume"].Parent(), panel)
+
+	ok := names["ok"].(*Button)
+	check.Eq(t, ok.Text(), "OK")
+	check.Eq(t, ok.HorizontalAnchor(), AnchorMax)
+
+	check.Eq(t, w.Children()[2].(*StringTable).Headers(), []string{"a", "b"})
+}
+
+func TestSavedWindowLoadsTheSame(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	w.SetTitle("Title")
+	w.SetAlpha(200)
+	w.SetHasMaxButton(false)
+	f, _ := NewFont(FontDesc{Name: "Arial", Bold: true})
+	w.SetFont(f)
+
+	p := NewPanel()
+	p.SetBounds(1, 2, 3, 4)
+	w.Add(p)
+	l := NewLabel()
+	l.SetText("label")
+	l.SetAlignment(AlignCenter)
+	l.SetAnchors(AnchorMinAndMax, AnchorCenter)
+	l.SetToolTip("tip")
+	p.Add(l)
+
+	combo := NewComboBox()
+	combo.SetItems([]string{"a", "b"})
+	combo.SetSelectedIndex(1)
+	w.Add(combo)
+
+	up := NewFloatUpDown()
+	up.SetPrecision(3)
+	up.SetValue(1.25)
+	up.SetEnabled(false)
+	w.Add(up)
+
+	var saved bytes.Buffer
+	check.Eq(t, SaveWindow(&saved, w, map[string]Control{"label": l}), nil)
+	check.Eq(t, strings.Contains(saved.String(), `"Alignment": "AlignCenter"`), true)
+
+	loaded, names, err := LoadWindow(bytes.NewReader(saved.Bytes()))
+	check.Eq(t, err, nil)
+	check.Eq(t, len(names), 1)
+	check.Eq(t, names["label"].(*Label).Text(), "label")
+	check.Eq(t, names["label"].(*Label).ToolTip(), "tip")
+
+	var again bytes.Buffer
+	check.Eq(t, SaveWindow(&again, loaded, names), nil)
+	check.Eq(t, again.String(), saved.String())
+}
+
+func TestDefaultWindowIsSavedWithoutProperties(t *testing.T) {
+	UseHeadless()
+
+	var buf bytes.Buffer
+	check.Eq(t, SaveWindow(&buf, NewWindow(), nil), nil)
+	check.Eq(t, buf.String(), "{\n\t\"type\": \"Window\"\n}\n")
+}
+
+func TestLoadWindowErrorsNameControlAndProperty(t *testing.T) {
+	UseHeadless()
+
+	loadErr := func(form string) string {
+		t.Helper()
+		_, _, err := LoadWindow(strings.NewReader(form))
+		if err == nil {
+			t.Fatal("error expected")
+		}
+		return err.Error()
+	}
+
+	check.Eq(t,
+		loadErr(`{"type": "Button"}`),
+		`wui.LoadWindow: top-level type must be Window but is "Button"`,
+	)
+	check.Eq(t,
+		loadErr(`{"type": "Window", "children": [{"type": "Buton"}]}`),
+		`wui.LoadWindow: Buton at window.children[0]: unknown control type "Buton"`,
+	)
+	check.Eq(t,
+		loadErr(`{"type": "Window", "children": [
+			{"type": "Button", "name": "ok", "properties": {"Txet": "OK"}}
+		]}`),
+		`wui.LoadWindow: Button "ok": unknown property "Txet"`,
+	)
+	check.Eq(t,
+		loadErr(`{"type": "Window", "children": [
+			{"type": "Panel", "children": [
+				{"type": "Label", "properties": {"Text": 5}}
+			]}
+		]}`),
+		`wui.LoadWindow: Label at window.children[0].children[0]: property Text: number is not a valid string`,
+	)
+	check.Eq(t,
+		loadErr(`{"type": "Window", "children": [
+			{"type": "Label", "name": "l", "properties": {"Alignment": "AlignTop"}}
+		]}`),
+		`wui.LoadWindow: Label "l": property Alignment: unknown TextAlignment "AlignTop"`,
+	)
+	check.Eq(t,
+		loadErr(`{"type": "Window", "children": [
+			{"type": "Button", "name": "b", "children": [{"type": "Label"}]}
+		]}`),
+		`wui.LoadWindow: Button "b": Button cannot have children`,
+	)
+	check.Eq(t,
+		loadErr(`{"type": "Window", "children": [
+			{"type": "Button", "name": "b"},
+			{"type": "Label", "name": "b"}
+		]}`),
+		`wui.LoadWindow: Label "b": name is used more than once`,
+	)
+}
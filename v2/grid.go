@@ -0,0 +1,324 @@
+package wui
+
+// NewGrid creates a layout that places controls in the cells of a table. Use
+// SetColumns and SetRows to define the sizes of the columns and rows and Place
+// or PlaceSpan to put controls into cells.
+func NewGrid() *Grid {
+	return &Grid{cells: make(map[Control]gridCell)}
+}
+
+// Grid is a Layout that places controls in rows and columns. Each control
+// fills its cell, a control can span multiple rows and columns.
+//
+// Rows and columns have a fixed size (GridFixed), are as large as their largest
+// control (GridAuto) or share the remaining space (GridStar). Rows and columns
+// that are not defined with SetRows and SetColumns are GridStar(1).
+//
+// Children that are not placed in the grid keep their bounds.
+type Grid struct {
+	layoutBase
+	columns []GridSize
+	rows    []GridSize
+	spacing int
+	cells   map[Control]gridCell
+}
+
+var _ Layout = (*Grid)(nil)
+
+type gridCell struct {
+	row, column, rowSpan, columnSpan int
+}
+
+// GridSize is the size of a Grid's row or column, see GridAuto, GridStar and
+// GridFixed.
+type GridSize struct {
+	kind  gridSizeKind
+	value float64
+}
+
+type gridSizeKind int
+
+const (
+	gridStar gridSizeKind = iota
+	gridAuto
+	gridFixed
+)
+
+// GridAuto makes a row or column as large as the preferred size of its largest
+// control. Controls spanning multiple rows or columns enlarge the auto rows or
+// columns that they span, if necessary.
+func GridAuto() GridSize {
+	return GridSize{kind: gridAuto}
+}
+
+// GridStar makes a row or column share the space that is left after the fixed
+// and auto rows or columns are placed. The space is shared proportionally to
+// the weights, e.g. a column with weight 2 is twice as wide as a column with
+// weight 1. Star rows and columns do not shrink below the minimum size of
+// their controls.
+func GridStar(weight float64) GridSize {
+	return GridSize{kind: gridStar, value: weight}
+}
+
+// GridFixed makes a row or column the given number of pixels large.
+func GridFixed(pixels int) GridSize {
+	return GridSize{kind: gridFixed, value: float64(pixels)}
+}
+
+func (g *Grid) Columns() []GridSize {
+	return g.columns
+}
+
+func (g *Grid) SetColumns(columns ...GridSize) {
+	g.columns = columns
+	g.update()
+}
+
+func (g *Grid) Rows() []GridSize {
+	return g.rows
+}
+
+func (g *Grid) SetRows(rows ...GridSize) {
+	g.rows = rows
+	g.update()
+}
+
+// Spacing returns the space in pixels between rows and between columns.
+func (g *Grid) Spacing() int {
+	return g.spacing
+}
+
+// SetSpacing sets the space in pixels between rows and between columns.
+func (g *Grid) SetSpacing(s int) {
+	g.spacing = s
+	g.update()
+}
+
+// Place puts the control in the cell at the given row and column. Indices
+// start at 0.
+func (g *Grid) Place(c Control, row, column int) {
+	g.PlaceSpan(c, row, column, 1, 1)
+}
+
+// PlaceSpan puts the control in the cell at the given row and column and
+// makes it span rowSpan rows and columnSpan columns. Indices start at 0.
+func (g *Grid) PlaceSpan(c Control, row, column, rowSpan, columnSpan int) {
+	g.cells[c] = gridCell{
+		row:        maxInt(0, row),
+		column:     maxInt(0, column),
+		rowSpan:    maxInt(1, rowSpan),
+		columnSpan: maxInt(1, columnSpan),
+	}
+	g.update()
+}
+
+// Unplace removes the control from the grid. It keeps its current bounds.
+func (g *Grid) Unplace(c Control) {
+	delete(g.cells, c)
+	g.update()
+}
+
+func (g *Grid) forget(c Control) {
+	g.layoutBase.forget(c)
+	delete(g.cells, c)
+}
+
+// Cell returns the row, column and spans of the control. ok is false if the
+// control is not placed in the grid.
+func (g *Grid) Cell(c Control) (row, column, rowSpan, columnSpan int, ok bool) {
+	cell, ok := g.cells[c]
+	return cell.row, cell.column, cell.rowSpan, cell.columnSpan, ok
+}
+
+// gridTrack is a row or a column.
+type gridTrack struct {
+	size      GridSize
+	preferred int
+	min       int
+}
+
+// gridSpan is the extent of an item along one axis.
+type gridSpan struct {
+	start, count   int
+	preferred, min int
+}
+
+// tracks computes the preferred and minimum sizes of the rows or columns.
+func (g *Grid) tracks(defined []GridSize, spans []gridSpan) []gridTrack {
+	n := len(defined)
+	for _, s := range spans {
+		n = maxInt(n, s.start+s.count)
+	}
+	tracks := make([]gridTrack, n)
+	for i := range tracks {
+		tracks[i].size = GridStar(1)
+		if i < len(defined) {
+			tracks[i].size = defined[i]
+		}
+		if tracks[i].size.kind == gridFixed {
+			tracks[i].preferred = int(tracks[i].size.value)
+			tracks[i].min = tracks[i].preferred
+		}
+	}
+
+	for _, s := range spans {
+		if s.count == 1 {
+			t := &tracks[s.start]
+			if t.size.kind != gridFixed {
+				t.preferred = maxInt(t.preferred, s.preferred)
+				t.min = maxInt(t.min, s.min)
+			}
+		}
+	}
+
+	// Items that span multiple tracks enlarge the auto tracks in their span if
+	// they do not fit.
+	for _, s := range spans {
+		if s.count == 1 {
+			continue
+		}
+		have := (s.count - 1) * g.spacing
+		var autos []int
+		for i := s.start; i < s.start+s.count; i++ {
+			have += tracks[i].preferred
+			if tracks[i].size.kind == gridAuto {
+				autos = append(autos, i)
+			}
+		}
+		if have < s.preferred && len(autos) > 0 {
+			weights := make([]float64, len(autos))
+			for i := range weights {
+				weights[i] = 1
+			}
+			for i, extra := range share(s.preferred-have, weights) {
+				tracks[autos[i]].preferred += extra
+			}
+		}
+	}
+
+	for i := range tracks {
+		if tracks[i].size.kind == gridAuto {
+			tracks[i].min = tracks[i].preferred
+		}
+	}
+	return tracks
+}
+
+// layoutTracks returns the sizes of the tracks for the available space.
+func layoutTracks(tracks []gridTrack, available int) []int {
+	sizes := make([]int, len(tracks))
+	rest := available
+	var stars []int
+	for i, t := range tracks {
+		if t.size.kind == gridStar {
+			stars = append(stars, i)
+		} else {
+			sizes[i] = t.preferred
+			rest -= t.preferred
+		}
+	}
+	weights := make([]float64, len(stars))
+	mins := make([]int, len(stars))
+	for i, s := range stars {
+		weights[i] = tracks[s].size.value
+		mins[i] = tracks[s].min
+	}
+	for i, size := range shareAtLeast(rest, weights, mins) {
+		sizes[stars[i]] = size
+	}
+	return sizes
+}
+
+func (g *Grid) spans(items []layoutItem) (rows, columns []gridSpan) {
+	for _, item := range items {
+		cell, ok := g.cells[item.control]
+		if !ok {
+			continue
+		}
+		rows = append(rows, gridSpan{
+			start:     cell.row,
+			count:     cell.rowSpan,
+			preferred: item.preferred.height,
+			min:       item.min.height,
+		})
+		columns = append(columns, gridSpan{
+			start:     cell.column,
+			count:     cell.columnSpan,
+			preferred: item.preferred.width,
+			min:       item.min.width,
+		})
+	}
+	return
+}
+
+func (g *Grid) arrange(width, height int, items []layoutItem) []Rectangle {
+	x0, y0, width, height := shrink(width, height, g.margin)
+	rowSpans, columnSpans := g.spans(items)
+	rows := g.tracks(g.rows, rowSpans)
+	columns := g.tracks(g.columns, columnSpans)
+	rowSizes := layoutTracks(rows, height-spacingFor(len(rows), g.spacing))
+	columnSizes := layoutTracks(columns, width-spacingFor(len(columns), g.spacing))
+	rowStarts := starts(y0, rowSizes, g.spacing)
+	columnStarts := starts(x0, columnSizes, g.spacing)
+
+	bounds := make([]Rectangle, len(items))
+	for i, item := range items {
+		cell, ok := g.cells[item.control]
+		if !ok {
+			x, y, w, h := item.control.Bounds()
+			bounds[i] = Rectangle{X: x, Y: y, Width: w, Height: h}
+			continue
+		}
+		lastRow := cell.row + cell.rowSpan - 1
+		lastColumn := cell.column + cell.columnSpan - 1
+		bounds[i] = Rectangle{
+			X:      columnStarts[cell.column],
+			Y:      rowStarts[cell.row],
+			Width:  columnStarts[lastColumn] + columnSizes[lastColumn] - columnStarts[cell.column],
+			Height: rowStarts[lastRow] + rowSizes[lastRow] - rowStarts[cell.row],
+		}
+	}
+	return bounds
+}
+
+func (g *Grid) measure(items []layoutItem) (preferred, min size) {
+	rowSpans, columnSpans := g.spans(items)
+	rows := g.tracks(g.rows, rowSpans)
+	columns := g.tracks(g.columns, columnSpans)
+	preferred.width, min.width = sumTracks(columns, g.spacing)
+	preferred.height, min.height = sumTracks(rows, g.spacing)
+	m := 2 * g.margin
+	preferred.width += m
+	preferred.height += m
+	min.width += m
+	min.height += m
+	return
+}
+
+func sumTracks(tracks []gridTrack, spacing int) (preferred, min int) {
+	for _, t := range tracks {
+		preferred += t.preferred
+		min += t.min
+	}
+	s := spacingFor(len(tracks), spacing)
+	return preferred + s, min + s
+}
+
+// spacingFor returns the space between n items.
+func spacingFor(n, spacing int) int {
+	if n <= 1 {
+		return 0
+	}
+	return (n - 1) * spacing
+}
+
+// starts returns the positions of items of the given sizes that are placed one
+// after the other, beginning at start.
+func starts(start int, sizes []int, spacing int) []int {
+	positions := make([]int, len(sizes))
+	for i, size := range sizes {
+		positions[i] = start
+		start += size + spacing
+	}
+	return positions
+}
@@ -0,0 +1,2037 @@
+package wui
+
+import (
+	"bytes"
+	"image"
+	"os"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gonutz/wui/v2/internal/win"
+)
+
+// UseHeadless replaces the native backend with a new, empty Headless backend.
+// From then on all windows and controls only exist in memory. This lets you
+// run and test your user interface code without a display, e.g. in go test on
+// a build server. On platforms other than Windows this is the default.
+//
+// Call UseHeadless before creating any windows, e.g. at the start of each
+// test. Windows that are still open in the old backend are forgotten.
+// Window.Show blocks until the window is closed so you typically put the test
+// code into the window's OnShow callback and call Window.Close at its end.
+func UseHeadless() *Headless {
+	h := newHeadless()
+	ui = h
+	app = application{}
+	return h
+}
+
+// Headless is a backend that records the state of all windows and controls in
+// memory instead of creating them on the screen. It also has functions to
+// simulate user input.
+type Headless struct {
+	// uiGoroutine is accessed atomically, see isUIThread. It comes first to be
+	// 64-bit aligned on 32-bit platforms.
+	uiGoroutine   uint64
+	handles       map[uintptr]*headlessHandle
+	menus         map[uintptr]*headlessMenu
+	fonts         map[uintptr]FontDesc
+	lastHandle    uintptr
+	focusHandle   uintptr
+	mouseHandle   uintptr
+	notifications []HeadlessNotification
+	wakeUp        chan struct{}
+	monitorDPI    int
+	clipboard     []clipboardItem
+	clipFormats   map[string]ClipboardFormat
+	clock         time.Duration
+	timers        []headlessTimer
+	trayIcons     map[headlessTrayKey]*HeadlessTrayIcon
+	hotKeys       map[hotKeyKeys]headlessHotKey
+	choosePopup   func(items []string) string
+	popupX        int // Screen position of the last popup menu.
+	popupY        int
+}
+
+// HeadlessTrayIcon is the state of a TrayIcon in the Headless backend.
+type HeadlessTrayIcon struct {
+	Icon    uintptr
+	ToolTip string
+	// Notifications are all notifications that were shown for the icon.
+	Notifications []HeadlessTrayNotification
+}
+
+// HeadlessTrayNotification is a notification that was shown with
+// TrayIcon.ShowNotification.
+type HeadlessTrayNotification struct {
+	Title string
+	Text  string
+	Kind  NotificationKind
+}
+
+// HeadlessTreeItem is the state of a TreeNode's native item in the Headless
+// backend.
+type HeadlessTreeItem struct {
+	Parent   uintptr // 0 for top-level items.
+	Children []uintptr
+	Text     string
+	// Image is the index into the TreeView's images, -1 means no image.
+	Image int
+	// HasChildren is true if the item shows an expand button.
+	HasChildren bool
+	Checked     bool
+	Expanded    bool
+}
+
+// HeadlessTab is the state of a TabControl's native tab in the Headless
+// backend.
+type HeadlessTab struct {
+	Text string
+	// Image is the index into the TabControl's images, -1 means no image.
+	Image    int
+	Closable bool
+}
+
+// HeadlessStatusPart is the state of a part of a native StatusBar in the
+// Headless backend.
+type HeadlessStatusPart struct {
+	// Right is the right edge of the part in pixels, -1 means the part reaches
+	// the border of the status bar.
+	Right int
+	Text  string
+	Icon  uintptr
+}
+
+type headlessTrayKey struct {
+	window uintptr
+	id     uint
+}
+
+// headlessHotKey is the owner of a registered hot key. Hot keys are
+// system-wide, window 0 stands for another program.
+type headlessHotKey struct {
+	window uintptr
+	id     int
+}
+
+// HeadlessControl is the state of a window or control in the Headless backend.
+type HeadlessControl struct {
+	Handle    uintptr
+	Parent    uintptr
+	ClassName string
+	Style     uint
+	ExStyle   uint
+	Text      string
+	X         int
+	Y         int
+	Width     int
+	Height    int
+	Enabled   bool
+	Visible   bool
+	Checked   bool
+	// Font is the description of the font that was set on the control, as it
+	// was created for the control's DPI.
+	Font FontDesc
+	// ErrorText is the message that an invalid edit control shows.
+	ErrorText string
+}
+
+// HeadlessNotification is a notification that a control sent to its window,
+// e.g. a click on a Button or a text change in an EditLine. Code is the
+// notification code as defined in the Windows API, e.g. BN_CLICKED or
+// EN_CHANGE.
+type HeadlessNotification struct {
+	Handle uintptr
+	Code   uintptr
+}
+
+type headlessHandle struct {
+	HeadlessControl
+	id     int
+	window *Window // The top-level window that this handle belongs to.
+	hooks  []controlHooks
+
+	// Top-level windows.
+	state        WindowState
+	restore      [4]int
+	menu         uintptr
+	accelerators []accelerator
+
+	// Edit controls.
+	selStart, selEnd int
+	limit            int
+	readOnly         bool
+	passwordChar     rune
+
+	// Combo boxes, list boxes, list views.
+	items    []string
+	selected int
+	columns  []string
+	rows     [][]string
+
+	// Sliders, progress bars and up-downs.
+	min, max, pos int
+	marquee       bool
+	buddy         uintptr
+
+	// Tree views.
+	treeItems    map[uintptr]*HeadlessTreeItem
+	treeRoots    []uintptr
+	treeSelected uintptr
+	treeImages   []*Image
+
+	// Tab controls, their selected tab is in selected.
+	tabs      []HeadlessTab
+	tabImages []*Image
+
+	// Status bars.
+	statusParts []HeadlessStatusPart
+
+	// Tooltips.
+	tools           []uintptr
+	toolTipDelay    time.Duration
+	toolTipDuration time.Duration
+	toolTipWidth    int
+}
+
+type headlessTimer struct {
+	window, id uintptr
+	interval   time.Duration
+	due        time.Duration
+}
+
+type headlessMenu struct {
+	items []menuItemInfo
+}
+
+func newHeadless() *Headless {
+	return &Headless{
+		handles:     make(map[uintptr]*headlessHandle),
+		fonts:       make(map[uintptr]FontDesc),
+		menus:       make(map[uintptr]*headlessMenu),
+		wakeUp:      make(chan struct{}, 1),
+		clipFormats: make(map[string]ClipboardFormat),
+		trayIcons:   make(map[headlessTrayKey]*HeadlessTrayIcon),
+		hotKeys:     make(map[hotKeyKeys]headlessHotKey),
+	}
+}
+
+func (h *Headless) newHandle() uintptr {
+	h.lastHandle++
+	return h.lastHandle
+}
+
+// Control returns the current state of the window or control with the given
+// handle. It returns false if the handle does not exist, e.g. because the
+// control was removed from its parent.
+func (h *Headless) Control(handle uintptr) (HeadlessControl, bool) {
+	if c, ok := h.handles[handle]; ok {
+		return c.HeadlessControl, true
+	}
+	return HeadlessControl{}, false
+}
+
+// Notifications returns all notifications that controls sent to their windows
+// so far, in order.
+func (h *Headless) Notifications() []HeadlessNotification {
+	return append([]HeadlessNotification(nil), h.notifications...)
+}
+
+// Click simulates a left mouse click on a button, check box or radio button.
+// Disabled and invisible controls are not clicked.
+func (h *Headless) Click(c Control) {
+	b := h.handles[c.Handle()]
+	if b == nil || !b.Enabled || !b.Visible || b.ClassName != "BUTTON" {
+		return
+	}
+	h.focus(b.Handle)
+	switch b.Style & 0xF {
+	case win.BS_AUTOCHECKBOX:
+		b.Checked = !b.Checked
+	case win.BS_AUTORADIOBUTTON:
+		for _, sibling := range h.handles {
+			if sibling.Parent == b.Parent &&
+				sibling.ClassName == "BUTTON" &&
+				sibling.Style&0xF == win.BS_AUTORADIOBUTTON {
+				sibling.Checked = false
+			}
+		}
+		b.Checked = true
+	}
+	h.notify(b, win.BN_CLICKED)
+}
+
+// Type simulates the user typing the given text into an edit control. Each
+// character goes through the same handling that wui installs for the native
+// controls.
+func (h *Headless) Type(c Control, text string) {
+	e := h.handles[c.Handle()]
+	if e == nil || !e.Enabled || !e.Visible {
+		return
+	}
+	h.focus(e.Handle)
+	for _, r := range text {
+		h.typeChar(e, r)
+	}
+}
+
+func (h *Headless) typeChar(e *headlessHandle, r rune) {
+	for i := len(e.hooks) - 1; i >= 0; i-- {
+		if e.hooks[i].char != nil && e.hooks[i].char(r) {
+			return
+		}
+	}
+	if e.ClassName != "EDIT" || e.readOnly {
+		return
+	}
+	text := []rune(e.Text)
+	start, end := e.selStart, e.selEnd
+	var insert []rune
+	switch {
+	case r == '\b':
+		if start == end {
+			if start == 0 {
+				return
+			}
+			start--
+		}
+	case r == '\r' || r == '\n':
+		if e.Style&win.ES_MULTILINE == 0 {
+			return
+		}
+		insert = []rune("\r\n")
+	case r == '\t':
+		if e.Style&win.ES_MULTILINE == 0 {
+			return
+		}
+		insert = []rune{r}
+	case r < ' ' || r == 127:
+		return
+	case e.Style&win.ES_NUMBER != 0 && !('0' <= r && r <= '9'):
+		return
+	default:
+		insert = []rune{r}
+	}
+	if len(text)-(end-start)+len(insert) > e.limit {
+		return
+	}
+	newText := append(append(append([]rune{}, text[:start]...), insert...), text[end:]...)
+	e.Text = string(newText)
+	e.selStart = start + len(insert)
+	e.selEnd = e.selStart
+	h.notify(e, win.EN_CHANGE)
+}
+
+// MouseMove simulates the user moving the mouse to x,y in the control's
+// coordinates. If the mouse was over another control before, that control is
+// notified that the mouse left it.
+func (h *Headless) MouseMove(c Control, x, y int) {
+	if h.mouseHandle != c.Handle() {
+		if old := h.handles[h.mouseHandle]; old != nil {
+			for i := len(old.hooks) - 1; i >= 0; i-- {
+				if old.hooks[i].mouseLeave != nil {
+					old.hooks[i].mouseLeave()
+				}
+			}
+		}
+	}
+	h.mouseHandle = c.Handle()
+	h.eachHook(c, func(hooks controlHooks) {
+		if hooks.mouseMove != nil {
+			hooks.mouseMove(x, y)
+		}
+	})
+}
+
+// MouseDown simulates the user pressing a mouse button over the control.
+func (h *Headless) MouseDown(c Control, b MouseButton, x, y int) {
+	h.eachHook(c, func(hooks controlHooks) {
+		if hooks.mouseDown != nil {
+			hooks.mouseDown(b, x, y)
+		}
+	})
+}
+
+// MouseUp simulates the user releasing a mouse button over the control.
+func (h *Headless) MouseUp(c Control, b MouseButton, x, y int) {
+	h.eachHook(c, func(hooks controlHooks) {
+		if hooks.mouseUp != nil {
+			hooks.mouseUp(b, x, y)
+		}
+	})
+}
+
+// DoubleClick simulates the user double-clicking the control.
+func (h *Headless) DoubleClick(c Control, b MouseButton, x, y int) {
+	h.eachHook(c, func(hooks controlHooks) {
+		if hooks.doubleClick != nil {
+			hooks.doubleClick(b, x, y)
+		}
+	})
+}
+
+// MouseWheel simulates the user turning the mouse wheel over the control.
+func (h *Headless) MouseWheel(c Control, x, y int, delta float64) {
+	h.eachHook(c, func(hooks controlHooks) {
+		if hooks.mouseWheel != nil {
+			hooks.mouseWheel(x, y, delta)
+		}
+	})
+}
+
+// KeyDown simulates the user pressing a key while the control has the focus.
+func (h *Headless) KeyDown(c Control, key Key) {
+	h.eachHook(c, func(hooks controlHooks) {
+		if hooks.keyDown != nil {
+			hooks.keyDown(int(key))
+		}
+	})
+}
+
+// KeyUp simulates the user releasing a key while the control has the focus.
+func (h *Headless) KeyUp(c Control, key Key) {
+	h.eachHook(c, func(hooks controlHooks) {
+		if hooks.keyUp != nil {
+			hooks.keyUp(int(key))
+		}
+	})
+}
+
+// eachHook calls f for the hooks of the enabled and visible control, the
+// latest hooks first.
+func (h *Headless) eachHook(c Control, f func(controlHooks)) {
+	e := h.handles[c.Handle()]
+	if e == nil || !e.Enabled || !e.Visible {
+		return
+	}
+	for i := len(e.hooks) - 1; i >= 0; i-- {
+		f(e.hooks[i])
+	}
+}
+
+// Drop simulates the user dropping files or text onto a window or control. The
+// position in d is relative to target. If target does not accept the data, its
+// parents are tried, just like on the screen. Drop returns false if the data
+// was rejected.
+func (h *Headless) Drop(target interface{ Handle() uintptr }, d DragData) bool {
+	c := h.handles[target.Handle()]
+	if c == nil || c.window == nil {
+		return false
+	}
+	handlers, d := c.window.dropTargetFor(c.Handle, d, func(handle uintptr) (int, int) {
+		x, y := d.X, d.Y
+		for e := c; e != nil && e.Handle != handle; e = h.handles[e.Parent] {
+			x += e.X
+			y += e.Y
+		}
+		return x, y
+	})
+	if handlers == nil {
+		return false
+	}
+	handlers.drop(d)
+	return true
+}
+
+// Select simulates the user selecting the item with the given index in a
+// ComboBox, StringList or StringTable.
+func (h *Headless) Select(c Control, index int) {
+	l := h.handles[c.Handle()]
+	if l == nil || !l.Enabled || !l.Visible {
+		return
+	}
+	h.focus(l.Handle)
+	if t, ok := c.(*StringTable); ok {
+		if 0 <= index && index < len(l.rows) {
+			l.selected = index
+			t.newItemSelected(index)
+		}
+		return
+	}
+	if 0 <= index && index < len(l.items) {
+		l.selected = index
+		if l.ClassName == "ComboBox" {
+			h.notify(l, win.CBN_SELCHANGE)
+		} else {
+			h.notify(l, win.LBN_SELCHANGE)
+		}
+	}
+}
+
+// TreeItem returns the native item of the TreeNode. It returns false if the
+// node is not shown.
+func (h *Headless) TreeItem(n *TreeNode) (HeadlessTreeItem, bool) {
+	if n.tree == nil {
+		return HeadlessTreeItem{}, false
+	}
+	if c := h.handles[n.tree.handle]; c != nil {
+		if item := c.treeItems[n.handle]; item != nil {
+			return *item, true
+		}
+	}
+	return HeadlessTreeItem{}, false
+}
+
+// treeItem returns the native item of the node if the user can reach it.
+func (h *Headless) treeItem(n *TreeNode) *HeadlessTreeItem {
+	if n.tree == nil {
+		return nil
+	}
+	c := h.handles[n.tree.handle]
+	if c == nil || !c.Enabled || !c.Visible {
+		return nil
+	}
+	return c.treeItems[n.handle]
+}
+
+// ExpandNode simulates the user clicking the expand button of the TreeNode.
+func (h *Headless) ExpandNode(n *TreeNode) {
+	item := h.treeItem(n)
+	if item == nil || item.Expanded || !item.HasChildren {
+		return
+	}
+	n.loadChildren()
+	if item.HasChildren {
+		item.Expanded = true
+		n.tree.nodeExpanded(n, true)
+	}
+}
+
+// CollapseNode simulates the user clicking the collapse button of the
+// TreeNode.
+func (h *Headless) CollapseNode(n *TreeNode) {
+	if item := h.treeItem(n); item != nil && item.Expanded {
+		item.Expanded = false
+		n.tree.nodeExpanded(n, false)
+	}
+}
+
+// SelectNode simulates the user clicking the TreeNode's text.
+func (h *Headless) SelectNode(n *TreeNode) {
+	if item := h.treeItem(n); item != nil {
+		c := h.handles[n.tree.handle]
+		h.focus(c.Handle)
+		c.treeSelected = n.handle
+		n.tree.nodeSelected(n)
+	}
+}
+
+// CheckNode simulates the user clicking the TreeNode's check box.
+func (h *Headless) CheckNode(n *TreeNode, checked bool) {
+	item := h.treeItem(n)
+	if item == nil || h.handles[n.tree.handle].Style&win.TVS_CHECKBOXES == 0 {
+		return
+	}
+	item.Checked = checked
+	n.tree.nodeChecked(n, checked)
+}
+
+// EditLabel simulates the user renaming the TreeNode. It returns false if the
+// TreeView's labels are not editable or if OnLabelEdit rejected the text.
+func (h *Headless) EditLabel(n *TreeNode, text string) bool {
+	item := h.treeItem(n)
+	if item == nil || h.handles[n.tree.handle].Style&win.TVS_EDITLABELS == 0 {
+		return false
+	}
+	if !n.tree.labelEdited(n, text) {
+		return false
+	}
+	item.Text = text
+	return true
+}
+
+// Tabs returns the native tabs of the TabControl.
+func (h *Headless) Tabs(t *TabControl) []HeadlessTab {
+	if c := h.handles[t.handle]; c != nil {
+		return c.tabs
+	}
+	return nil
+}
+
+// ClickTab simulates the user clicking the tab with the given index.
+func (h *Headless) ClickTab(t *TabControl, index int) {
+	c := h.handles[t.handle]
+	if c == nil || !c.Enabled || !c.Visible {
+		return
+	}
+	h.focus(c.Handle)
+	if 0 <= index && index < len(c.tabs) && index != c.selected {
+		c.selected = index
+		t.tabSelected(index)
+	}
+}
+
+// CloseTab simulates the user clicking the close button of the tab with the
+// given index. Only closable tabs have a close button.
+func (h *Headless) CloseTab(t *TabControl, index int) {
+	c := h.handles[t.handle]
+	if c == nil || !c.Enabled || !c.Visible {
+		return
+	}
+	if 0 <= index && index < len(c.tabs) && c.tabs[index].Closable {
+		t.closeClicked(index)
+	}
+}
+
+// StatusParts returns the native parts of the StatusBar.
+func (h *Headless) StatusParts(s *StatusBar) []HeadlessStatusPart {
+	if c := h.handles[s.handle]; c != nil {
+		return c.statusParts
+	}
+	return nil
+}
+
+// ClickStatusPart simulates the user clicking the StatusBar's part with the
+// given index.
+func (h *Headless) ClickStatusPart(s *StatusBar, index int) {
+	c := h.handles[s.handle]
+	if c == nil || !c.Enabled || !c.Visible {
+		return
+	}
+	if 0 <= index && index < len(c.statusParts) {
+		s.partClicked(index)
+	}
+}
+
+// Slide simulates the user dragging the Slider's cursor to the given position.
+func (h *Headless) Slide(s *Slider, pos int) {
+	c := h.handles[s.Handle()]
+	if c == nil || !c.Enabled || !c.Visible {
+		return
+	}
+	h.setSliderPosition(c.Handle, pos)
+	if c.window != nil {
+		c.window.sliderScrolled(c.Handle, win.TB_THUMBTRACK)
+	}
+}
+
+// ClickMenu simulates the user clicking the given menu item in the window's
+// menu bar. Nothing happens if the item or one of its menus is disabled.
+func (h *Headless) ClickMenu(m *MenuString) {
+	for _, n := range m.natives {
+		if n.tree.isBar {
+			if c := h.handles[n.tree.window.handle]; c != nil {
+				if h.menuBarHasEnabled(c.menu, n.id) {
+					n.tree.window.menuClicked(int(n.id))
+				}
+			}
+			return
+		}
+	}
+}
+
+// menuBarHasEnabled returns true if the item with the ID is in the menu or its
+// sub-menus and neither the item nor any of its menus are disabled.
+func (h *Headless) menuBarHasEnabled(menu uintptr, id uint) bool {
+	if m := h.menus[menu]; m != nil {
+		for _, item := range m.items {
+			if item.disabled {
+				continue
+			}
+			if item.subMenu != 0 && h.menuBarHasEnabled(item.subMenu, id) ||
+				item.subMenu == 0 && item.id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OpenMenu simulates the user opening the given sub-menu of the window's menu
+// bar, which calls its OnOpen function.
+func (h *Headless) OpenMenu(m *Menu) {
+	for _, n := range m.natives {
+		if n.tree.isBar && n.parent != nil {
+			h.menuOpened(n.tree.window.handle, n.handle)
+			return
+		}
+	}
+}
+
+// ToolTip returns the tooltip text that appears when the mouse hovers over the
+// control. It returns false if the control has no tooltip.
+func (h *Headless) ToolTip(c Control) (string, bool) {
+	handle := h.handles[c.Handle()]
+	if handle == nil || handle.window == nil {
+		return "", false
+	}
+	w := handle.window
+	if tip := h.handles[w.toolTipHandle]; tip != nil {
+		for _, tool := range tip.tools {
+			if tool == handle.Handle {
+				return w.toolTipText(tool), true
+			}
+		}
+	}
+	return "", false
+}
+
+// SetPopupMenuChoice sets the function that simulates the user's choice in
+// popup menus, e.g. the menu of a TrayIcon. It is passed the texts of all
+// clickable items, including those in sub-menus, and returns the text of the
+// item to click, or "" to close the menu without a click. Without a choice
+// function, popup menus are closed right away.
+func (h *Headless) SetPopupMenuChoice(choose func(items []string) string) {
+	h.choosePopup = choose
+}
+
+// OpenContextMenu simulates the user right-clicking target at x,y in its
+// coordinates. The context menu of target pops up, or that of its closest
+// parent that has one, see SetPopupMenuChoice.
+func (h *Headless) OpenContextMenu(target interface{ Handle() uintptr }, x, y int) {
+	h.contextMenu(target.Handle(), x, y)
+}
+
+// PressContextMenuKey simulates the user pressing the menu key, or Shift+F10,
+// while target has the focus. Like OpenContextMenu, but the menu appears at
+// the top-left corner of target.
+func (h *Headless) PressContextMenuKey(target interface{ Handle() uintptr }) {
+	h.contextMenu(target.Handle(), 0, 0)
+}
+
+func (h *Headless) contextMenu(handle uintptr, x, y int) {
+	c := h.handles[handle]
+	if c == nil || c.window == nil {
+		return
+	}
+	if c.Handle != c.window.handle && (!c.Enabled || !c.Visible) {
+		return
+	}
+	// Convert x,y to screen coordinates.
+	for e := c; e != nil; e = h.handles[e.Parent] {
+		if e.Handle == e.window.handle {
+			left, top, _, _ := h.clientBounds(e.Handle)
+			x += left
+			y += top
+			break
+		}
+		x += e.X
+		y += e.Y
+	}
+	for e := c; e != nil; e = h.handles[e.Parent] {
+		if e.Handle == e.window.handle {
+			e.window.contextMenuRequested(x, y)
+			return
+		}
+		for i := len(e.hooks) - 1; i >= 0; i-- {
+			if f := e.hooks[i].contextMenu; f != nil && f(x, y) {
+				return
+			}
+		}
+	}
+}
+
+// TrayIcon returns the current state of the tray icon. It returns false if
+// the icon is not visible.
+func (h *Headless) TrayIcon(t *TrayIcon) (HeadlessTrayIcon, bool) {
+	if icon, ok := h.trayIcons[headlessTrayKey{t.window.handle, t.id}]; ok {
+		state := *icon
+		state.Notifications = append([]HeadlessTrayNotification(nil), icon.Notifications...)
+		return state, true
+	}
+	return HeadlessTrayIcon{}, false
+}
+
+// ClickTrayIcon simulates a mouse click on the tray icon. A right-click pops
+// up the icon's menu, see SetPopupMenuChoice.
+func (h *Headless) ClickTrayIcon(t *TrayIcon, b MouseButton) {
+	h.trayIconEvent(t, trayClick, b)
+}
+
+// DoubleClickTrayIcon simulates a double-click with the left mouse button on
+// the tray icon. Like on Windows, this also is a click.
+func (h *Headless) DoubleClickTrayIcon(t *TrayIcon) {
+	h.trayIconEvent(t, trayClick, MouseButtonLeft)
+	h.trayIconEvent(t, trayDoubleClick, MouseButtonLeft)
+}
+
+// ClickTrayNotification simulates a click on the last notification of the
+// tray icon.
+func (h *Headless) ClickTrayNotification(t *TrayIcon) {
+	icon, ok := h.TrayIcon(t)
+	if ok && len(icon.Notifications) > 0 {
+		h.trayIconEvent(t, trayNotificationClick, MouseButtonLeft)
+	}
+}
+
+func (h *Headless) trayIconEvent(t *TrayIcon, e trayEvent, b MouseButton) {
+	if _, ok := h.TrayIcon(t); !ok {
+		return
+	}
+	if e == trayClick && b == MouseButtonRight {
+		e = trayRightClick
+	} else if e == trayClick && b != MouseButtonLeft {
+		return
+	}
+	t.window.trayIconEvent(t.id, e, 0, 0)
+}
+
+// PressShortcut simulates the user pressing the given key combination in the
+// window. The shortcut's function is called if it was set with
+// Window.SetShortcut.
+func (h *Headless) PressShortcut(w *Window, keys ...Key) {
+	if c := h.handles[w.handle]; c != nil {
+		a := toAccelerator(keys)
+		for i := range c.accelerators {
+			if c.accelerators[i] == a {
+				w.shortcutPressed(i)
+				return
+			}
+		}
+	}
+}
+
+// PressHotKey simulates the user pressing the given key combination while any
+// program has the focus. The function of the window that registered the keys
+// with Window.RegisterHotKey is called.
+func (h *Headless) PressHotKey(keys ...Key) {
+	owner, ok := h.hotKeys[toHotKeyKeys(keys)]
+	if !ok {
+		return
+	}
+	if c := h.handles[owner.window]; c != nil && c.window != nil {
+		c.window.hotKeyPressed(owner.id)
+	}
+}
+
+// RegisterForeignHotKey simulates another program registering the key
+// combination as its hot key. Window.RegisterHotKey then fails for these keys.
+func (h *Headless) RegisterForeignHotKey(keys ...Key) {
+	h.hotKeys[toHotKeyKeys(keys)] = headlessHotKey{}
+}
+
+// PressTab simulates the user pressing the Tab key, or Shift+Tab, in the
+// window. The keyboard focus moves to the next control, unless the focused
+// control uses tabs itself.
+func (h *Headless) PressTab(w *Window, shift bool) {
+	if !w.tabPressed(shift) {
+		if c := h.handles[h.focusHandle]; c != nil {
+			h.typeChar(c, '\t')
+		}
+	}
+}
+
+// notify sends the notification code to the top-level window of the control.
+func (h *Headless) notify(c *headlessHandle, code uintptr) {
+	h.notifications = append(h.notifications, HeadlessNotification{
+		Handle: c.Handle,
+		Code:   code,
+	})
+	if c.window != nil {
+		c.window.controlNotified(c.id, code)
+	}
+}
+
+// control returns the Control that the handle was created for.
+func (c *headlessHandle) control() Control {
+	if c.window != nil && 0 <= c.id && c.id < len(c.window.controls) {
+		if ctrl := c.window.controls[c.id]; ctrl.Handle() == c.Handle {
+			return ctrl
+		}
+	}
+	return nil
+}
+
+func (h *Headless) createWindow(w *Window) error {
+	c := &headlessHandle{
+		HeadlessControl: HeadlessControl{
+			Handle:    h.newHandle(),
+			ClassName: w.className,
+			Style:     w.style(),
+			ExStyle:   w.extendedStyle(),
+			Text:      w.title,
+			X:         w.x,
+			Y:         w.y,
+			Width:     w.width,
+			Height:    w.height,
+			Enabled:   true,
+		},
+		window: w,
+		state:  WindowNormal,
+	}
+	if w.parent != nil {
+		c.Parent = w.parent.handle
+	}
+	h.handles[c.Handle] = c
+	w.handle = c.Handle
+	atomic.StoreUint64(&h.uiGoroutine, goroutineID())
+	return nil
+}
+
+func (h *Headless) runMessageLoop(done func() bool) {
+	for !done() {
+		<-h.wakeUp
+		var windows []*Window
+		for handle, c := range h.handles {
+			if c.window != nil && c.window.handle == handle {
+				windows = append(windows, c.window)
+			}
+		}
+		for _, w := range windows {
+			w.runCalls()
+		}
+	}
+}
+
+func (h *Headless) isUIThread() bool {
+	return atomic.LoadUint64(&h.uiGoroutine) == goroutineID()
+}
+
+// goroutineID returns the ID of the calling goroutine. The Headless backend has
+// no thread of its own, its UI thread is the goroutine that creates the
+// windows.
+func goroutineID() uint64 {
+	var buf [64]byte
+	stack := buf[:runtime.Stack(buf[:], false)]
+	// The stack starts with "goroutine 123 [running]:".
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+	if i := bytes.IndexByte(stack, ' '); i != -1 {
+		stack = stack[:i]
+	}
+	id, _ := strconv.ParseUint(string(stack), 10, 64)
+	return id
+}
+
+func (h *Headless) wake(window uintptr) {
+	// The signal is buffered so waking up never blocks and is never lost. If
+	// the loop is already signaled we do not need to do it again.
+	select {
+	case h.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+func (h *Headless) close(window uintptr) {
+	if c := h.handles[window]; c != nil && c.window != nil {
+		if c.window.closeRequested() {
+			h.destroy(window)
+		}
+	}
+}
+
+func (h *Headless) bounds(window uintptr) (x, y, width, height int) {
+	if c := h.handles[window]; c != nil {
+		return c.X, c.Y, c.Width, c.Height
+	}
+	return
+}
+
+func (h *Headless) clientBounds(window uintptr) (x, y, width, height int) {
+	if c := h.handles[window]; c != nil {
+		left, top, right, bottom := h.frameSize(c.Style, c.ExStyle, c.menu != 0)
+		x = c.X + left
+		y = c.Y + top
+		width = c.Width - left - right
+		height = c.Height - top - bottom
+	}
+	return
+}
+
+// frameSize approximates the window borders of Windows 10 with the default
+// theme.
+func (*Headless) frameSize(style, exStyle uint, hasMenu bool) (left, top, right, bottom int) {
+	border := 0
+	if style&win.WS_THICKFRAME != 0 {
+		border = 8
+	} else if style&win.WS_CAPTION != 0 {
+		border = 3
+	}
+	if style&win.WS_BORDER != 0 && style&win.WS_CAPTION != win.WS_CAPTION {
+		border++
+	}
+	if style&win.WS_DLGFRAME != 0 && style&win.WS_CAPTION != win.WS_CAPTION {
+		border += 3
+	}
+	if exStyle&win.WS_EX_STATICEDGE != 0 {
+		border++
+	}
+	if exStyle&win.WS_EX_CLIENTEDGE != 0 {
+		border += 2
+	}
+	left, top, right, bottom = border, border, border, border
+	if style&win.WS_CAPTION == win.WS_CAPTION {
+		top += 23
+	}
+	if hasMenu {
+		top += 20
+	}
+	return
+}
+
+func (h *Headless) windowState(window uintptr) (WindowState, bool) {
+	if c := h.handles[window]; c != nil {
+		return c.state, true
+	}
+	return WindowNormal, false
+}
+
+func (h *Headless) setWindowState(window uintptr, s WindowState) {
+	c := h.handles[window]
+	if c == nil {
+		return
+	}
+	c.Visible = true
+	if s == c.state {
+		return
+	}
+	if c.state == WindowMaximized {
+		c.X, c.Y, c.Width, c.Height = c.restore[0], c.restore[1], c.restore[2], c.restore[3]
+	}
+	if s == WindowMaximized {
+		c.restore = [4]int{c.X, c.Y, c.Width, c.Height}
+		c.X, c.Y, c.Width, c.Height = 0, 0, 1920, 1080
+	}
+	c.state = s
+	if c.window != nil {
+		c.window.resized(s)
+	}
+}
+
+func (h *Headless) setBackground(window uintptr, color Color) {}
+
+func (h *Headless) setCursor(window uintptr, cursor uintptr) {}
+
+func (h *Headless) setIcon(window uintptr, icon uintptr) {}
+
+func (h *Headless) setAlpha(window uintptr, alpha uint8) {
+	if c := h.handles[window]; c != nil {
+		if alpha == 255 {
+			c.ExStyle &^= win.WS_EX_LAYERED
+		} else {
+			c.ExStyle |= win.WS_EX_LAYERED
+		}
+	}
+}
+
+func (h *Headless) setCloseButtonEnabled(window uintptr, enabled bool) {}
+
+func (h *Headless) setForeground(window uintptr) {}
+
+func (h *Headless) setAccelerators(window, table uintptr, keys []accelerator) uintptr {
+	if c := h.handles[window]; c != nil {
+		c.accelerators = append([]accelerator(nil), keys...)
+	}
+	return 0
+}
+
+func (h *Headless) scroll(window uintptr, dx, dy int) {}
+
+func (h *Headless) monitor(window uintptr) uintptr {
+	// There is only ever one monitor.
+	return 1
+}
+
+func (h *Headless) dpi(window uintptr) int {
+	if h.monitorDPI == 0 {
+		return DefaultDPI
+	}
+	return h.monitorDPI
+}
+
+func (h *Headless) setTimer(window, id uintptr, interval time.Duration) {
+	h.killTimer(window, id)
+	h.timers = append(h.timers, headlessTimer{
+		window:   window,
+		id:       id,
+		interval: interval,
+		due:      h.clock + interval,
+	})
+}
+
+func (h *Headless) killTimer(window, id uintptr) {
+	for i, t := range h.timers {
+		if t.window == window && t.id == id {
+			h.timers = append(h.timers[:i], h.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (h *Headless) registerHotKey(window uintptr, id int, keys hotKeyKeys) error {
+	if _, ok := h.hotKeys[keys]; ok {
+		return errHotKeyInUse
+	}
+	h.hotKeys[keys] = headlessHotKey{window: window, id: id}
+	return nil
+}
+
+func (h *Headless) unregisterHotKey(window uintptr, id int) {
+	// Unlike timers, hot keys outlive their window and can no longer be
+	// unregistered once it is destroyed.
+	if _, ok := h.handles[window]; !ok {
+		return
+	}
+	for keys, owner := range h.hotKeys {
+		if owner.window == window && owner.id == id {
+			delete(h.hotKeys, keys)
+		}
+	}
+}
+
+// headlessEpoch is the time at which the Headless clock starts.
+var headlessEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (h *Headless) now() time.Time {
+	return headlessEpoch.Add(h.clock)
+}
+
+// Advance moves the clock of the Headless backend forward, it does not change
+// by itself. All timers that become due in that time fire, in the order of
+// their due times, at the time that they are due. A timer that is due more
+// than once fires more than once.
+func (h *Headless) Advance(d time.Duration) {
+	end := h.clock + d
+	for {
+		next := -1
+		for i, t := range h.timers {
+			if t.due <= end && (next == -1 || t.due < h.timers[next].due) {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+		t := &h.timers[next]
+		h.clock = t.due
+		t.due += t.interval
+		if c := h.handles[t.window]; c != nil && c.window != nil {
+			c.window.timerFired(t.id)
+		}
+	}
+	h.clock = end
+}
+
+// SetDPI simulates moving all windows to a monitor with the given DPI. The
+// windows are scaled like on Windows, where the system suggests the new window
+// bounds. Windows that are shown later also use this DPI.
+func (h *Headless) SetDPI(dpi int) {
+	h.monitorDPI = dpi
+	for _, c := range h.handles {
+		w := c.window
+		if w != nil && w.handle == c.Handle && w.DPI() != dpi {
+			x, y, width, height := w.Bounds()
+			x, y, width, height = scaleBounds(x, y, width, height, dpi)
+			w.dpiChanged(dpi, x, y, width, height)
+		}
+	}
+}
+
+func (h *Headless) createMenu() uintptr {
+	m := h.newHandle()
+	h.menus[m] = &headlessMenu{}
+	return m
+}
+
+func (h *Headless) insertMenuItem(menu uintptr, pos int, item menuItemInfo) {
+	if m := h.menus[menu]; m != nil {
+		if pos < 0 || pos > len(m.items) {
+			pos = len(m.items)
+		}
+		m.items = append(m.items, menuItemInfo{})
+		copy(m.items[pos+1:], m.items[pos:])
+		m.items[pos] = item
+	}
+}
+
+func (h *Headless) setMenuItem(menu uintptr, pos int, item menuItemInfo) {
+	if m := h.menus[menu]; m != nil && 0 <= pos && pos < len(m.items) {
+		item.id = m.items[pos].id
+		item.subMenu = m.items[pos].subMenu
+		m.items[pos] = item
+	}
+}
+
+func (h *Headless) removeMenuItem(menu uintptr, pos int) {
+	if m := h.menus[menu]; m != nil && 0 <= pos && pos < len(m.items) {
+		if m.items[pos].subMenu != 0 {
+			h.destroyMenu(m.items[pos].subMenu)
+		}
+		m.items = append(m.items[:pos], m.items[pos+1:]...)
+	}
+}
+
+func (h *Headless) setMenuBar(window, menu uintptr) {
+	if c := h.handles[window]; c != nil {
+		c.menu = menu
+	}
+}
+
+func (h *Headless) drawMenuBar(window uintptr) {}
+
+func (h *Headless) createPopupMenu() uintptr {
+	return h.createMenu()
+}
+
+func (h *Headless) popupMenu(window, menu uintptr, x, y int) uint {
+	h.popupX, h.popupY = x, y
+	if h.choosePopup == nil {
+		return 0
+	}
+	// The user opens all sub-menus to see their items.
+	var texts []string
+	var ids []uint
+	var collect func(menu uintptr)
+	collect = func(menu uintptr) {
+		h.menuOpened(window, menu)
+		if m := h.menus[menu]; m != nil {
+			for _, item := range m.items {
+				if item.disabled {
+					continue
+				}
+				if item.subMenu != 0 {
+					collect(item.subMenu)
+				} else if item.id != 0 {
+					texts = append(texts, item.text)
+					ids = append(ids, item.id)
+				}
+			}
+		}
+	}
+	collect(menu)
+	choice := h.choosePopup(texts)
+	for i := range texts {
+		if texts[i] == choice {
+			return ids[i]
+		}
+	}
+	return 0
+}
+
+func (h *Headless) menuOpened(window, menu uintptr) {
+	if c := h.handles[window]; c != nil && c.window != nil {
+		c.window.menuOpened(menu)
+	}
+}
+
+func (h *Headless) destroyMenu(menu uintptr) {
+	if m := h.menus[menu]; m != nil {
+		delete(h.menus, menu)
+		for _, item := range m.items {
+			if item.subMenu != 0 {
+				h.destroyMenu(item.subMenu)
+			}
+		}
+	}
+}
+
+func (h *Headless) setTrayIcon(window uintptr, id uint, icon uintptr, toolTip string) {
+	key := headlessTrayKey{window, id}
+	t := h.trayIcons[key]
+	if t == nil {
+		t = &HeadlessTrayIcon{}
+		h.trayIcons[key] = t
+	}
+	t.Icon = icon
+	t.ToolTip = toolTip
+}
+
+func (h *Headless) removeTrayIcon(window uintptr, id uint) {
+	delete(h.trayIcons, headlessTrayKey{window, id})
+}
+
+func (h *Headless) showTrayNotification(
+	window uintptr,
+	id uint,
+	title, text string,
+	kind NotificationKind,
+) {
+	if t := h.trayIcons[headlessTrayKey{window, id}]; t != nil {
+		t.Notifications = append(t.Notifications, HeadlessTrayNotification{
+			Title: title,
+			Text:  text,
+			Kind:  kind,
+		})
+	}
+}
+
+func (h *Headless) createChild(
+	parent uintptr,
+	id int,
+	exStyle uint,
+	className string,
+	style uint,
+	x, y, width, height int,
+) uintptr {
+	p := h.handles[parent]
+	if p == nil {
+		return 0
+	}
+	c := &headlessHandle{
+		HeadlessControl: HeadlessControl{
+			Handle:    h.newHandle(),
+			Parent:    parent,
+			ClassName: className,
+			Style:     style,
+			ExStyle:   exStyle,
+			X:         x,
+			Y:         y,
+			Width:     width,
+			Height:    height,
+			Enabled:   true,
+			Visible:   style&win.WS_VISIBLE != 0,
+		},
+		id:       id,
+		window:   p.window,
+		limit:    30000,
+		selected: -1,
+		max:      100,
+	}
+	if className == win.UPDOWN_CLASS {
+		c.min, c.max = 100, 0
+	}
+	h.handles[c.Handle] = c
+	return c.Handle
+}
+
+func (h *Headless) destroy(handle uintptr) {
+	c, ok := h.handles[handle]
+	if !ok {
+		return
+	}
+	// Like WM_DESTROY, this is called while the handle is still valid.
+	if c.window != nil && c.window.handle == handle {
+		c.window.destroyed()
+	}
+	delete(h.handles, handle)
+	if c.menu != 0 {
+		h.destroyMenu(c.menu)
+	}
+	// Like on Windows, the timers die with their window.
+	timers := h.timers[:0]
+	for _, t := range h.timers {
+		if t.window != handle {
+			timers = append(timers, t)
+		}
+	}
+	h.timers = timers
+	if h.focusHandle == handle {
+		h.focusHandle = 0
+	}
+	for child, c := range h.handles {
+		if c.Parent == handle {
+			h.destroy(child)
+		}
+	}
+}
+
+func (h *Headless) setBounds(handle uintptr, x, y, width, height int) {
+	c := h.handles[handle]
+	if c == nil {
+		return
+	}
+	resized := width != c.Width || height != c.Height
+	c.X, c.Y, c.Width, c.Height = x, y, width, height
+	if resized && c.window != nil && c.window.handle == handle {
+		c.window.resized(c.state)
+	}
+}
+
+func (h *Headless) text(handle uintptr) string {
+	if c := h.handles[handle]; c != nil {
+		return c.Text
+	}
+	return ""
+}
+
+func (h *Headless) setText(handle uintptr, text string) {
+	if c := h.handles[handle]; c != nil {
+		changed := c.Text != text
+		c.Text = text
+		if c.ClassName == "EDIT" {
+			n := utf8.RuneCountInString(text)
+			c.selStart, c.selEnd = n, n
+			// Like the native control, multi-line edits do not notify about
+			// text that is set programmatically.
+			if changed && c.Style&win.ES_MULTILINE == 0 {
+				h.notify(c, win.EN_CHANGE)
+			}
+		}
+	}
+}
+
+func (h *Headless) setEnabled(handle uintptr, enabled bool) {
+	if c := h.handles[handle]; c != nil {
+		c.Enabled = enabled
+	}
+}
+
+func (h *Headless) setVisible(handle uintptr, visible bool) {
+	if c := h.handles[handle]; c != nil {
+		c.Visible = visible
+	}
+}
+
+func (h *Headless) style(handle uintptr) (style, exStyle uint) {
+	if c := h.handles[handle]; c != nil {
+		return c.Style, c.ExStyle
+	}
+	return
+}
+
+func (h *Headless) setStyle(handle uintptr, style, exStyle uint) {
+	if c := h.handles[handle]; c != nil {
+		c.Style, c.ExStyle = style, exStyle
+	}
+}
+
+func (h *Headless) repaint(handle uintptr) {
+	c := h.handles[handle]
+	if c == nil || c.ClassName != "STATIC" || c.Style&0x1F != win.SS_OWNERDRAW {
+		return
+	}
+	if p, ok := c.control().(*PaintBox); ok {
+		p.paint(&Canvas{
+			painter: &headlessPainter{},
+			width:   p.width,
+			height:  p.height,
+		})
+	}
+}
+
+func (h *Headless) setFont(handle uintptr, font uintptr) {
+	if c := h.handles[handle]; c != nil {
+		c.Font = h.fonts[font]
+	}
+}
+
+func (h *Headless) focus(handle uintptr) {
+	if h.focusHandle == handle {
+		return
+	}
+	if old := h.handles[h.focusHandle]; old != nil {
+		for i := len(old.hooks) - 1; i >= 0; i-- {
+			if old.hooks[i].focusLost != nil {
+				old.hooks[i].focusLost()
+			}
+		}
+	}
+	h.focusHandle = handle
+	if c := h.handles[handle]; c != nil {
+		for i := len(c.hooks) - 1; i >= 0; i-- {
+			if c.hooks[i].focusGained != nil {
+				c.hooks[i].focusGained()
+			}
+		}
+	}
+}
+
+func (h *Headless) focused() uintptr {
+	return h.focusHandle
+}
+
+func (h *Headless) keyDown(key Key) bool {
+	return false
+}
+
+func (h *Headless) hookControl(handle uintptr, hooks controlHooks) {
+	if c := h.handles[handle]; c != nil {
+		c.hooks = append(c.hooks, hooks)
+	}
+}
+
+func (h *Headless) notifyParent(handle uintptr, cmd uintptr) {
+	if c := h.handles[handle]; c != nil {
+		h.notify(c, cmd)
+	}
+}
+
+func (h *Headless) forwardNotifications(handle uintptr) {
+	// Notifications always go straight to the top-level window.
+}
+
+func (h *Headless) checked(handle uintptr) bool {
+	if c := h.handles[handle]; c != nil {
+		return c.Checked
+	}
+	return false
+}
+
+func (h *Headless) setChecked(handle uintptr, checked bool) {
+	if c := h.handles[handle]; c != nil {
+		c.Checked = checked
+	}
+}
+
+func (h *Headless) selection(handle uintptr) (start, end int) {
+	if c := h.handles[handle]; c != nil {
+		return c.selStart, c.selEnd
+	}
+	return
+}
+
+func (h *Headless) setSelection(handle uintptr, start, end int) {
+	if c := h.handles[handle]; c != nil {
+		n := utf8.RuneCountInString(c.Text)
+		clamp := func(i int) int {
+			if i < 0 || i > n {
+				return n
+			}
+			return i
+		}
+		c.selStart, c.selEnd = clamp(start), clamp(end)
+		if c.selStart > c.selEnd {
+			c.selStart, c.selEnd = c.selEnd, c.selStart
+		}
+	}
+}
+
+func (h *Headless) passwordChar(handle uintptr) rune {
+	if c := h.handles[handle]; c != nil {
+		if c.passwordChar == 0 && c.Style&win.ES_PASSWORD != 0 {
+			return '●'
+		}
+		return c.passwordChar
+	}
+	return 0
+}
+
+func (h *Headless) setPasswordChar(handle uintptr, char rune) {
+	if c := h.handles[handle]; c != nil {
+		c.passwordChar = char
+		if char == 0 {
+			c.Style &^= win.ES_PASSWORD
+		} else {
+			c.Style |= win.ES_PASSWORD
+		}
+	}
+}
+
+func (h *Headless) textLimit(handle uintptr) int {
+	if c := h.handles[handle]; c != nil {
+		return c.limit
+	}
+	return 0
+}
+
+func (h *Headless) setTextLimit(handle uintptr, limit int) {
+	if c := h.handles[handle]; c != nil {
+		c.limit = limit
+	}
+}
+
+func (h *Headless) setReadOnly(handle uintptr, readOnly bool) {
+	if c := h.handles[handle]; c != nil {
+		c.readOnly = readOnly
+	}
+}
+
+func (h *Headless) setErrorText(handle uintptr, text string) {
+	if c := h.handles[handle]; c != nil {
+		c.ErrorText = text
+	}
+}
+
+func (h *Headless) setToolTipOptions(toolTip uintptr, delay, duration time.Duration, maxWidth int) {
+	if t := h.handles[toolTip]; t != nil {
+		t.toolTipDelay = delay
+		t.toolTipDuration = duration
+		t.toolTipWidth = maxWidth
+	}
+}
+
+func (h *Headless) addToolTip(toolTip, window, control uintptr) {
+	h.removeToolTip(toolTip, window, control)
+	if t := h.handles[toolTip]; t != nil {
+		t.tools = append(t.tools, control)
+	}
+}
+
+func (h *Headless) removeToolTip(toolTip, window, control uintptr) {
+	if t := h.handles[toolTip]; t != nil {
+		for i := range t.tools {
+			if t.tools[i] == control {
+				t.tools = append(t.tools[:i], t.tools[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (h *Headless) addItem(handle uintptr, item string) {
+	if c := h.handles[handle]; c != nil {
+		c.items = append(c.items, item)
+	}
+}
+
+func (h *Headless) clearItems(handle uintptr) {
+	if c := h.handles[handle]; c != nil {
+		c.items = nil
+		c.selected = -1
+	}
+}
+
+func (h *Headless) selectedItem(handle uintptr) int {
+	if c := h.handles[handle]; c != nil {
+		return c.selected
+	}
+	return -1
+}
+
+func (h *Headless) selectItem(handle uintptr, index int) {
+	if c := h.handles[handle]; c != nil {
+		if 0 <= index && index < len(c.items) {
+			c.selected = index
+		} else {
+			c.selected = -1
+		}
+	}
+}
+
+func (h *Headless) setSliderRange(handle uintptr, min, max int) {
+	if c := h.handles[handle]; c != nil {
+		c.min, c.max = min, max
+		h.setSliderPosition(handle, c.pos)
+	}
+}
+
+func (h *Headless) sliderPosition(handle uintptr) int {
+	if c := h.handles[handle]; c != nil {
+		return c.pos
+	}
+	return 0
+}
+
+func (h *Headless) setSliderPosition(handle uintptr, pos int) {
+	if c := h.handles[handle]; c != nil {
+		if pos > c.max {
+			pos = c.max
+		}
+		if pos < c.min {
+			pos = c.min
+		}
+		c.pos = pos
+	}
+}
+
+func (h *Headless) setSliderTickFrequency(handle uintptr, n int) {}
+
+func (h *Headless) setSliderArrowIncrement(handle uintptr, inc int) {}
+
+func (h *Headless) setSliderMouseIncrement(handle uintptr, inc int) {}
+
+func (h *Headless) setProgressMarquee(handle uintptr, marquee bool) {
+	if c := h.handles[handle]; c != nil {
+		c.marquee = marquee
+	}
+}
+
+func (h *Headless) setProgressRange(handle uintptr, max int) {
+	if c := h.handles[handle]; c != nil {
+		c.min, c.max = 0, max
+	}
+}
+
+func (h *Headless) setProgress(handle uintptr, pos int) {
+	if c := h.handles[handle]; c != nil {
+		c.pos = pos
+	}
+}
+
+func (h *Headless) setUpDownBuddy(handle, buddy uintptr) {
+	if c := h.handles[handle]; c != nil {
+		c.buddy = buddy
+	}
+}
+
+func (h *Headless) setUpDownRange(handle uintptr, min, max int32) {
+	if c := h.handles[handle]; c != nil {
+		c.min, c.max = int(min), int(max)
+	}
+}
+
+func (h *Headless) upDownPosition(handle uintptr) int32 {
+	c := h.handles[handle]
+	if c == nil {
+		return 0
+	}
+	if buddy := h.handles[c.buddy]; buddy != nil && c.Style&win.UDS_SETBUDDYINT != 0 {
+		// Like the native control we read the value from the buddy window
+		// which the user might have edited.
+		if i, err := strconv.Atoi(buddy.Text); err == nil {
+			c.pos = i
+		}
+	}
+	return int32(c.pos)
+}
+
+func (h *Headless) setUpDownPosition(handle uintptr, pos int32) {
+	c := h.handles[handle]
+	if c == nil {
+		return
+	}
+	min, max := c.min, c.max
+	if min > max {
+		min, max = max, min
+	}
+	p := int(pos)
+	if p < min {
+		p = min
+	}
+	if p > max {
+		p = max
+	}
+	c.pos = p
+	if c.buddy != 0 && c.Style&win.UDS_SETBUDDYINT != 0 {
+		h.setText(c.buddy, strconv.Itoa(p))
+	}
+}
+
+func (h *Headless) setListViewColumns(handle uintptr, headers []string) {
+	if c := h.handles[handle]; c != nil {
+		c.columns = append([]string(nil), headers...)
+	}
+}
+
+func (h *Headless) insertListViewRow(handle uintptr, row int) {
+	if c := h.handles[handle]; c != nil && 0 <= row && row <= len(c.rows) {
+		c.rows = append(c.rows, nil)
+		copy(c.rows[row+1:], c.rows[row:])
+		c.rows[row] = make([]string, len(c.columns))
+	}
+}
+
+func (h *Headless) setListViewCell(handle uintptr, col, row int, text string) {
+	if c := h.handles[handle]; c != nil && 0 <= row && row < len(c.rows) {
+		for len(c.rows[row]) <= col {
+			c.rows[row] = append(c.rows[row], "")
+		}
+		c.rows[row][col] = text
+	}
+}
+
+func (h *Headless) deleteListViewRow(handle uintptr, row int) {
+	if c := h.handles[handle]; c != nil && 0 <= row && row < len(c.rows) {
+		c.rows = append(c.rows[:row], c.rows[row+1:]...)
+	}
+}
+
+func (h *Headless) pressKey(handle uintptr, key Key) {}
+
+func (h *Headless) setTreeImages(handle uintptr, images []*Image) {
+	if c := h.handles[handle]; c != nil {
+		c.treeImages = append([]*Image(nil), images...)
+	}
+}
+
+func (h *Headless) insertTreeItem(handle, parent uintptr, info treeItem) uintptr {
+	c := h.handles[handle]
+	if c == nil {
+		return 0
+	}
+	if c.treeItems == nil {
+		c.treeItems = make(map[uintptr]*HeadlessTreeItem)
+	}
+	item := h.newHandle()
+	c.treeItems[item] = &HeadlessTreeItem{Parent: parent}
+	h.setTreeItem(handle, item, info)
+	if p := c.treeItems[parent]; p != nil {
+		p.Children = append(p.Children, item)
+	} else {
+		c.treeRoots = append(c.treeRoots, item)
+	}
+	return item
+}
+
+func (h *Headless) setTreeItem(handle, item uintptr, info treeItem) {
+	if c := h.handles[handle]; c != nil {
+		if i := c.treeItems[item]; i != nil {
+			i.Text = info.text
+			i.Image = info.image
+			i.HasChildren = info.hasChildren
+			if info.checkBox {
+				i.Checked = info.checked
+			}
+		}
+	}
+}
+
+func (h *Headless) deleteTreeItem(handle, item uintptr) {
+	c := h.handles[handle]
+	if c == nil {
+		return
+	}
+	i := c.treeItems[item]
+	if i == nil {
+		return
+	}
+	for len(i.Children) > 0 {
+		h.deleteTreeItem(handle, i.Children[0])
+	}
+	delete(c.treeItems, item)
+	siblings := &c.treeRoots
+	if p := c.treeItems[i.Parent]; p != nil {
+		siblings = &p.Children
+	}
+	for j := range *siblings {
+		if (*siblings)[j] == item {
+			*siblings = append((*siblings)[:j], (*siblings)[j+1:]...)
+			break
+		}
+	}
+	if c.treeSelected == item {
+		c.treeSelected = 0
+	}
+}
+
+func (h *Headless) expandTreeItem(handle, item uintptr, expand bool) {
+	if c := h.handles[handle]; c != nil {
+		if i := c.treeItems[item]; i != nil && i.HasChildren {
+			i.Expanded = expand
+		}
+	}
+}
+
+func (h *Headless) selectTreeItem(handle, item uintptr) {
+	if c := h.handles[handle]; c != nil {
+		c.treeSelected = item
+	}
+}
+
+func (h *Headless) setTabImages(handle uintptr, images []*Image) {
+	if c := h.handles[handle]; c != nil {
+		c.tabImages = append([]*Image(nil), images...)
+	}
+}
+
+func (h *Headless) insertTab(handle uintptr, index int, info tabItem) {
+	c := h.handles[handle]
+	if c == nil || index < 0 || index > len(c.tabs) {
+		return
+	}
+	c.tabs = append(c.tabs, HeadlessTab{})
+	copy(c.tabs[index+1:], c.tabs[index:])
+	h.setTab(handle, index, info)
+	if len(c.tabs) == 1 {
+		// Like in Windows, the first tab is selected automatically.
+		c.selected = 0
+	} else if index <= c.selected {
+		c.selected++
+	}
+}
+
+func (h *Headless) setTab(handle uintptr, index int, info tabItem) {
+	if c := h.handles[handle]; c != nil && 0 <= index && index < len(c.tabs) {
+		c.tabs[index] = HeadlessTab{
+			Text:     info.text,
+			Image:    info.image,
+			Closable: info.closable,
+		}
+	}
+}
+
+func (h *Headless) deleteTab(handle uintptr, index int) {
+	c := h.handles[handle]
+	if c == nil || index < 0 || index >= len(c.tabs) {
+		return
+	}
+	c.tabs = append(c.tabs[:index], c.tabs[index+1:]...)
+	if index == c.selected {
+		c.selected = -1
+	} else if index < c.selected {
+		c.selected--
+	}
+}
+
+func (h *Headless) selectTab(handle uintptr, index int) {
+	if c := h.handles[handle]; c != nil {
+		if 0 <= index && index < len(c.tabs) {
+			c.selected = index
+		} else {
+			c.selected = -1
+		}
+	}
+}
+
+func (h *Headless) tabInsets(handle uintptr) (left, top, right, bottom int) {
+	dpi := h.dpi(0)
+	return Scale(tabPageMargin, dpi), Scale(tabHeaderHeight, dpi),
+		Scale(tabPageMargin, dpi), Scale(tabPageMargin, dpi)
+}
+
+func (h *Headless) setStatusParts(handle uintptr, rights []int) {
+	c := h.handles[handle]
+	if c == nil {
+		return
+	}
+	// Like in Windows, the parts keep their texts and icons.
+	parts := make([]HeadlessStatusPart, len(rights))
+	copy(parts, c.statusParts)
+	for i := range parts {
+		parts[i].Right = rights[i]
+	}
+	c.statusParts = parts
+}
+
+func (h *Headless) setStatusText(handle uintptr, part int, text string) {
+	if c := h.handles[handle]; c != nil && 0 <= part && part < len(c.statusParts) {
+		c.statusParts[part].Text = text
+	}
+}
+
+func (h *Headless) setStatusIcon(handle uintptr, part int, icon uintptr) {
+	if c := h.handles[handle]; c != nil && 0 <= part && part < len(c.statusParts) {
+		c.statusParts[part].Icon = icon
+	}
+}
+
+func (h *Headless) statusBarHeight(handle uintptr) int {
+	return Scale(defaultStatusBarHeight, h.dpi(0))
+}
+
+func (h *Headless) deleteFont(font uintptr) {
+	delete(h.fonts, font)
+}
+
+func (h *Headless) createFont(desc FontDesc) (handle uintptr, exactMatch bool) {
+	handle = h.newHandle()
+	h.fonts[handle] = desc
+	return handle, true
+}
+
+func (h *Headless) loadIcon(id uint16) uintptr {
+	return h.newHandle()
+}
+
+func (h *Headless) createIcon(data []byte) uintptr {
+	return h.newHandle()
+}
+
+func (h *Headless) loadIconResource(id int) uintptr {
+	// There are no resources compiled into the executable.
+	return 0
+}
+
+func (h *Headless) loadIconFile(path string) (uintptr, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, nil
+	}
+	return h.newHandle(), nil
+}
+
+func (h *Headless) loadCursor(id uint16) uintptr {
+	return h.newHandle()
+}
+
+func (h *Headless) createCursor(x, y, width, height int, and, xor []byte) uintptr {
+	return h.newHandle()
+}
+
+func (h *Headless) createBitmap(img *image.RGBA) uintptr {
+	return h.newHandle()
+}
+
+func (h *Headless) iconBitmap(icon uintptr) uintptr {
+	return h.newHandle()
+}
+
+func (h *Headless) sysColor(index int) Color {
+	return 0
+}
+
+// All dialogs are cancelled right away.
+
+func (h *Headless) messageBox(owner uintptr, caption, text string, flags uint) int {
+	return win.IDCANCEL
+}
+
+func (h *Headless) openFiles(
+	owner uintptr,
+	title, initPath string,
+	filters []fileFilter,
+	filterIndex int,
+	multiSelect bool,
+) (bool, []string) {
+	return false, nil
+}
+
+func (h *Headless) saveFile(
+	owner uintptr,
+	title, initPath string,
+	filters []fileFilter,
+	filterIndex int,
+) (ok bool, path string, selectedFilter int) {
+	return false, "", filterIndex
+}
+
+func (h *Headless) selectFolder(owner uintptr, title string) (bool, string) {
+	return false, ""
+}
+
+// registerClipboardFormat numbers formats from 0xC000 on, like Windows does.
+func (h *Headless) registerClipboardFormat(name string) (ClipboardFormat, error) {
+	if f, ok := h.clipFormats[name]; ok {
+		return f, nil
+	}
+	f := ClipboardFormat(0xC000 + len(h.clipFormats))
+	h.clipFormats[name] = f
+	return f, nil
+}
+
+func (h *Headless) hasClipboardFormat(f ClipboardFormat) bool {
+	for _, item := range h.clipboard {
+		if item.format == f {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Headless) clipboardData(f ClipboardFormat) ([]byte, error) {
+	for _, item := range h.clipboard {
+		if item.format == f {
+			return append([]byte{}, item.data...), nil
+		}
+	}
+	return nil, nil
+}
+
+func (h *Headless) setClipboardData(items []clipboardItem) error {
+	h.clipboard = h.clipboard[:0]
+	for _, item := range items {
+		data := append([]byte{}, item.data...)
+		h.clipboard = append(h.clipboard, clipboardItem{item.format, data})
+	}
+	Clipboard.changed()
+	return nil
+}
+
+// headlessPainter draws nothing. It measures text as if every character was 8
+// pixels wide and 16 pixels high.
+type headlessPainter struct{}
+
+func (*headlessPainter) handle() uintptr                                      { return 0 }
+func (*headlessPainter) pushDrawRegion(x, y, width, height int)               {}
+func (*headlessPainter) popDrawRegion()                                       {}
+func (*headlessPainter) clearDrawRegions()                                    {}
+func (*headlessPainter) rect(x, y, width, height int, c Color, fill bool)     {}
+func (*headlessPainter) line(x1, y1, x2, y2 int, c Color)                     {}
+func (*headlessPainter) ellipse(x, y, width, height int, c Color, fill bool)  {}
+func (*headlessPainter) polyline(p []Point, c Color)                          {}
+func (*headlessPainter) polygon(p []Point, c Color)                           {}
+func (*headlessPainter) arc(x, y, width, height, x1, y1, x2, y2 int, c Color) {}
+func (*headlessPainter) textOut(x, y int, s string, c Color)                  {}
+func (*headlessPainter) setFont(f *Font)                                      {}
+func (*headlessPainter) drawImage(img *Image, src Rectangle, destX, destY int) {
+}
+
+func (*headlessPainter) pie(x, y, width, height, x1, y1, x2, y2 int, c Color, fill bool) {
+}
+
+func (*headlessPainter) textRect(x, y, width, height int, s string, f Format, c Color) {
+}
+
+func (*headlessPainter) textExtent(s string) (width, height int) {
+	return 8 * utf8.RuneCountInString(s), 16
+}
+
+func (p *headlessPainter) textRectExtent(s string, width int) (int, int) {
+	w, h := p.textExtent(s)
+	if width <= 0 || w <= width {
+		return w, h
+	}
+	lines := (w + width - 1) / width
+	return width, lines * h
+}
@@ -0,0 +1,167 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestHeadlessWindowShowsAndCloses(t *testing.T) {
+	h := UseHeadless()
+
+	w := NewWindow()
+	w.SetTitle("Test")
+	w.SetBounds(10, 20, 300, 200)
+	shown := false
+	closed := false
+	w.SetOnShow(func() {
+		shown = true
+		c, ok := h.Control(w.Handle())
+		check.Eq(t, ok, true)
+		check.Eq(t, c.Text, "Test")
+		check.Eq(t, c.Visible, true)
+		check.Eq(t, [4]int{c.X, c.Y, c.Width, c.Height}, [4]int{10, 20, 300, 200})
+		w.Close()
+	})
+	w.SetOnClose(func() { closed = true })
+	check.Eq(t, w.Show(), nil)
+
+	check.Eq(t, shown, true)
+	check.Eq(t, closed, true)
+	check.Eq(t, w.Handle(), uintptr(0))
+}
+
+func TestHeadlessCanCloseCanPreventClosing(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	allowClose := false
+	w.SetOnCanClose(func() bool { return allowClose })
+	w.SetOnShow(func() {
+		w.Close()
+		check.Eq(t, w.Handle() != 0, true)
+		allowClose = true
+		w.Close()
+	})
+	w.Show()
+	check.Eq(t, w.Handle(), uintptr(0))
+}
+
+func TestHeadlessAddAndRemoveControls(t *testing.T) {
+	h := UseHeadless()
+
+	w := NewWindow()
+	before := NewButton()
+	before.SetText("before")
+	before.SetBounds(1, 2, 3, 4)
+	w.Add(before)
+	w.SetOnShow(func() {
+		c, ok := h.Control(before.Handle())
+		check.Eq(t, ok, true)
+		check.Eq(t, c.Parent, w.Handle())
+		check.Eq(t, c.ClassName, "BUTTON")
+		check.Eq(t, c.Text, "before")
+		check.Eq(t, [4]int{c.X, c.Y, c.Width, c.Height}, [4]int{1, 2, 3, 4})
+
+		after := NewLabel()
+		after.SetText("after")
+		w.Add(after)
+		c, ok = h.Control(after.Handle())
+		check.Eq(t, ok, true)
+		check.Eq(t, c.Text, "after")
+
+		handle := before.Handle()
+		w.Remove(before)
+		_, ok = h.Control(handle)
+		check.Eq(t, ok, false)
+
+		before.SetEnabled(false)
+		before.SetVisible(false)
+		w.Add(before)
+		c, ok = h.Control(before.Handle())
+		check.Eq(t, ok, true)
+		check.Eq(t, c.Enabled, false)
+		check.Eq(t, c.Visible, false)
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestHeadlessAnchorsFollowWindowSize(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	w.SetInnerSize(200, 100)
+	b := NewButton()
+	b.SetBounds(10, 10, 50, 20)
+	b.SetAnchors(AnchorMinAndMax, AnchorMax)
+	w.Add(b)
+	resized := 0
+	w.SetOnResize(func() { resized++ })
+	w.SetOnShow(func() {
+		resized = 0
+		width, height := w.Size()
+		w.SetSize(width+100, height+50)
+		check.Eq(t, resized, 1)
+		x, y, width, height := b.Bounds()
+		check.Eq(t, [4]int{x, y, width, height}, [4]int{10, 60, 150, 20})
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestHeadlessEventCallbacks(t *testing.T) {
+	h := UseHeadless()
+
+	w := NewWindow()
+
+	clicks := 0
+	button := NewButton()
+	button.SetOnClick(func() { clicks++ })
+	w.Add(button)
+
+	var checked []bool
+	checkBox := NewCheckBox()
+	checkBox.SetOnChange(func(c bool) { checked = append(checked, c) })
+	w.Add(checkBox)
+
+	textChanges := 0
+	edit := NewEditLine()
+	edit.SetOnTextChange(func() { textChanges++ })
+	w.Add(edit)
+
+	selected := -1
+	combo := NewComboBox()
+	combo.SetItems([]string{"zero", "one", "two"})
+	combo.SetOnChange(func(i int) { selected = i })
+	w.Add(combo)
+
+	w.SetOnShow(func() {
+		h.Click(button)
+		h.Click(button)
+		check.Eq(t, clicks, 2)
+
+		h.Click(checkBox)
+		h.Click(checkBox)
+		check.Eq(t, checked, []bool{true, false})
+		check.Eq(t, checkBox.Checked(), false)
+
+		h.Type(edit, "abc")
+		check.Eq(t, edit.Text(), "abc")
+		check.Eq(t, textChanges, 3)
+
+		h.Select(combo, 2)
+		check.Eq(t, selected, 2)
+		check.Eq(t, combo.SelectedIndex(), 2)
+
+		button.SetEnabled(false)
+		h.Click(button)
+		check.Eq(t, clicks, 2)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, len(h.Notifications()) > 0, true)
+}
@@ -0,0 +1,114 @@
+package wui
+
+import "errors"
+
+// hotKey is a system-wide key combination, see Window.RegisterHotKey.
+type hotKey struct {
+	id   int
+	keys hotKeyKeys
+	f    func()
+}
+
+// hotKeyKeys is the key combination of a hot key. Unlike shortcuts, hot keys
+// can use the Windows key as a modifier.
+type hotKeyKeys struct {
+	key                      Key
+	control, alt, shift, win bool
+}
+
+// errHotKeyInUse is returned by the backends if another hot key, of this or
+// another program, has the same keys.
+var errHotKeyInUse = errors.New("the key combination is already in use")
+
+func toHotKeyKeys(keys []Key) hotKeyKeys {
+	var k hotKeyKeys
+	var rest []Key
+	for _, key := range keys {
+		if key == KeyLeftWindows || key == KeyRightWindows {
+			k.win = true
+		} else {
+			rest = append(rest, key)
+		}
+	}
+	a := toAccelerator(rest)
+	k.key, k.control, k.alt, k.shift = a.key, a.control, a.alt, a.shift
+	return k
+}
+
+func (k hotKeyKeys) String() string {
+	var keys []Key
+	if k.control {
+		keys = append(keys, KeyControl)
+	}
+	if k.alt {
+		keys = append(keys, KeyAlt)
+	}
+	if k.shift {
+		keys = append(keys, KeyShift)
+	}
+	s := ShortcutString(append(keys, k.key)...)
+	if k.win {
+		s = "Win+" + s
+	}
+	return s
+}
+
+// RegisterHotKey makes f be called when the user presses the key combination,
+// even while another program has the focus. Use KeyControl, KeyAlt, KeyShift
+// and KeyLeftWindows as modifiers. Holding the keys down calls f only once.
+//
+// RegisterHotKey returns an error if the window is not shown, e.g. call it in
+// the window's OnShow, or if the key combination is already in use by this or
+// another program. Call unregister to remove the hot key. All hot keys of a
+// window are removed when it is closed.
+func (w *Window) RegisterHotKey(f func(), keys ...Key) (unregister func(), err error) {
+	k := toHotKeyKeys(keys)
+	if k.key == 0 {
+		return nil, errors.New("wui.Window.RegisterHotKey: missing key")
+	}
+	if w.handle == 0 {
+		return nil, errors.New("wui.Window.RegisterHotKey: window is not shown")
+	}
+	w.lastHotKeyID++
+	h := &hotKey{id: w.lastHotKeyID, keys: k, f: f}
+	if err := ui.registerHotKey(w.handle, h.id, k); err != nil {
+		return nil, errors.New(
+			"wui.Window.RegisterHotKey: " + k.String() + ": " + err.Error(),
+		)
+	}
+	w.hotKeys = append(w.hotKeys, h)
+	return func() { w.unregisterHotKey(h) }, nil
+}
+
+func (w *Window) unregisterHotKey(h *hotKey) {
+	for i := range w.hotKeys {
+		if w.hotKeys[i] == h {
+			w.hotKeys = append(w.hotKeys[:i], w.hotKeys[i+1:]...)
+			if w.handle != 0 {
+				ui.unregisterHotKey(w.handle, h.id)
+			}
+			return
+		}
+	}
+}
+
+// unregisterHotKeys removes all hot keys, this is called when the window is
+// destroyed.
+func (w *Window) unregisterHotKeys() {
+	for len(w.hotKeys) > 0 {
+		w.unregisterHotKey(w.hotKeys[0])
+	}
+}
+
+// hotKeyPressed is called by the backend when the user presses the hot key
+// with the given ID.
+func (w *Window) hotKeyPressed(id int) {
+	for _, h := range w.hotKeys {
+		if h.id == id {
+			if h.f != nil {
+				h.f()
+			}
+			return
+		}
+	}
+}
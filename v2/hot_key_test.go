@@ -0,0 +1,68 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestHotKeysWorkWhileWindowIsShown(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	presses := 0
+
+	_, err := w.RegisterHotKey(func() { presses++ }, KeyControl, KeyF9)
+	check.Eq(t, err.Error(), "wui.Window.RegisterHotKey: window is not shown")
+
+	w.SetOnShow(func() {
+		unregister, err := w.RegisterHotKey(
+			func() { presses++ },
+			KeyLeftWindows, KeyShift, KeyControl, KeyF9,
+		)
+		check.Eq(t, err, nil)
+		h.PressHotKey(KeyControl, KeyF9)
+		check.Eq(t, presses, 0)
+		h.PressHotKey(KeyControl, KeyShift, KeyRightWindows, KeyF9)
+		check.Eq(t, presses, 1)
+
+		unregister()
+		h.PressHotKey(KeyControl, KeyShift, KeyRightWindows, KeyF9)
+		check.Eq(t, presses, 1)
+		// Unregistering twice does nothing.
+		unregister()
+
+		_, err = w.RegisterHotKey(func() { presses++ }, KeyAlt, KeyF9)
+		check.Eq(t, err, nil)
+		w.Close()
+	})
+	w.Show()
+
+	h.PressHotKey(KeyAlt, KeyF9)
+	check.Eq(t, presses, 1)
+	check.Eq(t, len(h.hotKeys), 0)
+	check.Eq(t, len(w.hotKeys), 0)
+}
+
+func TestHotKeysConflictWithOtherHotKeys(t *testing.T) {
+	h := UseHeadless()
+	h.RegisterForeignHotKey(KeyControl, KeyAlt, KeyP)
+	w := NewWindow()
+
+	w.SetOnShow(func() {
+		_, err := w.RegisterHotKey(nil, KeyControl, KeyAlt, KeyP)
+		check.Eq(t, err.Error(), "wui.Window.RegisterHotKey: Ctrl+Alt+P: "+
+			"the key combination is already in use")
+
+		_, err = w.RegisterHotKey(nil, KeyLeftWindows, KeyP)
+		check.Eq(t, err, nil)
+		_, err = w.RegisterHotKey(nil, KeyRightWindows, KeyP)
+		check.Eq(t, err.Error(), "wui.Window.RegisterHotKey: Win+P: "+
+			"the key combination is already in use")
+
+		_, err = w.RegisterHotKey(nil, KeyControl, KeyShift)
+		check.Eq(t, err.Error(), "wui.Window.RegisterHotKey: missing key")
+
+		w.Close()
+	})
+	w.Show()
+}
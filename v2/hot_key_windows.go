@@ -0,0 +1,36 @@
+package wui
+
+import "syscall"
+
+func (*winAPI) registerHotKey(window uintptr, id int, keys hotKeyKeys) error {
+	modifiers := uintptr(modNoRepeat)
+	if keys.control {
+		modifiers |= modControl
+	}
+	if keys.alt {
+		modifiers |= modAlt
+	}
+	if keys.shift {
+		modifiers |= modShift
+	}
+	if keys.win {
+		modifiers |= modWin
+	}
+	ret, _, err := registerHotKeyProc.Call(
+		window,
+		uintptr(id),
+		modifiers,
+		uintptr(keys.key),
+	)
+	if ret != 0 {
+		return nil
+	}
+	if err == syscall.Errno(errorHotKeyAlreadyRegistered) {
+		return errHotKeyInUse
+	}
+	return err
+}
+
+func (*winAPI) unregisterHotKey(window uintptr, id int) {
+	unregisterHotKeyProc.Call(window, uintptr(id))
+}
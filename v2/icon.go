@@ -7,40 +7,39 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
-	"syscall"
-	"unsafe"
 
-	"github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2/internal/win"
 )
 
 // Icon holds a window icon. You can use a pre-defined Icon... variable (see
 // below) or create a custom icon with NewIconFromImage, NewIconFromExeResource,
 // NewIconFromFile or NewIconFromReader.
 type Icon struct {
-	handle w32.HICON
+	handle uintptr
+	bitmap uintptr // For menu items, created when first needed.
 }
 
 var (
 	// IconApplication is the default application icon.
-	IconApplication = loadIcon(w32.IDI_APPLICATION)
+	IconApplication = loadIcon(win.IDI_APPLICATION)
 	// IconQuestion is a question mark icon.
-	IconQuestion = loadIcon(w32.IDI_QUESTION)
+	IconQuestion = loadIcon(win.IDI_QUESTION)
 	// IconWinLogo is usually the same as IconApplication but on older Windows
 	// versions (like Windows 2000) this was a Windows icon.
-	IconWinLogo = loadIcon(w32.IDI_WINLOGO)
+	IconWinLogo = loadIcon(win.IDI_WINLOGO)
 	// IconShield is the icon that comes up when Windows asks admin permission.
 	// It looks like a knight's shield.
-	IconShield = loadIcon(w32.IDI_SHIELD)
+	IconShield = loadIcon(win.IDI_SHIELD)
 	// IconWarning is an exclamation mark icon.
-	IconWarning = loadIcon(w32.IDI_WARNING)
+	IconWarning = loadIcon(win.IDI_WARNING)
 	// IconError is a red cross icon.
-	IconError = loadIcon(w32.IDI_ERROR)
+	IconError = loadIcon(win.IDI_ERROR)
 	// IconInformation is a blue 'i' for information.
-	IconInformation = loadIcon(w32.IDI_INFORMATION)
+	IconInformation = loadIcon(win.IDI_INFORMATION)
 )
 
 func loadIcon(id uint16) *Icon {
-	return &Icon{handle: w32.LoadIcon(0, w32.MakeIntResource(id))}
+	return &Icon{handle: ui.loadIcon(id)}
 }
 
 // IconInformation creates an icon from an image. The image should have a
@@ -69,7 +68,7 @@ func NewIconFromImage(img image.Image) (*Icon, error) {
 	binary.LittleEndian.PutUint32(iconData[8:], uint32(size.Y*2))
 	binary.LittleEndian.PutUint16(iconData[12:], 1)
 	binary.LittleEndian.PutUint16(iconData[14:], 32)
-	binary.LittleEndian.PutUint32(iconData[16:], w32.BI_RGB)
+	binary.LittleEndian.PutUint32(iconData[16:], win.BI_RGB)
 	binary.LittleEndian.PutUint32(iconData[20:], uint32(size.X*size.Y*4))
 	// 4 uint32 0s follow, iconData[40:] is where the image data starts.
 	dest := iconData[headerLen:]
@@ -92,14 +91,7 @@ func NewIconFromImage(img image.Image) (*Icon, error) {
 		dest[i] = 0xFF
 	}
 
-	icon := w32.CreateIconFromResource(
-		unsafe.Pointer(&iconData[0]),
-		uint32(len(iconData)),
-		true, // true for icons, false for cursors.
-		// 0x30000 is a magic constant from the docs:
-		// https://docs.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-createiconfromresource
-		0x30000,
-	)
+	icon := ui.createIcon(iconData)
 	if icon == 0 {
 		return nil, errors.New("wui.NewIconFromImage: CreateIconFromResource returned 0 handle")
 	}
@@ -111,14 +103,7 @@ func NewIconFromImage(img image.Image) (*Icon, error) {
 // get a unique ID. See for example the rsrc tool which can create .syso files:
 // https://github.com/gonutz/rsrc
 func NewIconFromExeResource(resourceID int) (*Icon, error) {
-	icon := w32.HICON(w32.LoadImage(
-		w32.GetModuleHandle(""),
-		w32.MakeIntResource(uint16(resourceID)),
-		w32.IMAGE_ICON,
-		0,
-		0,
-		w32.LR_DEFAULTSIZE|w32.LR_SHARED,
-	))
+	icon := ui.loadIconResource(resourceID)
 	if icon == 0 {
 		return nil, errors.New("wui.NewIconFromExeResource: LoadImage returned 0 handle")
 	}
@@ -128,17 +113,10 @@ func NewIconFromExeResource(resourceID int) (*Icon, error) {
 // NewIconFromFile loads an icon from disk. The format must be .ico, not an
 // image.
 func NewIconFromFile(path string) (*Icon, error) {
-	p, err := syscall.UTF16PtrFromString(path)
+	icon, err := ui.loadIconFile(path)
 	if err != nil {
-		return nil, errors.New("wui.NewIconFromFile: invalid path: " + err.Error())
+		return nil, errors.New("wui.NewIconFromFile: " + err.Error())
 	}
-	icon := w32.HICON(w32.LoadImage(
-		0,
-		p,
-		w32.IMAGE_ICON,
-		0, 0,
-		w32.LR_LOADFROMFILE|w32.LR_DEFAULTSIZE,
-	))
 	if icon == 0 {
 		return nil, errors.New("wui.NewIconFromFile: LoadImage returned 0 handle")
 	}
@@ -168,3 +146,11 @@ func NewIconFromReader(r io.Reader) (*Icon, error) {
 	}
 	return icon, nil
 }
+
+// menuBitmap returns the icon as a bitmap for menu items.
+func (i *Icon) menuBitmap() uintptr {
+	if i.bitmap == 0 && i.handle != 0 {
+		i.bitmap = ui.iconBitmap(i.handle)
+	}
+	return i.bitmap
+}
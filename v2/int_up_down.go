@@ -4,7 +4,7 @@ import (
 	"math"
 	"strconv"
 
-	"github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2/internal/win"
 )
 
 // TODO Typing + or - into an IntUpDown shows an error. The same might be true
@@ -23,11 +23,11 @@ func NewIntUpDown() *IntUpDown {
 
 type IntUpDown struct {
 	textEditControl
-	upDownHandle  w32.HWND
+	upDownHandle  uintptr
 	value         int32
 	minValue      int32
 	maxValue      int32
-	onValueChange func(value int)
+	onValueChange IntEvent
 }
 
 var _ Control = (*IntUpDown)(nil)
@@ -35,7 +35,7 @@ var _ Control = (*IntUpDown)(nil)
 func (n *IntUpDown) destroy() {
 	n.textEditControl.destroy()
 	if n.upDownHandle != 0 {
-		w32.DestroyWindow(n.upDownHandle)
+		ui.destroy(n.upDownHandle)
 		n.upDownHandle = 0
 	}
 }
@@ -49,11 +49,15 @@ func (*IntUpDown) canFocus() bool {
 }
 
 func (n *IntUpDown) OnTabFocus() func() {
-	return n.onTabFocus
+	return n.onTabFocus.primary
 }
 
 func (n *IntUpDown) SetOnTabFocus(f func()) {
-	n.onTabFocus = f
+	n.onTabFocus.primary = f
+}
+
+func (n *IntUpDown) TabFocusEvent() *Event {
+	return &n.onTabFocus
 }
 
 func (*IntUpDown) eatsTabs() bool {
@@ -65,30 +69,27 @@ func (n *IntUpDown) create(id int) {
 	n.text = strconv.Itoa(int(n.value))
 	n.textEditControl.create(
 		id,
-		w32.WS_EX_CLIENTEDGE,
+		win.WS_EX_CLIENTEDGE,
 		"EDIT",
-		w32.WS_TABSTOP|w32.ES_NUMBER,
+		win.WS_TABSTOP|win.ES_NUMBER,
 	)
 	var visible uint
 	if !n.hidden {
-		visible = w32.WS_VISIBLE
-	}
-	upDown := w32.CreateWindowStr(
-		w32.UPDOWN_CLASS,
-		"",
-		visible|w32.WS_CHILD|
-			w32.UDS_SETBUDDYINT|w32.UDS_ALIGNRIGHT|w32.UDS_NOTHOUSANDS|
-			w32.UDS_ARROWKEYS,
-		n.x, n.y, n.width, n.height,
-		n.parent.getHandle(), 0, n.parent.getInstance(), nil,
-	)
-	w32.SendMessage(upDown, w32.UDM_SETBUDDY, uintptr(n.handle), 0)
-	w32.SendMessage(
-		upDown,
-		w32.UDM_SETRANGE32,
-		uintptr(n.minValue),
-		uintptr(n.maxValue),
+		visible = win.WS_VISIBLE
+	}
+	x, y, width, height := n.pixelBounds()
+	upDown := ui.createChild(
+		n.parent.getHandle(),
+		0,
+		0,
+		win.UPDOWN_CLASS,
+		visible|win.WS_CHILD|
+			win.UDS_SETBUDDYINT|win.UDS_ALIGNRIGHT|win.UDS_NOTHOUSANDS|
+			win.UDS_ARROWKEYS,
+		x, y, width, height,
 	)
+	ui.setUpDownBuddy(upDown, n.handle)
+	ui.setUpDownRange(upDown, n.minValue, n.maxValue)
 	n.upDownHandle = upDown
 }
 
@@ -119,18 +120,15 @@ func (n *IntUpDown) SetSize(width, height int) {
 func (n *IntUpDown) SetBounds(x, y, width, height int) {
 	n.textEditControl.SetBounds(x, y, width, height)
 	if n.upDownHandle != 0 {
-		w32.SetWindowPos(
-			n.upDownHandle, 0,
-			n.x, n.y, n.width, n.height,
-			w32.SWP_NOOWNERZORDER|w32.SWP_NOZORDER,
-		)
-		w32.SendMessage(n.upDownHandle, w32.UDM_SETBUDDY, uintptr(n.handle), 0)
+		x, y, width, height := n.pixelBounds()
+		ui.setBounds(n.upDownHandle, x, y, width, height)
+		ui.setUpDownBuddy(n.upDownHandle, n.handle)
 	}
 }
 
 func (n *IntUpDown) Value() int {
 	if n.upDownHandle != 0 {
-		n.value = int32(w32.SendMessage(n.upDownHandle, w32.UDM_GETPOS32, 0, 0))
+		n.value = ui.upDownPosition(n.upDownHandle)
 	}
 	return int(n.value)
 }
@@ -144,7 +142,7 @@ func (n *IntUpDown) SetValue(v int) {
 		n.value = n.maxValue
 	}
 	if n.upDownHandle != 0 {
-		w32.SendMessage(n.upDownHandle, w32.UDM_SETPOS32, 0, uintptr(v))
+		ui.setUpDownPosition(n.upDownHandle, int32(v))
 	}
 }
 
@@ -158,12 +156,7 @@ func (n *IntUpDown) SetMin(min int) {
 	}
 	n.minValue = int32(min)
 	if n.upDownHandle != 0 {
-		w32.SendMessage(
-			n.upDownHandle,
-			w32.UDM_SETRANGE32,
-			uintptr(n.minValue),
-			uintptr(n.maxValue),
-		)
+		ui.setUpDownRange(n.upDownHandle, n.minValue, n.maxValue)
 	}
 }
 
@@ -177,12 +170,7 @@ func (n *IntUpDown) SetMax(max int) {
 	}
 	n.maxValue = int32(max)
 	if n.upDownHandle != 0 {
-		w32.SendMessage(
-			n.upDownHandle,
-			w32.UDM_SETRANGE32,
-			uintptr(n.minValue),
-			uintptr(n.maxValue),
-		)
+		ui.setUpDownRange(n.upDownHandle, n.minValue, n.maxValue)
 	}
 }
 
@@ -199,37 +187,31 @@ func (n *IntUpDown) SetMinMax(min, max int) {
 	n.minValue = int32(min)
 	n.maxValue = int32(max)
 	if n.upDownHandle != 0 {
-		w32.SendMessage(
-			n.upDownHandle,
-			w32.UDM_SETRANGE32,
-			uintptr(n.minValue),
-			uintptr(n.maxValue),
-		)
+		ui.setUpDownRange(n.upDownHandle, n.minValue, n.maxValue)
 	}
 }
 
 func (n *IntUpDown) OnValueChange() func(value int) {
-	return n.onValueChange
+	return n.onValueChange.primary
 }
 
 func (n *IntUpDown) SetOnValueChange(f func(value int)) {
-	n.onValueChange = f
+	n.onValueChange.primary = f
+}
+
+func (n *IntUpDown) ValueChangeEvent() *IntEvent {
+	return &n.onValueChange
 }
 
 func (n *IntUpDown) SetVisible(v bool) {
 	n.textEditControl.SetVisible(v)
 	if n.upDownHandle != 0 {
-		if v {
-			w32.ShowWindow(n.upDownHandle, w32.SW_SHOW)
-		} else {
-			w32.ShowWindow(n.upDownHandle, w32.SW_HIDE)
-		}
-		w32.InvalidateRect(n.upDownHandle, nil, true)
+		ui.setVisible(n.upDownHandle, v)
 	}
 }
 
 func (n *IntUpDown) handleNotification(cmd uintptr) {
-	if cmd == w32.EN_CHANGE && n.onValueChange != nil {
-		n.onValueChange(n.Value())
+	if cmd == win.EN_CHANGE && !n.onValueChange.empty() {
+		n.onValueChange.fire(n.Value())
 	}
 }
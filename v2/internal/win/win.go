@@ -0,0 +1,233 @@
+// Package win contains the values of the Win32 constants used by wui. Having
+// them in a package of their own makes them available on all platforms, not
+// only when building for Windows.
+package win
+
+const (
+	UPDOWN_CLASS    = "msctls_updown32"
+	PROGRESS_CLASS  = "msctls_progress32"
+	TOOLTIPS_CLASS  = "tooltips_class32"
+	WC_TREEVIEW     = "SysTreeView32"
+	WC_TABCONTROL   = "SysTabControl32"
+	STATUSCLASSNAME = "msctls_statusbar32"
+)
+
+// Window styles.
+const (
+	WS_BORDER       = 0x00800000
+	WS_CAPTION      = 0x00C00000
+	WS_CHILD        = 0x40000000
+	WS_CLIPCHILDREN = 0x02000000
+	WS_DLGFRAME     = 0x00400000
+	WS_HSCROLL      = 0x00100000
+	WS_MAXIMIZEBOX  = 0x00010000
+	WS_MINIMIZEBOX  = 0x00020000
+	WS_POPUP        = 0x80000000
+	WS_SIZEBOX      = 0x00040000
+	WS_SYSMENU      = 0x00080000
+	WS_TABSTOP      = 0x00010000
+	WS_THICKFRAME   = 0x00040000
+	WS_VISIBLE      = 0x10000000
+	WS_VSCROLL      = 0x00200000
+)
+
+// Extended window styles.
+const (
+	WS_EX_CLIENTEDGE = 0x00000200
+	WS_EX_LAYERED    = 0x00080000
+	WS_EX_STATICEDGE = 0x00020000
+	WS_EX_TOPMOST    = 0x00000008
+)
+
+// Status bar styles.
+const (
+	SBARS_SIZEGRIP = 0x0100
+)
+
+// Tooltip styles.
+const (
+	TTS_ALWAYSTIP = 0x01
+	TTS_NOPREFIX  = 0x02
+	TTS_BALLOON   = 0x40
+)
+
+// Button styles and notifications.
+const (
+	BS_PUSHBUTTON      = 0
+	BS_AUTOCHECKBOX    = 3
+	BS_AUTORADIOBUTTON = 9
+	BS_NOTIFY          = 0x4000
+
+	BN_CLICKED = 0
+)
+
+// Combo box styles and notifications.
+const (
+	CBS_DROPDOWNLIST = 3
+
+	CBN_SELCHANGE = 1
+)
+
+// List box styles and notifications.
+const (
+	LBS_NOTIFY = 1
+
+	LBN_SELCHANGE = 1
+)
+
+// Edit control styles and notifications.
+const (
+	ES_LEFT        = 0
+	ES_MULTILINE   = 4
+	ES_PASSWORD    = 32
+	ES_AUTOVSCROLL = 64
+	ES_AUTOHSCROLL = 128
+	ES_WANTRETURN  = 4096
+	ES_NUMBER      = 8192
+
+	EN_CHANGE = 768
+)
+
+// Static control styles.
+const (
+	SS_LEFT        = 0
+	SS_CENTER      = 1
+	SS_RIGHT       = 2
+	SS_OWNERDRAW   = 13
+	SS_CENTERIMAGE = 512
+)
+
+// List view styles.
+const (
+	LVS_REPORT        = 1
+	LVS_SINGLESEL     = 4
+	LVS_SHOWSELALWAYS = 8
+	LVS_NOSORTHEADER  = 0x8000
+)
+
+// Tree view styles.
+const (
+	TVS_HASBUTTONS    = 0x0001
+	TVS_HASLINES      = 0x0002
+	TVS_LINESATROOT   = 0x0004
+	TVS_EDITLABELS    = 0x0008
+	TVS_SHOWSELALWAYS = 0x0020
+	TVS_CHECKBOXES    = 0x0100
+)
+
+// Progress bar styles.
+const (
+	PBS_SMOOTH   = 1
+	PBS_VERTICAL = 4
+	PBS_MARQUEE  = 8
+)
+
+// Track bar styles and scroll notifications.
+const (
+	TBS_AUTOTICKS = 1
+	TBS_VERT      = 2
+	TBS_HORZ      = 0
+	TBS_TOP       = 4
+	TBS_BOTTOM    = 0
+	TBS_BOTH      = 8
+	TBS_NOTICKS   = 16
+
+	TB_THUMBPOSITION = 4
+	TB_THUMBTRACK    = 5
+	TB_ENDTRACK      = 8
+)
+
+// Up-down control styles.
+const (
+	UDS_SETBUDDYINT = 2
+	UDS_ALIGNRIGHT  = 4
+	UDS_ARROWKEYS   = 32
+	UDS_NOTHOUSANDS = 128
+)
+
+// Message box flags and results.
+const (
+	MB_OK              = 0
+	MB_OKCANCEL        = 1
+	MB_YESNO           = 4
+	MB_ICONERROR       = 16
+	MB_ICONQUESTION    = 32
+	MB_ICONWARNING     = 48
+	MB_ICONINFORMATION = 64
+	MB_TOPMOST         = 0x40000
+
+	IDOK     = 1
+	IDCANCEL = 2
+	IDYES    = 6
+	IDNO     = 7
+)
+
+// System color indices.
+const (
+	COLOR_SCROLLBAR               = 0
+	COLOR_BACKGROUND              = 1
+	COLOR_DESKTOP                 = 1
+	COLOR_ACTIVECAPTION           = 2
+	COLOR_INACTIVECAPTION         = 3
+	COLOR_MENU                    = 4
+	COLOR_WINDOW                  = 5
+	COLOR_WINDOWFRAME             = 6
+	COLOR_MENUTEXT                = 7
+	COLOR_WINDOWTEXT              = 8
+	COLOR_CAPTIONTEXT             = 9
+	COLOR_ACTIVEBORDER            = 10
+	COLOR_INACTIVEBORDER          = 11
+	COLOR_APPWORKSPACE            = 12
+	COLOR_HIGHLIGHT               = 13
+	COLOR_HIGHLIGHTTEXT           = 14
+	COLOR_3DFACE                  = 15
+	COLOR_BTNFACE                 = 15
+	COLOR_3DSHADOW                = 16
+	COLOR_BTNSHADOW               = 16
+	COLOR_GRAYTEXT                = 17
+	COLOR_BTNTEXT                 = 18
+	COLOR_INACTIVECAPTIONTEXT     = 19
+	COLOR_3DHIGHLIGHT             = 20
+	COLOR_BTNHIGHLIGHT            = 20
+	COLOR_3DDKSHADOW              = 21
+	COLOR_3DLIGHT                 = 22
+	COLOR_INFOTEXT                = 23
+	COLOR_INFOBK                  = 24
+	COLOR_HOTLIGHT                = 26
+	COLOR_GRADIENTACTIVECAPTION   = 27
+	COLOR_GRADIENTINACTIVECAPTION = 28
+	COLOR_MENUHILIGHT             = 29
+	COLOR_MENUBAR                 = 30
+)
+
+// Predefined cursor resource IDs.
+const (
+	IDC_ARROW       = 32512
+	IDC_IBEAM       = 32513
+	IDC_WAIT        = 32514
+	IDC_CROSS       = 32515
+	IDC_UPARROW     = 32516
+	IDC_SIZENWSE    = 32642
+	IDC_SIZENESW    = 32643
+	IDC_SIZEWE      = 32644
+	IDC_SIZENS      = 32645
+	IDC_SIZEALL     = 32646
+	IDC_NO          = 32648
+	IDC_HAND        = 32649
+	IDC_APPSTARTING = 32650
+	IDC_HELP        = 32651
+)
+
+// Predefined icon resource IDs.
+const (
+	IDI_APPLICATION = 32512
+	IDI_ERROR       = 32513
+	IDI_QUESTION    = 32514
+	IDI_WARNING     = 32515
+	IDI_INFORMATION = 32516
+	IDI_WINLOGO     = 32517
+	IDI_SHIELD      = 32518
+)
+
+// Bitmap compression.
+const BI_RGB = 0
@@ -0,0 +1,200 @@
+package wui
+
+import "sync"
+
+// Post queues f to be called on the window's UI thread and returns right away.
+// This is the way to update controls from other goroutines, e.g. to set a
+// ProgressBar's value from a background job. Post is safe to call from any
+// goroutine.
+//
+// If the window is not yet shown, f is called once the window is shown. If the
+// window was closed, f is never called.
+func (w *Window) Post(f func()) {
+	w.calls.push(f, nil)
+}
+
+// Invoke calls f on the window's UI thread and waits until it has returned.
+// Invoke is safe to call from any goroutine. Called on the UI thread, e.g. from
+// an event handler, it calls f right away, even if the window is not yet shown.
+// Before the first window is shown there is no UI thread yet, Invoke then waits
+// until the window is shown.
+// If f panics, Invoke panics with the same value in the calling goroutine.
+//
+// Invoke returns true if f was called. It returns false if the window was
+// closed before f could run.
+func (w *Window) Invoke(f func()) bool {
+	if onUIThread() {
+		if w.calls.isClosed() {
+			return false
+		}
+		f()
+		return true
+	}
+	var panicValue interface{}
+	panicked := false
+	call := func() {
+		panicked = true
+		defer func() {
+			if panicked {
+				panicValue = recover()
+			}
+		}()
+		f()
+		panicked = false
+	}
+	done := make(chan bool, 1)
+	if !w.calls.push(call, done) {
+		return false
+	}
+	called := <-done
+	if panicked {
+		panic(panicValue)
+	}
+	return called
+}
+
+// runCalls calls all queued functions. It must only be called on the UI
+// thread. If a posted function panics, the calls after it stay queued.
+func (w *Window) runCalls() {
+	calls := w.calls.takeAll()
+	defer func() {
+		if len(calls) > 0 {
+			w.calls.requeue(calls)
+		}
+	}()
+	for len(calls) > 0 {
+		c := calls[0]
+		calls = calls[1:]
+		c.f()
+		if c.done != nil {
+			c.done <- true
+		}
+	}
+}
+
+// callQueue holds the functions that other goroutines want to run on a
+// window's UI thread. A queue is open from creation until closed. While the
+// window is shown, pushing a function wakes up its message loop.
+type callQueue struct {
+	mu     sync.Mutex
+	calls  []queuedCall
+	window uintptr
+	// ui is the backend that the window was created with. Other goroutines
+	// must not read the global ui variable.
+	ui     backend
+	closed bool
+}
+
+type queuedCall struct {
+	f    func()
+	done chan bool // done is nil for Post.
+}
+
+// push returns false if the queue is closed. In that case f is not queued.
+func (q *callQueue) push(f func(), done chan bool) bool {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return false
+	}
+	q.calls = append(q.calls, queuedCall{f: f, done: done})
+	window, ui := q.window, q.ui
+	q.mu.Unlock()
+
+	if window != 0 {
+		ui.wake(window)
+	}
+	return true
+}
+
+func (q *callQueue) takeAll() []queuedCall {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	calls := q.calls
+	q.calls = nil
+	return calls
+}
+
+// requeue puts the calls back at the front of the queue, they were taken but
+// not run. If the queue is closed, their Invoke callers are released.
+func (q *callQueue) requeue(calls []queuedCall) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		release(calls)
+		return
+	}
+	q.calls = append(calls, q.calls...)
+	window, ui := q.window, q.ui
+	q.mu.Unlock()
+
+	if window != 0 {
+		ui.wake(window)
+	}
+}
+
+func (q *callQueue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+// uiBackend is the backend that created the shown windows. Unlike the global
+// ui variable, it is safe to read from any goroutine.
+var uiBackend struct {
+	mu sync.Mutex
+	ui backend
+}
+
+// onUIThread returns true if it is called on the thread that created the
+// windows. This does not depend on a particular window being shown.
+func onUIThread() bool {
+	uiBackend.mu.Lock()
+	ui := uiBackend.ui
+	uiBackend.mu.Unlock()
+	return ui != nil && ui.isUIThread()
+}
+
+// open associates the queue with the shown window and re-opens it if it was
+// closed before. Calls that were queued before the window was shown wake up the
+// window right away.
+func (q *callQueue) open(window uintptr) {
+	q.mu.Lock()
+	q.window = window
+	q.ui = ui
+	q.closed = false
+	pending := len(q.calls) > 0
+	q.mu.Unlock()
+
+	uiBackend.mu.Lock()
+	uiBackend.ui = ui
+	uiBackend.mu.Unlock()
+
+	if pending {
+		ui.wake(window)
+	}
+}
+
+// close drops all remaining calls and releases their Invoke callers. Calls
+// pushed after close are rejected.
+func (q *callQueue) close() {
+	q.mu.Lock()
+	calls := q.calls
+	q.calls = nil
+	q.window = 0
+	q.ui = nil
+	q.closed = true
+	q.mu.Unlock()
+
+	release(calls)
+}
+
+// release tells the Invoke callers of the calls that their functions will
+// never run.
+func release(calls []queuedCall) {
+	for _, c := range calls {
+		if c.done != nil {
+			c.done <- false
+		}
+	}
+}
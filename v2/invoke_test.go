@@ -0,0 +1,171 @@
+package wui
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestCallQueueIsSafeForConcurrentUse(t *testing.T) {
+	UseHeadless()
+
+	var q callQueue
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				q.push(func() {}, nil)
+			}
+		}()
+	}
+	n := 0
+	for n < 1000 {
+		n += len(q.takeAll())
+	}
+	wg.Wait()
+	check.Eq(t, n, 1000)
+}
+
+func TestClosingCallQueueReleasesInvokers(t *testing.T) {
+	UseHeadless()
+
+	var q callQueue
+	done := make(chan bool, 1)
+	check.Eq(t, q.push(func() {}, done), true)
+	q.close()
+	check.Eq(t, <-done, false)
+	check.Eq(t, q.push(func() {}, nil), false)
+}
+
+func TestWorkersUpdateControlsThroughInvokeAndPost(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	label := NewLabel()
+	w.Add(label)
+	progress := NewProgressBar()
+	w.Add(progress)
+
+	const workers = 8
+	updates := 0
+	w.SetOnShow(func() {
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					w.Invoke(func() {
+						updates++
+						progress.SetValue(float64(updates) / (workers * 10))
+					})
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			w.Post(func() { label.SetText("done") })
+			w.Post(w.Close)
+		}()
+	})
+	check.Eq(t, w.Show(), nil)
+
+	check.Eq(t, updates, workers*10)
+	check.Eq(t, progress.Value(), 1.0)
+	check.Eq(t, label.Text(), "done")
+}
+
+func TestPostBeforeShowRunsOnceShown(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	shown := false
+	w.SetOnShow(func() { shown = true })
+	ranAfterShow := false
+	w.Post(func() {
+		ranAfterShow = shown
+		w.Close()
+	})
+	w.Show()
+	check.Eq(t, ranAfterShow, true)
+}
+
+func TestInvokeOnClosedWindowReturnsFalse(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	w.SetOnShow(w.Close)
+	w.Show()
+
+	called := false
+	check.Eq(t, w.Invoke(func() { called = true }), false)
+	check.Eq(t, called, false)
+}
+
+func TestInvokeOnTheUIThreadCallsRightAway(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	called := false
+	w.SetOnShow(func() {
+		check.Eq(t, w.Invoke(func() { called = true }), true)
+		check.Eq(t, called, true)
+		w.Close()
+	})
+	check.Eq(t, w.Show(), nil)
+}
+
+func TestInvokeOnTheUIThreadCallsRightAwayForWindowsThatAreNotShown(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	other := NewWindow()
+	called := false
+	w.SetOnShow(func() {
+		check.Eq(t, other.Invoke(func() { called = true }), true)
+		check.Eq(t, called, true)
+		w.Close()
+	})
+	check.Eq(t, w.Show(), nil)
+}
+
+func TestPanicsInInvokedFunctionsReachTheCaller(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	var recovered interface{}
+	afterPanic := false
+	w.SetOnShow(func() {
+		go func() {
+			func() {
+				defer func() { recovered = recover() }()
+				w.Invoke(func() { panic("oops") })
+			}()
+			w.Invoke(func() { afterPanic = true })
+			w.Post(w.Close)
+		}()
+	})
+	check.Eq(t, w.Show(), nil)
+
+	check.Eq(t, recovered, "oops")
+	check.Eq(t, afterPanic, true)
+}
+
+func TestCallsAfterAPanickingPostStayQueued(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	called := false
+	w.Post(func() { panic("oops") })
+	w.Post(func() { called = true })
+	func() {
+		defer func() { recover() }()
+		w.runCalls()
+	}()
+	check.Eq(t, called, false)
+	w.runCalls()
+	check.Eq(t, called, true)
+}
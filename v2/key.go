@@ -1,56 +1,55 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
-
 // Key represents a button on a keyboard. See the constants below.
 type Key int
 
-// These are the keyboard key constants.
+// These are the keyboard key constants. Their values are the Windows virtual
+// key codes.
 const (
-	KeyLeftMouseButton   = w32.VK_LBUTTON
-	KeyRightMouseButton  = w32.VK_RBUTTON
-	KeyCancel            = w32.VK_CANCEL
-	KeyMiddleMouseButton = w32.VK_MBUTTON
-	KeyXMouseButton1     = w32.VK_XBUTTON1
-	KeyXMouseButton2     = w32.VK_XBUTTON2
-	KeyBack              = w32.VK_BACK
-	KeyTab               = w32.VK_TAB
-	KeyClear             = w32.VK_CLEAR
-	KeyReturn            = w32.VK_RETURN
-	KeyShift             = w32.VK_SHIFT
-	KeyControl           = w32.VK_CONTROL
-	KeyAlt               = w32.VK_MENU
-	KeyPause             = w32.VK_PAUSE
-	KeyCapital           = w32.VK_CAPITAL
-	KeyKana              = w32.VK_KANA
-	KeyHangul            = w32.VK_HANGUL
-	KeyIMEOn             = w32.VK_IME_ON
-	KeyJunja             = w32.VK_JUNJA
-	KeyFinal             = w32.VK_FINAL
-	KeyHanja             = w32.VK_HANJA
-	KeyKanji             = w32.VK_KANJI
-	KeyIMEOff            = w32.VK_IME_OFF
-	KeyEscape            = w32.VK_ESCAPE
-	KeyConvert           = w32.VK_CONVERT
-	KeyNonConvert        = w32.VK_NONCONVERT
-	KeyAccept            = w32.VK_ACCEPT
-	KeyModeChange        = w32.VK_MODECHANGE
-	KeySpace             = w32.VK_SPACE
-	KeyPrior             = w32.VK_PRIOR
-	KeyNext              = w32.VK_NEXT
-	KeyEnd               = w32.VK_END
-	KeyHome              = w32.VK_HOME
-	KeyLeft              = w32.VK_LEFT
-	KeyUp                = w32.VK_UP
-	KeyRight             = w32.VK_RIGHT
-	KeyDown              = w32.VK_DOWN
-	KeySelect            = w32.VK_SELECT
-	KeyPrint             = w32.VK_PRINT
-	KeyExecute           = w32.VK_EXECUTE
-	KeySnapshot          = w32.VK_SNAPSHOT
-	KeyInsert            = w32.VK_INSERT
-	KeyDelete            = w32.VK_DELETE
-	KeyHelp              = w32.VK_HELP
+	KeyLeftMouseButton   = 0x01
+	KeyRightMouseButton  = 0x02
+	KeyCancel            = 0x03
+	KeyMiddleMouseButton = 0x04
+	KeyXMouseButton1     = 0x05
+	KeyXMouseButton2     = 0x06
+	KeyBack              = 0x08
+	KeyTab               = 0x09
+	KeyClear             = 0x0C
+	KeyReturn            = 0x0D
+	KeyShift             = 0x10
+	KeyControl           = 0x11
+	KeyAlt               = 0x12
+	KeyPause             = 0x13
+	KeyCapital           = 0x14
+	KeyKana              = 0x15
+	KeyHangul            = 0x15
+	KeyIMEOn             = 0x16
+	KeyJunja             = 0x17
+	KeyFinal             = 0x18
+	KeyHanja             = 0x19
+	KeyKanji             = 0x19
+	KeyIMEOff            = 0x1A
+	KeyEscape            = 0x1B
+	KeyConvert           = 0x1C
+	KeyNonConvert        = 0x1D
+	KeyAccept            = 0x1E
+	KeyModeChange        = 0x1F
+	KeySpace             = 0x20
+	KeyPrior             = 0x21
+	KeyNext              = 0x22
+	KeyEnd               = 0x23
+	KeyHome              = 0x24
+	KeyLeft              = 0x25
+	KeyUp                = 0x26
+	KeyRight             = 0x27
+	KeyDown              = 0x28
+	KeySelect            = 0x29
+	KeyPrint             = 0x2A
+	KeyExecute           = 0x2B
+	KeySnapshot          = 0x2C
+	KeyInsert            = 0x2D
+	KeyDelete            = 0x2E
+	KeyHelp              = 0x2F
 	Key0                 = '0'
 	Key1                 = '1'
 	Key2                 = '2'
@@ -87,121 +86,520 @@ const (
 	KeyX                 = 'X'
 	KeyY                 = 'Y'
 	KeyZ                 = 'Z'
-	KeyLeftWindows       = w32.VK_LWIN
-	KeyRightWindows      = w32.VK_RWIN
-	KeyApps              = w32.VK_APPS
-	KeySleep             = w32.VK_SLEEP
-	KeyNum0              = w32.VK_NUMPAD0
-	KeyNum1              = w32.VK_NUMPAD1
-	KeyNum2              = w32.VK_NUMPAD2
-	KeyNum3              = w32.VK_NUMPAD3
-	KeyNum4              = w32.VK_NUMPAD4
-	KeyNum5              = w32.VK_NUMPAD5
-	KeyNum6              = w32.VK_NUMPAD6
-	KeyNum7              = w32.VK_NUMPAD7
-	KeyNum8              = w32.VK_NUMPAD8
-	KeyNum9              = w32.VK_NUMPAD9
-	KeyMultiply          = w32.VK_MULTIPLY
-	KeyAdd               = w32.VK_ADD
-	KeySeparator         = w32.VK_SEPARATOR
-	KeySubtract          = w32.VK_SUBTRACT
-	KeyDecimal           = w32.VK_DECIMAL
-	KeyDivide            = w32.VK_DIVIDE
-	KeyF1                = w32.VK_F1
-	KeyF2                = w32.VK_F2
-	KeyF3                = w32.VK_F3
-	KeyF4                = w32.VK_F4
-	KeyF5                = w32.VK_F5
-	KeyF6                = w32.VK_F6
-	KeyF7                = w32.VK_F7
-	KeyF8                = w32.VK_F8
-	KeyF9                = w32.VK_F9
-	KeyF10               = w32.VK_F10
-	KeyF11               = w32.VK_F11
-	KeyF12               = w32.VK_F12
-	KeyF13               = w32.VK_F13
-	KeyF14               = w32.VK_F14
-	KeyF15               = w32.VK_F15
-	KeyF16               = w32.VK_F16
-	KeyF17               = w32.VK_F17
-	KeyF18               = w32.VK_F18
-	KeyF19               = w32.VK_F19
-	KeyF20               = w32.VK_F20
-	KeyF21               = w32.VK_F21
-	KeyF22               = w32.VK_F22
-	KeyF23               = w32.VK_F23
-	KeyF24               = w32.VK_F24
-	KeyNumLock           = w32.VK_NUMLOCK
-	KeyScroll            = w32.VK_SCROLL
-	KeyOEMNecEqual       = w32.VK_OEM_NEC_EQUAL
-	KeyOEMFjJisho        = w32.VK_OEM_FJ_JISHO
-	KeyOEMFjMasshou      = w32.VK_OEM_FJ_MASSHOU
-	KeyOEMFjTouroku      = w32.VK_OEM_FJ_TOUROKU
-	KeyOEMFjLoya         = w32.VK_OEM_FJ_LOYA
-	KeyOEMFjRoya         = w32.VK_OEM_FJ_ROYA
-	KeyLeftShift         = w32.VK_LSHIFT
-	KeyRightShift        = w32.VK_RSHIFT
-	KeyLeftControl       = w32.VK_LCONTROL
-	KeyRightControl      = w32.VK_RCONTROL
-	KeyLeftAlt           = w32.VK_LMENU
-	KeyRightAlt          = w32.VK_RMENU
-	KeyBrowserBack       = w32.VK_BROWSER_BACK
-	KeyBrowserForward    = w32.VK_BROWSER_FORWARD
-	KeyBrowserRefresh    = w32.VK_BROWSER_REFRESH
-	KeyBrowserStop       = w32.VK_BROWSER_STOP
-	KeyBrowserSearch     = w32.VK_BROWSER_SEARCH
-	KeyBrowserFavorites  = w32.VK_BROWSER_FAVORITES
-	KeyBrowserHome       = w32.VK_BROWSER_HOME
-	KeyVolumeMute        = w32.VK_VOLUME_MUTE
-	KeyVolumeDown        = w32.VK_VOLUME_DOWN
-	KeyVolumeUp          = w32.VK_VOLUME_UP
-	KeyMediaNextTrack    = w32.VK_MEDIA_NEXT_TRACK
-	KeyMediaPrevTrack    = w32.VK_MEDIA_PREV_TRACK
-	KeyMediaStop         = w32.VK_MEDIA_STOP
-	KeyMediaPlayPause    = w32.VK_MEDIA_PLAY_PAUSE
-	KeyLaunchMail        = w32.VK_LAUNCH_MAIL
-	KeyLaunchMediaSelect = w32.VK_LAUNCH_MEDIA_SELECT
-	KeyLaunchApp1        = w32.VK_LAUNCH_APP1
-	KeyLaunchApp2        = w32.VK_LAUNCH_APP2
-	KeyOEM1              = w32.VK_OEM_1
-	KeyOEMPlus           = w32.VK_OEM_PLUS
-	KeyOEMComma          = w32.VK_OEM_COMMA
-	KeyOEMMinus          = w32.VK_OEM_MINUS
-	KeyOEMPeriod         = w32.VK_OEM_PERIOD
-	KeyOEM2              = w32.VK_OEM_2
-	KeyOEM3              = w32.VK_OEM_3
-	KeyOEM4              = w32.VK_OEM_4
-	KeyOEM5              = w32.VK_OEM_5
-	KeyOEM6              = w32.VK_OEM_6
-	KeyOEM7              = w32.VK_OEM_7
-	KeyOEM8              = w32.VK_OEM_8
-	KeyOEMAx             = w32.VK_OEM_AX
-	KeyOEM102            = w32.VK_OEM_102
-	KeyIcoHelp           = w32.VK_ICO_HELP
-	KeyIco00             = w32.VK_ICO_00
-	KeyProcesskey        = w32.VK_PROCESSKEY
-	KeyIcoClear          = w32.VK_ICO_CLEAR
-	KeyPacket            = w32.VK_PACKET
-	KeyOEMReset          = w32.VK_OEM_RESET
-	KeyOEMJump           = w32.VK_OEM_JUMP
-	KeyOEMPA1            = w32.VK_OEM_PA1
-	KeyOEMPA2            = w32.VK_OEM_PA2
-	KeyOEMPA3            = w32.VK_OEM_PA3
-	KeyOEMWSControl      = w32.VK_OEM_WSCTRL
-	KeyOEMCuSel          = w32.VK_OEM_CUSEL
-	KeyOEMAttention      = w32.VK_OEM_ATTN
-	KeyOEMFinish         = w32.VK_OEM_FINISH
-	KeyOEMCopy           = w32.VK_OEM_COPY
-	KeyOEMAuto           = w32.VK_OEM_AUTO
-	KeyOEMEnlw           = w32.VK_OEM_ENLW
-	KeyOEMBacktab        = w32.VK_OEM_BACKTAB
-	KeyAttention         = w32.VK_ATTN
-	KeyCrSel             = w32.VK_CRSEL
-	KeyExSel             = w32.VK_EXSEL
-	KeyErEOF             = w32.VK_EREOF
-	KeyPlay              = w32.VK_PLAY
-	KeyZoom              = w32.VK_ZOOM
-	KeyNoName            = w32.VK_NONAME
-	KeyPa1               = w32.VK_PA1
-	KeyOEMClear          = w32.VK_OEM_CLEAR
+	KeyLeftWindows       = 0x5B
+	KeyRightWindows      = 0x5C
+	KeyApps              = 0x5D
+	KeySleep             = 0x5F
+	KeyNum0              = 0x60
+	KeyNum1              = 0x61
+	KeyNum2              = 0x62
+	KeyNum3              = 0x63
+	KeyNum4              = 0x64
+	KeyNum5              = 0x65
+	KeyNum6              = 0x66
+	KeyNum7              = 0x67
+	KeyNum8              = 0x68
+	KeyNum9              = 0x69
+	KeyMultiply          = 0x6A
+	KeyAdd               = 0x6B
+	KeySeparator         = 0x6C
+	KeySubtract          = 0x6D
+	KeyDecimal           = 0x6E
+	KeyDivide            = 0x6F
+	KeyF1                = 0x70
+	KeyF2                = 0x71
+	KeyF3                = 0x72
+	KeyF4                = 0x73
+	KeyF5                = 0x74
+	KeyF6                = 0x75
+	KeyF7                = 0x76
+	KeyF8                = 0x77
+	KeyF9                = 0x78
+	KeyF10               = 0x79
+	KeyF11               = 0x7A
+	KeyF12               = 0x7B
+	KeyF13               = 0x7C
+	KeyF14               = 0x7D
+	KeyF15               = 0x7E
+	KeyF16               = 0x7F
+	KeyF17               = 0x80
+	KeyF18               = 0x81
+	KeyF19               = 0x82
+	KeyF20               = 0x83
+	KeyF21               = 0x84
+	KeyF22               = 0x85
+	KeyF23               = 0x86
+	KeyF24               = 0x87
+	KeyNumLock           = 0x90
+	KeyScroll            = 0x91
+	KeyOEMNecEqual       = 0x92
+	KeyOEMFjJisho        = 0x92
+	KeyOEMFjMasshou      = 0x93
+	KeyOEMFjTouroku      = 0x94
+	KeyOEMFjLoya         = 0x95
+	KeyOEMFjRoya         = 0x96
+	KeyLeftShift         = 0xA0
+	KeyRightShift        = 0xA1
+	KeyLeftControl       = 0xA2
+	KeyRightControl      = 0xA3
+	KeyLeftAlt           = 0xA4
+	KeyRightAlt          = 0xA5
+	KeyBrowserBack       = 0xA6
+	KeyBrowserForward    = 0xA7
+	KeyBrowserRefresh    = 0xA8
+	KeyBrowserStop       = 0xA9
+	KeyBrowserSearch     = 0xAA
+	KeyBrowserFavorites  = 0xAB
+	KeyBrowserHome       = 0xAC
+	KeyVolumeMute        = 0xAD
+	KeyVolumeDown        = 0xAE
+	KeyVolumeUp          = 0xAF
+	KeyMediaNextTrack    = 0xB0
+	KeyMediaPrevTrack    = 0xB1
+	KeyMediaStop         = 0xB2
+	KeyMediaPlayPause    = 0xB3
+	KeyLaunchMail        = 0xB4
+	KeyLaunchMediaSelect = 0xB5
+	KeyLaunchApp1        = 0xB6
+	KeyLaunchApp2        = 0xB7
+	KeyOEM1              = 0xBA
+	KeyOEMPlus           = 0xBB
+	KeyOEMComma          = 0xBC
+	KeyOEMMinus          = 0xBD
+	KeyOEMPeriod         = 0xBE
+	KeyOEM2              = 0xBF
+	KeyOEM3              = 0xC0
+	KeyOEM4              = 0xDB
+	KeyOEM5              = 0xDC
+	KeyOEM6              = 0xDD
+	KeyOEM7              = 0xDE
+	KeyOEM8              = 0xDF
+	KeyOEMAx             = 0xE1
+	KeyOEM102            = 0xE2
+	KeyIcoHelp           = 0xE3
+	KeyIco00             = 0xE4
+	KeyProcesskey        = 0xE5
+	KeyIcoClear          = 0xE6
+	KeyPacket            = 0xE7
+	KeyOEMReset          = 0xE9
+	KeyOEMJump           = 0xEA
+	KeyOEMPA1            = 0xEB
+	KeyOEMPA2            = 0xEC
+	KeyOEMPA3            = 0xED
+	KeyOEMWSControl      = 0xEE
+	KeyOEMCuSel          = 0xEF
+	KeyOEMAttention      = 0xF0
+	KeyOEMFinish         = 0xF1
+	KeyOEMCopy           = 0xF2
+	KeyOEMAuto           = 0xF3
+	KeyOEMEnlw           = 0xF4
+	KeyOEMBacktab        = 0xF5
+	KeyAttention         = 0xF6
+	KeyCrSel             = 0xF7
+	KeyExSel             = 0xF8
+	KeyErEOF             = 0xF9
+	KeyPlay              = 0xFA
+	KeyZoom              = 0xFB
+	KeyNoName            = 0xFC
+	KeyPa1               = 0xFD
+	KeyOEMClear          = 0xFE
 )
+
+func (k Key) String() string {
+	// NOTE that these strings are used in the designer to get their
+	// representations as Go code so they must always correspond to their
+	// constant names and be prefixed with the package name. For keys that have
+	// more than one name, the first one is used.
+	switch k {
+	case KeyLeftMouseButton:
+		return "wui.KeyLeftMouseButton"
+	case KeyRightMouseButton:
+		return "wui.KeyRightMouseButton"
+	case KeyCancel:
+		return "wui.KeyCancel"
+	case KeyMiddleMouseButton:
+		return "wui.KeyMiddleMouseButton"
+	case KeyXMouseButton1:
+		return "wui.KeyXMouseButton1"
+	case KeyXMouseButton2:
+		return "wui.KeyXMouseButton2"
+	case KeyBack:
+		return "wui.KeyBack"
+	case KeyTab:
+		return "wui.KeyTab"
+	case KeyClear:
+		return "wui.KeyClear"
+	case KeyReturn:
+		return "wui.KeyReturn"
+	case KeyShift:
+		return "wui.KeyShift"
+	case KeyControl:
+		return "wui.KeyControl"
+	case KeyAlt:
+		return "wui.KeyAlt"
+	case KeyPause:
+		return "wui.KeyPause"
+	case KeyCapital:
+		return "wui.KeyCapital"
+	case KeyKana:
+		return "wui.KeyKana"
+	case KeyIMEOn:
+		return "wui.KeyIMEOn"
+	case KeyJunja:
+		return "wui.KeyJunja"
+	case KeyFinal:
+		return "wui.KeyFinal"
+	case KeyHanja:
+		return "wui.KeyHanja"
+	case KeyIMEOff:
+		return "wui.KeyIMEOff"
+	case KeyEscape:
+		return "wui.KeyEscape"
+	case KeyConvert:
+		return "wui.KeyConvert"
+	case KeyNonConvert:
+		return "wui.KeyNonConvert"
+	case KeyAccept:
+		return "wui.KeyAccept"
+	case KeyModeChange:
+		return "wui.KeyModeChange"
+	case KeySpace:
+		return "wui.KeySpace"
+	case KeyPrior:
+		return "wui.KeyPrior"
+	case KeyNext:
+		return "wui.KeyNext"
+	case KeyEnd:
+		return "wui.KeyEnd"
+	case KeyHome:
+		return "wui.KeyHome"
+	case KeyLeft:
+		return "wui.KeyLeft"
+	case KeyUp:
+		return "wui.KeyUp"
+	case KeyRight:
+		return "wui.KeyRight"
+	case KeyDown:
+		return "wui.KeyDown"
+	case KeySelect:
+		return "wui.KeySelect"
+	case KeyPrint:
+		return "wui.KeyPrint"
+	case KeyExecute:
+		return "wui.KeyExecute"
+	case KeySnapshot:
+		return "wui.KeySnapshot"
+	case KeyInsert:
+		return "wui.KeyInsert"
+	case KeyDelete:
+		return "wui.KeyDelete"
+	case KeyHelp:
+		return "wui.KeyHelp"
+	case Key0:
+		return "wui.Key0"
+	case Key1:
+		return "wui.Key1"
+	case Key2:
+		return "wui.Key2"
+	case Key3:
+		return "wui.Key3"
+	case Key4:
+		return "wui.Key4"
+	case Key5:
+		return "wui.Key5"
+	case Key6:
+		return "wui.Key6"
+	case Key7:
+		return "wui.Key7"
+	case Key8:
+		return "wui.Key8"
+	case Key9:
+		return "wui.Key9"
+	case KeyA:
+		return "wui.KeyA"
+	case KeyB:
+		return "wui.KeyB"
+	case KeyC:
+		return "wui.KeyC"
+	case KeyD:
+		return "wui.KeyD"
+	case KeyE:
+		return "wui.KeyE"
+	case KeyF:
+		return "wui.KeyF"
+	case KeyG:
+		return "wui.KeyG"
+	case KeyH:
+		return "wui.KeyH"
+	case KeyI:
+		return "wui.KeyI"
+	case KeyJ:
+		return "wui.KeyJ"
+	case KeyK:
+		return "wui.KeyK"
+	case KeyL:
+		return "wui.KeyL"
+	case KeyM:
+		return "wui.KeyM"
+	case KeyN:
+		return "wui.KeyN"
+	case KeyO:
+		return "wui.KeyO"
+	case KeyP:
+		return "wui.KeyP"
+	case KeyQ:
+		return "wui.KeyQ"
+	case KeyR:
+		return "wui.KeyR"
+	case KeyS:
+		return "wui.KeyS"
+	case KeyT:
+		return "wui.KeyT"
+	case KeyU:
+		return "wui.KeyU"
+	case KeyV:
+		return "wui.KeyV"
+	case KeyW:
+		return "wui.KeyW"
+	case KeyX:
+		return "wui.KeyX"
+	case KeyY:
+		return "wui.KeyY"
+	case KeyZ:
+		return "wui.KeyZ"
+	case KeyLeftWindows:
+		return "wui.KeyLeftWindows"
+	case KeyRightWindows:
+		return "wui.KeyRightWindows"
+	case KeyApps:
+		return "wui.KeyApps"
+	case KeySleep:
+		return "wui.KeySleep"
+	case KeyNum0:
+		return "wui.KeyNum0"
+	case KeyNum1:
+		return "wui.KeyNum1"
+	case KeyNum2:
+		return "wui.KeyNum2"
+	case KeyNum3:
+		return "wui.KeyNum3"
+	case KeyNum4:
+		return "wui.KeyNum4"
+	case KeyNum5:
+		return "wui.KeyNum5"
+	case KeyNum6:
+		return "wui.KeyNum6"
+	case KeyNum7:
+		return "wui.KeyNum7"
+	case KeyNum8:
+		return "wui.KeyNum8"
+	case KeyNum9:
+		return "wui.KeyNum9"
+	case KeyMultiply:
+		return "wui.KeyMultiply"
+	case KeyAdd:
+		return "wui.KeyAdd"
+	case KeySeparator:
+		return "wui.KeySeparator"
+	case KeySubtract:
+		return "wui.KeySubtract"
+	case KeyDecimal:
+		return "wui.KeyDecimal"
+	case KeyDivide:
+		return "wui.KeyDivide"
+	case KeyF1:
+		return "wui.KeyF1"
+	case KeyF2:
+		return "wui.KeyF2"
+	case KeyF3:
+		return "wui.KeyF3"
+	case KeyF4:
+		return "wui.KeyF4"
+	case KeyF5:
+		return "wui.KeyF5"
+	case KeyF6:
+		return "wui.KeyF6"
+	case KeyF7:
+		return "wui.KeyF7"
+	case KeyF8:
+		return "wui.KeyF8"
+	case KeyF9:
+		return "wui.KeyF9"
+	case KeyF10:
+		return "wui.KeyF10"
+	case KeyF11:
+		return "wui.KeyF11"
+	case KeyF12:
+		return "wui.KeyF12"
+	case KeyF13:
+		return "wui.KeyF13"
+	case KeyF14:
+		return "wui.KeyF14"
+	case KeyF15:
+		return "wui.KeyF15"
+	case KeyF16:
+		return "wui.KeyF16"
+	case KeyF17:
+		return "wui.KeyF17"
+	case KeyF18:
+		return "wui.KeyF18"
+	case KeyF19:
+		return "wui.KeyF19"
+	case KeyF20:
+		return "wui.KeyF20"
+	case KeyF21:
+		return "wui.KeyF21"
+	case KeyF22:
+		return "wui.KeyF22"
+	case KeyF23:
+		return "wui.KeyF23"
+	case KeyF24:
+		return "wui.KeyF24"
+	case KeyNumLock:
+		return "wui.KeyNumLock"
+	case KeyScroll:
+		return "wui.KeyScroll"
+	case KeyOEMNecEqual:
+		return "wui.KeyOEMNecEqual"
+	case KeyOEMFjMasshou:
+		return "wui.KeyOEMFjMasshou"
+	case KeyOEMFjTouroku:
+		return "wui.KeyOEMFjTouroku"
+	case KeyOEMFjLoya:
+		return "wui.KeyOEMFjLoya"
+	case KeyOEMFjRoya:
+		return "wui.KeyOEMFjRoya"
+	case KeyLeftShift:
+		return "wui.KeyLeftShift"
+	case KeyRightShift:
+		return "wui.KeyRightShift"
+	case KeyLeftControl:
+		return "wui.KeyLeftControl"
+	case KeyRightControl:
+		return "wui.KeyRightControl"
+	case KeyLeftAlt:
+		return "wui.KeyLeftAlt"
+	case KeyRightAlt:
+		return "wui.KeyRightAlt"
+	case KeyBrowserBack:
+		return "wui.KeyBrowserBack"
+	case KeyBrowserForward:
+		return "wui.KeyBrowserForward"
+	case KeyBrowserRefresh:
+		return "wui.KeyBrowserRefresh"
+	case KeyBrowserStop:
+		return "wui.KeyBrowserStop"
+	case KeyBrowserSearch:
+		return "wui.KeyBrowserSearch"
+	case KeyBrowserFavorites:
+		return "wui.KeyBrowserFavorites"
+	case KeyBrowserHome:
+		return "wui.KeyBrowserHome"
+	case KeyVolumeMute:
+		return "wui.KeyVolumeMute"
+	case KeyVolumeDown:
+		return "wui.KeyVolumeDown"
+	case KeyVolumeUp:
+		return "wui.KeyVolumeUp"
+	case KeyMediaNextTrack:
+		return "wui.KeyMediaNextTrack"
+	case KeyMediaPrevTrack:
+		return "wui.KeyMediaPrevTrack"
+	case KeyMediaStop:
+		return "wui.KeyMediaStop"
+	case KeyMediaPlayPause:
+		return "wui.KeyMediaPlayPause"
+	case KeyLaunchMail:
+		return "wui.KeyLaunchMail"
+	case KeyLaunchMediaSelect:
+		return "wui.KeyLaunchMediaSelect"
+	case KeyLaunchApp1:
+		return "wui.KeyLaunchApp1"
+	case KeyLaunchApp2:
+		return "wui.KeyLaunchApp2"
+	case KeyOEM1:
+		return "wui.KeyOEM1"
+	case KeyOEMPlus:
+		return "wui.KeyOEMPlus"
+	case KeyOEMComma:
+		return "wui.KeyOEMComma"
+	case KeyOEMMinus:
+		return "wui.KeyOEMMinus"
+	case KeyOEMPeriod:
+		return "wui.KeyOEMPeriod"
+	case KeyOEM2:
+		return "wui.KeyOEM2"
+	case KeyOEM3:
+		return "wui.KeyOEM3"
+	case KeyOEM4:
+		return "wui.KeyOEM4"
+	case KeyOEM5:
+		return "wui.KeyOEM5"
+	case KeyOEM6:
+		return "wui.KeyOEM6"
+	case KeyOEM7:
+		return "wui.KeyOEM7"
+	case KeyOEM8:
+		return "wui.KeyOEM8"
+	case KeyOEMAx:
+		return "wui.KeyOEMAx"
+	case KeyOEM102:
+		return "wui.KeyOEM102"
+	case KeyIcoHelp:
+		return "wui.KeyIcoHelp"
+	case KeyIco00:
+		return "wui.KeyIco00"
+	case KeyProcesskey:
+		return "wui.KeyProcesskey"
+	case KeyIcoClear:
+		return "wui.KeyIcoClear"
+	case KeyPacket:
+		return "wui.KeyPacket"
+	case KeyOEMReset:
+		return "wui.KeyOEMReset"
+	case KeyOEMJump:
+		return "wui.KeyOEMJump"
+	case KeyOEMPA1:
+		return "wui.KeyOEMPA1"
+	case KeyOEMPA2:
+		return "wui.KeyOEMPA2"
+	case KeyOEMPA3:
+		return "wui.KeyOEMPA3"
+	case KeyOEMWSControl:
+		return "wui.KeyOEMWSControl"
+	case KeyOEMCuSel:
+		return "wui.KeyOEMCuSel"
+	case KeyOEMAttention:
+		return "wui.KeyOEMAttention"
+	case KeyOEMFinish:
+		return "wui.KeyOEMFinish"
+	case KeyOEMCopy:
+		return "wui.KeyOEMCopy"
+	case KeyOEMAuto:
+		return "wui.KeyOEMAuto"
+	case KeyOEMEnlw:
+		return "wui.KeyOEMEnlw"
+	case KeyOEMBacktab:
+		return "wui.KeyOEMBacktab"
+	case KeyAttention:
+		return "wui.KeyAttention"
+	case KeyCrSel:
+		return "wui.KeyCrSel"
+	case KeyExSel:
+		return "wui.KeyExSel"
+	case KeyErEOF:
+		return "wui.KeyErEOF"
+	case KeyPlay:
+		return "wui.KeyPlay"
+	case KeyZoom:
+		return "wui.KeyZoom"
+	case KeyNoName:
+		return "wui.KeyNoName"
+	case KeyPa1:
+		return "wui.KeyPa1"
+	case KeyOEMClear:
+		return "wui.KeyOEMClear"
+	default:
+		return "unknown Key"
+	}
+}
@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewLabel() *Label {
 	return &Label{}
@@ -46,30 +46,26 @@ func (a TextAlignment) String() string {
 }
 
 func (l *Label) create(id int) {
-	l.textControl.create(id, 0, "STATIC", w32.SS_CENTERIMAGE|alignStyle(l.alignment))
+	l.textControl.create(id, 0, "STATIC", win.SS_CENTERIMAGE|alignStyle(l.alignment))
 }
 
 func alignStyle(a TextAlignment) uint {
 	if a == AlignCenter {
-		return w32.SS_CENTER
+		return win.SS_CENTER
 	}
 	if a == AlignRight {
-		return w32.SS_RIGHT
+		return win.SS_RIGHT
 	}
-	return w32.SS_LEFT
+	return win.SS_LEFT
 }
 
 func (l *Label) SetAlignment(a TextAlignment) {
 	l.alignment = a
 	if l.handle != 0 {
-		style := uint(w32.GetWindowLongPtr(l.handle, w32.GWL_STYLE))
-		style = style &^ w32.SS_LEFT &^ w32.SS_CENTER &^ w32.SS_RIGHT
-		w32.SetWindowLongPtr(
-			l.handle,
-			w32.GWL_STYLE,
-			uintptr(style|alignStyle(l.alignment)),
-		)
-		w32.InvalidateRect(l.handle, nil, true)
+		style, exStyle := ui.style(l.handle)
+		style = style &^ win.SS_LEFT &^ win.SS_CENTER &^ win.SS_RIGHT
+		ui.setStyle(l.handle, style|alignStyle(l.alignment), exStyle)
+		ui.repaint(l.handle)
 	}
 }
 
@@ -0,0 +1,305 @@
+package wui
+
+import "math"
+
+// Layout positions the children of a Container automatically. Set it with
+// Window.SetLayout or Panel.SetLayout. The layout is applied whenever the
+// container changes size and whenever children are added or removed. While a
+// container has a layout, the anchors of its children are ignored.
+//
+// Layouts work with the preferred and minimum sizes of the controls. The
+// preferred size of a control is its size at the time it is first laid out,
+// unless you set it with SetPreferredSize. The minimum size is 0 by default.
+// Panels that have a layout of their own use that layout's sizes. Invisible
+// controls are left out.
+//
+// The available layouts are Grid (NewGrid), Box (NewHBox and NewVBox) and Flow
+// (NewFlow).
+type Layout interface {
+	// arrange returns the bounds for the items in an area of the given size.
+	// The bounds are relative to the top-left corner of the area.
+	arrange(width, height int, items []layoutItem) []Rectangle
+	// measure returns the size that the layout needs to give all items their
+	// preferred size and the size it needs at least.
+	measure(items []layoutItem) (preferred, min size)
+	// forget drops all settings for the control, it was removed from the
+	// container.
+	forget(c Control)
+	base() *layoutBase
+}
+
+type size struct {
+	width, height int
+}
+
+// layoutItem is a control that takes part in a layout.
+type layoutItem struct {
+	control   Control
+	preferred size
+	min       size
+}
+
+// layoutBase holds the settings that all layouts have in common.
+type layoutBase struct {
+	margin    int
+	preferred map[Control]size
+	min       map[Control]size
+	// initial is the size that a control had the first time it was laid out.
+	// It is its preferred size if none was set explicitly.
+	initial map[Control]size
+	// container is the container that the layout is set on and field is the
+	// container's variable that holds the layout.
+	container Container
+	field     *Layout
+}
+
+func (l *layoutBase) base() *layoutBase {
+	return l
+}
+
+func (l *layoutBase) forget(c Control) {
+	delete(l.preferred, c)
+	delete(l.min, c)
+	delete(l.initial, c)
+}
+
+// Margin returns the space in pixels between the container's borders and its
+// children.
+func (l *layoutBase) Margin() int {
+	return l.margin
+}
+
+// SetMargin sets the space in pixels between the container's borders and its
+// children.
+func (l *layoutBase) SetMargin(m int) {
+	l.margin = m
+	l.update()
+}
+
+// SetPreferredSize sets the size that the layout gives the control if there is
+// enough space.
+func (l *layoutBase) SetPreferredSize(c Control, width, height int) {
+	if l.preferred == nil {
+		l.preferred = make(map[Control]size)
+	}
+	l.preferred[c] = size{width, height}
+	l.update()
+}
+
+// PreferredSize returns the size that the layout gives the control if there is
+// enough space.
+func (l *layoutBase) PreferredSize(c Control) (width, height int) {
+	s := l.preferredSize(c)
+	return s.width, s.height
+}
+
+// SetMinSize sets the size below which the layout does not shrink the
+// control.
+func (l *layoutBase) SetMinSize(c Control, width, height int) {
+	if l.min == nil {
+		l.min = make(map[Control]size)
+	}
+	l.min[c] = size{width, height}
+	l.update()
+}
+
+// MinSize returns the size below which the layout does not shrink the control.
+func (l *layoutBase) MinSize(c Control) (width, height int) {
+	s := l.minSize(c)
+	return s.width, s.height
+}
+
+func (l *layoutBase) preferredSize(c Control) size {
+	if s, ok := l.preferred[c]; ok {
+		return s
+	}
+	if sub := nestedLayout(c); sub != nil {
+		preferred, _ := measureContainer(c.(Container), sub)
+		return preferred
+	}
+	if s, ok := l.initial[c]; ok {
+		return s
+	}
+	_, _, width, height := c.Bounds()
+	if l.initial == nil {
+		l.initial = make(map[Control]size)
+	}
+	l.initial[c] = size{width, height}
+	return size{width, height}
+}
+
+func (l *layoutBase) minSize(c Control) size {
+	if s, ok := l.min[c]; ok {
+		return s
+	}
+	if sub := nestedLayout(c); sub != nil {
+		_, min := measureContainer(c.(Container), sub)
+		return min
+	}
+	return size{}
+}
+
+// update re-applies the layout after one of its settings changed.
+func (l *layoutBase) update() {
+	if l.container != nil {
+		relayout(l.container)
+	}
+}
+
+// nestedLayout returns the layout of c if it is a container with a layout.
+func nestedLayout(c Control) Layout {
+	if con, ok := c.(Container); ok {
+		return con.Layout()
+	}
+	return nil
+}
+
+// layoutItems returns the visible children of c with their sizes.
+func layoutItems(c Container, l Layout) []layoutItem {
+	var items []layoutItem
+	for _, child := range c.Children() {
+		if !child.Visible() {
+			continue
+		}
+		items = append(items, layoutItem{
+			control:   child,
+			preferred: l.base().preferredSize(child),
+			min:       l.base().minSize(child),
+		})
+	}
+	return items
+}
+
+// measureContainer returns the outer sizes that container c needs for its
+// layout.
+func measureContainer(c Container, l Layout) (preferred, min size) {
+	preferred, min = l.measure(layoutItems(c, l))
+	_, _, width, height := c.Bounds()
+	_, _, innerWidth, innerHeight := c.InnerBounds()
+	frameW := width - innerWidth
+	frameH := height - innerHeight
+	preferred.width += frameW
+	preferred.height += frameH
+	min.width += frameW
+	min.height += frameH
+	return
+}
+
+// setLayout replaces the layout of container c, which is stored in current,
+// and applies the new layout. A layout that is still set on another container
+// is removed from there first.
+func setLayout(c Container, current *Layout, l Layout) {
+	if l != nil && l.base().field != nil && l.base().field != current {
+		detachLayout(l)
+	}
+	if *current != nil && *current != l {
+		detachLayout(*current)
+	}
+	*current = l
+	if l != nil {
+		l.base().container = c
+		l.base().field = current
+		relayout(c)
+	}
+}
+
+// detachLayout removes the layout from its container. The children keep their
+// bounds.
+func detachLayout(l Layout) {
+	b := l.base()
+	for _, child := range b.container.Children() {
+		l.forget(child)
+	}
+	*b.field = nil
+	b.container = nil
+	b.field = nil
+}
+
+// forgetChild makes the layout of c, if any, forget the child that was removed
+// from c.
+func forgetChild(c Container, child Control) {
+	if l := c.Layout(); l != nil {
+		l.forget(child)
+	}
+}
+
+// relayout applies the layout of c to its children. It does nothing if c has
+// no layout.
+func relayout(c Container) {
+	l := c.Layout()
+	if l == nil {
+		return
+	}
+	items := layoutItems(c, l)
+	_, _, width, height := c.InnerBounds()
+	bounds := l.arrange(width, height, items)
+	for i, item := range items {
+		b := bounds[i]
+		item.control.SetBounds(b.X, b.Y, b.Width, b.Height)
+	}
+}
+
+// shrink removes the margin m from all sides of an area.
+func shrink(width, height, m int) (x, y, w, h int) {
+	return m, m, maxInt(0, width-2*m), maxInt(0, height-2*m)
+}
+
+// share splits total into parts that are proportional to the weights. The
+// parts add up to total, unless all weights are 0.
+func share(total int, weights []float64) []int {
+	parts := make([]int, len(weights))
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	if sum <= 0 {
+		return parts
+	}
+	acc := 0.0
+	given := 0
+	for i, w := range weights {
+		acc += w
+		end := int(math.Round(float64(total) * acc / sum))
+		parts[i] = end - given
+		given = end
+	}
+	return parts
+}
+
+// shareAtLeast splits total like share does but makes every part at least as
+// large as its minimum. Parts that would be too small get their minimum and
+// the rest is shared among the others.
+func shareAtLeast(total int, weights []float64, mins []int) []int {
+	atMin := make([]bool, len(weights))
+	for {
+		rest := total
+		w := make([]float64, len(weights))
+		for i := range weights {
+			if atMin[i] {
+				rest -= mins[i]
+			} else {
+				w[i] = weights[i]
+			}
+		}
+		parts := share(maxInt(0, rest), w)
+		done := true
+		for i := range parts {
+			if atMin[i] {
+				parts[i] = mins[i]
+			} else if parts[i] < mins[i] {
+				atMin[i] = true
+				done = false
+			}
+		}
+		if done {
+			return parts
+		}
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,282 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func items(controls []Control, preferred ...size) []layoutItem {
+	items := make([]layoutItem, len(controls))
+	for i := range items {
+		items[i] = layoutItem{control: controls[i], preferred: preferred[i]}
+	}
+	return items
+}
+
+func buttons(n int) []Control {
+	controls := make([]Control, n)
+	for i := range controls {
+		controls[i] = NewButton()
+	}
+	return controls
+}
+
+func TestShareAddsUpToTotal(t *testing.T) {
+	check.Eq(t, share(10, []float64{1, 1, 1}), []int{3, 4, 3})
+	check.Eq(t, share(10, []float64{1, 0, 4}), []int{2, 0, 8})
+	check.Eq(t, share(10, []float64{0, 0}), []int{0, 0})
+	check.Eq(t, share(0, []float64{1, 2}), []int{0, 0})
+}
+
+func TestShareAtLeastKeepsMinimums(t *testing.T) {
+	check.Eq(t, shareAtLeast(100, []float64{1, 1}, []int{0, 0}), []int{50, 50})
+	check.Eq(t, shareAtLeast(100, []float64{1, 1}, []int{0, 70}), []int{30, 70})
+	check.Eq(t, shareAtLeast(100, []float64{1, 3}, []int{40, 0}), []int{40, 60})
+	check.Eq(t, shareAtLeast(50, []float64{1, 1}, []int{40, 40}), []int{40, 40})
+}
+
+func TestGridWithFixedAutoAndStarSizes(t *testing.T) {
+	c := buttons(4)
+	g := NewGrid()
+	g.SetColumns(GridFixed(50), GridAuto(), GridStar(1), GridStar(2))
+	g.SetRows(GridAuto(), GridStar(1))
+	g.Place(c[0], 0, 0)
+	g.Place(c[1], 0, 1)
+	g.Place(c[2], 1, 2)
+	g.Place(c[3], 1, 3)
+
+	bounds := g.arrange(410, 300, items(c,
+		size{10, 20},
+		size{60, 30},
+		size{10, 10},
+		size{10, 10},
+	))
+	check.Eq(t, bounds, []Rectangle{
+		{X: 0, Y: 0, Width: 50, Height: 30},
+		{X: 50, Y: 0, Width: 60, Height: 30},
+		{X: 110, Y: 30, Width: 100, Height: 270},
+		{X: 210, Y: 30, Width: 200, Height: 270},
+	})
+}
+
+func TestGridSpansAndSpacing(t *testing.T) {
+	c := buttons(3)
+	g := NewGrid()
+	g.SetSpacing(10)
+	g.SetMargin(5)
+	g.PlaceSpan(c[0], 0, 0, 1, 2)
+	g.Place(c[1], 1, 0)
+	g.PlaceSpan(c[2], 1, 1, 2, 1)
+
+	bounds := g.arrange(110, 110, items(c, size{}, size{}, size{}))
+	check.Eq(t, bounds, []Rectangle{
+		{X: 5, Y: 5, Width: 100, Height: 27},
+		{X: 5, Y: 42, Width: 45, Height: 26},
+		{X: 60, Y: 42, Width: 45, Height: 63},
+	})
+}
+
+func TestGridSpanningItemEnlargesAutoColumns(t *testing.T) {
+	c := buttons(2)
+	g := NewGrid()
+	g.SetColumns(GridAuto(), GridAuto(), GridStar(1))
+	g.Place(c[0], 0, 0)
+	g.PlaceSpan(c[1], 1, 0, 1, 2)
+
+	preferred, min := g.measure(items(c, size{20, 10}, size{100, 10}))
+	check.Eq(t, preferred, size{100, 20})
+	check.Eq(t, min, size{100, 0})
+
+	bounds := g.arrange(300, 20, items(c, size{20, 10}, size{100, 10}))
+	check.Eq(t, bounds[0], Rectangle{X: 0, Y: 0, Width: 60, Height: 10})
+	check.Eq(t, bounds[1], Rectangle{X: 0, Y: 10, Width: 100, Height: 10})
+}
+
+func TestUnplacedGridChildrenKeepTheirBounds(t *testing.T) {
+	b := NewButton()
+	b.SetBounds(1, 2, 3, 4)
+	g := NewGrid()
+	bounds := g.arrange(100, 100, items([]Control{b}, size{}))
+	check.Eq(t, bounds, []Rectangle{{X: 1, Y: 2, Width: 3, Height: 4}})
+}
+
+func TestHBoxStretchesAndShrinks(t *testing.T) {
+	c := buttons(3)
+	box := NewHBox()
+	box.SetSpacing(10)
+	box.SetStretch(c[1], 1)
+	box.SetStretch(c[2], 3)
+	list := items(c, size{50, 10}, size{50, 10}, size{50, 10})
+
+	check.Eq(t, box.arrange(210, 30, list), []Rectangle{
+		{X: 0, Y: 0, Width: 50, Height: 30},
+		{X: 60, Y: 0, Width: 60, Height: 30},
+		{X: 130, Y: 0, Width: 80, Height: 30},
+	})
+
+	list[0].min = size{50, 0}
+	list[1].min = size{20, 0}
+	list[2].min = size{40, 0}
+	check.Eq(t, box.arrange(140, 30, list), []Rectangle{
+		{X: 0, Y: 0, Width: 50, Height: 30},
+		{X: 60, Y: 0, Width: 27, Height: 30},
+		{X: 97, Y: 0, Width: 43, Height: 30},
+	})
+
+	// Below the minimum sizes, the controls overflow.
+	check.Eq(t, box.arrange(0, 30, list), []Rectangle{
+		{X: 0, Y: 0, Width: 50, Height: 30},
+		{X: 60, Y: 0, Width: 20, Height: 30},
+		{X: 90, Y: 0, Width: 40, Height: 30},
+	})
+}
+
+func TestVBoxWithoutStretchLeavesSpaceAtTheEnd(t *testing.T) {
+	c := buttons(2)
+	box := NewVBox()
+	box.SetMargin(4)
+	bounds := box.arrange(100, 200, items(c, size{10, 20}, size{30, 40}))
+	check.Eq(t, bounds, []Rectangle{
+		{X: 4, Y: 4, Width: 92, Height: 20},
+		{X: 4, Y: 24, Width: 92, Height: 40},
+	})
+
+	preferred, min := box.measure(items(c, size{10, 20}, size{30, 40}))
+	check.Eq(t, preferred, size{38, 68})
+	check.Eq(t, min, size{8, 8})
+}
+
+func TestFlowWrapsLines(t *testing.T) {
+	c := buttons(4)
+	f := NewFlow()
+	f.SetSpacing(5)
+	bounds := f.arrange(100, 100, items(c,
+		size{40, 10},
+		size{40, 20},
+		size{30, 10},
+		size{200, 10},
+	))
+	check.Eq(t, bounds, []Rectangle{
+		{X: 0, Y: 0, Width: 40, Height: 10},
+		{X: 45, Y: 0, Width: 40, Height: 20},
+		{X: 0, Y: 25, Width: 30, Height: 10},
+		{X: 0, Y: 40, Width: 200, Height: 10},
+	})
+}
+
+func TestLayoutRunsOnResizeAndAddRemove(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	w.SetInnerSize(200, 100)
+	box := NewHBox()
+	w.SetLayout(box)
+
+	a := NewButton()
+	a.SetSize(50, 20)
+	w.Add(a)
+	check.Eq(t, rect(a), Rectangle{X: 0, Y: 0, Width: 50, Height: 100})
+
+	b := NewButton()
+	b.SetSize(30, 20)
+	box.SetStretch(b, 1)
+	w.Add(b)
+	check.Eq(t, rect(b), Rectangle{X: 50, Y: 0, Width: 150, Height: 100})
+
+	w.SetOnShow(func() {
+		w.SetInnerSize(300, 50)
+		check.Eq(t, rect(b), Rectangle{X: 50, Y: 0, Width: 250, Height: 50})
+
+		w.Remove(a)
+		check.Eq(t, rect(b), Rectangle{X: 0, Y: 0, Width: 300, Height: 50})
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestNestedLayoutsUseTheirPreferredSize(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	w.SetInnerSize(300, 100)
+	outer := NewHBox()
+	w.SetLayout(outer)
+
+	p := NewPanel()
+	inner := NewVBox()
+	inner.SetSpacing(10)
+	p.SetLayout(inner)
+	a, b := NewButton(), NewButton()
+	inner.SetPreferredSize(a, 80, 20)
+	inner.SetPreferredSize(b, 60, 20)
+	p.Add(a)
+	p.Add(b)
+	w.Add(p)
+
+	check.Eq(t, rect(p), Rectangle{X: 0, Y: 0, Width: 80, Height: 100})
+	check.Eq(t, rect(a), Rectangle{X: 0, Y: 0, Width: 80, Height: 20})
+	check.Eq(t, rect(b), Rectangle{X: 0, Y: 30, Width: 80, Height: 20})
+}
+
+func TestRemovedControlsAreForgottenByTheLayout(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	box := NewHBox()
+	w.SetLayout(box)
+	b := NewButton()
+	w.Add(b)
+	box.SetStretch(b, 1)
+	box.SetPreferredSize(b, 10, 10)
+	box.SetMinSize(b, 5, 5)
+
+	w.Remove(b)
+	check.Eq(t, len(box.stretch), 0)
+	check.Eq(t, len(box.preferred), 0)
+	check.Eq(t, len(box.min), 0)
+	check.Eq(t, len(box.initial), 0)
+}
+
+func TestLayoutIsOnlySetOnOneContainer(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	p := NewPanel()
+	box := NewVBox()
+	w.SetLayout(box)
+	p.SetLayout(box)
+	check.Eq(t, w.Layout(), nil)
+	check.Eq(t, p.Layout(), box)
+	check.Eq(t, box.container, p)
+
+	w.SetLayout(NewHBox())
+	check.Eq(t, p.Layout(), box)
+	p.SetLayout(nil)
+	check.Eq(t, box.container, nil)
+}
+
+func TestHidingAControlRunsTheLayout(t *testing.T) {
+	UseHeadless()
+
+	w := NewWindow()
+	w.SetInnerSize(200, 100)
+	w.SetLayout(NewHBox())
+	a := NewButton()
+	a.SetSize(50, 20)
+	w.Add(a)
+	b := NewButton()
+	b.SetSize(30, 20)
+	w.Add(b)
+	check.Eq(t, b.X(), 50)
+
+	a.SetVisible(false)
+	check.Eq(t, b.X(), 0)
+	a.SetVisible(true)
+	check.Eq(t, b.X(), 50)
+}
+
+func rect(c interface{ Bounds() (x, y, w, h int) }) Rectangle {
+	x, y, w, h := c.Bounds()
+	return Rectangle{X: x, Y: y, Width: w, Height: h}
+}
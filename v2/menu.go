@@ -1,12 +1,5 @@
 package wui
 
-import (
-	"syscall"
-	"unsafe"
-
-	"github.com/gonutz/w32/v2"
-)
-
 // NewMainMenu returns a new menu bar that can be added to a Window. You can add
 // sub-menus to it with Menu.Add.
 func NewMainMenu() *Menu {
@@ -25,9 +18,15 @@ func NewMenu(name string) *Menu {
 
 // Menu is a named container for MenuItems. It is not executable, clicking a
 // Menu will expand it and show its children. See NewMainMenu and NewMenu.
+//
+// All changes to a Menu and its items are shown right away, even while the
+// menu is open.
 type Menu struct {
-	name  string
-	items []MenuItem
+	name     string
+	items    []MenuItem
+	disabled bool
+	onOpen   Event
+	natives  []menuNative
 }
 
 // MenuItem is something that can go into a menu. Possible such things can be
@@ -40,10 +39,123 @@ func (*Menu) isMenuItem() {}
 
 // Add appends the given MenuItem to the Menu.
 func (m *Menu) Add(item MenuItem) *Menu {
-	m.items = append(m.items, item)
+	return m.Insert(len(m.items), item)
+}
+
+// Insert inserts the given MenuItem at the index, moving the items from there
+// on back by one. Indices that are out of range are clamped to the start or
+// end of the menu.
+func (m *Menu) Insert(index int, item MenuItem) *Menu {
+	if index < 0 {
+		index = 0
+	}
+	if index > len(m.items) {
+		index = len(m.items)
+	}
+	m.items = append(m.items, nil)
+	copy(m.items[index+1:], m.items[index:])
+	m.items[index] = item
+	for _, n := range m.natives {
+		n.tree.insert(m, n.handle, index, item)
+		n.tree.changed()
+	}
 	return m
 }
 
+// Remove removes the given MenuItem from the Menu. Pass NewMenuSeparator() to
+// remove the first separator.
+func (m *Menu) Remove(item MenuItem) {
+	if i := m.indexOf(item); i != -1 {
+		m.removeAt(i)
+	}
+}
+
+// Clear removes all items from the Menu.
+func (m *Menu) Clear() {
+	for len(m.items) > 0 {
+		m.removeAt(len(m.items) - 1)
+	}
+}
+
+func (m *Menu) removeAt(i int) {
+	item := m.items[i]
+	m.items = append(m.items[:i], m.items[i+1:]...)
+	for _, n := range m.natives {
+		ui.removeMenuItem(n.handle, i)
+		n.tree.forget(item, n.handle)
+		n.tree.changed()
+	}
+}
+
+func (m *Menu) indexOf(item MenuItem) int {
+	for i := range m.items {
+		if m.items[i] == item {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Menu) Items() []MenuItem {
+	return m.items
+}
+
+func (m *Menu) Name() string {
+	return m.name
+}
+
+// SetName renames the Menu. The name of a main menu or of a popup menu is not
+// shown, only that of sub-menus.
+func (m *Menu) SetName(name string) {
+	m.name = name
+	m.update()
+}
+
+func (m *Menu) Enabled() bool {
+	return !m.disabled
+}
+
+// SetEnabled enables or disables the Menu. A disabled sub-menu is grayed out
+// and cannot be opened.
+func (m *Menu) SetEnabled(e bool) {
+	m.disabled = !e
+	m.update()
+}
+
+func (m *Menu) OnOpen() func() {
+	return m.onOpen.primary
+}
+
+// SetOnOpen sets the function that is called right before the Menu opens. It
+// can change the Menu's items, e.g. fill a "Recent Files" menu. It is called
+// for sub-menus and popup menus, not for the main menu itself.
+func (m *Menu) SetOnOpen(f func()) {
+	m.onOpen.primary = f
+}
+
+func (m *Menu) OpenEvent() *Event {
+	return &m.onOpen
+}
+
+// update applies the Menu's name and state to its entries in their parent
+// menus.
+func (m *Menu) update() {
+	for _, n := range m.natives {
+		if n.parent != nil {
+			ui.setMenuItem(n.menu, n.parent.indexOf(m), m.info(n.handle))
+			n.tree.changed()
+		}
+	}
+}
+
+func (m *Menu) info(handle uintptr) menuItemInfo {
+	return menuItemInfo{
+		subMenu:  handle,
+		text:     m.name,
+		disabled: m.disabled,
+	}
+}
+
 // NewMenuString creates a new executable menu item with the given text.
 //
 // Insert an ampersand to underline the following character, e.g. "New &File" to
@@ -52,48 +164,67 @@ func (m *Menu) Add(item MenuItem) *Menu {
 //
 // If you want to display a shortcut text next to the menu, right aligned,
 // insert a tab and then your text, e.g. "Open File\tCtrl+O" to have "Open File"
-// on the left and "Ctrl+O" on the right.
+// on the left and "Ctrl+O" on the right. Use MenuString.SetShortcut to show the
+// text and make the keys work as well.
 func NewMenuString(text string) *MenuString {
 	return &MenuString{text: text}
 }
 
 // MenuString is an executable menu item, see NewMenuString.
 type MenuString struct {
-	window  w32.HWND
-	menu    w32.HMENU
-	id      uint
-	text    string
-	checked bool
-	onClick func()
+	text       string
+	checked    bool
+	disabled   bool
+	isDefault  bool
+	image      *Image
+	icon       *Icon
+	radioGroup *MenuRadioGroup
+	shortcut   []Key
+	onClick    Event
+	natives    []menuNative
 }
 
 func (*MenuString) isMenuItem() {}
 
 func (m *MenuString) SetOnClick(f func()) *MenuString {
-	m.onClick = f
+	m.onClick.primary = f
 	return m
 }
 
+func (m *MenuString) ClickEvent() *Event {
+	return &m.onClick
+}
+
 func (m *MenuString) OnClick() func() {
-	return m.onClick
+	return m.onClick.primary
+}
+
+// clicked is called when the user clicks the item. Items in a radio group are
+// checked before OnClick is called.
+func (m *MenuString) clicked() {
+	if m.radioGroup != nil {
+		m.SetChecked(true)
+	}
+	m.onClick.fire()
 }
 
 func (m *MenuString) Checked() bool {
 	return m.checked
 }
 
+// SetChecked shows or hides a check mark next to the item. Checking an item
+// that is in a MenuRadioGroup unchecks the other items in the group.
 func (m *MenuString) SetChecked(c bool) {
 	m.checked = c
-	if m.menu != 0 {
-		var info w32.MENUITEMINFO
-		info.Mask = w32.MIIM_STATE
-		if c {
-			info.State = w32.MFS_CHECKED
-		} else {
-			info.State = w32.MFS_UNCHECKED
+	if c && m.radioGroup != nil {
+		for _, other := range m.radioGroup.items {
+			if other != m && other.checked {
+				other.checked = false
+				other.update()
+			}
 		}
-		w32.SetMenuItemInfo(m.menu, m.id, false, &info)
 	}
+	m.update()
 }
 
 func (m *MenuString) Text() string {
@@ -102,15 +233,197 @@ func (m *MenuString) Text() string {
 
 func (m *MenuString) SetText(s string) {
 	m.text = s
-	if m.menu != 0 {
-		var info w32.MENUITEMINFO
-		info.Mask = w32.MIIM_STRING
-		info.TypeData = uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(s)))
-		w32.SetMenuItemInfo(m.menu, m.id, false, &info)
-		w32.DrawMenuBar(m.window)
+	m.update()
+}
+
+func (m *MenuString) Enabled() bool {
+	return !m.disabled
+}
+
+// SetEnabled enables or disables the item. A disabled item is grayed out and
+// cannot be clicked.
+func (m *MenuString) SetEnabled(e bool) {
+	m.disabled = !e
+	m.update()
+}
+
+func (m *MenuString) Default() bool {
+	return m.isDefault
+}
+
+// SetDefault shows the item's text in bold. This marks the item that is used
+// when the user does not choose, e.g. the action of a double-click. There
+// should be at most one default item per menu.
+func (m *MenuString) SetDefault(d bool) {
+	m.isDefault = d
+	m.update()
+}
+
+func (m *MenuString) Image() *Image {
+	return m.image
+}
+
+// SetImage shows the image left of the item's text. It should be as large as a
+// small icon, usually 16x16 pixels. Transparent pixels in the image show the
+// menu's background. SetImage replaces the icon set with SetIcon, pass nil to
+// remove the image.
+func (m *MenuString) SetImage(img *Image) {
+	m.image = img
+	m.icon = nil
+	m.update()
+}
+
+func (m *MenuString) Icon() *Icon {
+	return m.icon
+}
+
+// SetIcon shows the icon left of the item's text, at the size of a small icon.
+// SetIcon replaces the image set with SetImage, pass nil to remove the icon.
+func (m *MenuString) SetIcon(icon *Icon) {
+	m.icon = icon
+	m.image = nil
+	m.update()
+}
+
+func (m *MenuString) Shortcut() []Key {
+	return m.shortcut
+}
+
+// SetShortcut sets the key combination that clicks the item. Its text, see
+// ShortcutString, is shown right-aligned next to the item's text, so do not
+// put it in the item's text yourself. The shortcut works while the item is in
+// the menu bar of a shown window. Call SetShortcut without keys to remove the
+// shortcut.
+func (m *MenuString) SetShortcut(keys ...Key) {
+	m.registerShortcut(false)
+	m.shortcut = append([]Key(nil), keys...)
+	m.registerShortcut(true)
+	m.update()
+}
+
+// registerShortcut adds the item's shortcut to or removes it from the windows
+// whose menu bars contain the item.
+func (m *MenuString) registerShortcut(on bool) {
+	for _, n := range m.natives {
+		if n.tree.isBar {
+			m.registerShortcutIn(n.tree.window, on)
+		}
+	}
+}
+
+func (m *MenuString) registerShortcutIn(w *Window, on bool) {
+	if len(m.shortcut) == 0 {
+		return
+	}
+	if on {
+		w.setShortcut(shortcut{f: m.shortcutPressed, menuItem: m}, m.shortcut)
+	} else {
+		w.removeMenuShortcut(m, m.shortcut)
+	}
+}
+
+func (m *MenuString) shortcutPressed() {
+	if !m.disabled {
+		m.clicked()
+	}
+}
+
+// RadioGroup returns the group that the item was added to with
+// MenuRadioGroup.Add, or nil.
+func (m *MenuString) RadioGroup() *MenuRadioGroup {
+	return m.radioGroup
+}
+
+// update applies the item's text and state to its native menu items.
+func (m *MenuString) update() {
+	for _, n := range m.natives {
+		ui.setMenuItem(n.menu, n.parent.indexOf(m), m.info(n.id))
+		n.tree.changed()
 	}
 }
 
+func (m *MenuString) info(id uint) menuItemInfo {
+	info := menuItemInfo{
+		id:        id,
+		text:      m.text,
+		disabled:  m.disabled,
+		checked:   m.checked,
+		radio:     m.radioGroup != nil,
+		isDefault: m.isDefault,
+	}
+	if len(m.shortcut) > 0 {
+		info.text += "\t" + ShortcutString(m.shortcut...)
+	}
+	if m.image != nil {
+		info.bitmap = m.image.bitmap
+	} else if m.icon != nil {
+		info.bitmap = m.icon.menuBitmap()
+	}
+	return info
+}
+
+// NewMenuRadioGroup returns a group of MenuStrings that work like radio
+// buttons, see MenuRadioGroup.
+func NewMenuRadioGroup(items ...*MenuString) *MenuRadioGroup {
+	g := &MenuRadioGroup{}
+	for _, item := range items {
+		g.Add(item)
+	}
+	return g
+}
+
+// MenuRadioGroup makes MenuStrings work like radio buttons. They show a dot
+// instead of a check mark, clicking one checks it and checking one unchecks
+// all others in the group. The group only manages the checks, you still have
+// to add the MenuStrings to a Menu, usually next to each other with separators
+// around them.
+type MenuRadioGroup struct {
+	items []*MenuString
+}
+
+// Add puts the item into the group, removing it from any other group. If the
+// item is checked, all others in the group are unchecked.
+func (g *MenuRadioGroup) Add(item *MenuString) *MenuRadioGroup {
+	if item.radioGroup == g {
+		return g
+	}
+	if item.radioGroup != nil {
+		item.radioGroup.Remove(item)
+	}
+	item.radioGroup = g
+	g.items = append(g.items, item)
+	// This also updates the radio style of the item.
+	item.SetChecked(item.checked)
+	return g
+}
+
+// Remove takes the item out of the group, it then shows a normal check mark
+// again.
+func (g *MenuRadioGroup) Remove(item *MenuString) {
+	for i := range g.items {
+		if g.items[i] == item {
+			g.items = append(g.items[:i], g.items[i+1:]...)
+			item.radioGroup = nil
+			item.update()
+			return
+		}
+	}
+}
+
+func (g *MenuRadioGroup) Items() []*MenuString {
+	return g.items
+}
+
+// Checked returns the checked item of the group or nil if no item is checked.
+func (g *MenuRadioGroup) Checked() *MenuString {
+	for _, item := range g.items {
+		if item.checked {
+			return item
+		}
+	}
+	return nil
+}
+
 // NewMenuSeparator returns a horizontal line separating regions in a menu.
 func NewMenuSeparator() MenuItem {
 	return separator
@@ -121,3 +434,18 @@ type menuSeparator int
 func (menuSeparator) isMenuItem() {}
 
 var separator menuSeparator
+
+// popup shows the menu at the screen position x, y and calls OnClick of the
+// item that the user clicks. It returns when the menu is closed.
+func (m *Menu) popup(w *Window, x, y int) {
+	t := newMenuTree(w, m, false)
+	outer := w.popupMenu
+	w.popupMenu = t
+	id := ui.popupMenu(w.handle, t.handle, x, y)
+	w.popupMenu = outer
+	clicked := t.strings[id]
+	t.destroy()
+	if clicked != nil {
+		clicked.clicked()
+	}
+}
@@ -0,0 +1,327 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+// menuBarItem returns the native item of a Menu or MenuString in the menu bar.
+func menuBarItem(h *Headless, item MenuItem) menuItemInfo {
+	var natives []menuNative
+	switch item := item.(type) {
+	case *Menu:
+		natives = item.natives
+	case *MenuString:
+		natives = item.natives
+	}
+	for _, n := range natives {
+		if n.tree.isBar && n.parent != nil {
+			return h.menus[n.menu].items[n.parent.indexOf(item)]
+		}
+	}
+	panic("item is not in a menu bar")
+}
+
+// menuTexts returns the texts of the native items, separators are "-".
+func menuTexts(h *Headless, menu uintptr) []string {
+	texts := []string{}
+	for _, item := range h.menus[menu].items {
+		if item.separator {
+			texts = append(texts, "-")
+		} else {
+			texts = append(texts, item.text)
+		}
+	}
+	return texts
+}
+
+func TestMenuItemStateIsShownInMenuBar(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	img := NewImageFromHBITMAP(123, 16, 16)
+	open := NewMenuString("Open")
+	open.SetDefault(true)
+	open.SetImage(img)
+	save := NewMenuString("Save")
+	save.SetEnabled(false)
+	save.SetIcon(IconApplication)
+	wrap := NewMenuString("Wrap")
+	wrap.SetChecked(true)
+	file := NewMenu("File").Add(open).Add(save).Add(wrap)
+	w.SetMenu(NewMainMenu().Add(file))
+
+	check.Eq(t, open.Default(), true)
+	check.Eq(t, open.Image(), img)
+	check.Eq(t, save.Enabled(), false)
+	check.Eq(t, save.Icon(), IconApplication)
+
+	w.SetOnShow(func() {
+		check.Eq(t, menuBarItem(h, open), menuItemInfo{
+			id:        open.natives[0].id,
+			text:      "Open",
+			isDefault: true,
+			bitmap:    123,
+		})
+		saveItem := menuBarItem(h, save)
+		check.Eq(t, saveItem.disabled, true)
+		check.Eq(t, saveItem.bitmap != 0, true)
+		check.Eq(t, saveItem.bitmap, IconApplication.bitmap)
+		check.Eq(t, menuBarItem(h, wrap).checked, true)
+
+		open.SetDefault(false)
+		open.SetIcon(IconApplication)
+		check.Eq(t, open.Image() == nil, true)
+		check.Eq(t, menuBarItem(h, open).isDefault, false)
+		check.Eq(t, menuBarItem(h, open).bitmap, IconApplication.bitmap)
+		open.SetImage(nil)
+		check.Eq(t, open.Icon() == nil, true)
+		check.Eq(t, menuBarItem(h, open).bitmap, uintptr(0))
+
+		save.SetEnabled(true)
+		check.Eq(t, menuBarItem(h, save).disabled, false)
+
+		file.SetName("&File")
+		file.SetEnabled(false)
+		check.Eq(t, menuBarItem(h, file).text, "&File")
+		check.Eq(t, menuBarItem(h, file).disabled, true)
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestDisabledMenuItemsCannotBeClicked(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	clicks := 0
+	item := NewMenuString("Item").SetOnClick(func() { clicks++ })
+	menu := NewMenu("Menu").Add(item)
+	w.SetMenu(NewMainMenu().Add(menu))
+	w.SetOnShow(func() {
+		h.ClickMenu(item)
+		check.Eq(t, clicks, 1)
+
+		item.SetEnabled(false)
+		h.ClickMenu(item)
+		check.Eq(t, clicks, 1)
+
+		item.SetEnabled(true)
+		menu.SetEnabled(false)
+		h.ClickMenu(item)
+		check.Eq(t, clicks, 1)
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestMenuRadioGroupChecksOneItem(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	small := NewMenuString("Small")
+	medium := NewMenuString("Medium")
+	large := NewMenuString("Large")
+	medium.SetChecked(true)
+	large.SetChecked(true)
+	var clicked *MenuString
+	for _, m := range []*MenuString{small, medium, large} {
+		m := m
+		m.SetOnClick(func() { clicked = m })
+	}
+	g := NewMenuRadioGroup(small, medium, large)
+	check.Eq(t, g.Items(), []*MenuString{small, medium, large})
+	check.Eq(t, small.RadioGroup(), g)
+	// Adding a checked item unchecks the others.
+	check.Eq(t, g.Checked(), large)
+	check.Eq(t, medium.Checked(), false)
+
+	w.SetMenu(NewMainMenu().Add(NewMenu("Size").Add(small).Add(medium).Add(large)))
+	w.SetOnShow(func() {
+		check.Eq(t, menuBarItem(h, small).radio, true)
+
+		h.ClickMenu(small)
+		check.Eq(t, clicked, small)
+		check.Eq(t, g.Checked(), small)
+		check.Eq(t, menuBarItem(h, small).checked, true)
+		check.Eq(t, menuBarItem(h, large).checked, false)
+
+		medium.SetChecked(true)
+		check.Eq(t, g.Checked(), medium)
+		check.Eq(t, small.Checked(), false)
+		check.Eq(t, menuBarItem(h, small).checked, false)
+
+		// Unchecking leaves the group without a checked item.
+		medium.SetChecked(false)
+		check.Eq(t, g.Checked() == nil, true)
+
+		g.Remove(large)
+		check.Eq(t, large.RadioGroup() == nil, true)
+		check.Eq(t, menuBarItem(h, large).radio, false)
+		check.Eq(t, g.Items(), []*MenuString{small, medium})
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestMenuItemsCanBeChangedWhileShown(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	var clicks []string
+	item := func(text string) *MenuString {
+		return NewMenuString(text).SetOnClick(func() {
+			clicks = append(clicks, text)
+		})
+	}
+	a, b, c := item("A"), item("B"), item("C")
+	sub := NewMenu("Sub").Add(item("Sub Item"))
+	menu := NewMenu("Menu").Add(a)
+	w.SetMenu(NewMainMenu().Add(menu))
+
+	w.SetOnShow(func() {
+		handle := menu.natives[0].handle
+		menu.Add(c)
+		menu.Insert(1, b)
+		menu.Insert(-5, NewMenuSeparator())
+		menu.Insert(99, sub)
+		check.Eq(t, menuTexts(h, handle), []string{"-", "A", "B", "C", "Sub"})
+		check.Eq(t, menu.Items(), []MenuItem{separator, a, b, c, sub})
+		check.Eq(t, menuTexts(h, sub.natives[0].handle), []string{"Sub Item"})
+
+		h.ClickMenu(b)
+		h.ClickMenu(c)
+		h.ClickMenu(sub.items[0].(*MenuString))
+		check.Eq(t, clicks, []string{"B", "C", "Sub Item"})
+
+		menu.Remove(b)
+		menu.Remove(NewMenuSeparator())
+		check.Eq(t, menuTexts(h, handle), []string{"A", "C", "Sub"})
+		check.Eq(t, len(b.natives), 0)
+		b.SetText("not shown")
+		h.ClickMenu(b)
+		check.Eq(t, clicks, []string{"B", "C", "Sub Item"})
+
+		subHandle := sub.natives[0].handle
+		menu.Clear()
+		check.Eq(t, menuTexts(h, handle), []string{})
+		check.Eq(t, len(menu.Items()), 0)
+		check.Eq(t, len(sub.natives), 0)
+		check.Eq(t, h.menus[subHandle] == nil, true)
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestMenuOnOpenCanFillMenu(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	recentFiles := []string{"a.txt"}
+	var opened []string
+	recent := NewMenu("Recent Files")
+	recent.SetOnOpen(func() {
+		recent.Clear()
+		for _, f := range recentFiles {
+			f := f
+			recent.Add(NewMenuString(f).SetOnClick(func() {
+				opened = append(opened, f)
+			}))
+		}
+	})
+	file := NewMenu("File").Add(recent)
+	w.SetMenu(NewMainMenu().Add(file))
+	w.SetContextMenu(file)
+
+	h.SetPopupMenuChoice(func(items []string) string {
+		return items[len(items)-1]
+	})
+
+	w.SetOnShow(func() {
+		h.OpenMenu(recent)
+		check.Eq(t, menuTexts(h, recent.natives[0].handle), []string{"a.txt"})
+
+		recentFiles = append(recentFiles, "b.txt")
+		h.OpenMenu(recent)
+		check.Eq(t, menuTexts(h, recent.natives[0].handle), []string{"a.txt", "b.txt"})
+		h.ClickMenu(recent.items[0].(*MenuString))
+
+		// The same menu fills its copy in the popup when it opens.
+		recentFiles = append(recentFiles, "c.txt")
+		file.Popup(w, 0, 0)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, opened, []string{"a.txt", "c.txt"})
+}
+
+func TestMenuBarCanBeReplacedWhileShown(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	first := NewMenuString("First")
+	second := NewMenuString("Second")
+	firstBar := NewMainMenu().Add(NewMenu("Menu").Add(first))
+	w.SetMenu(firstBar)
+	w.SetOnShow(func() {
+		oldBar := h.handles[w.handle].menu
+		w.SetMenu(NewMainMenu().Add(NewMenu("Menu").Add(second)))
+		check.Eq(t, h.menus[oldBar] == nil, true)
+		check.Eq(t, len(first.natives), 0)
+		check.Eq(t, len(firstBar.natives), 0)
+		check.Eq(t, len(second.natives), 1)
+
+		w.SetMenu(nil)
+		check.Eq(t, h.handles[w.handle].menu, uintptr(0))
+		check.Eq(t, len(second.natives), 0)
+
+		w.SetMenu(firstBar)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, len(first.natives), 0)
+	check.Eq(t, len(h.menus), 0)
+}
+
+func TestMenuCommandIDsAreReusedWhenAllAreTaken(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	clicked := ""
+	item := func(text string) *MenuString {
+		return NewMenuString(text).SetOnClick(func() { clicked = text })
+	}
+	a, b := item("A"), item("B")
+	menu := NewMenu("Menu").Add(a).Add(b)
+	w.SetMenu(NewMainMenu().Add(menu))
+
+	w.SetOnShow(func() {
+		w.menuBar.lastID = maxMenuID - 1
+		c := item("C")
+		menu.Add(c)
+		check.Eq(t, c.natives[0].id, uint(maxMenuID))
+
+		// IDs are reused in the order that they became free.
+		aID, bID := a.natives[0].id, b.natives[0].id
+		menu.Remove(b)
+		menu.Remove(a)
+		d, e := item("D"), item("E")
+		menu.Add(d).Add(e)
+		check.Eq(t, d.natives[0].id, bID)
+		check.Eq(t, e.natives[0].id, aID)
+
+		h.ClickMenu(d)
+		check.Eq(t, clicked, "D")
+
+		func() {
+			defer func() {
+				check.Eq(t, recover(), "wui: a menu can have at most 65535 MenuStrings")
+			}()
+			menu.Add(item("too many"))
+		}()
+
+		w.Close()
+	})
+	w.Show()
+}
@@ -0,0 +1,181 @@
+package wui
+
+// menuTree is a native menu bar or popup menu with all its sub-menus. The
+// Menus and MenuStrings in it remember where they are shown so they can update
+// the native menus when they change. The same Menu can be in several trees,
+// e.g. in a menu bar and in a context menu.
+type menuTree struct {
+	window  *Window
+	root    *Menu
+	isBar   bool
+	handle  uintptr
+	lastID  uint
+	freeIDs []uint               // IDs of forgotten MenuStrings, the oldest first.
+	strings map[uint]*MenuString // By command ID.
+	menus   map[uintptr]*Menu    // By native menu.
+}
+
+// menuNative is a place where a Menu or MenuString is shown on the screen.
+type menuNative struct {
+	tree   *menuTree
+	parent *Menu   // The Menu that contains the item, nil for a tree's root.
+	menu   uintptr // The native menu that contains the item.
+	handle uintptr // For Menus, the native menu with their items.
+	id     uint    // For MenuStrings, the command ID, 0 means no command.
+}
+
+// menuItemInfo is a native menu item.
+type menuItemInfo struct {
+	id        uint    // Command ID of a MenuString.
+	subMenu   uintptr // Native menu of a sub-Menu.
+	separator bool
+	text      string
+	disabled  bool
+	checked   bool
+	radio     bool
+	isDefault bool
+	bitmap    uintptr
+}
+
+func newMenuTree(w *Window, root *Menu, isBar bool) *menuTree {
+	t := &menuTree{
+		window:  w,
+		root:    root,
+		isBar:   isBar,
+		strings: make(map[uint]*MenuString),
+		menus:   make(map[uintptr]*Menu),
+	}
+	if isBar {
+		t.handle = ui.createMenu()
+	} else {
+		t.handle = ui.createPopupMenu()
+	}
+	t.menus[t.handle] = root
+	root.natives = append(root.natives, menuNative{tree: t, handle: t.handle})
+	for i, item := range root.items {
+		t.insert(root, t.handle, i, item)
+	}
+	return t
+}
+
+// insert creates the native item for the parent's item at the position in the
+// native menu.
+func (t *menuTree) insert(parent *Menu, menu uintptr, pos int, item MenuItem) {
+	switch item := item.(type) {
+	case *Menu:
+		handle := ui.createPopupMenu()
+		ui.insertMenuItem(menu, pos, item.info(handle))
+		t.menus[handle] = item
+		item.natives = append(item.natives, menuNative{
+			tree:   t,
+			parent: parent,
+			menu:   menu,
+			handle: handle,
+		})
+		for i, child := range item.items {
+			t.insert(item, handle, i, child)
+		}
+	case *MenuString:
+		id := t.newID()
+		t.strings[id] = item
+		ui.insertMenuItem(menu, pos, item.info(id))
+		item.natives = append(item.natives, menuNative{
+			tree:   t,
+			parent: parent,
+			menu:   menu,
+			id:     id,
+		})
+		if t.isBar {
+			item.registerShortcutIn(t.window, true)
+		}
+	case menuSeparator:
+		ui.insertMenuItem(menu, pos, menuItemInfo{separator: true})
+	}
+}
+
+// maxMenuID is the largest command ID, WM_COMMAND only has 16 bits for it.
+const maxMenuID = 0xFFFF
+
+// newID returns an unused command ID. IDs of forgotten items are only reused
+// once all IDs were handed out, so a click on a removed item that is still in
+// the message queue does not reach a new item right away.
+func (t *menuTree) newID() uint {
+	if t.lastID < maxMenuID {
+		t.lastID++
+		return t.lastID
+	}
+	if len(t.freeIDs) == 0 {
+		panic("wui: a menu can have at most 65535 MenuStrings")
+	}
+	id := t.freeIDs[0]
+	t.freeIDs = t.freeIDs[1:]
+	return id
+}
+
+// forget removes the item's places in the given native menu of the tree, and
+// those of its children.
+func (t *menuTree) forget(item MenuItem, menu uintptr) {
+	switch item := item.(type) {
+	case *Menu:
+		natives := item.natives[:0]
+		for _, n := range item.natives {
+			if n.tree == t && n.menu == menu {
+				delete(t.menus, n.handle)
+				for _, child := range item.items {
+					t.forget(child, n.handle)
+				}
+			} else {
+				natives = append(natives, n)
+			}
+		}
+		item.natives = natives
+	case *MenuString:
+		natives := item.natives[:0]
+		for _, n := range item.natives {
+			if n.tree == t && n.menu == menu {
+				delete(t.strings, n.id)
+				t.freeIDs = append(t.freeIDs, n.id)
+				if t.isBar {
+					item.registerShortcutIn(t.window, false)
+				}
+			} else {
+				natives = append(natives, n)
+			}
+		}
+		item.natives = natives
+	}
+}
+
+// detach makes all items forget the tree. This is called when the native menu
+// is gone, e.g. when the window was destroyed.
+func (t *menuTree) detach() {
+	t.forget(t.root, 0)
+}
+
+// destroy detaches the tree and destroys the native menu.
+func (t *menuTree) destroy() {
+	t.detach()
+	ui.destroyMenu(t.handle)
+}
+
+// changed redraws the menu bar after an item changed. Popup menus are redrawn
+// automatically.
+func (t *menuTree) changed() {
+	if t.isBar && t.window.handle != 0 {
+		ui.drawMenuBar(t.window.handle)
+	}
+}
+
+func (t *menuTree) clicked(id uint) {
+	if m := t.strings[id]; m != nil {
+		m.clicked()
+	}
+}
+
+func (t *menuTree) opened(menu uintptr) bool {
+	if m := t.menus[menu]; m != nil {
+		m.onOpen.fire()
+		return true
+	}
+	return false
+}
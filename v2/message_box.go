@@ -1,33 +1,33 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 func MessageBox(caption, text string) {
-	msgBox(caption, text, w32.MB_OK)
+	msgBox(caption, text, win.MB_OK)
 }
 
 func MessageBoxError(caption, text string) {
-	msgBox(caption, text, w32.MB_OK|w32.MB_ICONERROR)
+	msgBox(caption, text, win.MB_OK|win.MB_ICONERROR)
 }
 
 func MessageBoxWarning(caption, text string) {
-	msgBox(caption, text, w32.MB_OK|w32.MB_ICONWARNING)
+	msgBox(caption, text, win.MB_OK|win.MB_ICONWARNING)
 }
 
 func MessageBoxInfo(caption, text string) {
-	msgBox(caption, text, w32.MB_OK|w32.MB_ICONINFORMATION)
+	msgBox(caption, text, win.MB_OK|win.MB_ICONINFORMATION)
 }
 
 func MessageBoxQuestion(caption, text string) {
-	msgBox(caption, text, w32.MB_OK|w32.MB_ICONQUESTION)
+	msgBox(caption, text, win.MB_OK|win.MB_ICONQUESTION)
 }
 
 func MessageBoxOKCancel(caption, text string) bool {
-	return msgBox(caption, text, w32.MB_OKCANCEL) == w32.IDOK
+	return msgBox(caption, text, win.MB_OKCANCEL) == win.IDOK
 }
 
 func MessageBoxYesNo(caption, text string) bool {
-	return msgBox(caption, text, w32.MB_YESNO|w32.MB_ICONQUESTION) == w32.IDYES
+	return msgBox(caption, text, win.MB_YESNO|win.MB_ICONQUESTION) == win.IDYES
 }
 
 func MessageBoxCustom(caption, text string, flags uint) int {
@@ -35,10 +35,10 @@ func MessageBoxCustom(caption, text string, flags uint) int {
 }
 
 func msgBox(caption, text string, flags uint) int {
-	var handle w32.HWND
-	parent := windows.top()
+	var handle uintptr
+	parent := app.top()
 	if parent != nil {
 		handle = parent.handle
 	}
-	return w32.MessageBox(handle, text, caption, w32.MB_TOPMOST|flags)
+	return ui.messageBox(handle, caption, text, win.MB_TOPMOST|flags)
 }
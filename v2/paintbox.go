@@ -4,11 +4,8 @@ import (
 	"image"
 	"image/draw"
 	"math"
-	"reflect"
-	"syscall"
-	"unsafe"
 
-	"github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2/internal/win"
 )
 
 func NewPaintBox() *PaintBox {
@@ -17,9 +14,7 @@ func NewPaintBox() *PaintBox {
 
 type PaintBox struct {
 	control
-	backBuffer  backBuffer
-	onPaint     func(*Canvas)
-	onMouseMove func(x, y int)
+	onPaint CanvasEvent
 }
 
 var _ Control = (*PaintBox)(nil)
@@ -32,80 +27,51 @@ func (*PaintBox) eatsTabs() bool {
 	return false
 }
 
-type backBuffer struct {
-	w, h int
-	dc   w32.HDC
-	bmp  w32.HBITMAP
-}
-
-func (b *backBuffer) setMinSize(hdc w32.HDC, w, h int) {
-	if w > b.w || h > b.h {
-		if b.dc != 0 {
-			w32.DeleteObject(w32.HGDIOBJ(b.bmp))
-			w32.DeleteDC(b.dc)
-		}
-
-		b.dc = w32.CreateCompatibleDC(hdc)
-		b.bmp = w32.CreateCompatibleBitmap(hdc, w, h)
-		b.w = w
-		b.h = h
-	}
-}
-
 func (p *PaintBox) create(id int) {
-	p.control.create(id, 0, "STATIC", w32.SS_OWNERDRAW)
-	w32.SetWindowSubclass(p.handle, syscall.NewCallback(func(
-		window w32.HWND,
-		msg uint32,
-		wParam, lParam uintptr,
-		subclassID uintptr,
-		refData uintptr,
-	) uintptr {
-		switch msg {
-		case w32.WM_NCHITTEST:
-			if p.onMouseMove != nil {
-				x := int(int16(lParam & 0xFFFF))
-				y := int(int16((lParam & 0xFFFF0000) >> 16))
-				x, y, _ = w32.ScreenToClient(p.handle, x, y)
-				p.onMouseMove(x, y)
-			}
-		}
-		return w32.DefSubclassProc(window, msg, wParam, lParam)
-	}), 0, 0)
+	p.control.create(id, 0, "STATIC", win.SS_OWNERDRAW)
 }
 
-func (p *PaintBox) OnMouseMove() func(x, y int) {
-	return p.onMouseMove
+// paint is called by the backend when the PaintBox needs to be redrawn. c
+// draws to a back buffer that is copied to the screen afterwards.
+func (p *PaintBox) paint(c *Canvas) {
+	if p.onPaint.empty() {
+		return
+	}
+	if p.parent != nil {
+		c.SetFont(p.parent.Font())
+	}
+	c.ClearDrawRegions()
+	p.onPaint.fire(c)
 }
 
-func (p *PaintBox) SetOnMouseMove(f func(x, y int)) {
-	p.onMouseMove = f
+func (p *PaintBox) SetOnPaint(f func(*Canvas)) {
+	p.onPaint.primary = f
 }
 
-func (p *PaintBox) SetOnPaint(f func(*Canvas)) {
-	p.onPaint = f
+func (p *PaintBox) PaintEvent() *CanvasEvent {
+	return &p.onPaint
 }
 
 func (p *PaintBox) OnPaint() func(*Canvas) {
-	return p.onPaint
+	return p.onPaint.primary
 }
 
 func (p *PaintBox) Paint() {
 	if p.handle != 0 {
-		w32.InvalidateRect(p.handle, nil, true)
+		ui.repaint(p.handle)
 	}
 }
 
+// Canvas is passed to a PaintBox's OnPaint callback and is used to draw on it.
 type Canvas struct {
-	hdc     w32.HDC
+	painter painter
 	width   int
 	height  int
-	regions []w32.HRGN
 }
 
 // Handle returns the handle to the canvas' device context (HDC).
 func (c *Canvas) Handle() uintptr {
-	return uintptr(c.hdc)
+	return c.painter.handle()
 }
 
 func (c *Canvas) Size() (width, height int) {
@@ -122,70 +88,35 @@ func (c *Canvas) Height() int {
 }
 
 func (c *Canvas) PushDrawRegion(x, y, width, height int) {
-	r := w32.CreateRectRgn(x, y, x+width, y+height)
-	if len(c.regions) > 0 {
-		w32.CombineRgn(r, r, c.regions[len(c.regions)-1], w32.RGN_AND)
-	}
-	c.regions = append(c.regions, r)
-	w32.SelectClipRgn(c.hdc, r)
+	c.painter.pushDrawRegion(x, y, width, height)
 }
 
 func (c *Canvas) PopDrawRegion() {
-	n := len(c.regions)
-	if n == 0 {
-		return
-	}
-	if n == 1 {
-		w32.SelectClipRgn(c.hdc, 0)
-	} else {
-		w32.SelectClipRgn(c.hdc, c.regions[n-2])
-	}
-	w32.DeleteObject(w32.HGDIOBJ(c.regions[n-1]))
-	c.regions = c.regions[:n-1]
+	c.painter.popDrawRegion()
 }
 
 func (c *Canvas) ClearDrawRegions() {
-	w32.SelectClipRgn(c.hdc, 0)
-	for _, r := range c.regions {
-		w32.DeleteObject(w32.HGDIOBJ(r))
-	}
+	c.painter.clearDrawRegions()
 }
 
 func (c *Canvas) DrawRect(x, y, width, height int, color Color) {
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
-	w32.Rectangle(c.hdc, x, y, x+width, y+height)
+	c.painter.rect(x, y, width, height, color, false)
 }
 
 func (c *Canvas) FillRect(x, y, width, height int, color Color) {
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_BRUSH))
-	w32.SetDCBrushColor(c.hdc, w32.COLORREF(color))
-	w32.Rectangle(c.hdc, x, y, x+width, y+height)
+	c.painter.rect(x, y, width, height, color, true)
 }
 
 func (c *Canvas) Line(x1, y1, x2, y2 int, color Color) {
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.MoveToEx(c.hdc, x1, y1, nil)
-	w32.LineTo(c.hdc, x2, y2)
+	c.painter.line(x1, y1, x2, y2, color)
 }
 
 func (c *Canvas) DrawEllipse(x, y, width, height int, color Color) {
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
-	w32.Ellipse(c.hdc, x, y, x+width, y+height)
+	c.painter.ellipse(x, y, width, height, color, false)
 }
 
 func (c *Canvas) FillEllipse(x, y, width, height int, color Color) {
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_BRUSH))
-	w32.SetDCBrushColor(c.hdc, w32.COLORREF(color))
-	w32.Ellipse(c.hdc, x, y, x+width, y+height)
+	c.painter.ellipse(x, y, width, height, color, true)
 }
 
 type Point struct {
@@ -196,48 +127,38 @@ func (c *Canvas) Polyline(p []Point, color Color) {
 	if len(p) < 2 {
 		return
 	}
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
-	w32.PolylineMem(c.hdc, unsafe.Pointer(&p[0]), len(p))
+	c.painter.polyline(p, color)
 }
 
 func (c *Canvas) Polygon(p []Point, color Color) {
 	if len(p) < 2 {
 		return
 	}
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_BRUSH))
-	w32.SetDCBrushColor(c.hdc, w32.COLORREF(color))
-	w32.PolygonMem(c.hdc, unsafe.Pointer(&p[0]), len(p))
+	c.painter.polygon(p, color)
 }
 
 func (c *Canvas) Arc(x, y, width, height int, fromClockAngle, dAngle float64, color Color) {
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	c.arcLike(x, y, width, height, fromClockAngle, dAngle, w32.Arc)
+	x1, y1, x2, y2 := arcEnds(x, y, width, height, fromClockAngle, dAngle)
+	c.painter.arc(x, y, width, height, x1, y1, x2, y2, color)
 }
 
 func (c *Canvas) FillPie(x, y, width, height int, fromClockAngle, dAngle float64, color Color) {
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_BRUSH))
-	w32.SetDCBrushColor(c.hdc, w32.COLORREF(color))
-	c.arcLike(x, y, width, height, fromClockAngle, dAngle, w32.Pie)
+	x1, y1, x2, y2 := arcEnds(x, y, width, height, fromClockAngle, dAngle)
+	c.painter.pie(x, y, width, height, x1, y1, x2, y2, color, true)
 }
 
 func (c *Canvas) DrawPie(x, y, width, height int, fromClockAngle, dAngle float64, color Color) {
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.DC_PEN))
-	w32.SetDCPenColor(c.hdc, w32.COLORREF(color))
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
-	c.arcLike(x, y, width, height, fromClockAngle, dAngle, w32.Pie)
+	x1, y1, x2, y2 := arcEnds(x, y, width, height, fromClockAngle, dAngle)
+	c.painter.pie(x, y, width, height, x1, y1, x2, y2, color, false)
 }
 
-func (c *Canvas) arcLike(
+// arcEnds returns two points that lie on the rays from the center of the given
+// rectangle through the start and end of the arc. The arc is drawn counter
+// clock-wise from the first to the second point.
+func arcEnds(
 	x, y, width, height int,
 	fromClockAngle, dAngle float64,
-	draw func(w32.HDC, int, int, int, int, int, int, int, int) bool) {
+) (x1, y1, x2, y2 int) {
 	toRad := func(clock float64) float64 {
 		return (90 - clock) * math.Pi / 180
 	}
@@ -245,9 +166,9 @@ func (c *Canvas) arcLike(
 	if dAngle < 0 {
 		a, b = b, a
 	}
-	y1, x1 := math.Sincos(toRad(a))
-	y2, x2 := math.Sincos(toRad(b))
-	x1, x2, y1, y2 = 100*x1, 100*x2, -100*y1, -100*y2
+	fy1, fx1 := math.Sincos(toRad(a))
+	fy2, fx2 := math.Sincos(toRad(b))
+	fx1, fx2, fy1, fy2 = 100*fx1, 100*fx2, -100*fy1, -100*fy2
 	round := func(f float64) int {
 		if f < 0 {
 			return int(f - 0.5)
@@ -256,28 +177,16 @@ func (c *Canvas) arcLike(
 	}
 	cx := float64(x) + float64(width)/2.0
 	cy := float64(y) + float64(height)/2.0
-	draw(
-		c.hdc,
-		x, y, x+width, y+height,
-		round(cx+100*x1), round(cy+100*y1), round(cx+100*x2), round(cy+100*y2),
-	)
+	return round(cx + 100*fx1), round(cy + 100*fy1),
+		round(cx + 100*fx2), round(cy + 100*fy2)
 }
 
 func (c *Canvas) TextExtent(s string) (width, height int) {
-	size, ok := w32.GetTextExtentPoint32(c.hdc, s)
-	if ok {
-		width = int(size.CX)
-		height = int(size.CY)
-	}
-	return
+	return c.painter.textExtent(s)
 }
 
 func (c *Canvas) TextOut(x, y int, s string, color Color) {
-	w32.SetBkMode(c.hdc, w32.TRANSPARENT)
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
-	w32.SetTextColor(c.hdc, w32.COLORREF(color))
-	w32.TextOut(c.hdc, x, y, s)
-	w32.SetBkMode(c.hdc, w32.OPAQUE)
+	c.painter.textOut(x, y, s, color)
 }
 
 // TODO What about line breaks in TextRects (\n vs \r\n)?
@@ -292,15 +201,11 @@ func (c *Canvas) TextRect(x, y, w, h int, s string, color Color) {
 func (c *Canvas) TextRectExtent(s string, givenWidth int) (width, height int) {
 	// TODO What is the max of givenWidth, int can be larger than 0x7FFFFFFF (or
 	// whatever it is) so do we clamp it or have a const NoWidth=0x7FFFFFFF?
-	var flags uint = w32.DT_WORDBREAK | w32.DT_NOFULLWIDTHCHARBREAK | w32.DT_EXPANDTABS
-	var r w32.RECT
 	const maxInt32 = 0x7FFFFFFF
 	if givenWidth > maxInt32 {
 		givenWidth = maxInt32
 	}
-	r.Right = int32(givenWidth)
-	w32.DrawText(c.hdc, s, &r, flags|w32.DT_CALCRECT)
-	return int(r.Width()), int(r.Height())
+	return c.painter.textRectExtent(s, givenWidth)
 }
 
 type Format int
@@ -318,52 +223,12 @@ const (
 )
 
 func (c *Canvas) TextRectFormat(x, y, w, h int, s string, format Format, color Color) {
-	w32.SetBkMode(c.hdc, w32.TRANSPARENT)
-	w32.SelectObject(c.hdc, w32.GetStockObject(w32.NULL_BRUSH))
-	w32.SetTextColor(c.hdc, w32.COLORREF(color))
-	r := w32.RECT{
-		Left:   int32(x),
-		Top:    int32(y),
-		Right:  int32(x + w),
-		Bottom: int32(y + h),
-	}
-	var flags uint = w32.DT_WORDBREAK | w32.DT_NOFULLWIDTHCHARBREAK | w32.DT_EXPANDTABS
-	// add the appropriate horizontal positioning flag
-	switch format {
-	default:
-		flags |= w32.DT_LEFT
-	case FormatTopCenter, FormatCenter, FormatBottomCenter:
-		flags |= w32.DT_CENTER
-	case FormatTopRight, FormatCenterRight, FormatBottomRight:
-		flags |= w32.DT_RIGHT
-	}
-	// w32.DrawText will only respect w32.DT_VCENTER and w32.DT_BOTTOM if the
-	// single-line option is also set, this means that we actually have to do
-	// the work of positioning the text vertically ourselves
-	switch format {
-	default:
-		w32.DrawText(c.hdc, s, &r, flags)
-	case FormatCenterLeft, FormatCenter, FormatCenterRight:
-		calc := r
-		w32.DrawText(c.hdc, s, &calc, flags|w32.DT_CALCRECT)
-		if calc.Height() < r.Height() {
-			r.Top += (r.Height() - calc.Height()) / 2
-		}
-		w32.DrawText(c.hdc, s, &r, flags)
-	case FormatBottomLeft, FormatBottomCenter, FormatBottomRight:
-		calc := r
-		w32.DrawText(c.hdc, s, &calc, flags|w32.DT_CALCRECT)
-		if calc.Height() < r.Height() {
-			r.Top += r.Height() - calc.Height()
-		}
-		w32.DrawText(c.hdc, s, &r, flags)
-	}
-	w32.SetBkMode(c.hdc, w32.OPAQUE)
+	c.painter.textRect(x, y, w, h, s, format, color)
 }
 
 func (c *Canvas) SetFont(font *Font) {
 	if font != nil {
-		w32.SelectObject(c.hdc, w32.HGDIOBJ(font.handle))
+		c.painter.setFont(font)
 	}
 }
 
@@ -374,64 +239,22 @@ func (c *Canvas) DrawImage(img *Image, src Rectangle, destX, destY int) {
 	if src.Height == 0 {
 		src.Height = img.height
 	}
-
-	hdcMem := w32.CreateCompatibleDC(c.hdc)
-	old := w32.SelectObject(hdcMem, w32.HGDIOBJ(img.bitmap))
-
-	w32.AlphaBlend(
-		c.hdc,
-		destX, destY, src.Width, src.Height,
-		hdcMem,
-		src.X, src.Y, src.Width, src.Height,
-		w32.BLENDFUNC{
-			BlendOp:             w32.AC_SRC_OVER,
-			BlendFlags:          0,
-			SourceConstantAlpha: 255,
-			AlphaFormat:         w32.AC_SRC_ALPHA,
-		},
-	)
-
-	w32.SelectObject(hdcMem, old)
-	w32.DeleteDC(hdcMem)
+	c.painter.drawImage(img, src, destX, destY)
 }
 
 // NewImageFromHBITMAP takes a handle to a bitmap (HBITMAP) and makes it an
 // Image that you can use in Canvas.DrawImage.
 func NewImageFromHBITMAP(bitmap uintptr, width, height int) *Image {
 	return &Image{
-		bitmap: w32.HBITMAP(bitmap),
+		bitmap: bitmap,
 		width:  width,
 		height: height,
 	}
 }
 
 func NewImage(img image.Image) *Image {
-	var bmp w32.BITMAPINFO
-	bmp.BmiHeader.BiSize = uint32(unsafe.Sizeof(bmp.BmiHeader))
-	bmp.BmiHeader.BiWidth = int32(img.Bounds().Dx())
-	bmp.BmiHeader.BiHeight = -int32(img.Bounds().Dy())
-	bmp.BmiHeader.BiPlanes = 1
-	bmp.BmiHeader.BiBitCount = 32
-	bmp.BmiHeader.BiCompression = w32.BI_RGB
-
-	var bits unsafe.Pointer
-	bitmap := w32.CreateDIBSection(0, &bmp, 0, &bits, 0, 0)
-	rgba := toRGBA(img)
-	pixels := rgba.Pix
-	var dest []byte
-	hdrp := (*reflect.SliceHeader)(unsafe.Pointer(&dest))
-	hdrp.Data = uintptr(bits)
-	hdrp.Len = len(pixels)
-	hdrp.Cap = hdrp.Len
-	// swap red and blue because we need BGR and not RGB on Windows
-	for i := 0; i < len(pixels); i += 4 {
-		dest[i+0] = pixels[i+2]
-		dest[i+1] = pixels[i+1]
-		dest[i+2] = pixels[i+0]
-		dest[i+3] = pixels[i+3]
-	}
 	return &Image{
-		bitmap: bitmap,
+		bitmap: ui.createBitmap(toRGBA(img)),
 		width:  img.Bounds().Dx(),
 		height: img.Bounds().Dy(),
 	}
@@ -447,7 +270,7 @@ func toRGBA(img image.Image) *image.RGBA {
 }
 
 type Image struct {
-	bitmap w32.HBITMAP
+	bitmap uintptr
 	width  int
 	height int
 }
@@ -1,10 +1,6 @@
 package wui
 
-import (
-	"syscall"
-
-	"github.com/gonutz/w32/v2"
-)
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewPanel() *Panel {
 	return &Panel{}
@@ -13,6 +9,7 @@ func NewPanel() *Panel {
 type Panel struct {
 	control
 	children []Control
+	layout   Layout
 	border   PanelBorderStyle
 	font     *Font
 }
@@ -75,47 +72,29 @@ func (s PanelBorderStyle) String() string {
 
 func borderStyleEx(b PanelBorderStyle) uint {
 	if b == PanelBorderSunken {
-		return w32.WS_EX_STATICEDGE
+		return win.WS_EX_STATICEDGE
 	}
 	if b == PanelBorderSunkenThick {
-		return w32.WS_EX_CLIENTEDGE
+		return win.WS_EX_CLIENTEDGE
 	}
 	return 0
 }
 
 func borderStyle(b PanelBorderStyle) uint {
 	if b == PanelBorderSingleLine {
-		return w32.WS_BORDER
+		return win.WS_BORDER
 	}
 	if b == PanelBorderRaised {
-		return w32.WS_DLGFRAME
+		return win.WS_DLGFRAME
 	}
 	return 0
 }
 
 func (p *Panel) create(id int) {
 	p.control.create(id, borderStyleEx(p.border), "STATIC", borderStyle(p.border))
-	w32.SetWindowSubclass(p.handle, syscall.NewCallback(func(
-		window w32.HWND,
-		msg uint32,
-		wParam, lParam uintptr,
-		subclassID uintptr,
-		refData uintptr,
-	) uintptr {
-		switch msg {
-		case w32.WM_COMMAND:
-			p.onWM_COMMAND(wParam, lParam)
-			return 0
-		case w32.WM_DRAWITEM:
-			p.onWM_DRAWITEM(wParam, lParam)
-			return 0
-		case w32.WM_NOTIFY:
-			p.onWM_NOTIFY(wParam, lParam)
-			return 0
-		default:
-			return w32.DefSubclassProc(window, msg, wParam, lParam)
-		}
-	}), 0, 0)
+	// Notifications of our children are sent to us, their parent window, but
+	// they are handled in the top-level window.
+	ui.forwardNotifications(p.handle)
 	for _, c := range p.children {
 		c.create(p.getIDFor(c))
 	}
@@ -127,41 +106,39 @@ func (p *Panel) Add(c Control) {
 	if p.handle != 0 {
 		c.create(p.getIDFor(c))
 	}
+	relayout(p)
 }
 
 func (p *Panel) Remove(c Control) {
 	for i, child := range p.children {
 		if child == c {
+			forgetChild(p, child)
 			child.setParent(nil)
 			child.destroy()
 			p.children = append(p.children[:i], p.children[i+1:]...)
+			relayout(p)
 			return
 		}
 	}
 }
 
-func (p *Panel) getHandle() w32.HWND {
+func (p *Panel) getHandle() uintptr {
 	return p.handle
 }
 
-func (p *Panel) getInstance() w32.HINSTANCE {
-	return p.parent.getInstance()
-}
-
 func (p *Panel) SetBorderStyle(s PanelBorderStyle) {
 	p.border = s
 	if p.handle != 0 {
-		style := uint(w32.GetWindowLongPtr(p.handle, w32.GWL_STYLE))
-		style = style &^ w32.WS_BORDER &^ w32.WS_DLGFRAME
+		style, exStyle := ui.style(p.handle)
+
+		style = style &^ win.WS_BORDER &^ win.WS_DLGFRAME
 		style |= borderStyle(s)
-		w32.SetWindowLongPtr(p.handle, w32.GWL_STYLE, uintptr(style))
 
-		exStyle := uint(w32.GetWindowLongPtr(p.handle, w32.GWL_EXSTYLE))
-		exStyle = exStyle &^ w32.WS_EX_STATICEDGE &^ w32.WS_EX_CLIENTEDGE
+		exStyle = exStyle &^ win.WS_EX_STATICEDGE &^ win.WS_EX_CLIENTEDGE
 		exStyle |= borderStyleEx(s)
-		w32.SetWindowLongPtr(p.handle, w32.GWL_EXSTYLE, uintptr(exStyle))
 
-		w32.InvalidateRect(p.parent.getHandle(), nil, true)
+		ui.setStyle(p.handle, style, exStyle)
+		ui.repaint(p.parent.getHandle())
 	}
 }
 
@@ -176,20 +153,23 @@ func (p *Panel) getIDFor(c Control) int {
 	return p.parent.getIDFor(c)
 }
 
-func (p *Panel) Children() []Control {
-	return p.children
+func (p *Panel) getDPI() int {
+	return p.dpi()
 }
 
-func (p *Panel) onWM_COMMAND(w, l uintptr) {
-	p.parent.onWM_COMMAND(w, l)
+func (p *Panel) Children() []Control {
+	return p.children
 }
 
-func (p *Panel) onWM_DRAWITEM(w, l uintptr) {
-	p.parent.onWM_DRAWITEM(w, l)
+func (p *Panel) Layout() Layout {
+	return p.layout
 }
 
-func (p *Panel) onWM_NOTIFY(w, l uintptr) {
-	p.parent.onWM_NOTIFY(w, l)
+// SetLayout makes the layout position the panel's children, see Layout. Pass
+// nil to position them by their anchors again. A layout can only be used by one
+// container at a time, setting it here removes it from its previous container.
+func (p *Panel) SetLayout(l Layout) {
+	setLayout(p, &p.layout, l)
 }
 
 func (p *Panel) Font() *Font {
@@ -200,10 +180,14 @@ func (p *Panel) Font() *Font {
 }
 
 func (p *Panel) SetFont(f *Font) {
+	old := p.font
 	p.font = f
 	for _, c := range p.children {
 		c.parentFontChanged()
 	}
+	if old != f {
+		releaseScaledFonts(old)
+	}
 }
 
 func (p *Panel) InnerX() int {
@@ -238,12 +222,13 @@ func (p *Panel) InnerSize() (width, height int) {
 
 func (p *Panel) InnerBounds() (x, y, width, height int) {
 	x, y, width, height = p.Bounds()
-	var r w32.RECT
-	w32.AdjustWindowRectEx(&r, borderStyle(p.border), false, borderStyleEx(p.border))
-	x -= int(r.Left)
-	y -= int(r.Top)
-	width -= int(r.Width())
-	height -= int(r.Height())
+	left, top, right, bottom := ui.frameSize(
+		borderStyle(p.border), borderStyleEx(p.border), false,
+	)
+	x += left
+	y += top
+	width -= left + right
+	height -= top + bottom
 	return
 }
 
@@ -278,12 +263,13 @@ func (p *Panel) SetInnerSize(width, height int) {
 }
 
 func (p *Panel) SetInnerBounds(x, y, width, height int) {
-	var r w32.RECT
-	w32.AdjustWindowRectEx(&r, borderStyle(p.border), false, borderStyleEx(p.border))
-	x += int(r.Left)
-	y += int(r.Top)
-	width += int(r.Width())
-	height += int(r.Height())
+	left, top, right, bottom := ui.frameSize(
+		borderStyle(p.border), borderStyleEx(p.border), false,
+	)
+	x -= left
+	y -= top
+	width += left + right
+	height += top + bottom
 	p.SetBounds(x, y, width, height)
 }
 
@@ -291,7 +277,11 @@ func (p *Panel) SetBounds(x, y, width, height int) {
 	_, _, oldW, oldH := p.InnerBounds()
 	p.control.SetBounds(x, y, width, height)
 	_, _, newW, newH := p.InnerBounds()
-	repositionChidrenByAnchors(p, oldW, oldH, newW, newH)
+	if p.layout != nil {
+		relayout(p)
+	} else {
+		repositionChidrenByAnchors(p, oldW, oldH, newW, newH)
+	}
 }
 
 // NOTE that we need to re-write all the Set... functions here to make them go
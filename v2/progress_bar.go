@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewProgressBar() *ProgressBar {
 	return &ProgressBar{}
@@ -36,20 +36,20 @@ func (p *ProgressBar) recreate() {
 	// have to destroy the window and create it anew. This changes the window
 	// handle but is the only option we have.
 	if p.handle != 0 {
-		w32.DestroyWindow(p.handle)
+		ui.destroy(p.handle)
 	}
-	var style uint = w32.PBS_SMOOTH
+	var style uint = win.PBS_SMOOTH
 	if p.vertical {
-		style |= w32.PBS_VERTICAL
+		style |= win.PBS_VERTICAL
 	}
 	if p.movesForever {
-		style |= w32.PBS_MARQUEE
+		style |= win.PBS_MARQUEE
 	}
-	p.control.create(p.id, w32.WS_EX_CLIENTEDGE, w32.PROGRESS_CLASS, style)
+	p.control.create(p.id, win.WS_EX_CLIENTEDGE, win.PROGRESS_CLASS, style)
 	if p.movesForever {
-		w32.SendMessage(p.handle, w32.PBM_SETMARQUEE, 1, 0)
+		ui.setProgressMarquee(p.handle, true)
 	} else {
-		w32.SendMessage(p.handle, w32.PBM_SETRANGE32, 0, maxProgressBarValue)
+		ui.setProgressRange(p.handle, maxProgressBarValue)
 		p.SetValue(p.value)
 	}
 }
@@ -95,6 +95,6 @@ func (p *ProgressBar) SetValue(v float64) {
 
 	if !p.movesForever && p.handle != 0 {
 		pos := int(v*maxProgressBarValue + 0.5)
-		w32.SendMessage(p.handle, w32.PBM_SETPOS, uintptr(pos), 0)
+		ui.setProgress(p.handle, pos)
 	}
 }
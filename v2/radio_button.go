@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 // TODO: We can create two radio buttons both set to checked and only later add
 // them both to their parents. This will keep them both checked, SetChecked only
@@ -21,7 +21,7 @@ func NewRadioButton() *RadioButton {
 type RadioButton struct {
 	textControl
 	checked bool
-	onCheck func(bool)
+	onCheck BoolEvent
 }
 
 var _ Control = (*RadioButton)(nil)
@@ -31,11 +31,15 @@ func (*RadioButton) canFocus() bool {
 }
 
 func (r *RadioButton) OnTabFocus() func() {
-	return r.onTabFocus
+	return r.onTabFocus.primary
 }
 
 func (r *RadioButton) SetOnTabFocus(f func()) {
-	r.onTabFocus = f
+	r.onTabFocus.primary = f
+}
+
+func (r *RadioButton) TabFocusEvent() *Event {
+	return &r.onTabFocus
 }
 
 func (*RadioButton) eatsTabs() bool {
@@ -43,10 +47,58 @@ func (*RadioButton) eatsTabs() bool {
 }
 
 func (r *RadioButton) create(id int) {
-	var style uint = w32.WS_TABSTOP | w32.BS_AUTORADIOBUTTON | w32.BS_NOTIFY
+	var style uint = win.WS_TABSTOP | win.BS_AUTORADIOBUTTON | win.BS_NOTIFY
 	r.textControl.create(id, 0, "BUTTON", style)
 	if r.checked {
-		w32.SendMessage(r.handle, w32.BM_SETCHECK, toCheckState(r.checked), 0)
+		ui.setChecked(r.handle, r.checked)
+	}
+	ui.hookControl(r.handle, controlHooks{keyDown: r.keyDown})
+}
+
+// keyDown moves the focus and the check mark to the next or previous radio
+// button in the same container when an arrow key is pressed.
+func (r *RadioButton) keyDown(key int) {
+	var backwards bool
+	switch Key(key) {
+	case KeyLeft, KeyUp:
+		backwards = true
+	case KeyRight, KeyDown:
+	default:
+		return
+	}
+	if r.parent == nil {
+		return
+	}
+
+	var group []*RadioButton
+	var stops []tabStop
+	cur := -1
+	for _, c := range r.parent.Children() {
+		if radio, ok := c.(*RadioButton); ok {
+			if radio == r {
+				cur = len(group)
+			}
+			group = append(group, radio)
+			stops = append(stops, tabStop{
+				focusable: radio.handle != 0 && Visible(radio) && Enabled(radio),
+			})
+		}
+	}
+	next := nextTabStop(stops, cur, backwards)
+	if next == -1 || group[next] == r {
+		return
+	}
+
+	n := group[next]
+	ui.focus(n.handle)
+	for _, radio := range group {
+		if radio != n {
+			radio.SetChecked(false)
+		}
+	}
+	if !n.Checked() {
+		n.SetChecked(true)
+		n.onCheck.fire(true)
 	}
 }
 
@@ -58,7 +110,7 @@ func (r *RadioButton) Checked() bool {
 
 func (r *RadioButton) updateCachedCheckState() {
 	if r.handle != 0 {
-		r.checked = w32.BST_CHECKED == w32.SendMessage(r.handle, w32.BM_GETCHECK, 0, 0)
+		r.checked = ui.checked(r.handle)
 	}
 }
 
@@ -74,7 +126,7 @@ func (r *RadioButton) SetChecked(checked bool) {
 	r.checked = checked
 	if r.handle != 0 {
 		// Windows will uncheck all siblings for us.
-		w32.SendMessage(r.handle, w32.BM_SETCHECK, toCheckState(r.checked), 0)
+		ui.setChecked(r.handle, r.checked)
 	} else if checked {
 		// If a radio button gets checked before we have a window handle,
 		// Windows will not uncheck its siblings for us, we have to do it
@@ -88,28 +140,30 @@ func (r *RadioButton) SetChecked(checked bool) {
 				}
 			}
 		}
-		if r.onCheck != nil {
-			r.onCheck(r.checked)
-		}
+		r.onCheck.fire(r.checked)
 	}
 }
 
 // OnCheck returns the callback set in SetOnCheck.
 func (r *RadioButton) OnCheck() func(checked bool) {
-	return r.onCheck
+	return r.onCheck.primary
 }
 
 // SetOnCheck sets a callback for when the RadioButton is checked. It is not
 // called when the RadioButton is being unchecked.
 func (r *RadioButton) SetOnCheck(f func(checked bool)) {
-	r.onCheck = f
+	r.onCheck.primary = f
+}
+
+func (r *RadioButton) CheckEvent() *BoolEvent {
+	return &r.onCheck
 }
 
 func (r *RadioButton) handleNotification(cmd uintptr) {
-	if cmd == w32.BN_CLICKED {
+	if cmd == win.BN_CLICKED {
 		r.updateCachedCheckState()
-		if r.checked && r.onCheck != nil {
-			r.onCheck(r.checked)
+		if r.checked {
+			r.onCheck.fire(r.checked)
 		}
 	}
 }
@@ -0,0 +1,204 @@
+package wui
+
+import (
+	"errors"
+	"strings"
+)
+
+// ModifierNames are the texts of the modifier keys in shortcut texts.
+type ModifierNames struct {
+	Control string
+	Alt     string
+	Shift   string
+}
+
+// ShortcutModifiers are the names of the modifier keys that ShortcutString
+// writes and ParseShortcut reads. Change them to the user's language, e.g. for
+// German:
+//
+//	wui.ShortcutModifiers = wui.ModifierNames{
+//		Control: "Strg",
+//		Alt:     "Alt",
+//		Shift:   "Umschalt",
+//	}
+//
+// ParseShortcut always understands the English names as well.
+var ShortcutModifiers = englishModifiers
+
+var englishModifiers = ModifierNames{
+	Control: "Ctrl",
+	Alt:     "Alt",
+	Shift:   "Shift",
+}
+
+// shortcutKeyNames are the texts of keys in shortcuts, where they differ from
+// the key constant names without the "Key" prefix.
+var shortcutKeyNames = map[Key]string{
+	KeyBack:      "Backspace",
+	KeyReturn:    "Enter",
+	KeyEscape:    "Esc",
+	KeyPrior:     "PgUp",
+	KeyNext:      "PgDn",
+	KeyInsert:    "Ins",
+	KeyDelete:    "Del",
+	KeySnapshot:  "PrtScn",
+	KeyCapital:   "CapsLock",
+	KeyScroll:    "ScrollLock",
+	KeyApps:      "Menu",
+	KeyMultiply:  "Num*",
+	KeyAdd:       "Num+",
+	KeySubtract:  "Num-",
+	KeyDivide:    "Num/",
+	KeyDecimal:   "Num.",
+	KeyOEMPlus:   "+",
+	KeyOEMMinus:  "-",
+	KeyOEMComma:  ",",
+	KeyOEMPeriod: ".",
+}
+
+// shortcutKeys maps the lower case names that ParseShortcut understands to
+// their keys. These are the texts that ShortcutString writes, all key
+// constant names without the "Key" prefix and some common alternatives.
+var shortcutKeys = makeShortcutKeys()
+
+func makeShortcutKeys() map[string]Key {
+	keys := map[string]Key{
+		"hangul":     KeyHangul,
+		"kanji":      KeyKanji,
+		"oemfjjisho": KeyOEMFjJisho,
+		"pageup":     KeyPrior,
+		"pagedown":   KeyNext,
+		"esc":        KeyEscape,
+		"del":        KeyDelete,
+		"ins":        KeyInsert,
+		"plus":       KeyOEMPlus,
+		"minus":      KeyOEMMinus,
+	}
+	for k := Key(0); k < 256; k++ {
+		if name := k.String(); strings.HasPrefix(name, "wui.Key") {
+			keys[strings.ToLower(strings.TrimPrefix(name, "wui.Key"))] = k
+		}
+	}
+	for k, name := range shortcutKeyNames {
+		keys[strings.ToLower(name)] = k
+	}
+	return keys
+}
+
+func isModifier(k Key) bool {
+	switch k {
+	case KeyControl, KeyLeftControl, KeyRightControl,
+		KeyShift, KeyLeftShift, KeyRightShift,
+		KeyAlt, KeyLeftAlt, KeyRightAlt:
+		return true
+	}
+	return false
+}
+
+// ParseShortcut reads a key combination like "Ctrl+Shift+S" and returns its
+// keys, the modifiers first, e.g. KeyControl, KeyShift, KeyS. Names are not
+// case-sensitive. The keys are named like their Key constants without the
+// "Key" prefix, e.g. "F5" or "Home", or like ShortcutString writes them, e.g.
+// "Del" or "PgUp". The modifiers are named as in ShortcutModifiers or in
+// English.
+func ParseShortcut(s string) ([]Key, error) {
+	var a accelerator
+	rest := strings.TrimSpace(s)
+	for {
+		key, n := parseModifier(rest)
+		if n == 0 {
+			break
+		}
+		switch key {
+		case KeyControl:
+			a.control = true
+		case KeyAlt:
+			a.alt = true
+		case KeyShift:
+			a.shift = true
+		}
+		rest = strings.TrimSpace(rest[n:])
+	}
+	if rest == "" {
+		return nil, errors.New(`wui.ParseShortcut: missing key in "` + s + `"`)
+	}
+	key, ok := shortcutKeys[strings.ToLower(rest)]
+	if !ok {
+		return nil, errors.New(`wui.ParseShortcut: unknown key "` + rest + `" in "` + s + `"`)
+	}
+	if isModifier(key) {
+		return nil, errors.New(`wui.ParseShortcut: missing key in "` + s + `"`)
+	}
+	var keys []Key
+	if a.control {
+		keys = append(keys, KeyControl)
+	}
+	if a.alt {
+		keys = append(keys, KeyAlt)
+	}
+	if a.shift {
+		keys = append(keys, KeyShift)
+	}
+	return append(keys, key), nil
+}
+
+// parseModifier returns the modifier key at the start of s and the number of
+// bytes up to and including the following '+'. It returns 0 bytes if s does
+// not start with a modifier.
+func parseModifier(s string) (Key, int) {
+	modifiers := []struct {
+		name string
+		key  Key
+	}{
+		{ShortcutModifiers.Control, KeyControl},
+		{ShortcutModifiers.Alt, KeyAlt},
+		{ShortcutModifiers.Shift, KeyShift},
+		{englishModifiers.Control, KeyControl},
+		{"Control", KeyControl},
+		{englishModifiers.Alt, KeyAlt},
+		{englishModifiers.Shift, KeyShift},
+	}
+	for _, m := range modifiers {
+		n := len(m.name)
+		if n == 0 || len(s) <= n || !strings.EqualFold(s[:n], m.name) {
+			continue
+		}
+		after := strings.TrimLeft(s[n:], " ")
+		if strings.HasPrefix(after, "+") {
+			return m.key, len(s) - len(after) + 1
+		}
+	}
+	return 0, 0
+}
+
+// ShortcutString returns the text for a key combination, e.g. "Ctrl+Shift+S"
+// for KeyControl, KeyShift, KeyS. The modifiers come first, in the order
+// Ctrl, Alt, Shift, see ShortcutModifiers for their names. ParseShortcut
+// understands the result.
+func ShortcutString(keys ...Key) string {
+	a := toAccelerator(keys)
+	var parts []string
+	if a.control {
+		parts = append(parts, ShortcutModifiers.Control)
+	}
+	if a.alt {
+		parts = append(parts, ShortcutModifiers.Alt)
+	}
+	if a.shift {
+		parts = append(parts, ShortcutModifiers.Shift)
+	}
+	if name := shortcutKeyName(a.key); name != "" {
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, "+")
+}
+
+func shortcutKeyName(k Key) string {
+	if name, ok := shortcutKeyNames[k]; ok {
+		return name
+	}
+	if name := k.String(); strings.HasPrefix(name, "wui.Key") {
+		return strings.TrimPrefix(name, "wui.Key")
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package wui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestParseShortcut(t *testing.T) {
+	tests := []struct {
+		text string
+		keys []Key
+	}{
+		{"S", []Key{KeyS}},
+		{"Ctrl+S", []Key{KeyControl, KeyS}},
+		{"ctrl+shift+s", []Key{KeyControl, KeyShift, KeyS}},
+		{" Shift + Alt + Ctrl + F5 ", []Key{KeyControl, KeyAlt, KeyShift, KeyF5}},
+		{"Control+Del", []Key{KeyControl, KeyDelete}},
+		{"Ctrl+Delete", []Key{KeyControl, KeyDelete}},
+		{"Alt+PgUp", []Key{KeyAlt, KeyPrior}},
+		{"Alt+PageDown", []Key{KeyAlt, KeyNext}},
+		{"Ctrl++", []Key{KeyControl, KeyOEMPlus}},
+		{"Ctrl+-", []Key{KeyControl, KeyOEMMinus}},
+		{"Ctrl+Num+", []Key{KeyControl, KeyAdd}},
+		{"Ctrl+Num0", []Key{KeyControl, KeyNum0}},
+		{"Shift+Enter", []Key{KeyShift, KeyReturn}},
+		{"Shift+Return", []Key{KeyShift, KeyReturn}},
+		{"Esc", []Key{KeyEscape}},
+		{"Ctrl+OEM1", []Key{KeyControl, KeyOEM1}},
+		{"Kanji", []Key{KeyKanji}},
+		{"Ctrl+Ctrl+A", []Key{KeyControl, KeyA}},
+	}
+	for _, test := range tests {
+		keys, err := ParseShortcut(test.text)
+		check.Eq(t, err, nil, test.text)
+		check.Eq(t, keys, test.keys, test.text)
+	}
+}
+
+func TestParseShortcutErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"Ctrl+",
+		"Ctrl",
+		"Ctrl+Shift",
+		"Ctrl+Foo",
+		"Hyper+S",
+		"Ctrl+S+T",
+	} {
+		keys, err := ParseShortcut(text)
+		check.Eq(t, keys == nil, true, text)
+		check.Eq(t, err != nil, true, text)
+		check.Eq(t, strings.HasPrefix(err.Error(), "wui.ParseShortcut: "), true)
+	}
+}
+
+func TestShortcutString(t *testing.T) {
+	check.Eq(t, ShortcutString(), "")
+	check.Eq(t, ShortcutString(KeyS), "S")
+	check.Eq(t, ShortcutString(KeyControl, KeyS), "Ctrl+S")
+	check.Eq(t, ShortcutString(KeyS, KeyShift, KeyLeftControl), "Ctrl+Shift+S")
+	check.Eq(t, ShortcutString(KeyShift, KeyRightAlt, KeyControl, KeyF12), "Ctrl+Alt+Shift+F12")
+	check.Eq(t, ShortcutString(KeyControl, KeyDelete), "Ctrl+Del")
+	check.Eq(t, ShortcutString(KeyControl, KeyOEMPlus), "Ctrl++")
+	check.Eq(t, ShortcutString(KeyAlt, KeyNum5), "Alt+Num5")
+	check.Eq(t, ShortcutString(KeyControl, KeyShift), "Ctrl+Shift")
+}
+
+func TestShortcutStringCanBeParsedForAllKeys(t *testing.T) {
+	for k := Key(0); k < 256; k++ {
+		if k.String() == "unknown Key" || isModifier(k) {
+			continue
+		}
+		for _, keys := range [][]Key{
+			{k},
+			{KeyControl, k},
+			{KeyControl, KeyAlt, KeyShift, k},
+		} {
+			text := ShortcutString(keys...)
+			parsed, err := ParseShortcut(text)
+			check.Eq(t, err, nil, text)
+			check.Eq(t, parsed, keys, text)
+		}
+	}
+}
+
+func TestShortcutModifiersCanBeLocalized(t *testing.T) {
+	defer func() { ShortcutModifiers = englishModifiers }()
+	ShortcutModifiers = ModifierNames{Control: "Strg", Alt: "Alt", Shift: "Umschalt"}
+
+	check.Eq(t, ShortcutString(KeyControl, KeyShift, KeyS), "Strg+Umschalt+S")
+	keys, err := ParseShortcut("Strg+Umschalt+S")
+	check.Eq(t, err, nil)
+	check.Eq(t, keys, []Key{KeyControl, KeyShift, KeyS})
+	// English names always work.
+	keys, err = ParseShortcut("Ctrl+Shift+S")
+	check.Eq(t, err, nil)
+	check.Eq(t, keys, []Key{KeyControl, KeyShift, KeyS})
+}
+
+func TestKeyStringIsGoCode(t *testing.T) {
+	check.Eq(t, Key(KeyA).String(), "wui.KeyA")
+	check.Eq(t, Key(Key0).String(), "wui.Key0")
+	check.Eq(t, Key(KeyF24).String(), "wui.KeyF24")
+	check.Eq(t, Key(KeyOEMClear).String(), "wui.KeyOEMClear")
+	// Keys with more than one name use the first one.
+	check.Eq(t, Key(KeyHangul).String(), "wui.KeyKana")
+	check.Eq(t, Key(0).String(), "unknown Key")
+}
+
+func TestMenuStringShortcut(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	clicks := 0
+	save := NewMenuString("&Save").SetOnClick(func() { clicks++ })
+	save.SetShortcut(KeyControl, KeyS)
+	check.Eq(t, save.Shortcut(), []Key{KeyControl, KeyS})
+	file := NewMenu("&File").Add(save)
+	w.SetMenu(NewMainMenu().Add(file))
+
+	w.SetOnShow(func() {
+		check.Eq(t, save.Text(), "&Save")
+		check.Eq(t, menuBarItem(h, save).text, "&Save\tCtrl+S")
+		h.PressShortcut(w, KeyControl, KeyS)
+		check.Eq(t, clicks, 1)
+
+		save.SetEnabled(false)
+		h.PressShortcut(w, KeyControl, KeyS)
+		check.Eq(t, clicks, 1)
+		save.SetEnabled(true)
+
+		save.SetShortcut(KeyF2)
+		check.Eq(t, menuBarItem(h, save).text, "&Save\tF2")
+		h.PressShortcut(w, KeyControl, KeyS)
+		check.Eq(t, clicks, 1)
+		h.PressShortcut(w, KeyF2)
+		check.Eq(t, clicks, 2)
+
+		file.Remove(save)
+		h.PressShortcut(w, KeyF2)
+		check.Eq(t, clicks, 2)
+
+		file.Add(save)
+		h.PressShortcut(w, KeyF2)
+		check.Eq(t, clicks, 3)
+
+		save.SetShortcut()
+		check.Eq(t, menuBarItem(h, save).text, "&Save")
+		h.PressShortcut(w, KeyF2)
+		check.Eq(t, clicks, 3)
+		check.Eq(t, len(w.shortcuts), 0)
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestMenuShortcutsMoveWithTheMenuBar(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	var clicked []string
+	first := NewMenuString("First").SetOnClick(func() {
+		clicked = append(clicked, "first")
+	})
+	first.SetShortcut(KeyControl, KeyN)
+	second := NewMenuString("Second").SetOnClick(func() {
+		clicked = append(clicked, "second")
+	})
+	second.SetShortcut(KeyControl, KeyN)
+	w.SetMenu(NewMainMenu().Add(NewMenu("Menu").Add(first)))
+
+	w.SetOnShow(func() {
+		h.PressShortcut(w, KeyControl, KeyN)
+		w.SetMenu(NewMainMenu().Add(NewMenu("Menu").Add(second)))
+		h.PressShortcut(w, KeyControl, KeyN)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, clicked, []string{"first", "second"})
+}
+
+func TestRemovedMenuShortcutsKeepWindowShortcuts(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	var pressed []string
+	save := NewMenuString("&Save").SetOnClick(func() {
+		pressed = append(pressed, "menu")
+	})
+	save.SetShortcut(KeyControl, KeyS)
+	file := NewMenu("&File").Add(save)
+	w.SetMenu(NewMainMenu().Add(file))
+
+	w.SetOnShow(func() {
+		// The window's own shortcut replaces the menu item's.
+		w.SetShortcut(func() { pressed = append(pressed, "window") }, KeyControl, KeyS)
+		save.SetShortcut(KeyF2)
+		h.PressShortcut(w, KeyControl, KeyS)
+		file.Remove(save)
+		h.PressShortcut(w, KeyControl, KeyS)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, pressed, []string{"window", "window"})
+}
@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewSlider() *Slider {
 	return &Slider{
@@ -22,7 +22,7 @@ type Slider struct {
 	hideTicks     bool
 	vertical      bool
 	tickPosition  TickPosition
-	onChange      func(cursor int)
+	onChange      IntEvent
 }
 
 var _ Control = (*Slider)(nil)
@@ -36,11 +36,15 @@ func (*Slider) canFocus() bool {
 }
 
 func (s *Slider) OnTabFocus() func() {
-	return s.onTabFocus
+	return s.onTabFocus.primary
 }
 
 func (s *Slider) SetOnTabFocus(f func()) {
-	s.onTabFocus = f
+	s.onTabFocus.primary = f
+}
+
+func (s *Slider) TabFocusEvent() *Event {
+	return &s.onTabFocus
 }
 
 func (*Slider) eatsTabs() bool {
@@ -93,27 +97,27 @@ func (p TickPosition) String() string {
 }
 
 func (s *Slider) create(id int) {
-	var style uint = w32.WS_TABSTOP
+	var style uint = win.WS_TABSTOP
 
 	if s.hideTicks {
-		style |= w32.TBS_NOTICKS
+		style |= win.TBS_NOTICKS
 	} else {
-		style |= w32.TBS_AUTOTICKS
+		style |= win.TBS_AUTOTICKS
 	}
 
 	if s.vertical {
-		style |= w32.TBS_VERT
+		style |= win.TBS_VERT
 	} else {
-		style |= w32.TBS_HORZ
+		style |= win.TBS_HORZ
 	}
 
 	switch s.tickPosition {
 	case TicksBottomOrRight:
-		style |= w32.TBS_BOTTOM
+		style |= win.TBS_BOTTOM
 	case TicksTopOrLeft:
-		style |= w32.TBS_TOP
+		style |= win.TBS_TOP
 	case TicksOnBothSides:
-		style |= w32.TBS_BOTH
+		style |= win.TBS_BOTH
 	}
 
 	s.control.create(id, 0, "msctls_trackbar32", style)
@@ -143,9 +147,7 @@ func (s *Slider) SetMax(max int) {
 func (s *Slider) SetMinMax(min, max int) {
 	s.min, s.max = min, max
 	if s.handle != 0 {
-		const redraw = 1
-		w32.SendMessage(s.handle, w32.TBM_SETRANGEMIN, 0, uintptr(min))
-		w32.SendMessage(s.handle, w32.TBM_SETRANGEMAX, redraw, uintptr(max))
+		ui.setSliderRange(s.handle, min, max)
 	} else {
 		if s.cursor < min {
 			s.cursor = min
@@ -181,15 +183,14 @@ func (s *Slider) SetCursorPosition(cursor int) {
 	}
 	s.cursor = cursor
 	if s.handle != 0 {
-		const redraw = 1
-		w32.SendMessage(s.handle, w32.TBM_SETPOS, redraw, uintptr(s.cursor))
+		ui.setSliderPosition(s.handle, s.cursor)
 	}
 }
 
 // CursorPosition returns the current position of the Slider.
 func (s *Slider) CursorPosition() int {
 	if s.handle != 0 {
-		s.cursor = int(w32.SendMessage(s.handle, w32.TBM_GETPOS, 0, 0))
+		s.cursor = ui.sliderPosition(s.handle)
 	}
 	return s.cursor
 }
@@ -207,7 +208,7 @@ func (s *Slider) SetTickFrequency(n int) {
 	}
 	s.tickFrequency = n
 	if s.handle != 0 {
-		w32.SendMessage(s.handle, w32.TBM_SETTICFREQ, uintptr(n), 0)
+		ui.setSliderTickFrequency(s.handle, n)
 	}
 }
 
@@ -215,7 +216,7 @@ func (s *Slider) SetTickFrequency(n int) {
 func (s *Slider) SetArrowIncrement(inc int) {
 	s.arrowInc = inc
 	if s.handle != 0 {
-		w32.SendMessage(s.handle, w32.TBM_SETLINESIZE, 0, uintptr(inc))
+		ui.setSliderArrowIncrement(s.handle, inc)
 	}
 }
 
@@ -227,7 +228,7 @@ func (s *Slider) ArrowIncrement() int {
 func (s *Slider) SetMouseIncrement(inc int) {
 	s.mouseInc = inc
 	if s.handle != 0 {
-		w32.SendMessage(s.handle, w32.TBM_SETPAGESIZE, 0, uintptr(inc))
+		ui.setSliderMouseIncrement(s.handle, inc)
 	}
 }
 
@@ -275,16 +276,20 @@ func (s *Slider) TickPosition() TickPosition {
 }
 
 func (s *Slider) SetOnChange(f func(cursor int)) {
-	s.onChange = f
+	s.onChange.primary = f
+}
+
+func (s *Slider) ChangeEvent() *IntEvent {
+	return &s.onChange
 }
 
 func (s *Slider) OnChange() func(cursor int) {
-	return s.onChange
+	return s.onChange.primary
 }
 
 func (s *Slider) handleChange(reason uintptr) {
-	if s.onChange != nil &&
-		reason != w32.TB_ENDTRACK && reason != w32.TB_THUMBPOSITION {
-		s.onChange(s.CursorPosition())
+	if !s.onChange.empty() &&
+		reason != win.TB_ENDTRACK && reason != win.TB_THUMBPOSITION {
+		s.onChange.fire(s.CursorPosition())
 	}
 }
@@ -0,0 +1,264 @@
+package wui
+
+import "github.com/gonutz/wui/v2/internal/win"
+
+// defaultStatusBarHeight estimates the height of a status bar before it is
+// created. Once the window is shown, the native status bar decides.
+const defaultStatusBarHeight = 22
+
+// NewStatusBar creates a status bar without parts. Attach it to a window with
+// Window.SetStatusBar.
+func NewStatusBar() *StatusBar {
+	return &StatusBar{}
+}
+
+// StatusBar is the bar at the bottom of a Window that shows the program's
+// status. It is divided into parts, each with its own text and icon. The
+// window's inner area does not include the status bar, its children are placed
+// above it.
+type StatusBar struct {
+	window        *Window
+	handle        uintptr
+	parts         []*StatusBarPart
+	hidesSizeGrip bool
+}
+
+// StatusBarPart is one section of a StatusBar. Create it with
+// StatusBar.AddPart.
+type StatusBarPart struct {
+	bar     *StatusBar
+	text    string
+	icon    *Icon
+	width   int
+	onClick Event
+}
+
+// Window returns the window that the status bar is attached to, or nil.
+func (s *StatusBar) Window() *Window {
+	return s.window
+}
+
+func (s *StatusBar) create() {
+	style := uint(win.WS_CHILD | win.WS_VISIBLE)
+	if !s.hidesSizeGrip {
+		style |= win.SBARS_SIZEGRIP
+	}
+	s.handle = ui.createChild(
+		s.window.handle, 0, 0, win.STATUSCLASSNAME, style, 0, 0, 0, 0,
+	)
+	s.applyFont()
+	s.applyParts()
+	for i := range s.parts {
+		s.applyPart(i)
+	}
+}
+
+func (s *StatusBar) destroy() {
+	if s.handle != 0 {
+		ui.destroy(s.handle)
+		s.handle = 0
+	}
+}
+
+// height returns the height of the status bar at the default DPI.
+func (s *StatusBar) height() int {
+	if s.handle != 0 {
+		return Unscale(ui.statusBarHeight(s.handle), s.window.DPI())
+	}
+	return defaultStatusBarHeight
+}
+
+// applyFont gives the native status bar the window's font.
+func (s *StatusBar) applyFont() {
+	if s.handle != 0 {
+		var font uintptr
+		if s.window.font != nil {
+			font = s.window.font.handleFor(s.window.DPI())
+		}
+		ui.setFont(s.handle, font)
+	}
+}
+
+// applyParts divides the native status bar according to the widths of the
+// parts. The stretching parts share the space that the fixed parts leave.
+func (s *StatusBar) applyParts() {
+	if s.handle == 0 {
+		return
+	}
+	if len(s.parts) == 0 {
+		// The native status bar always has at least one part, it stays empty.
+		ui.setStatusParts(s.handle, []int{-1})
+		ui.setStatusText(s.handle, 0, "")
+		ui.setStatusIcon(s.handle, 0, 0)
+		return
+	}
+	dpi := s.window.DPI()
+	_, _, space, _ := ui.clientBounds(s.window.handle)
+	stretching := 0
+	for _, p := range s.parts {
+		if p.width > 0 {
+			space -= Scale(p.width, dpi)
+		} else {
+			stretching++
+		}
+	}
+	space = maxInt(0, space)
+	rights := make([]int, len(s.parts))
+	right := 0
+	for i, p := range s.parts {
+		if p.width > 0 {
+			right += Scale(p.width, dpi)
+		} else {
+			share := space / stretching
+			space -= share
+			stretching--
+			right += share
+		}
+		rights[i] = right
+	}
+	if s.parts[len(s.parts)-1].width <= 0 {
+		// The last part reaches the right border, under the size grip.
+		rights[len(rights)-1] = -1
+	}
+	ui.setStatusParts(s.handle, rights)
+}
+
+// applyPart sets the text and icon of the native part at the index.
+func (s *StatusBar) applyPart(index int) {
+	if s.handle == 0 {
+		return
+	}
+	p := s.parts[index]
+	ui.setStatusText(s.handle, index, p.text)
+	var icon uintptr
+	if p.icon != nil {
+		icon = p.icon.handle
+	}
+	ui.setStatusIcon(s.handle, index, icon)
+}
+
+// AddPart appends a part with the given text. New parts stretch, use
+// StatusBarPart.SetWidth to give them a fixed width.
+func (s *StatusBar) AddPart(text string) *StatusBarPart {
+	p := &StatusBarPart{bar: s, text: text}
+	s.parts = append(s.parts, p)
+	s.applyParts()
+	s.applyPart(len(s.parts) - 1)
+	return p
+}
+
+// RemovePart removes the part from the status bar.
+func (s *StatusBar) RemovePart(p *StatusBarPart) {
+	for i := range s.parts {
+		if s.parts[i] == p {
+			p.bar = nil
+			s.parts = append(s.parts[:i], s.parts[i+1:]...)
+			s.applyParts()
+			// The native texts do not move with the parts.
+			for j := i; j < len(s.parts); j++ {
+				s.applyPart(j)
+			}
+			return
+		}
+	}
+}
+
+func (s *StatusBar) Parts() []*StatusBarPart {
+	return append([]*StatusBarPart(nil), s.parts...)
+}
+
+func (s *StatusBar) SizeGrip() bool {
+	return !s.hidesSizeGrip
+}
+
+// SetSizeGrip shows or hides the grip in the bottom-right corner that the
+// user can drag to resize the window. The size grip is shown by default.
+func (s *StatusBar) SetSizeGrip(show bool) {
+	s.hidesSizeGrip = !show
+	if s.handle != 0 {
+		style, exStyle := ui.style(s.handle)
+		if show {
+			style |= win.SBARS_SIZEGRIP
+		} else {
+			style &^= win.SBARS_SIZEGRIP
+		}
+		ui.setStyle(s.handle, style, exStyle)
+		ui.repaint(s.handle)
+	}
+}
+
+// partClicked is called by the backend when the user clicks the part at the
+// index.
+func (s *StatusBar) partClicked(index int) {
+	if 0 <= index && index < len(s.parts) {
+		s.parts[index].onClick.fire()
+	}
+}
+
+// index returns the part's index in its status bar, or -1 if it was removed.
+func (p *StatusBarPart) index() int {
+	if p.bar != nil {
+		for i := range p.bar.parts {
+			if p.bar.parts[i] == p {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// StatusBar returns the status bar that the part belongs to, or nil if it was
+// removed.
+func (p *StatusBarPart) StatusBar() *StatusBar {
+	return p.bar
+}
+
+func (p *StatusBarPart) Text() string {
+	return p.text
+}
+
+func (p *StatusBarPart) SetText(text string) {
+	p.text = text
+	if i := p.index(); i != -1 {
+		p.bar.applyPart(i)
+	}
+}
+
+func (p *StatusBarPart) Icon() *Icon {
+	return p.icon
+}
+
+// SetIcon sets the icon that is shown left of the text, nil removes it.
+func (p *StatusBarPart) SetIcon(icon *Icon) {
+	p.icon = icon
+	if i := p.index(); i != -1 {
+		p.bar.applyPart(i)
+	}
+}
+
+// Width returns the fixed width of the part, 0 if it stretches.
+func (p *StatusBarPart) Width() int {
+	return p.width
+}
+
+// SetWidth gives the part a fixed width. A width of 0 or less makes the part
+// stretch. Stretching parts share the space that is left by the fixed parts.
+func (p *StatusBarPart) SetWidth(width int) {
+	p.width = maxInt(0, width)
+	if p.bar != nil {
+		p.bar.applyParts()
+	}
+}
+
+func (p *StatusBarPart) OnClick() func() {
+	return p.onClick.primary
+}
+
+// SetOnClick sets the function that is called when the user clicks the part.
+func (p *StatusBarPart) SetOnClick(f func()) {
+	p.onClick.primary = f
+}
+
+func (p *StatusBarPart) ClickEvent() *Event {
+	return &p.onClick
+}
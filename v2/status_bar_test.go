@@ -0,0 +1,134 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/wui/v2/internal/win"
+)
+
+func TestStatusBarIsNotPartOfTheInnerArea(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	w.SetInnerSize(600, 400)
+	b := NewButton()
+	b.SetBounds(10, 370, 80, 25)
+	b.SetVerticalAnchor(AnchorMax)
+	w.Add(b)
+
+	bar := NewStatusBar()
+	w.SetStatusBar(bar)
+	check.Eq(t, bar.Window(), w)
+	check.Eq(t, w.InnerHeight(), 400-defaultStatusBarHeight)
+	check.Eq(t, b.Y(), 370-defaultStatusBarHeight)
+
+	w.SetInnerSize(600, 400)
+	check.Eq(t, w.InnerHeight(), 400)
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.handles[bar.handle].ClassName, win.STATUSCLASSNAME)
+		check.Eq(t, w.InnerHeight(), 400)
+
+		w.SetStatusBar(nil)
+		check.Eq(t, bar.handle, uintptr(0))
+		check.Eq(t, bar.Window(), (*Window)(nil))
+		check.Eq(t, w.InnerHeight(), 400+defaultStatusBarHeight)
+		check.Eq(t, b.Y(), 370)
+
+		w.SetStatusBar(bar)
+		check.Eq(t, w.InnerHeight(), 400)
+		check.Eq(t, b.Y(), 370-defaultStatusBarHeight)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, bar.handle, uintptr(0))
+}
+
+func TestStatusBarPartsShareTheWidth(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	w.SetInnerSize(600, 400)
+	bar := NewStatusBar()
+	w.SetStatusBar(bar)
+	fixed := bar.AddPart("fixed")
+	fixed.SetWidth(100)
+	bar.AddPart("stretch 1")
+	small := bar.AddPart("small")
+	small.SetWidth(50)
+	last := bar.AddPart("stretch 2")
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.StatusParts(bar), []HeadlessStatusPart{
+			{Right: 100, Text: "fixed"},
+			{Right: 325, Text: "stretch 1"},
+			{Right: 375, Text: "small"},
+			{Right: -1, Text: "stretch 2"},
+		})
+
+		w.SetInnerWidth(700)
+		check.Eq(t, h.StatusParts(bar)[1].Right, 375)
+
+		last.SetWidth(80)
+		check.Eq(t, h.StatusParts(bar)[1].Right, 570)
+		check.Eq(t, h.StatusParts(bar)[3].Right, 700)
+
+		// The texts move with the parts.
+		bar.RemovePart(fixed)
+		check.Eq(t, fixed.StatusBar(), (*StatusBar)(nil))
+		check.Eq(t, h.StatusParts(bar), []HeadlessStatusPart{
+			{Right: 570, Text: "stretch 1"},
+			{Right: 620, Text: "small"},
+			{Right: 700, Text: "stretch 2"},
+		})
+
+		for _, p := range bar.Parts() {
+			bar.RemovePart(p)
+		}
+		check.Eq(t, h.StatusParts(bar), []HeadlessStatusPart{{Right: -1}})
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestStatusBarPartTextsIconsAndClicks(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	bar := NewStatusBar()
+	w.SetStatusBar(bar)
+	ready := bar.AddPart("Ready")
+	ready.SetIcon(IconInformation)
+	line := bar.AddPart("Line 1")
+	var clicks []string
+	ready.SetOnClick(func() { clicks = append(clicks, "ready") })
+	line.SetOnClick(func() { clicks = append(clicks, "line") })
+
+	check.Eq(t, bar.SizeGrip(), true)
+
+	w.SetOnShow(func() {
+		parts := h.StatusParts(bar)
+		check.Eq(t, parts[0].Icon, IconInformation.handle)
+		check.Eq(t, parts[1].Icon, uintptr(0))
+
+		line.SetText("Line 2")
+		ready.SetIcon(nil)
+		parts = h.StatusParts(bar)
+		check.Eq(t, parts[0].Icon, uintptr(0))
+		check.Eq(t, parts[1].Text, "Line 2")
+
+		h.ClickStatusPart(bar, 1)
+		h.ClickStatusPart(bar, 0)
+		h.ClickStatusPart(bar, 2)
+
+		check.Eq(t, h.handles[bar.handle].Style&win.SBARS_SIZEGRIP != 0, true)
+		bar.SetSizeGrip(false)
+		check.Eq(t, h.handles[bar.handle].Style&win.SBARS_SIZEGRIP, uint(0))
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, clicks, []string{"line", "ready"})
+}
@@ -0,0 +1,53 @@
+package wui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+func (*winAPI) setStatusParts(handle uintptr, rights []int) {
+	parts := make([]int32, len(rights))
+	for i := range rights {
+		parts[i] = int32(rights[i])
+	}
+	w32.SendMessage(
+		w32.HWND(handle),
+		w32.SB_SETPARTS,
+		uintptr(len(parts)),
+		uintptr(unsafe.Pointer(&parts[0])),
+	)
+}
+
+func (*winAPI) setStatusText(handle uintptr, part int, text string) {
+	ptr, _ := syscall.UTF16PtrFromString(text)
+	w32.SendMessage(
+		w32.HWND(handle),
+		w32.SB_SETTEXTW,
+		uintptr(part),
+		uintptr(unsafe.Pointer(ptr)),
+	)
+}
+
+func (*winAPI) setStatusIcon(handle uintptr, part int, icon uintptr) {
+	w32.SendMessage(w32.HWND(handle), w32.SB_SETICON, uintptr(part), icon)
+}
+
+func (*winAPI) statusBarHeight(handle uintptr) int {
+	r := w32.GetWindowRect(w32.HWND(handle))
+	if r == nil {
+		return 0
+	}
+	return int(r.Height())
+}
+
+// onNotify handles the WM_NOTIFY messages of the status bar and returns the
+// message result.
+func (s *StatusBar) onNotify(code uint32, lParam uintptr) uintptr {
+	if code == nmClick {
+		nm := (*nmMouse)(unsafe.Pointer(lParam))
+		s.partClicked(int(nm.itemSpec))
+	}
+	return 0
+}
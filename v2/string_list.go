@@ -1,11 +1,6 @@
 package wui
 
-import (
-	"syscall"
-	"unsafe"
-
-	"github.com/gonutz/w32/v2"
-)
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewStringList() *StringList {
 	return &StringList{selected: -1}
@@ -15,7 +10,7 @@ type StringList struct {
 	textControl
 	items    []string
 	selected int
-	onChange func(newIndex int)
+	onChange IntEvent
 }
 
 var _ Control = (*StringList)(nil)
@@ -35,9 +30,9 @@ func (*StringList) eatsTabs() bool {
 func (l *StringList) create(id int) {
 	l.textControl.create(
 		id,
-		w32.WS_EX_CLIENTEDGE,
+		win.WS_EX_CLIENTEDGE,
 		"LISTBOX",
-		w32.WS_TABSTOP|w32.LBS_NOTIFY,
+		win.WS_TABSTOP|win.LBS_NOTIFY,
 	)
 	for _, s := range l.items {
 		l.addItem(s)
@@ -53,14 +48,13 @@ func (l *StringList) AddItem(s string) {
 }
 
 func (l *StringList) addItem(s string) {
-	ptr, _ := syscall.UTF16PtrFromString(s)
-	w32.SendMessage(l.handle, w32.LB_ADDSTRING, 0, uintptr(unsafe.Pointer(ptr)))
+	ui.addItem(l.handle, s)
 }
 
 func (l *StringList) Clear() {
 	l.items = nil
 	if l.handle != 0 {
-		w32.SendMessage(l.handle, w32.LB_RESETCONTENT, 0, 0)
+		ui.clearItems(l.handle)
 	}
 }
 
@@ -71,7 +65,7 @@ func (l *StringList) Items() []string {
 func (l *StringList) SetItems(items []string) {
 	l.items = items
 	if l.handle != 0 {
-		w32.SendMessage(l.handle, w32.LB_RESETCONTENT, 0, 0)
+		ui.clearItems(l.handle)
 		for _, s := range l.items {
 			l.addItem(s)
 		}
@@ -80,7 +74,7 @@ func (l *StringList) SetItems(items []string) {
 
 func (l *StringList) SelectedIndex() int {
 	if l.handle != 0 {
-		l.selected = int(w32.SendMessage(l.handle, w32.LB_GETCURSEL, 0, 0))
+		l.selected = ui.selectedItem(l.handle)
 	}
 	return l.selected
 }
@@ -97,23 +91,25 @@ func (l *StringList) SetSelectedIndex(i int) {
 	}
 	l.selected = i
 	if l.handle != 0 {
-		w32.SendMessage(l.handle, w32.LB_SETCURSEL, uintptr(i), 0)
-		if l.onChange != nil {
-			l.onChange(i)
-		}
+		ui.selectItem(l.handle, i)
+		l.onChange.fire(i)
 	}
 }
 
 func (l *StringList) OnChange() func(newIndex int) {
-	return l.onChange
+	return l.onChange.primary
 }
 
 func (l *StringList) SetOnChange(f func(newIndex int)) {
-	l.onChange = f
+	l.onChange.primary = f
+}
+
+func (l *StringList) ChangeEvent() *IntEvent {
+	return &l.onChange
 }
 
 func (l *StringList) handleNotification(cmd uintptr) {
-	if cmd == w32.LBN_SELCHANGE && l.onChange != nil {
-		l.onChange(l.SelectedIndex())
+	if cmd == win.LBN_SELCHANGE && !l.onChange.empty() {
+		l.onChange.fire(l.SelectedIndex())
 	}
 }
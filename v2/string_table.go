@@ -1,11 +1,6 @@
 package wui
 
-import (
-	"syscall"
-	"unsafe"
-
-	"github.com/gonutz/w32/v2"
-)
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewStringTable(header1 string, headers ...string) *StringTable {
 	return &StringTable{
@@ -20,7 +15,8 @@ type StringTable struct {
 	headers           []string
 	items             []string
 	createdRows       int
-	onSelectionChange func()
+	onSelectionChange Event
+	selectionLocked   bool
 	selected          int
 }
 
@@ -31,11 +27,15 @@ func (*StringTable) canFocus() bool {
 }
 
 func (s *StringTable) OnTabFocus() func() {
-	return s.onTabFocus
+	return s.onTabFocus.primary
 }
 
 func (s *StringTable) SetOnTabFocus(f func()) {
-	s.onTabFocus = f
+	s.onTabFocus.primary = f
+}
+
+func (s *StringTable) TabFocusEvent() *Event {
+	return &s.onTabFocus
 }
 
 func (*StringTable) eatsTabs() bool {
@@ -45,39 +45,12 @@ func (*StringTable) eatsTabs() bool {
 func (c *StringTable) create(id int) {
 	c.textControl.create(
 		id,
-		w32.WS_EX_CLIENTEDGE,
+		win.WS_EX_CLIENTEDGE,
 		"SysListView32",
-		w32.WS_TABSTOP|w32.LVS_REPORT|w32.LVS_SINGLESEL|w32.LVS_NOSORTHEADER|
-			w32.LVS_SHOWSELALWAYS,
+		win.WS_TABSTOP|win.LVS_REPORT|win.LVS_SINGLESEL|win.LVS_NOSORTHEADER|
+			win.LVS_SHOWSELALWAYS,
 	)
-	w32.SendMessage(c.handle, w32.LVM_SETEXTENDEDLISTVIEWSTYLE, 0,
-		w32.LVS_EX_FULLROWSELECT|w32.LVS_EX_DOUBLEBUFFER|w32.LVS_EX_GRIDLINES)
-
-	hdc := w32.GetDC(c.handle)
-	defer w32.ReleaseDC(c.handle, hdc)
-	var maxW int32
-	for i := range c.headers {
-		size, ok := w32.GetTextExtentPoint32(hdc, c.headers[i])
-		if ok && size.CX > maxW {
-			maxW = size.CX
-		}
-	}
-	for i := range c.headers {
-		header, _ := syscall.UTF16PtrFromString(c.headers[i])
-		var w int32 = 5
-		size, ok := w32.GetTextExtentPoint32(hdc, c.headers[i])
-		if ok {
-			w = size.CX
-		}
-		w32.SendMessage(c.handle, w32.LVM_INSERTCOLUMN, uintptr(i), uintptr(unsafe.Pointer(
-			&w32.LVCOLUMN{
-				Mask:     w32.LVCF_FMT | w32.LVCF_WIDTH | w32.LVCF_TEXT | w32.LVCF_SUBITEM,
-				Fmt:      w32.LVCFMT_CENTER,
-				Cx:       w + 12, // we need a margin or the headers will not be fully displayed
-				PszText:  header,
-				ISubItem: int32(i + 1),
-			})))
-	}
+	ui.setListViewColumns(c.handle, c.headers)
 	for i, item := range c.items {
 		c.SetCell(c.indexToCol(i), c.indexToRow(i), item)
 	}
@@ -101,22 +74,11 @@ func (c *StringTable) SetCell(col, row int, s string) {
 	} else {
 		// make sure there are enough rows available
 		for c.createdRows <= row {
-			w32.SendMessage(
-				c.handle,
-				w32.LVM_INSERTITEM,
-				0,
-				uintptr(unsafe.Pointer(&w32.LVITEM{IItem: int32(c.createdRows)})),
-			)
+			ui.insertListViewRow(c.handle, c.createdRows)
 			c.createdRows++
 		}
 		// set the cell's text
-		text, _ := syscall.UTF16PtrFromString(s)
-		w32.SendMessage(c.handle, w32.LVM_SETITEMTEXT, uintptr(row),
-			uintptr(unsafe.Pointer(&w32.LVITEM{
-				Mask:     w32.LVIF_TEXT,
-				PszText:  text,
-				ISubItem: int32(col),
-			})))
+		ui.setListViewCell(c.handle, col, row, s)
 	}
 }
 
@@ -133,10 +95,10 @@ func (c *StringTable) indexToRow(i int) int {
 }
 
 func (c *StringTable) lockOnSelectionChange() (unlock func()) {
-	lock := c.onSelectionChange
-	c.onSelectionChange = nil
+	locked := c.selectionLocked
+	c.selectionLocked = true
 	return func() {
-		c.onSelectionChange = lock
+		c.selectionLocked = locked
 	}
 }
 
@@ -156,28 +118,32 @@ func (c *StringTable) DeleteRow(row int) {
 		}
 		defer c.lockOnSelectionChange()()
 		if c.handle != 0 {
-			w32.SendMessage(c.handle, w32.LVM_DELETEITEM, uintptr(row), 0)
+			ui.deleteListViewRow(c.handle, row)
 			c.createdRows--
 			if c.createdRows > 0 && c.HasFocus() {
 				// make sure the selection is still active
-				press := func(key uintptr) {
-					w32.SendMessage(c.handle, w32.WM_KEYDOWN, key, 0)
-					w32.SendMessage(c.handle, w32.WM_KEYUP, key, 0)
+				press := func(key Key) {
+					ui.pressKey(c.handle, key)
 				}
 				if c.createdRows == 1 {
-					press(w32.VK_UP)
+					press(KeyUp)
 				} else if row == 0 {
-					press(w32.VK_DOWN)
-					press(w32.VK_UP)
+					press(KeyDown)
+					press(KeyUp)
 				} else {
-					press(w32.VK_UP)
-					press(w32.VK_DOWN)
+					press(KeyUp)
+					press(KeyDown)
 				}
 			}
 		}
 	}
 }
 
+// Headers returns the column headers that the table was created with.
+func (c *StringTable) Headers() []string {
+	return append([]string{}, c.headers...)
+}
+
 func (c *StringTable) RowCount() int {
 	if c.handle == 0 {
 		return (len(c.items) + len(c.headers) - 1) / len(c.headers)
@@ -197,8 +163,8 @@ func (c *StringTable) SelectedRow() int {
 func (c *StringTable) newItemSelected(i int) {
 	if i != c.selected {
 		c.selected = i
-		if c.onSelectionChange != nil {
-			c.onSelectionChange()
+		if !c.selectionLocked {
+			c.onSelectionChange.fire()
 		}
 	}
 }
@@ -212,15 +178,19 @@ func (c *StringTable) Clear() {
 	defer c.lockOnSelectionChange()()
 
 	for i := c.RowCount() - 1; i >= 0; i-- {
-		w32.SendMessage(c.handle, w32.LVM_DELETEITEM, uintptr(i), 0)
+		ui.deleteListViewRow(c.handle, i)
 	}
 	c.createdRows = 0
 }
 
 func (c *StringTable) SetOnSelectionChange(f func()) {
-	c.onSelectionChange = f
+	c.onSelectionChange.primary = f
+}
+
+func (c *StringTable) SelectionChangeEvent() *Event {
+	return &c.onSelectionChange
 }
 
 func (c *StringTable) OnSelectionChange() func() {
-	return c.onSelectionChange
+	return c.onSelectionChange.primary
 }
@@ -0,0 +1,355 @@
+package wui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+// These are the Win32 functions that the w32 package does not provide.
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+	ole32    = syscall.NewLazyDLL("ole32.dll")
+	shell32  = syscall.NewLazyDLL("shell32.dll")
+	gdi32    = syscall.NewLazyDLL("gdi32.dll")
+
+	registerWindowMessageW         = user32.NewProc("RegisterWindowMessageW")
+	getDpiForWindowProc            = user32.NewProc("GetDpiForWindow")
+	setProcessDpiAwarenessContextW = user32.NewProc("SetProcessDpiAwarenessContext")
+	childWindowFromPointEx         = user32.NewProc("ChildWindowFromPointEx")
+	registerClipboardFormatW       = user32.NewProc("RegisterClipboardFormatW")
+	killTimerProc                  = user32.NewProc("KillTimer")
+	registerHotKeyProc             = user32.NewProc("RegisterHotKey")
+	unregisterHotKeyProc           = user32.NewProc("UnregisterHotKey")
+
+	globalSizeProc         = kernel32.NewProc("GlobalSize")
+	getCurrentThreadIdProc = kernel32.NewProc("GetCurrentThreadId")
+
+	oleInitializeProc    = ole32.NewProc("OleInitialize")
+	registerDragDropProc = ole32.NewProc("RegisterDragDrop")
+	revokeDragDropProc   = ole32.NewProc("RevokeDragDrop")
+	releaseStgMediumProc = ole32.NewProc("ReleaseStgMedium")
+	dragQueryFileW       = shell32.NewProc("DragQueryFileW")
+	shellNotifyIconW     = shell32.NewProc("Shell_NotifyIconW")
+	gdiFlushProc         = gdi32.NewProc("GdiFlush")
+)
+
+const (
+	wmDPIChanged = 0x02E0
+
+	emShowBalloonTip = 0x1503
+	emHideBalloonTip = 0x1504
+
+	nimAdd    = 0
+	nimModify = 1
+	nimDelete = 2
+
+	nifMessage = 0x01
+	nifIcon    = 0x02
+	nifTip     = 0x04
+	nifInfo    = 0x10
+
+	niifInfo    = 1
+	niifWarning = 2
+	niifError   = 3
+
+	ninBalloonUserClick = w32.WM_USER + 5
+
+	modAlt      = 0x0001
+	modControl  = 0x0002
+	modShift    = 0x0004
+	modWin      = 0x0008
+	modNoRepeat = 0x4000
+
+	errorHotKeyAlreadyRegistered = 1409
+
+	tpmRightButton = 0x0002
+	tpmReturnCmd   = 0x0100
+
+	ttmAddToolW       = w32.WM_USER + 50
+	ttmDelToolW       = w32.WM_USER + 51
+	ttnGetDispInfoW   = ^uint32(530 - 1) // TTN_FIRST - 10 = -530
+	ttdtAutoPop       = 2
+	ttdtInitial       = 3
+	lpstrTextCallback = ^uintptr(0)
+
+	tvmInsertItemW     = 0x1132
+	tvmSetItemW        = 0x113F
+	tvmDeleteItem      = 0x1101
+	tvmExpand          = 0x1102
+	tvmSelectItem      = 0x110B
+	tvmSetImageList    = 0x1109
+	tvifText           = 0x0001
+	tvifImage          = 0x0002
+	tvifState          = 0x0008
+	tvifHandle         = 0x0010
+	tvifSelectedImage  = 0x0020
+	tvifChildren       = 0x0040
+	tvisExpanded       = 0x0020
+	tvisStateImageMask = 0xF000
+	tveCollapse        = 1
+	tveExpand          = 2
+	tvgnCaret          = 9
+	tvsilNormal        = 0
+	tviRoot            = ^uintptr(0xFFFF) // -0x10000
+	tviLast            = ^uintptr(0xFFFD) // -0xFFFE
+	iImageNone         = -2
+	tvnSelChangedW     = ^uint32(451 - 1) // TVN_FIRST - 51 = -451
+	tvnItemExpandingW  = ^uint32(454 - 1) // TVN_FIRST - 54 = -454
+	tvnItemExpandedW   = ^uint32(455 - 1) // TVN_FIRST - 55 = -455
+	tvnBeginLabelEditW = ^uint32(459 - 1) // TVN_FIRST - 59 = -459
+	tvnEndLabelEditW   = ^uint32(460 - 1) // TVN_FIRST - 60 = -460
+	tvnItemChangedW    = ^uint32(419 - 1) // TVN_FIRST - 19 = -419
+
+	tcmGetItemW    = 0x133C
+	tcmSetItemW    = 0x133D
+	tcmInsertItemW = 0x133E
+	tcifText       = 0x0001
+	tcifImage      = 0x0002
+	tcifParam      = 0x0008
+	tcnSelChange   = ^uint32(551 - 1) // TCN_FIRST - 1 = -551
+	// tabCloseClicked is our own notification code for nmTabClose.
+	tabCloseClicked = 1
+
+	nmClick = ^uint32(2 - 1) // NM_CLICK = -2
+
+	// DPI_AWARENESS_CONTEXT_PER_MONITOR_AWARE_V2 is the handle -4.
+	dpiAwarenessContextPerMonitorAwareV2 = ^uintptr(3)
+)
+
+func registerWindowMessage(name string) uint32 {
+	ret, _, _ := registerWindowMessageW.Call(
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(name))),
+	)
+	return uint32(ret)
+}
+
+func registerClipboardFormat(name string) uint {
+	ret, _, _ := registerClipboardFormatW.Call(
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(name))),
+	)
+	return uint(ret)
+}
+
+func killTimer(window, id uintptr) {
+	killTimerProc.Call(window, id)
+}
+
+func getCurrentThreadId() uint32 {
+	ret, _, _ := getCurrentThreadIdProc.Call()
+	return uint32(ret)
+}
+
+func globalSize(mem uintptr) int {
+	ret, _, _ := globalSizeProc.Call(mem)
+	return int(ret)
+}
+
+// getDpiForWindow returns 0 if GetDpiForWindow is not available, it was added
+// in Windows 10 1607.
+func getDpiForWindow(window uintptr) int {
+	if getDpiForWindowProc.Find() != nil {
+		return 0
+	}
+	ret, _, _ := getDpiForWindowProc.Call(window)
+	return int(ret)
+}
+
+// setProcessDpiAwarenessContext returns false if the awareness could not be
+// set, e.g. because SetProcessDpiAwarenessContext is not available before
+// Windows 10 1703.
+func setProcessDpiAwarenessContext(context uintptr) bool {
+	if setProcessDpiAwarenessContextW.Find() != nil {
+		return false
+	}
+	ret, _, _ := setProcessDpiAwarenessContextW.Call(context)
+	return ret != 0
+}
+
+// editBalloonTip is the EDITBALLOONTIP struct for EM_SHOWBALLOONTIP.
+type editBalloonTip struct {
+	size  uint32
+	title *uint16
+	text  *uint16
+	icon  int32
+}
+
+// formatEtc is the FORMATETC struct.
+type formatEtc struct {
+	format uint16
+	device uintptr
+	aspect uint32
+	index  int32
+	tymed  uint32
+}
+
+// stgMedium is the STGMEDIUM struct. handle is the union of its storage types.
+type stgMedium struct {
+	tymed         uint32
+	handle        uintptr
+	unkForRelease uintptr
+}
+
+// oleInitialize returns false if OLE cannot be used on this thread, e.g.
+// because COM was initialized for multi-threading before.
+func oleInitialize() bool {
+	ret, _, _ := oleInitializeProc.Call(0)
+	// S_FALSE means OLE was already initialized.
+	return ret == 0 || ret == 1
+}
+
+func registerDragDrop(window, target uintptr) bool {
+	ret, _, _ := registerDragDropProc.Call(window, target)
+	return ret == 0
+}
+
+func revokeDragDrop(window uintptr) {
+	revokeDragDropProc.Call(window)
+}
+
+func releaseStgMedium(m *stgMedium) {
+	releaseStgMediumProc.Call(uintptr(unsafe.Pointer(m)))
+}
+
+// dragQueryFile returns the number of files if i is 0xFFFFFFFF. Otherwise it
+// copies the i'th path to buf and returns its length without the terminating
+// 0. If buf is nil, it only returns the length.
+func dragQueryFile(drop uintptr, i uint32, buf []uint16) int {
+	var p uintptr
+	if len(buf) > 0 {
+		p = uintptr(unsafe.Pointer(&buf[0]))
+	}
+	ret, _, _ := dragQueryFileW.Call(drop, uintptr(i), p, uintptr(len(buf)))
+	return int(ret)
+}
+
+// notifyIconData is the NOTIFYICONDATAW struct.
+type notifyIconData struct {
+	size            uint32
+	window          uintptr
+	id              uint32
+	flags           uint32
+	callbackMessage uint32
+	icon            uintptr
+	tip             [128]uint16
+	state           uint32
+	stateMask       uint32
+	info            [256]uint16
+	version         uint32
+	infoTitle       [64]uint16
+	infoFlags       uint32
+	guid            w32.GUID
+	balloonIcon     uintptr
+}
+
+func shellNotifyIcon(message uint32, data *notifyIconData) bool {
+	ret, _, _ := shellNotifyIconW.Call(
+		uintptr(message),
+		uintptr(unsafe.Pointer(data)),
+	)
+	return ret != 0
+}
+
+// toolInfo is the TTTOOLINFOW struct.
+type toolInfo struct {
+	size     uint32
+	flags    uint32
+	window   uintptr
+	id       uintptr
+	rect     w32.RECT
+	instance uintptr
+	text     uintptr
+	lParam   uintptr
+	reserved uintptr
+}
+
+// toolTipDispInfo is the NMTTDISPINFOW struct.
+type toolTipDispInfo struct {
+	header   w32.NMHDR
+	text     *uint16
+	buffer   [80]uint16
+	instance uintptr
+	flags    uint32
+	lParam   uintptr
+}
+
+// tvItem is the TVITEMW struct.
+type tvItem struct {
+	mask          uint32
+	item          uintptr
+	state         uint32
+	stateMask     uint32
+	text          *uint16
+	textMax       int32
+	image         int32
+	selectedImage int32
+	children      int32
+	lParam        uintptr
+}
+
+// tvInsertStruct is the TVINSERTSTRUCTW struct, its item is a TVITEMEXW.
+type tvInsertStruct struct {
+	parent      uintptr
+	insertAfter uintptr
+	item        tvItem
+	// These are the fields that TVITEMEXW adds to TVITEMW.
+	integral      int32
+	stateEx       uint32
+	window        uintptr
+	expandedImage int32
+	reserved      int32
+}
+
+// nmTreeView is the NMTREEVIEWW struct.
+type nmTreeView struct {
+	header  w32.NMHDR
+	action  uint32
+	itemOld tvItem
+	itemNew tvItem
+	drag    w32.POINT
+}
+
+// nmTVDispInfo is the NMTVDISPINFOW struct.
+type nmTVDispInfo struct {
+	header w32.NMHDR
+	item   tvItem
+}
+
+// nmTVItemChange is the NMTVITEMCHANGE struct.
+type nmTVItemChange struct {
+	header   w32.NMHDR
+	changed  uint32
+	item     uintptr
+	stateNew uint32
+	stateOld uint32
+	lParam   uintptr
+}
+
+// tcItem is the TCITEMW struct.
+type tcItem struct {
+	mask      uint32
+	state     uint32
+	stateMask uint32
+	text      *uint16
+	textMax   int32
+	image     int32
+	lParam    uintptr
+}
+
+// nmMouse is the NMMOUSE struct.
+type nmMouse struct {
+	header   w32.NMHDR
+	itemSpec uintptr
+	itemData uintptr
+	pt       w32.POINT
+	hitInfo  uintptr
+}
+
+// nmTabClose is sent with WM_NOTIFY when the user clicks the close button of a
+// tab, see closableTabProc.
+type nmTabClose struct {
+	header w32.NMHDR
+	index  int32
+}
@@ -0,0 +1,683 @@
+package wui
+
+import (
+	"fmt"
+
+	"github.com/gonutz/wui/v2/internal/win"
+)
+
+// NewTabControl returns a container that shows one of its TabPages at a time.
+// The user switches between the pages by clicking their tabs. Use
+// TabControl.AddPage to fill it.
+func NewTabControl() *TabControl {
+	return &TabControl{}
+}
+
+// TabControl is a Container for TabPages. Only the page of the selected tab is
+// visible, all other pages and their children are hidden. Controls other than
+// TabPages cannot be added to a TabControl, add them to its pages instead.
+type TabControl struct {
+	control
+	pages        []*TabPage
+	selected     int
+	closableTabs bool
+	font         *Font
+	images       []*Image
+	onChange     IntEvent
+}
+
+var _ Control = (*TabControl)(nil)
+var _ Container = (*TabControl)(nil)
+
+// tabItem is a native tab.
+type tabItem struct {
+	text     string
+	image    int // Index into the tab control's images, -1 for no image.
+	closable bool
+}
+
+// Without a native tab control, e.g. before the window is shown, the pages are
+// placed inside these margins, in logical units.
+const (
+	tabHeaderHeight = 24
+	tabPageMargin   = 4
+)
+
+func (*TabControl) canFocus() bool {
+	return true
+}
+
+func (t *TabControl) OnTabFocus() func() {
+	return t.onTabFocus.primary
+}
+
+func (t *TabControl) SetOnTabFocus(f func()) {
+	t.onTabFocus.primary = f
+}
+
+func (t *TabControl) TabFocusEvent() *Event {
+	return &t.onTabFocus
+}
+
+func (*TabControl) eatsTabs() bool {
+	return false
+}
+
+func (t *TabControl) closing() {
+	for _, p := range t.pages {
+		p.closing()
+	}
+}
+
+func (t *TabControl) create(id int) {
+	t.control.create(id, 0, win.WC_TABCONTROL, win.WS_TABSTOP|win.WS_CLIPCHILDREN)
+	// The pages are our children, their notifications are handled in the
+	// top-level window.
+	ui.forwardNotifications(t.handle)
+	ui.setFont(t.handle, t.fontHandle())
+	if len(t.images) > 0 {
+		ui.setTabImages(t.handle, t.images)
+	}
+	for i, p := range t.pages {
+		ui.insertTab(t.handle, i, t.tabInfo(p))
+	}
+	ui.selectTab(t.handle, t.selected)
+	// The page area depends on the native tab headers.
+	t.layoutPages()
+	for _, p := range t.pages {
+		p.create(t.getIDFor(p))
+	}
+}
+
+func (t *TabControl) destroy() {
+	if t.handle != 0 {
+		// Tab controls do not destroy their image lists themselves.
+		if len(t.images) > 0 {
+			ui.setTabImages(t.handle, nil)
+		}
+		for _, p := range t.pages {
+			p.destroy()
+		}
+		t.control.destroy()
+	}
+}
+
+func (t *TabControl) tabInfo(p *TabPage) tabItem {
+	return tabItem{
+		text:     p.title,
+		image:    t.imageIndex(p.image),
+		closable: t.closableTabs,
+	}
+}
+
+// imageIndex returns the index of the image in the tab control's image list,
+// adding it if it is new, or -1 for nil.
+func (t *TabControl) imageIndex(img *Image) int {
+	if img == nil {
+		return -1
+	}
+	for i := range t.images {
+		if t.images[i] == img {
+			return i
+		}
+	}
+	t.images = append(t.images, img)
+	if t.handle != 0 {
+		ui.setTabImages(t.handle, t.images)
+	}
+	return len(t.images) - 1
+}
+
+// updateTabs sets the native tabs to the current page properties.
+func (t *TabControl) updateTabs() {
+	if t.handle != 0 {
+		for i, p := range t.pages {
+			ui.setTab(t.handle, i, t.tabInfo(p))
+		}
+		t.layoutPages()
+	}
+}
+
+// pageBounds returns the area that the pages cover, relative to the
+// TabControl.
+func (t *TabControl) pageBounds() (x, y, width, height int) {
+	left, top, right, bottom := tabPageMargin, tabHeaderHeight, tabPageMargin, tabPageMargin
+	if t.handle != 0 {
+		dpi := t.dpi()
+		left, top, right, bottom = ui.tabInsets(t.handle)
+		left, top = Unscale(left, dpi), Unscale(top, dpi)
+		right, bottom = Unscale(right, dpi), Unscale(bottom, dpi)
+	}
+	return left, top, maxInt(0, t.width-left-right), maxInt(0, t.height-top-bottom)
+}
+
+// layoutPages moves all pages to the page area and shows only the selected
+// page.
+func (t *TabControl) layoutPages() {
+	for i, p := range t.pages {
+		p.setBounds(t.pageBounds())
+		if p.hidden != (i != t.selected) {
+			p.control.SetVisible(i == t.selected)
+		}
+	}
+}
+
+// AddPage appends a new page with the given title and returns it.
+func (t *TabControl) AddPage(title string) *TabPage {
+	p := NewTabPage()
+	p.title = title
+	t.Add(p)
+	return p
+}
+
+// Add appends the control as a new page. Only TabPages can be added to a
+// TabControl, Add panics for other controls. Add them to a page instead.
+func (t *TabControl) Add(c Control) {
+	p, ok := c.(*TabPage)
+	if !ok {
+		panic(fmt.Sprintf("wui: TabControl.Add needs a *TabPage, not %T", c))
+	}
+	if p.Parent() != nil {
+		p.Parent().Remove(p)
+	}
+	t.pages = append(t.pages, p)
+	p.setParent(t)
+	// The page's children are placed relative to the page area, they are not
+	// moved by their anchors when the page gets its size.
+	p.control.SetBounds(t.pageBounds())
+	if t.handle != 0 {
+		ui.insertTab(t.handle, len(t.pages)-1, t.tabInfo(p))
+		ui.selectTab(t.handle, t.selected)
+	}
+	t.layoutPages()
+	if t.handle != 0 {
+		p.create(t.getIDFor(p))
+	}
+}
+
+// Remove removes the page from the TabControl. If it was the selected page, the
+// page after it, or the new last page, is selected instead and OnChange is
+// called.
+func (t *TabControl) Remove(c Control) {
+	for i, p := range t.pages {
+		if p == c {
+			p.setParent(nil)
+			p.destroy()
+			t.pages = append(t.pages[:i], t.pages[i+1:]...)
+			if t.handle != 0 {
+				ui.deleteTab(t.handle, i)
+			}
+			selectionChanged := false
+			if i < t.selected {
+				t.selected--
+			} else if i == t.selected {
+				if t.selected >= len(t.pages) {
+					t.selected = maxInt(0, len(t.pages)-1)
+				}
+				selectionChanged = len(t.pages) > 0
+			}
+			if t.handle != 0 {
+				ui.selectTab(t.handle, t.selected)
+			}
+			t.layoutPages()
+			if selectionChanged {
+				t.onChange.fire(t.selected)
+			}
+			return
+		}
+	}
+}
+
+// Children returns the TabPages as Controls.
+func (t *TabControl) Children() []Control {
+	children := make([]Control, len(t.pages))
+	for i := range t.pages {
+		children[i] = t.pages[i]
+	}
+	return children
+}
+
+// Pages returns the pages in the order of their tabs.
+func (t *TabControl) Pages() []*TabPage {
+	return t.pages
+}
+
+// SelectedIndex returns the index of the visible page. It is 0 by default.
+func (t *TabControl) SelectedIndex() int {
+	return t.selected
+}
+
+// SetSelectedIndex shows the page with the given index and hides all others.
+// The index is not clamped to the number of pages, this lets you set it before
+// adding the pages. If no page has the index, no page is visible.
+func (t *TabControl) SetSelectedIndex(i int) {
+	t.selected = i
+	if t.handle != 0 {
+		ui.selectTab(t.handle, i)
+	}
+	t.layoutPages()
+}
+
+// SelectedPage returns the visible page or nil if there is none.
+func (t *TabControl) SelectedPage() *TabPage {
+	if 0 <= t.selected && t.selected < len(t.pages) {
+		return t.pages[t.selected]
+	}
+	return nil
+}
+
+// OnChange returns the function that is called after the user selected a
+// different tab. It is not called when you call SetSelectedIndex.
+func (t *TabControl) OnChange() func(newIndex int) {
+	return t.onChange.primary
+}
+
+// SetOnChange sets the function that is called after the user selected a
+// different tab, see OnChange.
+func (t *TabControl) SetOnChange(f func(newIndex int)) {
+	t.onChange.primary = f
+}
+
+func (t *TabControl) ChangeEvent() *IntEvent {
+	return &t.onChange
+}
+
+// tabSelected is called when the user selects the tab with the given index.
+func (t *TabControl) tabSelected(index int) {
+	if index == t.selected {
+		return
+	}
+	t.selected = index
+	t.layoutPages()
+	t.onChange.fire(index)
+}
+
+// closeClicked is called when the user clicks the close button of a tab.
+func (t *TabControl) closeClicked(index int) {
+	if 0 <= index && index < len(t.pages) {
+		t.pages[index].Close()
+	}
+}
+
+func (t *TabControl) ClosableTabs() bool {
+	return t.closableTabs
+}
+
+// SetClosableTabs shows a close button on every tab. Clicking it calls the
+// page's Close.
+func (t *TabControl) SetClosableTabs(closable bool) {
+	t.closableTabs = closable
+	t.updateTabs()
+}
+
+func (t *TabControl) Font() *Font {
+	if t.font == nil && t.parent != nil {
+		return t.parent.Font()
+	}
+	return t.font
+}
+
+func (t *TabControl) SetFont(f *Font) {
+	old := t.font
+	t.font = f
+	t.parentFontChanged()
+	if old != f {
+		releaseScaledFonts(old)
+	}
+}
+
+func (t *TabControl) parentFontChanged() {
+	if t.handle != 0 {
+		ui.setFont(t.handle, t.fontHandle())
+		// The tab headers change size with the font.
+		t.layoutPages()
+	}
+	for _, p := range t.pages {
+		p.parentFontChanged()
+	}
+}
+
+func (t *TabControl) fontHandle() uintptr {
+	if f := t.Font(); f != nil {
+		return f.handleFor(t.dpi())
+	}
+	return 0
+}
+
+// Layout returns nil, the TabControl always places its pages itself.
+func (t *TabControl) Layout() Layout {
+	return nil
+}
+
+func (t *TabControl) InnerBounds() (x, y, width, height int) {
+	return t.Bounds()
+}
+
+func (t *TabControl) getHandle() uintptr {
+	return t.handle
+}
+
+func (t *TabControl) getIDFor(c Control) int {
+	if t.parent == nil {
+		return -1
+	}
+	return t.parent.getIDFor(c)
+}
+
+func (t *TabControl) getDPI() int {
+	return t.dpi()
+}
+
+func (t *TabControl) SetBounds(x, y, width, height int) {
+	t.control.SetBounds(x, y, width, height)
+	t.layoutPages()
+}
+
+// NOTE that we need to re-write all the Set... functions here to make them go
+// throught TabControl's SetBounds, see Panel.
+
+func (t *TabControl) SetX(x int) {
+	_, y, width, height := t.Bounds()
+	t.SetBounds(x, y, width, height)
+}
+
+func (t *TabControl) SetY(y int) {
+	x, _, width, height := t.Bounds()
+	t.SetBounds(x, y, width, height)
+}
+
+func (t *TabControl) SetPosition(x, y int) {
+	_, _, width, height := t.Bounds()
+	t.SetBounds(x, y, width, height)
+}
+
+func (t *TabControl) SetWidth(width int) {
+	x, y, _, height := t.Bounds()
+	t.SetBounds(x, y, width, height)
+}
+
+func (t *TabControl) SetHeight(height int) {
+	x, y, width, _ := t.Bounds()
+	t.SetBounds(x, y, width, height)
+}
+
+func (t *TabControl) SetSize(width, height int) {
+	x, y, _, _ := t.Bounds()
+	t.SetBounds(x, y, width, height)
+}
+
+// NewTabPage returns an empty page without a title. Add it to a TabControl or
+// use TabControl.AddPage instead.
+func NewTabPage() *TabPage {
+	return &TabPage{}
+}
+
+// TabPage is a Container on a TabControl. Its children are only visible while
+// its tab is selected. The TabControl determines the page's bounds and
+// visibility.
+type TabPage struct {
+	control
+	title      string
+	image      *Image
+	children   []Control
+	layout     Layout
+	font       *Font
+	onCanClose VetoEvent
+	onClose    Event
+}
+
+var _ Control = (*TabPage)(nil)
+var _ Container = (*TabPage)(nil)
+
+func (p *TabPage) closing() {
+	for _, c := range p.children {
+		c.closing()
+	}
+}
+
+func (p *TabPage) destroy() {
+	if p.handle != 0 {
+		for _, c := range p.children {
+			c.destroy()
+		}
+		p.control.destroy()
+	}
+}
+
+func (*TabPage) canFocus() bool {
+	return false
+}
+
+func (*TabPage) eatsTabs() bool {
+	return false
+}
+
+func (p *TabPage) create(id int) {
+	p.control.create(id, 0, "STATIC", 0)
+	// Notifications of our children are sent to us, their parent window, but
+	// they are handled in the top-level window.
+	ui.forwardNotifications(p.handle)
+	for _, c := range p.children {
+		c.create(p.getIDFor(c))
+	}
+}
+
+// tabControl returns the TabControl that the page is on, or nil.
+func (p *TabPage) tabControl() *TabControl {
+	t, _ := p.parent.(*TabControl)
+	return t
+}
+
+func (p *TabPage) Add(c Control) {
+	p.children = append(p.children, c)
+	c.setParent(p)
+	if p.handle != 0 {
+		c.create(p.getIDFor(c))
+	}
+	relayout(p)
+}
+
+func (p *TabPage) Remove(c Control) {
+	for i, child := range p.children {
+		if child == c {
+			forgetChild(p, child)
+			child.setParent(nil)
+			child.destroy()
+			p.children = append(p.children[:i], p.children[i+1:]...)
+			relayout(p)
+			return
+		}
+	}
+}
+
+func (p *TabPage) Children() []Control {
+	return p.children
+}
+
+func (p *TabPage) Title() string {
+	return p.title
+}
+
+// SetTitle sets the text on the page's tab.
+func (p *TabPage) SetTitle(title string) {
+	p.title = title
+	if t := p.tabControl(); t != nil {
+		t.updateTabs()
+	}
+}
+
+func (p *TabPage) Image() *Image {
+	return p.image
+}
+
+// SetImage sets the icon on the page's tab, pass nil to remove it. All images
+// of a TabControl must have the same size.
+func (p *TabPage) SetImage(img *Image) {
+	p.image = img
+	if t := p.tabControl(); t != nil {
+		t.updateTabs()
+	}
+}
+
+// Close removes the page from its TabControl, unless the CanClose event vetoes
+// it. Clicking the page's close button calls Close, see
+// TabControl.SetClosableTabs.
+func (p *TabPage) Close() {
+	if !p.onCanClose.fire() {
+		return
+	}
+	if p.parent != nil {
+		p.parent.Remove(p)
+	}
+	p.onClose.fire()
+}
+
+// OnCanClose returns the function that is called before the page is closed. If
+// it returns false, the page stays open.
+func (p *TabPage) OnCanClose() func() bool {
+	return p.onCanClose.primary
+}
+
+// SetOnCanClose sets the function that is called before the page is closed,
+// see OnCanClose.
+func (p *TabPage) SetOnCanClose(f func() bool) {
+	p.onCanClose.primary = f
+}
+
+func (p *TabPage) CanCloseEvent() *VetoEvent {
+	return &p.onCanClose
+}
+
+// OnClose returns the function that is called after the page was removed from
+// its TabControl by Close.
+func (p *TabPage) OnClose() func() {
+	return p.onClose.primary
+}
+
+func (p *TabPage) SetOnClose(f func()) {
+	p.onClose.primary = f
+}
+
+func (p *TabPage) CloseEvent() *Event {
+	return &p.onClose
+}
+
+func (p *TabPage) getHandle() uintptr {
+	return p.handle
+}
+
+func (p *TabPage) getIDFor(c Control) int {
+	if p.parent == nil {
+		return -1
+	}
+	return p.parent.getIDFor(c)
+}
+
+func (p *TabPage) getDPI() int {
+	return p.dpi()
+}
+
+func (p *TabPage) Layout() Layout {
+	return p.layout
+}
+
+// SetLayout makes the layout position the page's children, see Layout. Pass
+// nil to position them by their anchors again. A layout can only be used by one
+// container at a time, setting it here removes it from its previous container.
+func (p *TabPage) SetLayout(l Layout) {
+	setLayout(p, &p.layout, l)
+}
+
+func (p *TabPage) Font() *Font {
+	if p.font == nil && p.parent != nil {
+		return p.parent.Font()
+	}
+	return p.font
+}
+
+func (p *TabPage) SetFont(f *Font) {
+	old := p.font
+	p.font = f
+	p.parentFontChanged()
+	if old != f {
+		releaseScaledFonts(old)
+	}
+}
+
+func (p *TabPage) parentFontChanged() {
+	for _, c := range p.children {
+		c.parentFontChanged()
+	}
+}
+
+// SetVisible does nothing, the TabControl shows the page of the selected tab
+// and hides all others.
+func (p *TabPage) SetVisible(bool) {}
+
+func (p *TabPage) InnerBounds() (x, y, width, height int) {
+	return p.Bounds()
+}
+
+func (p *TabPage) InnerWidth() int {
+	return p.width
+}
+
+func (p *TabPage) InnerHeight() int {
+	return p.height
+}
+
+func (p *TabPage) InnerSize() (width, height int) {
+	return p.width, p.height
+}
+
+// SetBounds only changes the bounds of pages that are not on a TabControl. The
+// TabControl places its pages itself.
+func (p *TabPage) SetBounds(x, y, width, height int) {
+	if t := p.tabControl(); t != nil {
+		x, y, width, height = t.pageBounds()
+	}
+	p.setBounds(x, y, width, height)
+}
+
+func (p *TabPage) setBounds(x, y, width, height int) {
+	_, _, oldW, oldH := p.Bounds()
+	p.control.SetBounds(x, y, width, height)
+	if p.layout != nil {
+		relayout(p)
+	} else {
+		repositionChidrenByAnchors(p, oldW, oldH, width, height)
+	}
+}
+
+// NOTE that we need to re-write all the Set... functions here to make them go
+// throught TabPage's SetBounds, see Panel.
+
+func (p *TabPage) SetX(x int) {
+	_, y, width, height := p.Bounds()
+	p.SetBounds(x, y, width, height)
+}
+
+func (p *TabPage) SetY(y int) {
+	x, _, width, height := p.Bounds()
+	p.SetBounds(x, y, width, height)
+}
+
+func (p *TabPage) SetPosition(x, y int) {
+	_, _, width, height := p.Bounds()
+	p.SetBounds(x, y, width, height)
+}
+
+func (p *TabPage) SetWidth(width int) {
+	x, y, _, height := p.Bounds()
+	p.SetBounds(x, y, width, height)
+}
+
+func (p *TabPage) SetHeight(height int) {
+	x, y, width, _ := p.Bounds()
+	p.SetBounds(x, y, width, height)
+}
+
+func (p *TabPage) SetSize(width, height int) {
+	x, y, _, _ := p.Bounds()
+	p.SetBounds(x, y, width, height)
+}
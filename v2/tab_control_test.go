@@ -0,0 +1,204 @@
+package wui
+
+import (
+	"bytes"
+	"image"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestOnlyTheSelectedTabPageIsVisible(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tabs := NewTabControl()
+	tabs.SetBounds(0, 0, 200, 100)
+	w.Add(tabs)
+	general := tabs.AddPage("General")
+	advanced := tabs.AddPage("Advanced")
+	var changes []int
+	tabs.SetOnChange(func(i int) { changes = append(changes, i) })
+
+	check.Eq(t, tabs.SelectedPage(), general)
+	check.Eq(t, general.Visible(), true)
+	check.Eq(t, advanced.Visible(), false)
+	check.Eq(t, general.Parent(), tabs)
+	check.Eq(t, tabs.Children(), []Control{general, advanced})
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.Tabs(tabs), []HeadlessTab{
+			{Text: "General", Image: -1},
+			{Text: "Advanced", Image: -1},
+		})
+		check.Eq(t, h.handles[tabs.handle].selected, 0)
+		check.Eq(t, h.handles[general.handle].Visible, true)
+		check.Eq(t, h.handles[advanced.handle].Visible, false)
+
+		h.ClickTab(tabs, 1)
+		check.Eq(t, tabs.SelectedIndex(), 1)
+		check.Eq(t, h.handles[general.handle].Visible, false)
+		check.Eq(t, h.handles[advanced.handle].Visible, true)
+		h.ClickTab(tabs, 1)
+
+		// Selecting in code does not call OnChange, like in ComboBox.
+		tabs.SetSelectedIndex(0)
+		check.Eq(t, h.handles[tabs.handle].selected, 0)
+		check.Eq(t, general.Visible(), true)
+
+		advanced.SetTitle("Expert")
+		check.Eq(t, h.Tabs(tabs)[1].Text, "Expert")
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, changes, []int{1})
+}
+
+func TestTabPagesFillTheTabControl(t *testing.T) {
+	UseHeadless()
+	w := NewWindow()
+	tabs := NewTabControl()
+	tabs.SetBounds(10, 20, 200, 100)
+	w.Add(tabs)
+	page := tabs.AddPage("page")
+	b := NewButton()
+	b.SetBounds(0, 0, 50, 20)
+	b.SetHorizontalAnchor(AnchorMax)
+	page.Add(b)
+
+	check.Eq(t, Rect(page.Bounds()), Rect(4, 24, 192, 72))
+	// Pages cannot be moved.
+	page.SetBounds(0, 0, 10, 10)
+	check.Eq(t, Rect(page.Bounds()), Rect(4, 24, 192, 72))
+
+	tabs.SetWidth(300)
+	check.Eq(t, Rect(page.Bounds()), Rect(4, 24, 292, 72))
+	check.Eq(t, b.X(), 100)
+}
+
+func TestClosingTabPages(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tabs := NewTabControl()
+	w.Add(tabs)
+	a := tabs.AddPage("a")
+	b := tabs.AddPage("b")
+	c := tabs.AddPage("c")
+	canClose := false
+	b.SetOnCanClose(func() bool { return canClose })
+	var events []string
+	b.SetOnClose(func() { events = append(events, "close b") })
+	tabs.SetOnChange(func(i int) { events = append(events, "change "+strconv.Itoa(i)) })
+
+	w.SetOnShow(func() {
+		h.ClickTab(tabs, 1)
+
+		// Without close buttons, the user cannot close tabs.
+		h.CloseTab(tabs, 1)
+		check.Eq(t, len(tabs.Pages()), 3)
+
+		tabs.SetClosableTabs(true)
+		check.Eq(t, h.Tabs(tabs)[0].Closable, true)
+		h.CloseTab(tabs, 1)
+		check.Eq(t, len(tabs.Pages()), 3)
+
+		canClose = true
+		h.CloseTab(tabs, 1)
+		check.Eq(t, tabs.Pages(), []*TabPage{a, c})
+		check.Eq(t, b.Parent(), nil)
+		check.Eq(t, b.handle, uintptr(0))
+		check.Eq(t, tabs.SelectedPage(), c)
+		check.Eq(t, h.handles[tabs.handle].selected, 1)
+		check.Eq(t, h.handles[c.handle].Visible, true)
+
+		// Removing a page before the selected one keeps the selection.
+		tabs.Remove(a)
+		check.Eq(t, tabs.SelectedIndex(), 0)
+		check.Eq(t, tabs.SelectedPage(), c)
+		check.Eq(t, h.Tabs(tabs), []HeadlessTab{{Text: "c", Image: -1, Closable: true}})
+
+		c.Close()
+		check.Eq(t, len(tabs.Pages()), 0)
+		check.Eq(t, tabs.SelectedPage(), (*TabPage)(nil))
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, events, []string{"change 1", "change 1", "close b"})
+}
+
+func TestTabImagesShareTheTabControlsImageList(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tabs := NewTabControl()
+	w.Add(tabs)
+	img := NewImage(image.NewRGBA(image.Rect(0, 0, 16, 16)))
+	a := tabs.AddPage("a")
+	a.SetImage(img)
+	b := tabs.AddPage("b")
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.Tabs(tabs)[0].Image, 0)
+		check.Eq(t, h.handles[tabs.handle].tabImages, []*Image{img})
+
+		b.SetImage(img)
+		check.Eq(t, h.Tabs(tabs)[1].Image, 0)
+		b.SetImage(nil)
+		check.Eq(t, h.Tabs(tabs)[1].Image, -1)
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestTabControlsAreSavedWithTheirPages(t *testing.T) {
+	UseHeadless()
+	w := NewWindow()
+	tabs := NewTabControl()
+	tabs.SetBounds(0, 0, 200, 100)
+	tabs.SetClosableTabs(true)
+	w.Add(tabs)
+	tabs.AddPage("first")
+	second := tabs.AddPage("second")
+	second.SetEnabled(false)
+	cb := NewCheckBox()
+	cb.SetText("check")
+	second.Add(cb)
+	tabs.SetSelectedIndex(1)
+
+	var saved bytes.Buffer
+	check.Eq(t, SaveWindow(&saved, w, map[string]Control{"tabs": tabs}), nil)
+	loaded, names, err := LoadWindow(bytes.NewReader(saved.Bytes()))
+	check.Eq(t, err, nil)
+
+	loadedTabs := names["tabs"].(*TabControl)
+	check.Eq(t, loadedTabs.ClosableTabs(), true)
+	check.Eq(t, loadedTabs.SelectedIndex(), 1)
+	check.Eq(t, len(loadedTabs.Pages()), 2)
+	check.Eq(t, loadedTabs.Pages()[1].Title(), "second")
+	check.Eq(t, loadedTabs.Pages()[1].Enabled(), false)
+	check.Eq(t, loadedTabs.Pages()[1].Children()[0].(*CheckBox).Text(), "check")
+
+	var again bytes.Buffer
+	check.Eq(t, SaveWindow(&again, loaded, names), nil)
+	check.Eq(t, again.String(), saved.String())
+
+	_, _, err = LoadWindow(strings.NewReader(`{
+		"type": "Window",
+		"children": [
+			{"type": "TabControl", "children": [{"type": "Button"}]}
+		]
+	}`))
+	check.Eq(t, err.Error(), `wui.LoadWindow: TabControl at window.children[0]: a TabControl can only have TabPages as children`)
+}
+
+func TestOnlyTabPagesCanBeAddedToTabControls(t *testing.T) {
+	defer func() {
+		check.Eq(t, recover(), "wui: TabControl.Add needs a *TabPage, not *wui.Button")
+	}()
+	NewTabControl().Add(NewButton())
+}
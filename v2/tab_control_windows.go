@@ -0,0 +1,196 @@
+package wui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+func (*winAPI) setTabImages(handle uintptr, images []*Image) {
+	old := w32.SendMessage(
+		w32.HWND(handle),
+		w32.TCM_SETIMAGELIST,
+		0,
+		uintptr(newImageList(images)),
+	)
+	if old != 0 {
+		w32.ImageList_Destroy(w32.HIMAGELIST(old))
+	}
+}
+
+// closeButtonSpace is appended to the texts of closable tabs to make room for
+// their close buttons.
+const closeButtonSpace = "      "
+
+func newTCItem(info tabItem) tcItem {
+	text := info.text
+	// The close button is drawn by closableTabProc, it finds the closable tabs
+	// by their lParam.
+	var closable uintptr
+	if info.closable {
+		text += closeButtonSpace
+		closable = 1
+	}
+	ptr, _ := syscall.UTF16PtrFromString(text)
+	return tcItem{
+		mask:   tcifText | tcifImage | tcifParam,
+		text:   ptr,
+		image:  int32(info.image),
+		lParam: closable,
+	}
+}
+
+func (*winAPI) insertTab(handle uintptr, index int, info tabItem) {
+	item := newTCItem(info)
+	w32.SendMessage(
+		w32.HWND(handle),
+		tcmInsertItemW,
+		uintptr(index),
+		uintptr(unsafe.Pointer(&item)),
+	)
+	if info.closable {
+		w32.SetWindowSubclass(w32.HWND(handle), closableTabProc, 0, 0)
+	}
+}
+
+func (*winAPI) setTab(handle uintptr, index int, info tabItem) {
+	item := newTCItem(info)
+	w32.SendMessage(
+		w32.HWND(handle),
+		tcmSetItemW,
+		uintptr(index),
+		uintptr(unsafe.Pointer(&item)),
+	)
+	if info.closable {
+		w32.SetWindowSubclass(w32.HWND(handle), closableTabProc, 0, 0)
+	}
+}
+
+func (*winAPI) deleteTab(handle uintptr, index int) {
+	w32.SendMessage(w32.HWND(handle), w32.TCM_DELETEITEM, uintptr(index), 0)
+}
+
+func (*winAPI) selectTab(handle uintptr, index int) {
+	w32.SendMessage(w32.HWND(handle), w32.TCM_SETCURSEL, uintptr(index), 0)
+}
+
+func (*winAPI) tabInsets(handle uintptr) (left, top, right, bottom int) {
+	client := w32.GetClientRect(w32.HWND(handle))
+	if client == nil {
+		return 0, 0, 0, 0
+	}
+	page := *client
+	w32.SendMessage(
+		w32.HWND(handle),
+		w32.TCM_ADJUSTRECT,
+		0,
+		uintptr(unsafe.Pointer(&page)),
+	)
+	return int(page.Left - client.Left),
+		int(page.Top - client.Top),
+		int(client.Right - page.Right),
+		int(client.Bottom - page.Bottom)
+}
+
+// closeButtonRect returns the client area of the close button of the tab at the
+// index. ok is false if the tab is not closable.
+func closeButtonRect(tab w32.HWND, index int) (r w32.RECT, ok bool) {
+	item := tcItem{mask: tcifParam}
+	if w32.SendMessage(
+		tab,
+		tcmGetItemW,
+		uintptr(index),
+		uintptr(unsafe.Pointer(&item)),
+	) == 0 || item.lParam == 0 {
+		return r, false
+	}
+	w32.SendMessage(tab, w32.TCM_GETITEMRECT, uintptr(index), uintptr(unsafe.Pointer(&r)))
+	// The button is a square on the right of the tab.
+	r.Left = r.Right - (r.Bottom - r.Top)
+	return r, true
+}
+
+// closeButtonAt returns the index of the tab whose close button is at the
+// client position, or -1.
+func closeButtonAt(tab w32.HWND, x, y int) int {
+	n := int(w32.SendMessage(tab, w32.TCM_GETITEMCOUNT, 0, 0))
+	for i := 0; i < n; i++ {
+		r, ok := closeButtonRect(tab, i)
+		if ok && int(r.Left) <= x && x < int(r.Right) &&
+			int(r.Top) <= y && y < int(r.Bottom) {
+			return i
+		}
+	}
+	return -1
+}
+
+// closableTabProc draws the close buttons on closable tabs and notifies the
+// parent with tabCloseClicked when the user clicks one.
+var closableTabProc = syscall.NewCallback(func(
+	window w32.HWND,
+	msg uint32,
+	wParam, lParam uintptr,
+	subclassID uintptr,
+	refData uintptr,
+) uintptr {
+	switch msg {
+	case w32.WM_PAINT:
+		ret := w32.DefSubclassProc(window, msg, wParam, lParam)
+		drawCloseButtons(window)
+		return ret
+	case w32.WM_LBUTTONDOWN:
+		// Clicking the close button must not select the tab.
+		x, y := mousePos(lParam)
+		if closeButtonAt(window, x, y) != -1 {
+			return 0
+		}
+	case w32.WM_LBUTTONUP:
+		x, y := mousePos(lParam)
+		if i := closeButtonAt(window, x, y); i != -1 {
+			id := uintptr(w32.GetDlgCtrlID(window))
+			nm := nmTabClose{
+				header: w32.NMHDR{HwndFrom: window, IdFrom: id, Code: tabCloseClicked},
+				index:  int32(i),
+			}
+			w32.SendMessage(
+				parentOf(window),
+				w32.WM_NOTIFY,
+				id,
+				uintptr(unsafe.Pointer(&nm)),
+			)
+			return 0
+		}
+	}
+	return w32.DefSubclassProc(window, msg, wParam, lParam)
+})
+
+func drawCloseButtons(tab w32.HWND) {
+	dc := w32.GetDC(tab)
+	defer w32.ReleaseDC(tab, dc)
+	if font := w32.SendMessage(tab, w32.WM_GETFONT, 0, 0); font != 0 {
+		old := w32.SelectObject(dc, w32.HGDIOBJ(font))
+		defer w32.SelectObject(dc, old)
+	}
+	w32.SetBkMode(dc, w32.TRANSPARENT)
+	w32.SetTextColor(dc, w32.COLORREF(w32.GetSysColor(w32.COLOR_BTNTEXT)))
+	n := int(w32.SendMessage(tab, w32.TCM_GETITEMCOUNT, 0, 0))
+	for i := 0; i < n; i++ {
+		if r, ok := closeButtonRect(tab, i); ok {
+			w32.DrawText(dc, "×", &r, w32.DT_CENTER|w32.DT_VCENTER|w32.DT_SINGLELINE)
+		}
+	}
+}
+
+// onNotify handles the WM_NOTIFY messages of the tab control and returns the
+// message result.
+func (t *TabControl) onNotify(code uint32, lParam uintptr) uintptr {
+	switch code {
+	case tcnSelChange:
+		t.tabSelected(int(w32.SendMessage(w32.HWND(t.handle), w32.TCM_GETCURSEL, 0, 0)))
+	case tabCloseClicked:
+		nm := (*nmTabClose)(unsafe.Pointer(lParam))
+		t.closeClicked(int(nm.index))
+	}
+	return 0
+}
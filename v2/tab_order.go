@@ -0,0 +1,147 @@
+package wui
+
+import "sort"
+
+// tabStop is what nextTabStop needs to know about a control.
+type tabStop struct {
+	tabIndex  int
+	focusable bool
+}
+
+// nextTabStop returns the index of the stop that gets the keyboard focus after
+// the stop at current, or before it if backwards is true. Focusable stops are
+// visited in ascending order of their tabIndex, stops with equal tabIndex in
+// the order of the stops slice. current does not have to be focusable and it
+// may be -1 if no stop has the focus. The result is -1 if no stop is
+// focusable.
+func nextTabStop(stops []tabStop, current int, backwards bool) int {
+	order := make([]int, 0, len(stops))
+	for i, s := range stops {
+		if s.focusable {
+			order = append(order, i)
+		}
+	}
+	if len(order) == 0 {
+		return -1
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return stops[order[i]].tabIndex < stops[order[j]].tabIndex
+	})
+
+	first, last := order[0], order[len(order)-1]
+	if current < 0 || current >= len(stops) {
+		if backwards {
+			return last
+		}
+		return first
+	}
+
+	// Stops are ordered by (tabIndex, index), find the one right after or
+	// before current in that order.
+	before := func(a, b int) bool {
+		return stops[a].tabIndex < stops[b].tabIndex ||
+			stops[a].tabIndex == stops[b].tabIndex && a < b
+	}
+	if backwards {
+		for i := len(order) - 1; i >= 0; i-- {
+			if before(order[i], current) {
+				return order[i]
+			}
+		}
+		return last
+	}
+	for _, i := range order {
+		if before(current, i) {
+			return i
+		}
+	}
+	return first
+}
+
+// TabIndex returns the position of the control in the tab order of its
+// window, see SetTabIndex.
+func (c *control) TabIndex() int {
+	return c.tabIndex
+}
+
+// SetTabIndex sets the position of the control in the tab order of its window.
+// Pressing Tab moves the keyboard focus to the control with the next higher
+// TabIndex. Controls with the same TabIndex are visited in the order in which
+// they were added to the window. The default TabIndex is 0.
+func (c *control) SetTabIndex(i int) {
+	c.tabIndex = i
+}
+
+// TabStop returns true if the Tab key moves the keyboard focus to this control.
+// Controls that cannot have the focus, like Labels, are never visited, no
+// matter their TabStop.
+func (c *control) TabStop() bool {
+	return !c.noTabStop
+}
+
+// SetTabStop sets whether the Tab key moves the keyboard focus to this control.
+// Controls are tab stops by default. The control can still be focused with the
+// mouse or in code.
+func (c *control) SetTabStop(stop bool) {
+	c.noTabStop = !stop
+}
+
+// FocusedControl returns the control that has the keyboard focus or nil if
+// no control in this window is focused.
+func (w *Window) FocusedControl() Control {
+	if i := w.focusedIndex(); i != -1 {
+		return w.controls[i]
+	}
+	return nil
+}
+
+func (w *Window) focusedIndex() int {
+	focus := ui.focused()
+	if focus == 0 {
+		return -1
+	}
+	for i := range w.controls {
+		if w.controls[i].Handle() == focus {
+			return i
+		}
+	}
+	return -1
+}
+
+// FocusNext moves the keyboard focus to the next control in the tab order,
+// just like the Tab key does. This calls the control's OnTabFocus.
+func (w *Window) FocusNext() {
+	if c := w.moveFocus(false); c != nil {
+		c.wasFocussedWithTab()
+	}
+}
+
+// FocusPrevious moves the keyboard focus to the previous control in the tab
+// order, just like Shift+Tab does. This calls the control's OnTabFocus.
+func (w *Window) FocusPrevious() {
+	if c := w.moveFocus(true); c != nil {
+		c.wasFocussedWithTab()
+	}
+}
+
+// moveFocus focuses the next or previous tab stop and returns it. It returns
+// nil if there is no control to focus.
+func (w *Window) moveFocus(backwards bool) Control {
+	stops := make([]tabStop, len(w.controls))
+	for i, c := range w.controls {
+		stops[i] = tabStop{
+			tabIndex: c.TabIndex(),
+			focusable: c.Parent() != nil &&
+				c.canFocus() &&
+				c.TabStop() &&
+				Visible(c) &&
+				Enabled(c),
+		}
+	}
+	next := nextTabStop(stops, w.focusedIndex(), backwards)
+	if next == -1 {
+		return nil
+	}
+	ui.focus(w.controls[next].Handle())
+	return w.controls[next]
+}
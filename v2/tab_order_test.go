@@ -0,0 +1,128 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestNextTabStopOrdersByTabIndexThenPosition(t *testing.T) {
+	stops := []tabStop{
+		{tabIndex: 2, focusable: true},  // 0
+		{tabIndex: 1, focusable: true},  // 1
+		{tabIndex: 1, focusable: false}, // 2
+		{tabIndex: 1, focusable: true},  // 3
+		{tabIndex: 0, focusable: true},  // 4
+	}
+	// The order is 4, 1, 3, 0.
+	walk := func(start int, backwards bool) []int {
+		var visited []int
+		cur := start
+		for range stops[:4] {
+			cur = nextTabStop(stops, cur, backwards)
+			visited = append(visited, cur)
+		}
+		return visited
+	}
+	check.Eq(t, walk(-1, false), []int{4, 1, 3, 0})
+	check.Eq(t, walk(-1, true), []int{0, 3, 1, 4})
+	check.Eq(t, walk(1, false), []int{3, 0, 4, 1})
+	// A control that is not a tab stop continues from its place in the order.
+	check.Eq(t, nextTabStop(stops, 2, false), 3)
+	check.Eq(t, nextTabStop(stops, 2, true), 1)
+
+	check.Eq(t, nextTabStop(nil, -1, false), -1)
+	check.Eq(t, nextTabStop([]tabStop{{}, {}}, 0, false), -1)
+	check.Eq(t, nextTabStop([]tabStop{{focusable: true}}, 0, false), 0)
+}
+
+func TestTabIndexAndTabStopChangeFocusOrder(t *testing.T) {
+	h := UseHeadless()
+
+	w := NewWindow()
+	a := NewEditLine()
+	b := NewButton()
+	c := NewCheckBox()
+	skipped := NewEditLine()
+	w.Add(a)
+	w.Add(b)
+	w.Add(c)
+	w.Add(skipped)
+	a.SetTabIndex(2)
+	skipped.SetTabStop(false)
+	var tabFocus []string
+	a.SetOnTabFocus(func() { tabFocus = append(tabFocus, "a") })
+	b.SetOnTabFocus(func() { tabFocus = append(tabFocus, "b") })
+	c.SetOnTabFocus(func() { tabFocus = append(tabFocus, "c") })
+
+	w.SetOnShow(func() {
+		check.Eq(t, w.FocusedControl(), nil)
+		w.FocusNext()
+		check.Eq(t, w.FocusedControl(), b)
+		h.PressTab(w, false)
+		check.Eq(t, w.FocusedControl(), c)
+		w.FocusNext()
+		check.Eq(t, w.FocusedControl(), a)
+		w.FocusNext()
+		check.Eq(t, w.FocusedControl(), b)
+		w.FocusPrevious()
+		check.Eq(t, w.FocusedControl(), a)
+
+		skipped.Focus()
+		check.Eq(t, w.FocusedControl(), skipped)
+		// skipped is after c in the tab order.
+		w.FocusNext()
+		check.Eq(t, w.FocusedControl(), a)
+		w.Close()
+	})
+	w.Show()
+
+	// FocusNext and FocusPrevious work like the Tab key.
+	check.Eq(t, tabFocus, []string{"b", "c", "a", "b", "a", "a"})
+}
+
+func TestArrowKeysMoveThroughRadioGroup(t *testing.T) {
+	h := UseHeadless()
+
+	w := NewWindow()
+	p := NewPanel()
+	w.Add(p)
+	r1 := NewRadioButton()
+	r2 := NewRadioButton()
+	disabled := NewRadioButton()
+	disabled.SetEnabled(false)
+	r3 := NewRadioButton()
+	p.Add(r1)
+	p.Add(NewCheckBox())
+	p.Add(r2)
+	p.Add(disabled)
+	p.Add(r3)
+	other := NewRadioButton()
+	w.Add(other)
+	var checked []*RadioButton
+	for _, r := range []*RadioButton{r1, r2, r3, other} {
+		r := r
+		r.SetOnCheck(func(bool) { checked = append(checked, r) })
+	}
+
+	w.SetOnShow(func() {
+		h.Click(r1)
+		h.KeyDown(r1, KeyDown)
+		check.Eq(t, w.FocusedControl(), r2)
+		check.Eq(t, r1.Checked(), false)
+		check.Eq(t, r2.Checked(), true)
+		h.KeyDown(r2, KeyRight)
+		check.Eq(t, w.FocusedControl(), r3)
+		h.KeyDown(r3, KeyDown)
+		check.Eq(t, w.FocusedControl(), r1)
+		h.KeyDown(r1, KeyUp)
+		check.Eq(t, w.FocusedControl(), r3)
+		h.KeyDown(r3, KeyReturn)
+		check.Eq(t, w.FocusedControl(), r3)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, checked, []*RadioButton{r1, r2, r3, r1, r3})
+	check.Eq(t, other.Checked(), false)
+}
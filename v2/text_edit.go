@@ -1,6 +1,6 @@
 package wui
 
-import "github.com/gonutz/w32/v2"
+import "github.com/gonutz/wui/v2/internal/win"
 
 func NewTextEdit() *TextEdit {
 	return &TextEdit{
@@ -15,7 +15,7 @@ type TextEdit struct {
 	autoHScroll  bool
 	writesTabs   bool
 	readOnly     bool
-	onTextChange func()
+	onTextChange Event
 }
 
 var _ Control = (*TextEdit)(nil)
@@ -29,11 +29,15 @@ func (*TextEdit) canFocus() bool {
 }
 
 func (e *TextEdit) OnTabFocus() func() {
-	return e.onTabFocus
+	return e.onTabFocus.primary
 }
 
 func (e *TextEdit) SetOnTabFocus(f func()) {
-	e.onTabFocus = f
+	e.onTabFocus.primary = f
+}
+
+func (e *TextEdit) TabFocusEvent() *Event {
+	return &e.onTabFocus
 }
 
 func (e *TextEdit) eatsTabs() bool {
@@ -43,13 +47,13 @@ func (e *TextEdit) eatsTabs() bool {
 func (e *TextEdit) create(id int) {
 	var hScroll uint
 	if e.autoHScroll {
-		hScroll = w32.ES_AUTOHSCROLL | w32.WS_HSCROLL
+		hScroll = win.ES_AUTOHSCROLL | win.WS_HSCROLL
 	}
 	e.textEditControl.create(
-		id, w32.WS_EX_CLIENTEDGE, "EDIT",
-		w32.WS_TABSTOP|w32.WS_VSCROLL|
-			w32.ES_LEFT|w32.ES_MULTILINE|w32.ES_AUTOVSCROLL|hScroll|
-			w32.ES_WANTRETURN,
+		id, win.WS_EX_CLIENTEDGE, "EDIT",
+		win.WS_TABSTOP|win.WS_VSCROLL|
+			win.ES_LEFT|win.ES_MULTILINE|win.ES_AUTOVSCROLL|hScroll|
+			win.ES_WANTRETURN,
 	)
 	if e.limit != 0 {
 		e.SetCharacterLimit(e.limit)
@@ -63,13 +67,13 @@ func (e *TextEdit) SetCharacterLimit(count int) {
 	}
 	e.limit = count
 	if e.handle != 0 {
-		w32.SendMessage(e.handle, w32.EM_SETLIMITTEXT, uintptr(e.limit), 0)
+		ui.setTextLimit(e.handle, e.limit)
 	}
 }
 
 func (e *TextEdit) CharacterLimit() int {
 	if e.handle != 0 {
-		e.limit = int(w32.SendMessage(e.handle, w32.EM_GETLIMITTEXT, 0, 0))
+		e.limit = ui.textLimit(e.handle)
 	}
 	return e.limit
 }
@@ -94,27 +98,27 @@ func (e *TextEdit) SetWritesTabs(tabs bool) {
 }
 
 func (e *TextEdit) SetOnTextChange(f func()) {
-	e.onTextChange = f
+	e.onTextChange.primary = f
+}
+
+func (e *TextEdit) TextChangeEvent() *Event {
+	return &e.onTextChange
 }
 
 func (e *TextEdit) OnTextChange() func() {
-	return e.onTextChange
+	return e.onTextChange.primary
 }
 
 func (e *TextEdit) handleNotification(cmd uintptr) {
-	if cmd == w32.EN_CHANGE && e.onTextChange != nil {
-		e.onTextChange()
+	if cmd == win.EN_CHANGE {
+		e.onTextChange.fire()
 	}
 }
 
 func (e *TextEdit) SetReadOnly(readOnly bool) {
 	e.readOnly = readOnly
 	if e.handle != 0 {
-		if readOnly {
-			w32.SendMessage(e.handle, w32.EM_SETREADONLY, 1, 0)
-		} else {
-			w32.SendMessage(e.handle, w32.EM_SETREADONLY, 0, 0)
-		}
+		ui.setReadOnly(e.handle, readOnly)
 	}
 }
 
@@ -0,0 +1,199 @@
+package wui
+
+import "time"
+
+// Timer calls a function periodically on the UI thread of a window. It only
+// runs while the window is shown. Create it with Window.NewTimer. All its
+// methods must be called on the UI thread, use Window.Post from other
+// goroutines.
+type Timer struct {
+	window   *Window
+	id       uintptr
+	interval time.Duration
+	f        func()
+	running  bool
+	// started is called whenever the native timer is started, the
+	// FrameTicker uses it to measure the time from there.
+	started func()
+}
+
+// NewTimer creates a running Timer that calls f every interval. If the window
+// is not yet shown, the timer starts once it is. Intervals below one
+// millisecond are rounded up to one millisecond. Note that Windows does not
+// fire timers more often than about every 10 milliseconds.
+//
+// All timers are stopped when the window is closed.
+func (w *Window) NewTimer(interval time.Duration, f func()) *Timer {
+	return w.newTimer(interval, f, nil)
+}
+
+func (w *Window) newTimer(interval time.Duration, f, started func()) *Timer {
+	w.lastTimerID++
+	t := &Timer{
+		window:   w,
+		id:       w.lastTimerID,
+		interval: clampInterval(interval),
+		f:        f,
+		started:  started,
+	}
+	t.Start()
+	return t
+}
+
+func clampInterval(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return time.Millisecond
+	}
+	return d
+}
+
+// Start starts the timer if it is stopped. The first call to the timer's
+// function happens one interval after the call to Start.
+func (t *Timer) Start() {
+	if t.running {
+		return
+	}
+	t.running = true
+	t.window.addTimer(t)
+	t.apply()
+}
+
+// Stop stops the timer if it is running. The timer's function will not be
+// called until the next Start or Reset.
+func (t *Timer) Stop() {
+	if !t.running {
+		return
+	}
+	t.running = false
+	if t.window.handle != 0 {
+		ui.killTimer(t.window.handle, t.id)
+	}
+}
+
+// Reset sets a new interval and (re-)starts the timer. The next call to the
+// timer's function happens one interval after the call to Reset.
+func (t *Timer) Reset(interval time.Duration) {
+	t.interval = clampInterval(interval)
+	t.running = true
+	t.window.addTimer(t)
+	t.apply()
+}
+
+// Running returns true if the timer was started and not stopped, even if its
+// window is not yet shown.
+func (t *Timer) Running() bool {
+	return t.running
+}
+
+func (t *Timer) Interval() time.Duration {
+	return t.interval
+}
+
+// apply starts the native timer if the window exists. Starting it again
+// restarts the interval.
+func (t *Timer) apply() {
+	if t.running && t.window.handle != 0 {
+		ui.setTimer(t.window.handle, t.id, t.interval)
+		if t.started != nil {
+			t.started()
+		}
+	}
+}
+
+func (w *Window) addTimer(t *Timer) {
+	for _, timer := range w.timers {
+		if timer == t {
+			return
+		}
+	}
+	w.timers = append(w.timers, t)
+}
+
+// startTimers starts all running timers after the window was created.
+func (w *Window) startTimers() {
+	for _, t := range w.timers {
+		t.apply()
+	}
+}
+
+// stopTimers stops all timers and forgets them, this is called when the
+// window is destroyed.
+func (w *Window) stopTimers() {
+	for _, t := range w.timers {
+		t.Stop()
+	}
+	w.timers = nil
+}
+
+// timerFired is called by the backend when the timer with the given ID is due.
+func (w *Window) timerFired(id uintptr) {
+	for _, t := range w.timers {
+		if t.id == id {
+			if t.running && t.f != nil {
+				t.f()
+			}
+			return
+		}
+	}
+}
+
+// FrameTicker calls a function for every frame of an animation, on the UI
+// thread of a window. It passes the time that has actually passed since the
+// last frame, since timers are not exact and frames might be late. Create it
+// with Window.NewFrameTicker.
+type FrameTicker struct {
+	timer *Timer
+	f     func(delta, total time.Duration)
+	last  time.Time
+	total time.Duration
+}
+
+// NewFrameTicker creates a running FrameTicker that calls f about fps times
+// per second. delta is the time since the last frame and total is the sum of
+// all deltas, i.e. the time that the ticker has been running, not counting
+// the time that it was stopped.
+//
+// For example, to move an object with 100 pixels per second, add
+// 100*delta.Seconds() to its position in every frame.
+func (w *Window) NewFrameTicker(fps int, f func(delta, total time.Duration)) *FrameTicker {
+	if fps < 1 {
+		fps = 1
+	}
+	t := &FrameTicker{f: f}
+	t.timer = w.newTimer(
+		time.Second/time.Duration(fps),
+		t.tick,
+		func() { t.last = ui.now() },
+	)
+	return t
+}
+
+func (t *FrameTicker) tick() {
+	now := ui.now()
+	delta := now.Sub(t.last)
+	t.last = now
+	t.total += delta
+	if t.f != nil {
+		t.f(delta, t.total)
+	}
+}
+
+// Start continues a stopped ticker. The time while it was stopped is not part
+// of the next delta.
+func (t *FrameTicker) Start() {
+	t.timer.Start()
+}
+
+func (t *FrameTicker) Stop() {
+	t.timer.Stop()
+}
+
+func (t *FrameTicker) Running() bool {
+	return t.timer.Running()
+}
+
+// Total returns the time that the ticker has been running up to the last
+// frame.
+func (t *FrameTicker) Total() time.Duration {
+	return t.total
+}
@@ -0,0 +1,92 @@
+package wui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gonutz/check"
+)
+
+func TestTimerFiresEveryIntervalWhileRunning(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	var fired []time.Duration
+	start := h.now()
+	timer := w.NewTimer(100*time.Millisecond, func() {
+		fired = append(fired, h.now().Sub(start))
+	})
+	check.Eq(t, timer.Running(), true)
+
+	w.SetOnShow(func() {
+		h.Advance(250 * time.Millisecond)
+		check.Eq(t, fired, []time.Duration{
+			100 * time.Millisecond,
+			200 * time.Millisecond,
+		})
+
+		timer.Stop()
+		check.Eq(t, timer.Running(), false)
+		h.Advance(time.Second)
+		check.Eq(t, len(fired), 2)
+
+		timer.Reset(300 * time.Millisecond)
+		check.Eq(t, timer.Interval(), 300*time.Millisecond)
+		h.Advance(600 * time.Millisecond)
+		check.Eq(t, fired[2:], []time.Duration{
+			1550 * time.Millisecond,
+			1850 * time.Millisecond,
+		})
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestTimersStartWithWindowAndStopWhenItCloses(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	count := 0
+	timer := w.NewTimer(10*time.Millisecond, func() { count++ })
+
+	h.Advance(time.Second)
+	check.Eq(t, count, 0)
+
+	w.SetOnShow(func() {
+		h.Advance(30 * time.Millisecond)
+		check.Eq(t, count, 3)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, timer.Running(), false)
+	check.Eq(t, len(w.timers), 0)
+	check.Eq(t, len(h.timers), 0)
+	h.Advance(time.Second)
+	check.Eq(t, count, 3)
+}
+
+func TestFrameTickerReportsElapsedTime(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	type frame struct{ delta, total time.Duration }
+	var frames []frame
+	ticker := w.NewFrameTicker(50, func(delta, total time.Duration) {
+		frames = append(frames, frame{delta, total})
+	})
+
+	w.SetOnShow(func() {
+		h.Advance(40 * time.Millisecond)
+		ticker.Stop()
+		h.Advance(time.Second)
+		ticker.Start()
+		h.Advance(20 * time.Millisecond)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, frames, []frame{
+		{20 * time.Millisecond, 20 * time.Millisecond},
+		{20 * time.Millisecond, 40 * time.Millisecond},
+		{20 * time.Millisecond, 60 * time.Millisecond},
+	})
+	check.Eq(t, ticker.Total(), 60*time.Millisecond)
+}
@@ -0,0 +1,171 @@
+package wui
+
+import (
+	"time"
+
+	"github.com/gonutz/wui/v2/internal/win"
+)
+
+// toolTipMaxWidth is the width at which tooltips wrap their lines, in pixels
+// at the default DPI. Lines also break at '\n'.
+const toolTipMaxWidth = 400
+
+func (c *control) ToolTip() string {
+	return c.toolTip
+}
+
+// SetToolTip sets the text that appears when the mouse hovers over the
+// control. Use '\n' for line breaks. An empty text removes the tooltip. See
+// Window.SetToolTipDelay for its timing.
+func (c *control) SetToolTip(text string) {
+	c.toolTip = text
+	c.updateToolTip()
+}
+
+func (c *control) ToolTipFunc() func() string {
+	return c.toolTipFunc
+}
+
+// SetToolTipFunc sets a function that returns the tooltip text every time the
+// tooltip is about to appear, e.g. to show the current state of the control.
+// It takes precedence over the text set with SetToolTip. Return an empty text
+// to show no tooltip.
+func (c *control) SetToolTipFunc(f func() string) {
+	c.toolTipFunc = f
+	c.updateToolTip()
+}
+
+func (c *control) hasToolTip() bool {
+	return c.toolTip != "" || c.toolTipFunc != nil
+}
+
+func (c *control) toolTipText() string {
+	if c.toolTipFunc != nil {
+		return c.toolTipFunc()
+	}
+	return c.toolTip
+}
+
+// updateToolTip adds the control to or removes it from its window's tooltip.
+func (c *control) updateToolTip() {
+	if c.handle == 0 {
+		return
+	}
+	if w := windowOf(c.parent); w != nil {
+		w.setToolTipFor(c.handle, c.hasToolTip())
+	}
+}
+
+// windowOf returns the top-level window that the container is on, or nil if
+// it is not yet on a window.
+func windowOf(c Container) *Window {
+	for c != nil {
+		if w, ok := c.(*Window); ok {
+			return w
+		}
+		c = c.Parent()
+	}
+	return nil
+}
+
+// setToolTipFor registers the control with the window's tooltip or removes it.
+// The tooltip is created the first time it is needed.
+func (w *Window) setToolTipFor(control uintptr, on bool) {
+	if w.handle == 0 {
+		return
+	}
+	if w.toolTipHandle == 0 {
+		if !on {
+			return
+		}
+		w.toolTipHandle = ui.createChild(
+			w.handle,
+			0,
+			win.WS_EX_TOPMOST,
+			win.TOOLTIPS_CLASS,
+			win.WS_POPUP|win.TTS_ALWAYSTIP|win.TTS_NOPREFIX,
+			0, 0, 0, 0,
+		)
+		w.applyToolTipOptions()
+	}
+	if on {
+		ui.addToolTip(w.toolTipHandle, w.handle, control)
+	} else {
+		ui.removeToolTip(w.toolTipHandle, w.handle, control)
+	}
+}
+
+// toolTipText is called by the backend when the tooltip for the control with
+// the given handle is about to appear.
+func (w *Window) toolTipText(control uintptr) string {
+	for _, c := range w.controls {
+		if c.Handle() == control && control != 0 {
+			return c.toolTipText()
+		}
+	}
+	return ""
+}
+
+func (w *Window) applyToolTipOptions() {
+	if w.toolTipHandle == 0 {
+		return
+	}
+	style, exStyle := ui.style(w.toolTipHandle)
+	if w.toolTipBalloon {
+		style |= win.TTS_BALLOON
+	} else {
+		style &^= win.TTS_BALLOON
+	}
+	ui.setStyle(w.toolTipHandle, style, exStyle)
+	ui.setToolTipOptions(
+		w.toolTipHandle,
+		w.toolTipDelay,
+		w.toolTipDuration,
+		Scale(toolTipMaxWidth, w.dpi),
+	)
+}
+
+// ToolTipDelay returns the time that the mouse has to rest on a control before
+// its tooltip appears. 0 means the system default.
+func (w *Window) ToolTipDelay() time.Duration {
+	return w.toolTipDelay
+}
+
+// SetToolTipDelay sets the time that the mouse has to rest on a control before
+// its tooltip appears. 0 means the system default. This applies to all
+// controls in the window.
+func (w *Window) SetToolTipDelay(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	w.toolTipDelay = d
+	w.applyToolTipOptions()
+}
+
+// ToolTipDuration returns how long a tooltip stays visible if the mouse does
+// not move. 0 means the system default.
+func (w *Window) ToolTipDuration() time.Duration {
+	return w.toolTipDuration
+}
+
+// SetToolTipDuration sets how long a tooltip stays visible if the mouse does
+// not move. 0 means the system default. This applies to all controls in the
+// window.
+func (w *Window) SetToolTipDuration(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	w.toolTipDuration = d
+	w.applyToolTipOptions()
+}
+
+func (w *Window) ToolTipBalloon() bool {
+	return w.toolTipBalloon
+}
+
+// SetToolTipBalloon makes the tooltips of all controls in the window look like
+// speech bubbles that point at the mouse, instead of rectangles.
+func (w *Window) SetToolTipBalloon(balloon bool) {
+	w.toolTipBalloon = balloon
+	w.applyToolTipOptions()
+}
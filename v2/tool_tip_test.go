@@ -0,0 +1,99 @@
+package wui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gonutz/check"
+	"github.com/gonutz/wui/v2/internal/win"
+)
+
+func TestToolTipsAreSharedByTheWindow(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	button := NewButton()
+	button.SetToolTip("Saves the file\nCtrl+S")
+	w.Add(button)
+	panel := NewPanel()
+	w.Add(panel)
+	label := NewLabel()
+	panel.Add(label)
+	slider := NewSlider()
+	w.Add(slider)
+	count := 0
+	slider.SetToolTipFunc(func() string {
+		count++
+		return "Position " + string('0'+rune(count))
+	})
+	noTip := NewEditLine()
+	w.Add(noTip)
+
+	w.SetOnShow(func() {
+		tip, ok := h.ToolTip(button)
+		check.Eq(t, ok, true)
+		check.Eq(t, tip, "Saves the file\nCtrl+S")
+
+		tip, _ = h.ToolTip(slider)
+		check.Eq(t, tip, "Position 1")
+		tip, _ = h.ToolTip(slider)
+		check.Eq(t, tip, "Position 2")
+
+		_, ok = h.ToolTip(noTip)
+		check.Eq(t, ok, false)
+
+		_, ok = h.ToolTip(label)
+		check.Eq(t, ok, false)
+		label.SetToolTip("in a panel")
+		tip, ok = h.ToolTip(label)
+		check.Eq(t, ok, true)
+		check.Eq(t, tip, "in a panel")
+
+		button.SetToolTip("")
+		_, ok = h.ToolTip(button)
+		check.Eq(t, ok, false)
+
+		// All controls share one tooltip window.
+		toolTips := 0
+		for _, c := range h.handles {
+			if c.ClassName == win.TOOLTIPS_CLASS {
+				toolTips++
+				check.Eq(t, c.Parent, w.Handle())
+				check.Eq(t, c.tools, []uintptr{slider.Handle(), label.Handle()})
+			}
+		}
+		check.Eq(t, toolTips, 1)
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestToolTipOptions(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	w.SetToolTipDelay(250 * time.Millisecond)
+	b := NewButton()
+	b.SetToolTip("tip")
+	w.Add(b)
+
+	w.SetOnShow(func() {
+		tip := h.handles[w.toolTipHandle]
+		check.Eq(t, tip.toolTipDelay, 250*time.Millisecond)
+		check.Eq(t, tip.toolTipDuration, time.Duration(0))
+		check.Eq(t, tip.toolTipWidth, toolTipMaxWidth)
+		check.Eq(t, tip.Style&win.TTS_BALLOON, 0)
+
+		w.SetToolTipDuration(5 * time.Second)
+		w.SetToolTipBalloon(true)
+		check.Eq(t, tip.toolTipDuration, 5*time.Second)
+		check.Eq(t, tip.Style&win.TTS_BALLOON != 0, true)
+
+		h.SetDPI(2 * DefaultDPI)
+		check.Eq(t, tip.toolTipWidth, 2*toolTipMaxWidth)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, w.toolTipHandle, uintptr(0))
+}
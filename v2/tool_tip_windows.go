@@ -0,0 +1,58 @@
+package wui
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+func (*winAPI) setToolTipOptions(toolTip uintptr, delay, duration time.Duration, maxWidth int) {
+	// -1 resets a delay time to its default.
+	ms := func(d time.Duration) uintptr {
+		if d == 0 {
+			return ^uintptr(0)
+		}
+		return uintptr(d / time.Millisecond)
+	}
+	h := w32.HWND(toolTip)
+	w32.SendMessage(h, w32.TTM_SETDELAYTIME, ttdtInitial, ms(delay))
+	w32.SendMessage(h, w32.TTM_SETDELAYTIME, ttdtAutoPop, ms(duration))
+	// Setting a maximum width also makes the tooltip break lines at '\n'.
+	w32.SendMessage(h, w32.TTM_SETMAXTIPWIDTH, 0, uintptr(maxWidth))
+}
+
+func newToolInfo(window, control uintptr) toolInfo {
+	var info toolInfo
+	info.size = uint32(unsafe.Sizeof(info))
+	info.flags = w32.TTF_IDISHWND | w32.TTF_SUBCLASS
+	info.window = window
+	info.id = control
+	info.text = lpstrTextCallback
+	return info
+}
+
+func (*winAPI) addToolTip(toolTip, window, control uintptr) {
+	info := newToolInfo(window, control)
+	// Adding a tool twice would show its tooltip twice.
+	w32.SendMessage(w32.HWND(toolTip), ttmDelToolW, 0, uintptr(unsafe.Pointer(&info)))
+	w32.SendMessage(w32.HWND(toolTip), ttmAddToolW, 0, uintptr(unsafe.Pointer(&info)))
+}
+
+func (*winAPI) removeToolTip(toolTip, window, control uintptr) {
+	info := newToolInfo(window, control)
+	w32.SendMessage(w32.HWND(toolTip), ttmDelToolW, 0, uintptr(unsafe.Pointer(&info)))
+}
+
+// onToolTipText handles TTN_GETDISPINFOW, the tooltip asks for the text of the
+// control with the handle idFrom.
+func (w *Window) onToolTipText(lParam uintptr) {
+	info := (*toolTipDispInfo)(unsafe.Pointer(lParam))
+	text := w.toolTipText(info.header.IdFrom)
+	// The text must stay valid after we return, so the backend keeps it until
+	// the next tooltip asks for its text.
+	a := ui.(*winAPI)
+	a.toolTipText, _ = syscall.UTF16FromString(text)
+	info.text = &a.toolTipText[0]
+}
@@ -0,0 +1,211 @@
+package wui
+
+// TrayIcon is an icon in the notification area of the task bar, at the right
+// of the Windows task bar. It belongs to a Window and only exists while the
+// window is shown. Create it with Window.NewTrayIcon.
+type TrayIcon struct {
+	window              *Window
+	id                  uint
+	icon                *Icon
+	toolTip             string
+	menu                *Menu
+	hidden              bool
+	removed             bool
+	onClick             Event
+	onDoubleClick       Event
+	onNotificationClick Event
+}
+
+// NotificationKind is the icon that is shown in a tray notification.
+type NotificationKind int
+
+const (
+	NotificationNone NotificationKind = iota
+	NotificationInfo
+	NotificationWarning
+	NotificationError
+)
+
+// NewTrayIcon adds an icon to the notification area of the task bar. If the
+// window is not yet shown, the icon appears once it is. When the window is
+// closed, the icon is removed for good, it does not come back if the window is
+// shown again. Create a new TrayIcon in that case.
+func (w *Window) NewTrayIcon(icon *Icon) *TrayIcon {
+	w.lastTrayIconID++
+	t := &TrayIcon{
+		window: w,
+		id:     w.lastTrayIconID,
+		icon:   icon,
+	}
+	w.trayIcons = append(w.trayIcons, t)
+	t.apply()
+	return t
+}
+
+// trayEvent is a user interaction with a TrayIcon.
+type trayEvent int
+
+const (
+	trayClick trayEvent = iota
+	trayRightClick
+	trayDoubleClick
+	trayNotificationClick
+)
+
+// trayIconEvent is called by the backend when the user interacts with the
+// tray icon with the given ID. x and y are the mouse position on the screen.
+func (w *Window) trayIconEvent(id uint, e trayEvent, x, y int) {
+	for _, t := range w.trayIcons {
+		if t.id == id {
+			switch e {
+			case trayClick:
+				t.onClick.fire()
+			case trayRightClick:
+				if t.menu != nil {
+					t.menu.popup(w, x, y)
+				}
+			case trayDoubleClick:
+				t.onDoubleClick.fire()
+			case trayNotificationClick:
+				t.onNotificationClick.fire()
+			}
+			return
+		}
+	}
+}
+
+// showTrayIcons adds all visible tray icons after the window was created.
+// This is also needed when the task bar was re-created, e.g. after the
+// Explorer crashed.
+func (w *Window) showTrayIcons() {
+	for _, t := range w.trayIcons {
+		t.apply()
+	}
+}
+
+// removeTrayIcons removes all tray icons and forgets them, this is called
+// when the window is destroyed. The removed icons stay removed, even if the
+// window is shown again.
+func (w *Window) removeTrayIcons() {
+	for _, t := range w.trayIcons {
+		if t.shown() {
+			ui.removeTrayIcon(w.handle, t.id)
+		}
+		t.removed = true
+	}
+	w.trayIcons = nil
+}
+
+// shown returns true if the native icon exists.
+func (t *TrayIcon) shown() bool {
+	return t.window.handle != 0 && !t.hidden && !t.removed
+}
+
+func (t *TrayIcon) apply() {
+	if t.shown() {
+		var icon uintptr
+		if t.icon != nil {
+			icon = t.icon.handle
+		}
+		ui.setTrayIcon(t.window.handle, t.id, icon, t.toolTip)
+	}
+}
+
+func (t *TrayIcon) Icon() *Icon {
+	return t.icon
+}
+
+// SetIcon changes the icon, e.g. to show a status change.
+func (t *TrayIcon) SetIcon(icon *Icon) {
+	t.icon = icon
+	t.apply()
+}
+
+func (t *TrayIcon) ToolTip() string {
+	return t.toolTip
+}
+
+// SetToolTip sets the text that appears when the mouse hovers over the icon.
+// Windows shows at most 127 characters.
+func (t *TrayIcon) SetToolTip(text string) {
+	t.toolTip = text
+	t.apply()
+}
+
+func (t *TrayIcon) Menu() *Menu {
+	return t.menu
+}
+
+// SetMenu sets the menu that pops up when the user right-clicks the icon. Use
+// NewMenu to create it, its name is not shown.
+func (t *TrayIcon) SetMenu(m *Menu) {
+	t.menu = m
+}
+
+func (t *TrayIcon) Visible() bool {
+	return !t.hidden
+}
+
+func (t *TrayIcon) SetVisible(v bool) {
+	if v == !t.hidden {
+		return
+	}
+	if v {
+		t.hidden = false
+		t.apply()
+	} else {
+		if t.shown() {
+			ui.removeTrayIcon(t.window.handle, t.id)
+		}
+		t.hidden = true
+	}
+}
+
+// ShowNotification shows a message next to the icon. On Windows 10 and later
+// this is a toast notification, on older versions it is a balloon. Nothing is
+// shown if the icon or its window is not visible.
+func (t *TrayIcon) ShowNotification(title, text string, kind NotificationKind) {
+	if t.shown() {
+		ui.showTrayNotification(t.window.handle, t.id, title, text, kind)
+	}
+}
+
+func (t *TrayIcon) OnClick() func() {
+	return t.onClick.primary
+}
+
+// SetOnClick sets the function that is called when the user left-clicks the
+// icon. Note that a double-click also calls this for the first click.
+func (t *TrayIcon) SetOnClick(f func()) {
+	t.onClick.primary = f
+}
+
+func (t *TrayIcon) ClickEvent() *Event {
+	return &t.onClick
+}
+
+func (t *TrayIcon) OnDoubleClick() func() {
+	return t.onDoubleClick.primary
+}
+
+func (t *TrayIcon) SetOnDoubleClick(f func()) {
+	t.onDoubleClick.primary = f
+}
+
+func (t *TrayIcon) DoubleClickEvent() *Event {
+	return &t.onDoubleClick
+}
+
+func (t *TrayIcon) OnNotificationClick() func() {
+	return t.onNotificationClick.primary
+}
+
+// SetOnNotificationClick sets the function that is called when the user
+// clicks a notification that was shown with ShowNotification.
+func (t *TrayIcon) SetOnNotificationClick(f func()) {
+	t.onNotificationClick.primary = f
+}
+
+func (t *TrayIcon) NotificationClickEvent() *Event {
+	return &t.onNotificationClick
+}
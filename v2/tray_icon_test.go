@@ -0,0 +1,132 @@
+package wui
+
+import (
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestTrayIconLivesWithItsWindow(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tray := w.NewTrayIcon(IconInformation)
+	tray.SetToolTip("Service running")
+
+	_, ok := h.TrayIcon(tray)
+	check.Eq(t, ok, false)
+
+	w.SetOnShow(func() {
+		icon, ok := h.TrayIcon(tray)
+		check.Eq(t, ok, true)
+		check.Eq(t, icon.Icon, IconInformation.handle)
+		check.Eq(t, icon.ToolTip, "Service running")
+
+		tray.SetIcon(IconWarning)
+		tray.SetToolTip("Service stopped")
+		icon, _ = h.TrayIcon(tray)
+		check.Eq(t, icon.Icon, IconWarning.handle)
+		check.Eq(t, icon.ToolTip, "Service stopped")
+
+		tray.SetVisible(false)
+		_, ok = h.TrayIcon(tray)
+		check.Eq(t, ok, false)
+		tray.SetVisible(true)
+		_, ok = h.TrayIcon(tray)
+		check.Eq(t, ok, true)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, len(h.trayIcons), 0)
+	check.Eq(t, len(w.trayIcons), 0)
+}
+
+func TestTrayIconClicksAndMenu(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tray := w.NewTrayIcon(IconApplication)
+	var events []string
+	tray.SetOnClick(func() { events = append(events, "click") })
+	tray.DoubleClickEvent().Subscribe(func() {
+		events = append(events, "double click")
+	})
+	tray.SetOnNotificationClick(func() {
+		events = append(events, "notification")
+	})
+	tray.SetMenu(NewMenu("").
+		Add(NewMenuString("Open").SetOnClick(func() {
+			events = append(events, "open")
+		})).
+		Add(NewMenuSeparator()).
+		Add(NewMenu("More").Add(NewMenuString("Quit").SetOnClick(func() {
+			events = append(events, "quit")
+		}))),
+	)
+	var popupItems []string
+	h.SetPopupMenuChoice(func(items []string) string {
+		popupItems = items
+		return "Quit"
+	})
+
+	w.SetOnShow(func() {
+		h.ClickTrayIcon(tray, MouseButtonLeft)
+		h.DoubleClickTrayIcon(tray)
+		h.ClickTrayIcon(tray, MouseButtonRight)
+		// Without a notification, there is nothing to click.
+		h.ClickTrayNotification(tray)
+		tray.ShowNotification("Done", "The backup is complete", NotificationInfo)
+		h.ClickTrayNotification(tray)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, events, []string{
+		"click",
+		"click",
+		"double click",
+		"quit",
+		"notification",
+	})
+	check.Eq(t, popupItems, []string{"Open", "Quit"})
+	// All popup menus were destroyed.
+	check.Eq(t, len(h.menus), 0)
+}
+
+func TestTrayNotificationsAreRecorded(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tray := w.NewTrayIcon(IconApplication)
+	tray.ShowNotification("Not", "yet shown", NotificationNone)
+	w.SetOnShow(func() {
+		tray.ShowNotification("Disk full", "Free some space", NotificationError)
+		icon, _ := h.TrayIcon(tray)
+		check.Eq(t, icon.Notifications, []HeadlessTrayNotification{
+			{Title: "Disk full", Text: "Free some space", Kind: NotificationError},
+		})
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestTrayIconsStayRemovedWhenTheWindowIsShownAgain(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tray := w.NewTrayIcon(IconInformation)
+	w.SetOnShow(w.Close)
+	w.Show()
+
+	w.SetOnShow(func() {
+		tray.SetIcon(IconWarning)
+		tray.SetToolTip("removed")
+		tray.SetVisible(false)
+		tray.SetVisible(true)
+		tray.ShowNotification("removed", "", NotificationNone)
+		_, ok := h.TrayIcon(tray)
+		check.Eq(t, ok, false)
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, len(h.trayIcons), 0)
+}
@@ -0,0 +1,134 @@
+package wui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+// wmTrayIcon is the message that tray icons send to their window. wParam is
+// the icon's ID and lParam is the mouse or notification message.
+var wmTrayIcon = registerWindowMessage("wui.TrayIcon")
+
+// wmTaskbarCreated is sent to all top-level windows when the task bar was
+// re-created, e.g. after the Explorer crashed. All tray icons are gone then.
+var wmTaskbarCreated = registerWindowMessage("TaskbarCreated")
+
+type trayIconKey struct {
+	window w32.HWND
+	id     uint
+}
+
+func newNotifyIconData(window uintptr, id uint) notifyIconData {
+	var data notifyIconData
+	data.size = uint32(unsafe.Sizeof(data))
+	data.window = window
+	data.id = uint32(id)
+	return data
+}
+
+func (a *winAPI) setTrayIcon(window uintptr, id uint, icon uintptr, toolTip string) {
+	data := newNotifyIconData(window, id)
+	data.flags = nifMessage | nifIcon | nifTip
+	data.callbackMessage = wmTrayIcon
+	data.icon = icon
+	copyUTF16(data.tip[:], toolTip)
+	key := trayIconKey{w32.HWND(window), id}
+	if a.trayIcons[key] {
+		shellNotifyIcon(nimModify, &data)
+	} else if shellNotifyIcon(nimAdd, &data) {
+		a.trayIcons[key] = true
+	}
+}
+
+func (a *winAPI) removeTrayIcon(window uintptr, id uint) {
+	key := trayIconKey{w32.HWND(window), id}
+	if a.trayIcons[key] {
+		data := newNotifyIconData(window, id)
+		shellNotifyIcon(nimDelete, &data)
+		delete(a.trayIcons, key)
+	}
+}
+
+func (a *winAPI) showTrayNotification(
+	window uintptr,
+	id uint,
+	title, text string,
+	kind NotificationKind,
+) {
+	data := newNotifyIconData(window, id)
+	data.flags = nifInfo
+	copyUTF16(data.infoTitle[:], title)
+	copyUTF16(data.info[:], text)
+	switch kind {
+	case NotificationInfo:
+		data.infoFlags = niifInfo
+	case NotificationWarning:
+		data.infoFlags = niifWarning
+	case NotificationError:
+		data.infoFlags = niifError
+	}
+	shellNotifyIcon(nimModify, &data)
+}
+
+// taskbarCreated forgets the window's tray icons, they have to be added
+// again.
+func (a *winAPI) taskbarCreated(window uintptr) {
+	for key := range a.trayIcons {
+		if key.window == w32.HWND(window) {
+			delete(a.trayIcons, key)
+		}
+	}
+}
+
+// onTrayIconMessage handles wmTrayIcon.
+func (w *Window) onTrayIconMessage(wParam, lParam uintptr) {
+	x, y, _ := w32.GetCursorPos()
+	id := uint(wParam)
+	switch lParam & 0xFFFF {
+	case w32.WM_LBUTTONUP:
+		w.trayIconEvent(id, trayClick, x, y)
+	case w32.WM_LBUTTONDBLCLK:
+		w.trayIconEvent(id, trayDoubleClick, x, y)
+	case w32.WM_RBUTTONUP:
+		w.trayIconEvent(id, trayRightClick, x, y)
+	case ninBalloonUserClick:
+		w.trayIconEvent(id, trayNotificationClick, x, y)
+	}
+}
+
+func (*winAPI) createPopupMenu() uintptr {
+	return uintptr(w32.CreatePopupMenu())
+}
+
+func (*winAPI) popupMenu(window, menu uintptr, x, y int) uint {
+	// The window must be in the foreground or the menu does not close when
+	// the user clicks outside of it. The WM_NULL afterwards is needed for
+	// the menu to work the next time, see the remarks of TrackPopupMenu.
+	w32.SetForegroundWindow(w32.HWND(window))
+	id := w32.TrackPopupMenu(
+		w32.HMENU(menu),
+		tpmReturnCmd|tpmRightButton,
+		x, y,
+		w32.HWND(window),
+		nil,
+	)
+	w32.PostMessage(w32.HWND(window), w32.WM_NULL, 0, 0)
+	return uint(id)
+}
+
+func (*winAPI) destroyMenu(menu uintptr) {
+	w32.DestroyMenu(w32.HMENU(menu))
+}
+
+// copyUTF16 copies s into the fixed size buffer, cutting it off if necessary.
+// The buffer is always 0-terminated.
+func copyUTF16(buf []uint16, s string) {
+	u, _ := syscall.UTF16FromString(s)
+	if len(u) > len(buf) {
+		u = u[:len(buf)]
+		u[len(u)-1] = 0
+	}
+	copy(buf, u)
+}
@@ -0,0 +1,587 @@
+package wui
+
+import "github.com/gonutz/wui/v2/internal/win"
+
+// NewTreeView returns a control that shows nodes in a hierarchy, like the
+// folders in the Explorer. Use TreeView.AddNode and TreeNode.AddNode to fill
+// it.
+func NewTreeView() *TreeView {
+	return &TreeView{handles: make(map[uintptr]*TreeNode)}
+}
+
+// TreeView shows TreeNodes in a hierarchy. The user can expand and collapse
+// nodes that have children, select a node and, optionally, check nodes and
+// rename them.
+type TreeView struct {
+	textControl
+	roots             []*TreeNode
+	selected          *TreeNode
+	hasCheckBoxes     bool
+	editableLabels    bool
+	images            []*Image
+	handles           map[uintptr]*TreeNode // By native item.
+	onSelectionChange Event
+	onExpand          TreeNodeEvent
+	onCollapse        TreeNodeEvent
+	onCheck           TreeNodeEvent
+	onLoadChildren    TreeNodeEvent
+	onLabelEdit       LabelEditEvent
+}
+
+var _ Control = (*TreeView)(nil)
+
+// treeItem is a native tree view item.
+type treeItem struct {
+	text        string
+	image       int // Index into the tree's images, -1 for no image.
+	hasChildren bool
+	checkBox    bool // If false, the item's check box is left alone.
+	checked     bool
+}
+
+func (*TreeView) canFocus() bool {
+	return true
+}
+
+func (t *TreeView) OnTabFocus() func() {
+	return t.onTabFocus.primary
+}
+
+func (t *TreeView) SetOnTabFocus(f func()) {
+	t.onTabFocus.primary = f
+}
+
+func (t *TreeView) TabFocusEvent() *Event {
+	return &t.onTabFocus
+}
+
+func (*TreeView) eatsTabs() bool {
+	return false
+}
+
+func (t *TreeView) create(id int) {
+	t.textControl.create(
+		id,
+		win.WS_EX_CLIENTEDGE,
+		win.WC_TREEVIEW,
+		win.WS_TABSTOP|win.TVS_HASBUTTONS|win.TVS_HASLINES|
+			win.TVS_LINESATROOT|win.TVS_SHOWSELALWAYS|t.optionalStyle(),
+	)
+	// Check boxes must be turned on after creating the tree view, otherwise
+	// they might not show the right state.
+	if t.hasCheckBoxes {
+		t.updateStyle()
+	}
+	if len(t.images) > 0 {
+		ui.setTreeImages(t.handle, t.images)
+	}
+	for _, n := range t.roots {
+		t.insert(n)
+	}
+	if t.selected != nil {
+		ui.selectTreeItem(t.handle, t.selected.handle)
+	}
+}
+
+func (t *TreeView) destroy() {
+	if t.handle != 0 {
+		// Tree views do not destroy their image lists themselves.
+		if len(t.images) > 0 {
+			ui.setTreeImages(t.handle, nil)
+		}
+		for _, n := range t.roots {
+			n.forgetHandles()
+		}
+		t.control.destroy()
+	}
+}
+
+func (t *TreeView) optionalStyle() uint {
+	var style uint
+	if t.editableLabels {
+		style |= win.TVS_EDITLABELS
+	}
+	return style
+}
+
+func (t *TreeView) updateStyle() {
+	if t.handle == 0 {
+		return
+	}
+	style, exStyle := ui.style(t.handle)
+	style &^= win.TVS_EDITLABELS | win.TVS_CHECKBOXES
+	style |= t.optionalStyle()
+	if t.hasCheckBoxes {
+		style |= win.TVS_CHECKBOXES
+	}
+	ui.setStyle(t.handle, style, exStyle)
+}
+
+// insert creates the native items for the node and its children.
+func (t *TreeView) insert(n *TreeNode) {
+	var parent uintptr
+	if n.parent != nil {
+		parent = n.parent.handle
+	}
+	n.handle = ui.insertTreeItem(t.handle, parent, n.info())
+	t.handles[n.handle] = n
+	for _, child := range n.children {
+		t.insert(child)
+	}
+	if n.expanded {
+		ui.expandTreeItem(t.handle, n.handle, true)
+	}
+}
+
+// imageIndex returns the index of the image in the tree's image list, adding
+// it if it is new, or -1 for nil.
+func (t *TreeView) imageIndex(img *Image) int {
+	if img == nil {
+		return -1
+	}
+	for i := range t.images {
+		if t.images[i] == img {
+			return i
+		}
+	}
+	t.images = append(t.images, img)
+	if t.handle != 0 {
+		ui.setTreeImages(t.handle, t.images)
+	}
+	return len(t.images) - 1
+}
+
+// AddNode appends a new top-level node with the given text and returns it.
+func (t *TreeView) AddNode(text string) *TreeNode {
+	n := &TreeNode{tree: t, text: text}
+	t.roots = append(t.roots, n)
+	if t.handle != 0 {
+		t.insert(n)
+	}
+	return n
+}
+
+// Nodes returns the top-level nodes.
+func (t *TreeView) Nodes() []*TreeNode {
+	return t.roots
+}
+
+// Clear removes all nodes.
+func (t *TreeView) Clear() {
+	for len(t.roots) > 0 {
+		t.roots[len(t.roots)-1].Remove()
+	}
+}
+
+// Selected returns the selected node or nil if no node is selected.
+func (t *TreeView) Selected() *TreeNode {
+	return t.selected
+}
+
+// SetSelected selects the node, pass nil to remove the selection. Nodes that
+// are not in the TreeView are ignored.
+func (t *TreeView) SetSelected(n *TreeNode) {
+	if n != nil && n.tree != t || n == t.selected {
+		return
+	}
+	t.selected = n
+	if t.handle != 0 {
+		var item uintptr
+		if n != nil {
+			item = n.handle
+		}
+		ui.selectTreeItem(t.handle, item)
+	}
+	t.onSelectionChange.fire()
+}
+
+func (t *TreeView) HasCheckBoxes() bool {
+	return t.hasCheckBoxes
+}
+
+// SetHasCheckBoxes shows or hides a check box in front of every node. See
+// TreeNode.SetChecked and TreeView.SetOnCheck.
+func (t *TreeView) SetHasCheckBoxes(has bool) {
+	if has == t.hasCheckBoxes {
+		return
+	}
+	t.hasCheckBoxes = has
+	t.updateStyle()
+	if has {
+		// The items have no check box state until we set it.
+		for _, n := range t.roots {
+			n.updateAll()
+		}
+	}
+}
+
+func (t *TreeView) EditableLabels() bool {
+	return t.editableLabels
+}
+
+// SetEditableLabels lets the user rename nodes by clicking the selected node's
+// text or pressing F2. See TreeView.SetOnLabelEdit.
+func (t *TreeView) SetEditableLabels(editable bool) {
+	t.editableLabels = editable
+	t.updateStyle()
+}
+
+func (t *TreeView) OnSelectionChange() func() {
+	return t.onSelectionChange.primary
+}
+
+// SetOnSelectionChange sets the function that is called when the selected
+// node changes, by the user or by SetSelected.
+func (t *TreeView) SetOnSelectionChange(f func()) {
+	t.onSelectionChange.primary = f
+}
+
+func (t *TreeView) SelectionChangeEvent() *Event {
+	return &t.onSelectionChange
+}
+
+func (t *TreeView) OnExpand() func(*TreeNode) {
+	return t.onExpand.primary
+}
+
+// SetOnExpand sets the function that is called after a node was expanded, by
+// the user or by TreeNode.Expand.
+func (t *TreeView) SetOnExpand(f func(*TreeNode)) {
+	t.onExpand.primary = f
+}
+
+func (t *TreeView) ExpandEvent() *TreeNodeEvent {
+	return &t.onExpand
+}
+
+func (t *TreeView) OnCollapse() func(*TreeNode) {
+	return t.onCollapse.primary
+}
+
+// SetOnCollapse sets the function that is called after a node was collapsed,
+// by the user or by TreeNode.Collapse.
+func (t *TreeView) SetOnCollapse(f func(*TreeNode)) {
+	t.onCollapse.primary = f
+}
+
+func (t *TreeView) CollapseEvent() *TreeNodeEvent {
+	return &t.onCollapse
+}
+
+func (t *TreeView) OnCheck() func(*TreeNode) {
+	return t.onCheck.primary
+}
+
+// SetOnCheck sets the function that is called when a node is checked or
+// unchecked, by the user or by TreeNode.SetChecked.
+func (t *TreeView) SetOnCheck(f func(*TreeNode)) {
+	t.onCheck.primary = f
+}
+
+func (t *TreeView) CheckEvent() *TreeNodeEvent {
+	return &t.onCheck
+}
+
+func (t *TreeView) OnLoadChildren() func(*TreeNode) {
+	return t.onLoadChildren.primary
+}
+
+// SetOnLoadChildren sets the function that adds the children of a node with
+// lazy children, see TreeNode.SetHasLazyChildren. It is called once, right
+// before the node is expanded for the first time.
+func (t *TreeView) SetOnLoadChildren(f func(*TreeNode)) {
+	t.onLoadChildren.primary = f
+}
+
+func (t *TreeView) LoadChildrenEvent() *TreeNodeEvent {
+	return &t.onLoadChildren
+}
+
+func (t *TreeView) OnLabelEdit() func(n *TreeNode, text string) bool {
+	return t.onLabelEdit.primary
+}
+
+// SetOnLabelEdit sets the function that is called when the user renamed a
+// node, see SetEditableLabels. It returns false to reject the new text, the
+// node then keeps its old text.
+func (t *TreeView) SetOnLabelEdit(f func(n *TreeNode, text string) bool) {
+	t.onLabelEdit.primary = f
+}
+
+func (t *TreeView) LabelEditEvent() *LabelEditEvent {
+	return &t.onLabelEdit
+}
+
+// nodeSelected is called by the backend when the user selects a node, n is
+// nil if the selection was removed.
+func (t *TreeView) nodeSelected(n *TreeNode) {
+	if n != t.selected {
+		t.selected = n
+		t.onSelectionChange.fire()
+	}
+}
+
+// nodeExpanded is called by the backend after the user expanded or collapsed
+// the node.
+func (t *TreeView) nodeExpanded(n *TreeNode, expanded bool) {
+	if n.expanded == expanded {
+		return
+	}
+	n.expanded = expanded
+	if expanded {
+		t.onExpand.fire(n)
+	} else {
+		t.onCollapse.fire(n)
+	}
+}
+
+// nodeChecked is called by the backend when the user clicked a check box.
+func (t *TreeView) nodeChecked(n *TreeNode, checked bool) {
+	if n.checked != checked {
+		n.checked = checked
+		t.onCheck.fire(n)
+	}
+}
+
+// labelEdited is called by the backend when the user renamed the node. It
+// returns false if the new text is rejected.
+func (t *TreeView) labelEdited(n *TreeNode, text string) bool {
+	if !t.onLabelEdit.fire(n, text) {
+		return false
+	}
+	n.SetText(text)
+	return true
+}
+
+// TreeNode is an entry in a TreeView. Create nodes with TreeView.AddNode and
+// TreeNode.AddNode.
+type TreeNode struct {
+	tree     *TreeView // nil after the node was removed.
+	parent   *TreeNode // nil for top-level nodes.
+	children []*TreeNode
+	handle   uintptr
+	text     string
+	data     interface{}
+	image    *Image
+	checked  bool
+	expanded bool
+	lazy     bool
+}
+
+// AddNode appends a new child node with the given text and returns it.
+func (n *TreeNode) AddNode(text string) *TreeNode {
+	child := &TreeNode{tree: n.tree, parent: n, text: text}
+	n.children = append(n.children, child)
+	if n.handle != 0 {
+		n.tree.insert(child)
+		if len(n.children) == 1 {
+			// The node now shows an expand button.
+			n.update()
+		}
+	}
+	return child
+}
+
+func (n *TreeNode) Children() []*TreeNode {
+	return n.children
+}
+
+// Parent returns the node that contains n or nil for top-level nodes.
+func (n *TreeNode) Parent() *TreeNode {
+	return n.parent
+}
+
+// Remove removes the node and its children from their TreeView. If the
+// selected node is removed, the TreeView has no selection afterwards.
+func (n *TreeNode) Remove() {
+	t := n.tree
+	if t != nil && t.selected.isIn(n) {
+		t.SetSelected(nil)
+	}
+	if n.parent != nil {
+		n.parent.children = removeTreeNode(n.parent.children, n)
+	} else if t != nil {
+		t.roots = removeTreeNode(t.roots, n)
+	}
+	if t != nil && n.handle != 0 {
+		ui.deleteTreeItem(t.handle, n.handle)
+		if n.parent != nil && len(n.parent.children) == 0 {
+			// The expand button goes away.
+			n.parent.update()
+		}
+	}
+	n.forgetHandles()
+	n.detach()
+	n.parent = nil
+}
+
+func removeTreeNode(nodes []*TreeNode, n *TreeNode) []*TreeNode {
+	for i := range nodes {
+		if nodes[i] == n {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
+
+// isIn returns true if n is the given node or one of its (grand-)children.
+func (n *TreeNode) isIn(ancestor *TreeNode) bool {
+	for ; n != nil; n = n.parent {
+		if n == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// forgetHandles is called when the native items of the node and its children
+// are gone.
+func (n *TreeNode) forgetHandles() {
+	if n.tree != nil {
+		delete(n.tree.handles, n.handle)
+	}
+	n.handle = 0
+	for _, child := range n.children {
+		child.forgetHandles()
+	}
+}
+
+// detach takes the node and its children out of their TreeView.
+func (n *TreeNode) detach() {
+	n.tree = nil
+	for _, child := range n.children {
+		child.detach()
+	}
+}
+
+func (n *TreeNode) Text() string {
+	return n.text
+}
+
+func (n *TreeNode) SetText(text string) {
+	n.text = text
+	n.update()
+}
+
+// Data returns the value that was set with SetData.
+func (n *TreeNode) Data() interface{} {
+	return n.data
+}
+
+// SetData associates any value with the node, e.g. the file that it shows.
+func (n *TreeNode) SetData(data interface{}) {
+	n.data = data
+}
+
+func (n *TreeNode) Image() *Image {
+	return n.image
+}
+
+// SetImage shows the image left of the node's text, pass nil to remove it. All
+// images in a TreeView must have the same size, usually 16x16 pixels.
+func (n *TreeNode) SetImage(img *Image) {
+	n.image = img
+	n.update()
+}
+
+func (n *TreeNode) Checked() bool {
+	return n.checked
+}
+
+// SetChecked checks or unchecks the node's check box, see
+// TreeView.SetHasCheckBoxes.
+func (n *TreeNode) SetChecked(checked bool) {
+	if checked == n.checked {
+		return
+	}
+	n.checked = checked
+	n.update()
+	if n.tree != nil {
+		n.tree.onCheck.fire(n)
+	}
+}
+
+func (n *TreeNode) Expanded() bool {
+	return n.expanded
+}
+
+// Expand shows the node's children. It does nothing if the node has no
+// children. Nodes with lazy children load them first, see
+// SetHasLazyChildren.
+func (n *TreeNode) Expand() {
+	if n.expanded {
+		return
+	}
+	n.loadChildren()
+	if len(n.children) == 0 {
+		return
+	}
+	n.expanded = true
+	if n.handle != 0 {
+		ui.expandTreeItem(n.tree.handle, n.handle, true)
+	}
+	if n.tree != nil {
+		n.tree.onExpand.fire(n)
+	}
+}
+
+// Collapse hides the node's children.
+func (n *TreeNode) Collapse() {
+	if !n.expanded {
+		return
+	}
+	n.expanded = false
+	if n.handle != 0 {
+		ui.expandTreeItem(n.tree.handle, n.handle, false)
+	}
+	if n.tree != nil {
+		n.tree.onCollapse.fire(n)
+	}
+}
+
+func (n *TreeNode) HasLazyChildren() bool {
+	return n.lazy
+}
+
+// SetHasLazyChildren marks the node as having children that are not added
+// yet, e.g. the sub-folders of a folder. The node shows an expand button and
+// the TreeView's OnLoadChildren is called to add the children when the node
+// is expanded for the first time.
+func (n *TreeNode) SetHasLazyChildren(lazy bool) {
+	n.lazy = lazy
+	n.update()
+}
+
+// loadChildren calls OnLoadChildren for nodes with lazy children. It is called
+// before the node expands.
+func (n *TreeNode) loadChildren() {
+	if n.lazy && n.tree != nil {
+		n.lazy = false
+		n.tree.onLoadChildren.fire(n)
+		n.update()
+	}
+}
+
+func (n *TreeNode) info() treeItem {
+	return treeItem{
+		text:        n.text,
+		image:       n.tree.imageIndex(n.image),
+		hasChildren: len(n.children) > 0 || n.lazy,
+		checkBox:    n.tree.hasCheckBoxes,
+		checked:     n.checked,
+	}
+}
+
+// update applies the node's state to its native item.
+func (n *TreeNode) update() {
+	if n.handle != 0 {
+		ui.setTreeItem(n.tree.handle, n.handle, n.info())
+	}
+}
+
+func (n *TreeNode) updateAll() {
+	n.update()
+	for _, child := range n.children {
+		child.updateAll()
+	}
+}
@@ -0,0 +1,270 @@
+package wui
+
+import (
+	"image"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestTreeNodesAreCreatedWhenTheWindowShows(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tree := NewTreeView()
+	w.Add(tree)
+	docs := tree.AddNode("Documents")
+	letter := docs.AddNode("letter.txt")
+	letter.SetData(42)
+	music := tree.AddNode("Music")
+	music.SetHasLazyChildren(true)
+	docs.Expand()
+
+	w.SetOnShow(func() {
+		item, ok := h.TreeItem(docs)
+		check.Eq(t, ok, true)
+		check.Eq(t, item.Text, "Documents")
+		check.Eq(t, item.Parent, uintptr(0))
+		check.Eq(t, item.Children, []uintptr{letter.handle})
+		check.Eq(t, item.HasChildren, true)
+		check.Eq(t, item.Expanded, true)
+		check.Eq(t, item.Image, -1)
+
+		item, _ = h.TreeItem(letter)
+		check.Eq(t, item.Parent, docs.handle)
+		check.Eq(t, item.HasChildren, false)
+
+		item, _ = h.TreeItem(music)
+		check.Eq(t, item.HasChildren, true)
+		check.Eq(t, item.Expanded, false)
+
+		check.Eq(t, h.handles[tree.handle].treeRoots, []uintptr{docs.handle, music.handle})
+
+		// Adding the first child shows the expand button.
+		song := letter.AddNode("song.mp3")
+		item, _ = h.TreeItem(letter)
+		check.Eq(t, item.HasChildren, true)
+		item, _ = h.TreeItem(song)
+		check.Eq(t, item.Parent, letter.handle)
+
+		song.Remove()
+		item, _ = h.TreeItem(letter)
+		check.Eq(t, item.HasChildren, false)
+		check.Eq(t, item.Children, []uintptr{})
+		_, ok = h.TreeItem(song)
+		check.Eq(t, ok, false)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, letter.Data(), 42)
+	check.Eq(t, letter.Parent(), docs)
+	check.Eq(t, docs.Children(), []*TreeNode{letter})
+	check.Eq(t, tree.Nodes(), []*TreeNode{docs, music})
+	check.Eq(t, docs.handle, uintptr(0))
+	check.Eq(t, len(tree.handles), 0)
+}
+
+func TestTreeViewSelection(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tree := NewTreeView()
+	w.Add(tree)
+	a := tree.AddNode("a")
+	b := a.AddNode("b")
+	c := tree.AddNode("c")
+	changes := 0
+	tree.SetOnSelectionChange(func() { changes++ })
+	tree.SetSelected(b)
+	check.Eq(t, changes, 1)
+	tree.SetSelected(NewTreeView().AddNode("other tree"))
+	check.Eq(t, tree.Selected(), b)
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.handles[tree.handle].treeSelected, b.handle)
+
+		h.SelectNode(c)
+		check.Eq(t, tree.Selected(), c)
+		check.Eq(t, changes, 2)
+		h.SelectNode(c)
+		check.Eq(t, changes, 2)
+
+		tree.SetSelected(b)
+		check.Eq(t, h.handles[tree.handle].treeSelected, b.handle)
+		check.Eq(t, changes, 3)
+
+		// Removing the parent of the selected node removes the selection.
+		a.Remove()
+		check.Eq(t, tree.Selected(), (*TreeNode)(nil))
+		check.Eq(t, h.handles[tree.handle].treeSelected, uintptr(0))
+		check.Eq(t, changes, 4)
+		check.Eq(t, tree.Nodes(), []*TreeNode{c})
+
+		tree.Clear()
+		check.Eq(t, len(tree.Nodes()), 0)
+		check.Eq(t, len(h.handles[tree.handle].treeItems), 0)
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestTreeNodesLoadLazyChildrenWhenFirstExpanded(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tree := NewTreeView()
+	w.Add(tree)
+	folder := tree.AddNode("folder")
+	folder.SetHasLazyChildren(true)
+	empty := tree.AddNode("empty")
+	empty.SetHasLazyChildren(true)
+
+	var events []string
+	tree.SetOnLoadChildren(func(n *TreeNode) {
+		events = append(events, "load "+n.Text())
+		if n == folder {
+			n.AddNode("file 1")
+			n.AddNode("file 2")
+		}
+	})
+	tree.SetOnExpand(func(n *TreeNode) {
+		events = append(events, "expand "+n.Text())
+	})
+	tree.SetOnCollapse(func(n *TreeNode) {
+		events = append(events, "collapse "+n.Text())
+	})
+
+	w.SetOnShow(func() {
+		h.ExpandNode(folder)
+		check.Eq(t, folder.Expanded(), true)
+		check.Eq(t, folder.HasLazyChildren(), false)
+		check.Eq(t, len(folder.Children()), 2)
+		item, _ := h.TreeItem(folder)
+		check.Eq(t, item.Expanded, true)
+		check.Eq(t, len(item.Children), 2)
+
+		h.CollapseNode(folder)
+		check.Eq(t, folder.Expanded(), false)
+		folder.Expand()
+		item, _ = h.TreeItem(folder)
+		check.Eq(t, item.Expanded, true)
+		folder.Collapse()
+		item, _ = h.TreeItem(folder)
+		check.Eq(t, item.Expanded, false)
+
+		// A lazy node without children loses its expand button.
+		h.ExpandNode(empty)
+		check.Eq(t, empty.Expanded(), false)
+		item, _ = h.TreeItem(empty)
+		check.Eq(t, item.HasChildren, false)
+		h.ExpandNode(empty)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, events, []string{
+		"load folder",
+		"expand folder",
+		"collapse folder",
+		"expand folder",
+		"collapse folder",
+		"load empty",
+	})
+}
+
+func TestTreeNodeCheckBoxes(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tree := NewTreeView()
+	w.Add(tree)
+	a := tree.AddNode("a")
+	b := tree.AddNode("b")
+	b.SetChecked(true)
+	var checked []string
+	tree.SetOnCheck(func(n *TreeNode) {
+		checked = append(checked, n.Text())
+	})
+
+	w.SetOnShow(func() {
+		// Without check boxes, the user cannot check nodes.
+		h.CheckNode(a, true)
+		check.Eq(t, a.Checked(), false)
+
+		tree.SetHasCheckBoxes(true)
+		item, _ := h.TreeItem(b)
+		check.Eq(t, item.Checked, true)
+
+		h.CheckNode(a, true)
+		check.Eq(t, a.Checked(), true)
+		b.SetChecked(false)
+		item, _ = h.TreeItem(b)
+		check.Eq(t, item.Checked, false)
+
+		w.Close()
+	})
+	w.Show()
+
+	check.Eq(t, checked, []string{"a", "b"})
+}
+
+func TestTreeNodeLabelsCanBeEdited(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tree := NewTreeView()
+	w.Add(tree)
+	n := tree.AddNode("old")
+	tree.SetOnLabelEdit(func(n *TreeNode, text string) bool {
+		return text != ""
+	})
+
+	w.SetOnShow(func() {
+		check.Eq(t, h.EditLabel(n, "new"), false)
+		check.Eq(t, n.Text(), "old")
+
+		tree.SetEditableLabels(true)
+		check.Eq(t, h.EditLabel(n, ""), false)
+		check.Eq(t, n.Text(), "old")
+		check.Eq(t, h.EditLabel(n, "new"), true)
+		check.Eq(t, n.Text(), "new")
+		item, _ := h.TreeItem(n)
+		check.Eq(t, item.Text, "new")
+
+		w.Close()
+	})
+	w.Show()
+}
+
+func TestTreeNodeImagesShareTheTreesImageList(t *testing.T) {
+	h := UseHeadless()
+	w := NewWindow()
+	tree := NewTreeView()
+	w.Add(tree)
+	folder := NewImage(image.NewRGBA(image.Rect(0, 0, 16, 16)))
+	file := NewImage(image.NewRGBA(image.Rect(0, 0, 16, 16)))
+	a := tree.AddNode("a")
+	a.SetImage(folder)
+	b := a.AddNode("b")
+
+	w.SetOnShow(func() {
+		item, _ := h.TreeItem(a)
+		check.Eq(t, item.Image, 0)
+		check.Eq(t, h.handles[tree.handle].treeImages, []*Image{folder})
+
+		b.SetImage(file)
+		tree.AddNode("c").SetImage(folder)
+		item, _ = h.TreeItem(b)
+		check.Eq(t, item.Image, 1)
+		check.Eq(t, h.handles[tree.handle].treeImages, []*Image{folder, file})
+
+		b.SetImage(nil)
+		item, _ = h.TreeItem(b)
+		check.Eq(t, item.Image, -1)
+
+		w.Close()
+	})
+	w.Show()
+
+	// The image list is destroyed with the tree view.
+	check.Eq(t, h.handles[tree.handle] == nil, true)
+}
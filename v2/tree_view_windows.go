@@ -0,0 +1,146 @@
+package wui
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+func (*winAPI) setTreeImages(handle uintptr, images []*Image) {
+	old := w32.SendMessage(
+		w32.HWND(handle),
+		tvmSetImageList,
+		tvsilNormal,
+		uintptr(newImageList(images)),
+	)
+	if old != 0 {
+		w32.ImageList_Destroy(w32.HIMAGELIST(old))
+	}
+}
+
+// newImageList returns a list of the images, which all have the size of the
+// first image, or 0 if there are no images.
+func newImageList(images []*Image) w32.HIMAGELIST {
+	if len(images) == 0 {
+		return 0
+	}
+	list := w32.ImageList_Create(
+		images[0].width,
+		images[0].height,
+		w32.ILC_COLOR32,
+		len(images),
+		1,
+	)
+	for _, img := range images {
+		w32.ImageList_Add(list, w32.HBITMAP(img.bitmap), 0)
+	}
+	return list
+}
+
+func newTVItem(info treeItem) tvItem {
+	text, _ := syscall.UTF16PtrFromString(info.text)
+	image := int32(info.image)
+	if image < 0 {
+		image = iImageNone
+	}
+	item := tvItem{
+		mask:          tvifText | tvifImage | tvifSelectedImage | tvifChildren,
+		text:          text,
+		image:         image,
+		selectedImage: image,
+	}
+	if info.hasChildren {
+		item.children = 1
+	}
+	if info.checkBox {
+		// The state image 1 is the unchecked and 2 the checked box.
+		item.mask |= tvifState
+		item.stateMask = tvisStateImageMask
+		item.state = 1 << 12
+		if info.checked {
+			item.state = 2 << 12
+		}
+	}
+	return item
+}
+
+func (*winAPI) insertTreeItem(handle, parent uintptr, info treeItem) uintptr {
+	if parent == 0 {
+		parent = tviRoot
+	}
+	insert := tvInsertStruct{
+		parent:      parent,
+		insertAfter: tviLast,
+		item:        newTVItem(info),
+	}
+	return w32.SendMessage(
+		w32.HWND(handle),
+		tvmInsertItemW,
+		0,
+		uintptr(unsafe.Pointer(&insert)),
+	)
+}
+
+func (*winAPI) setTreeItem(handle, item uintptr, info treeItem) {
+	tv := newTVItem(info)
+	tv.mask |= tvifHandle
+	tv.item = item
+	w32.SendMessage(w32.HWND(handle), tvmSetItemW, 0, uintptr(unsafe.Pointer(&tv)))
+}
+
+func (*winAPI) deleteTreeItem(handle, item uintptr) {
+	w32.SendMessage(w32.HWND(handle), tvmDeleteItem, 0, item)
+}
+
+func (*winAPI) expandTreeItem(handle, item uintptr, expand bool) {
+	var action uintptr = tveCollapse
+	if expand {
+		action = tveExpand
+	}
+	w32.SendMessage(w32.HWND(handle), tvmExpand, action, item)
+}
+
+func (*winAPI) selectTreeItem(handle, item uintptr) {
+	w32.SendMessage(w32.HWND(handle), tvmSelectItem, tvgnCaret, item)
+}
+
+// onNotify handles the WM_NOTIFY messages of the tree view and returns the
+// message result.
+func (t *TreeView) onNotify(code uint32, lParam uintptr) uintptr {
+	switch code {
+	case tvnSelChangedW:
+		nm := (*nmTreeView)(unsafe.Pointer(lParam))
+		t.nodeSelected(t.handles[nm.itemNew.item])
+	case tvnItemExpandingW:
+		nm := (*nmTreeView)(unsafe.Pointer(lParam))
+		if n := t.handles[nm.itemNew.item]; n != nil && nm.action&tveExpand != 0 {
+			n.loadChildren()
+		}
+	case tvnItemExpandedW:
+		nm := (*nmTreeView)(unsafe.Pointer(lParam))
+		if n := t.handles[nm.itemNew.item]; n != nil {
+			t.nodeExpanded(n, nm.itemNew.state&tvisExpanded != 0)
+		}
+	case tvnBeginLabelEditW:
+		// Returning 1 cancels the edit.
+		if !t.editableLabels {
+			return 1
+		}
+	case tvnEndLabelEditW:
+		info := (*nmTVDispInfo)(unsafe.Pointer(lParam))
+		// The text is nil if the user cancelled the edit. Returning 1 accepts
+		// the new text.
+		n := t.handles[info.item.item]
+		if n != nil && info.item.text != nil &&
+			t.labelEdited(n, utf16PtrToString(info.item.text)) {
+			return 1
+		}
+	case tvnItemChangedW:
+		change := (*nmTVItemChange)(unsafe.Pointer(lParam))
+		if n := t.handles[change.item]; n != nil && t.hasCheckBoxes {
+			t.nodeChecked(n, change.stateNew&tvisStateImageMask == 2<<12)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,199 @@
+package wui
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rule checks the text of a control. It returns nil if the text is valid or an
+// error whose message tells the user what is wrong. You can write your own
+// rules as functions, e.g.
+//
+//	noSpaces := func(text string) error {
+//		if strings.Contains(text, " ") {
+//			return errors.New("Spaces are not allowed.")
+//		}
+//		return nil
+//	}
+type Rule func(text string) error
+
+// Required accepts all texts that are not empty or only white space.
+func Required() Rule {
+	return func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return errors.New("A value is required.")
+		}
+		return nil
+	}
+}
+
+// Match accepts texts that match the regular expression. If they do not, the
+// error has the given message.
+func Match(re *regexp.Regexp, message string) Rule {
+	return func(text string) error {
+		if !re.MatchString(text) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// Number accepts texts that are numbers. A comma is accepted as the decimal
+// separator, like in FloatUpDowns.
+func Number() Rule {
+	return func(text string) error {
+		_, err := parseNumber(text)
+		return err
+	}
+}
+
+// Range accepts numbers in the range [min..max].
+func Range(min, max float64) Rule {
+	return func(text string) error {
+		x, err := parseNumber(text)
+		if err != nil {
+			return err
+		}
+		if x < min || x > max {
+			return fmt.Errorf("The value must be between %v and %v.", min, max)
+		}
+		return nil
+	}
+}
+
+func parseNumber(text string) (float64, error) {
+	t := strings.Replace(strings.TrimSpace(text), ",", ".", 1)
+	x, err := strconv.ParseFloat(t, 64)
+	if err != nil {
+		return 0, errors.New("The value must be a number.")
+	}
+	return x, nil
+}
+
+// Validatable is a control whose text can be checked by Rules. These are
+// EditLine, TextEdit, IntUpDown and FloatUpDown.
+type Validatable interface {
+	Control
+	Text() string
+	ErrorText() string
+	SetErrorText(text string)
+	Focus()
+}
+
+// ValidationError is a failed Rule or check of a Form.
+type ValidationError struct {
+	// Control is the first control that the error belongs to. It is nil for
+	// checks of the Form that were added without controls.
+	Control Control
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// NewForm returns a Form without rules.
+func NewForm() *Form {
+	return &Form{}
+}
+
+// Form is a set of Rules for controls, together with checks that concern
+// multiple controls, like two passwords that must be equal. Call Validate
+// before using the values in the controls, e.g. when the user clicks a save
+// button.
+type Form struct {
+	fields []formField
+	checks []formCheck
+}
+
+type formField struct {
+	control Validatable
+	rules   []Rule
+}
+
+type formCheck struct {
+	check    func() error
+	controls []Validatable
+}
+
+// Add adds rules for the control. They are checked in the given order, the
+// first rule that fails sets the error. Adding a control again appends the
+// rules to the existing ones.
+func (f *Form) Add(c Validatable, rules ...Rule) {
+	for i := range f.fields {
+		if f.fields[i].control == c {
+			f.fields[i].rules = append(f.fields[i].rules, rules...)
+			return
+		}
+	}
+	f.fields = append(f.fields, formField{control: c, rules: rules})
+}
+
+// AddCheck adds a check that is run after the Rules of the controls. If it
+// fails, all the given controls are marked as invalid, unless they already
+// have an error of their own.
+func (f *Form) AddCheck(check func() error, controls ...Validatable) {
+	f.checks = append(f.checks, formCheck{check: check, controls: controls})
+}
+
+// Validate runs all Rules and checks and returns the errors in the order that
+// the controls and checks were added. It returns nil if everything is valid.
+//
+// Invalid controls are marked with their error message, see
+// Validatable.SetErrorText, and all other controls are unmarked. The first
+// invalid control gets the keyboard focus.
+func (f *Form) Validate() []ValidationError {
+	var errs []ValidationError
+	messages := make(map[Validatable]string)
+	var invalid []Validatable
+
+	mark := func(c Validatable, message string) {
+		if _, ok := messages[c]; !ok {
+			messages[c] = message
+			invalid = append(invalid, c)
+		}
+	}
+
+	for _, field := range f.fields {
+		for _, rule := range field.rules {
+			if err := rule(field.control.Text()); err != nil {
+				errs = append(errs, ValidationError{
+					Control: field.control,
+					Message: err.Error(),
+				})
+				mark(field.control, err.Error())
+				break
+			}
+		}
+	}
+
+	for _, check := range f.checks {
+		if err := check.check(); err != nil {
+			e := ValidationError{Message: err.Error()}
+			if len(check.controls) > 0 {
+				e.Control = check.controls[0]
+			}
+			errs = append(errs, e)
+			for _, c := range check.controls {
+				mark(c, err.Error())
+			}
+		}
+	}
+
+	for _, field := range f.fields {
+		field.control.SetErrorText(messages[field.control])
+	}
+	for _, check := range f.checks {
+		for _, c := range check.controls {
+			c.SetErrorText(messages[c])
+		}
+	}
+	if len(invalid) > 0 {
+		invalid[0].Focus()
+	}
+
+	return errs
+}
@@ -0,0 +1,94 @@
+package wui
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/gonutz/check"
+)
+
+func TestRules(t *testing.T) {
+	valid := func(r Rule, text string) bool { return r(text) == nil }
+
+	check.Eq(t, valid(Required(), "x"), true)
+	check.Eq(t, valid(Required(), " \t"), false)
+
+	zip := Match(regexp.MustCompile(`^[0-9]{5}$`), "Enter 5 digits.")
+	check.Eq(t, valid(zip, "12345"), true)
+	check.Eq(t, zip("1234").Error(), "Enter 5 digits.")
+
+	check.Eq(t, valid(Number(), "-1,5"), true)
+	check.Eq(t, Number()("1.5.3").Error(), "The value must be a number.")
+
+	check.Eq(t, valid(Range(1, 10), "1"), true)
+	check.Eq(t, valid(Range(1, 10), " 10 "), true)
+	check.Eq(t, Range(1, 10)("0.5").Error(), "The value must be between 1 and 10.")
+	check.Eq(t, Range(1, 10)("ten").Error(), "The value must be a number.")
+}
+
+func TestFormValidatesFieldsAndChecks(t *testing.T) {
+	UseHeadless()
+
+	name := NewEditLine()
+	age := NewIntUpDown()
+	password := NewEditLine()
+	repeat := NewEditLine()
+
+	f := NewForm()
+	f.Add(name, Required())
+	f.Add(age, Range(0, 150))
+	f.Add(password, Required())
+	f.AddCheck(func() error {
+		if password.Text() != repeat.Text() {
+			return errors.New("The passwords differ.")
+		}
+		return nil
+	}, password, repeat)
+
+	password.SetText("secret")
+	errs := f.Validate()
+	check.Eq(t, errs, []ValidationError{
+		{Control: name, Message: "A value is required."},
+		{Control: age, Message: "The value must be a number."},
+		{Control: password, Message: "The passwords differ."},
+	})
+	check.Eq(t, name.ErrorText(), "A value is required.")
+	check.Eq(t, password.ErrorText(), "The passwords differ.")
+	check.Eq(t, repeat.ErrorText(), "The passwords differ.")
+
+	name.SetText("Bob")
+	age.SetText("30")
+	repeat.SetText("secret")
+	check.Eq(t, len(f.Validate()), 0)
+	check.Eq(t, name.ErrorText(), "")
+	check.Eq(t, repeat.ErrorText(), "")
+}
+
+func TestErrorTextIsShownAndFirstInvalidControlFocused(t *testing.T) {
+	h := UseHeadless()
+
+	w := NewWindow()
+	first := NewEditLine()
+	first.SetText("ok")
+	w.Add(first)
+	second := NewFloatUpDown()
+	w.Add(second)
+	second.SetErrorText("set before showing")
+
+	f := NewForm()
+	f.Add(first, Required())
+	f.Add(second, func(string) error { return errors.New("custom") })
+
+	w.SetOnShow(func() {
+		native, _ := h.Control(second.Handle())
+		check.Eq(t, native.ErrorText, "set before showing")
+
+		f.Validate()
+		native, _ = h.Control(second.Handle())
+		check.Eq(t, native.ErrorText, "custom")
+		check.Eq(t, second.HasFocus(), true)
+		w.Close()
+	})
+	w.Show()
+}
@@ -2,41 +2,10 @@ package wui
 
 import (
 	"errors"
-	"io/ioutil"
-	"os"
-	"runtime"
-	"syscall"
-	"unicode/utf16"
-	"unsafe"
-
-	"github.com/gonutz/w32/v2"
-)
-
-var windows windowStack
+	"time"
 
-// TODO windowStack used to have a mutex but the solution was never complete.
-// Try to make the system as a whole safe for concurrent access. This is not the
-// highest priority. Make it work synchronously first.
-type windowStack struct {
-	windows []*Window
-}
-
-func (s *windowStack) top() *Window {
-	if len(s.windows) == 0 {
-		return nil
-	}
-	return s.windows[len(s.windows)-1]
-}
-
-func (s *windowStack) push(w *Window) {
-	s.windows = append(s.windows, w)
-}
-
-func (s *windowStack) pop() {
-	if len(s.windows) > 0 {
-		s.windows = s.windows[:len(s.windows)-1]
-	}
-}
+	"github.com/gonutz/wui/v2/internal/win"
+)
 
 type WindowState int
 
@@ -59,17 +28,6 @@ func (s WindowState) String() string {
 	}
 }
 
-func (s WindowState) toCmd() int {
-	switch s {
-	case WindowMaximized:
-		return w32.SW_MAXIMIZE
-	case WindowMinimized:
-		return w32.SW_MINIMIZE
-	default:
-		return w32.SW_SHOWNORMAL
-	}
-}
-
 func NewWindow() *Window {
 	w := &Window{
 		className:  "wui_window",
@@ -82,8 +40,9 @@ func NewWindow() *Window {
 }
 
 type Window struct {
+	dropHandlers
 	className        string
-	handle           w32.HWND
+	handle           uintptr
 	parent           *Window
 	hidesBorder      bool
 	fixedSize        bool
@@ -101,28 +60,45 @@ type Window struct {
 	background       Color
 	cursor           *Cursor
 	menu             *Menu
-	menuStrings      []*MenuString
+	menuBar          *menuTree
+	popupMenu        *menuTree
+	statusBar        *StatusBar
 	font             *Font
 	controls         []Control
 	children         []Control
+	layout           Layout
+	dpi              int
+	onDPIChange      Event
 	icon             *Icon
 	showConsole      bool
 	altF4disabled    bool
 	shortcuts        []shortcut
-	accelTable       w32.HACCEL
-	lastFocus        w32.HWND
+	accelTable       uintptr
+	lastFocus        uintptr
 	alpha            uint8
-	onShow           func()
-	onClose          func()
-	onCanClose       func() bool
-	onMouseMove      func(x, y int)
-	onMouseWheel     func(x, y int, delta float64)
-	onMouseDown      func(button MouseButton, x, y int)
-	onMouseUp        func(button MouseButton, x, y int)
-	onKeyDown        func(key int)
-	onKeyUp          func(key int)
-	onChar           func(r rune)
-	onResize         func()
+	calls            callQueue
+	timers           []*Timer
+	lastTimerID      uintptr
+	trayIcons        []*TrayIcon
+	hotKeys          []*hotKey
+	lastHotKeyID     int
+	lastTrayIconID   uint
+	toolTipHandle    uintptr
+	toolTipDelay     time.Duration
+	toolTipDuration  time.Duration
+	toolTipBalloon   bool
+	contextMenu      *Menu
+	onShow           Event
+	onClose          Event
+	onCanClose       VetoEvent
+	onMouseMove      PointEvent
+	onMouseWheel     WheelEvent
+	onMouseDown      MouseEvent
+	onMouseUp        MouseEvent
+	onKeyDown        IntEvent
+	onKeyUp          IntEvent
+	onChar           RuneEvent
+	onResize         Event
 	onMessage        MessageCallback
 }
 
@@ -160,6 +136,10 @@ type Control interface {
 	Handle() uintptr
 	Visible() bool
 	Enabled() bool
+	TabIndex() int
+	TabStop() bool
+	ToolTip() string
+	ContextMenu() *Menu
 
 	setParent(parent Container)
 	create(id int)
@@ -168,6 +148,8 @@ type Control interface {
 	canFocus() bool
 	wasFocussedWithTab()
 	eatsTabs() bool
+	getDropHandlers() *dropHandlers
+	toolTipText() string
 	closing()
 	destroy()
 }
@@ -180,31 +162,25 @@ type Container interface {
 	Bounds() (x, y, width, height int)
 	SetBounds(x, y, width, height int)
 	InnerBounds() (x, y, width, height int)
+	Layout() Layout
 	Font() *Font
 	Visible() bool
 	Enabled() bool
 
 	setParent(parent Container)
-	getHandle() w32.HWND
-	getInstance() w32.HINSTANCE
-	onWM_COMMAND(w, l uintptr)
-	onWM_DRAWITEM(w, l uintptr)
-	onWM_NOTIFY(w, l uintptr)
+	getHandle() uintptr
 	getIDFor(c Control) int
+	getDPI() int
 }
 
 func (*Window) setParent(parent Container) {
 	// TODO This is just to implement the container interface.
 }
 
-func (w *Window) getHandle() w32.HWND {
+func (w *Window) getHandle() uintptr {
 	return w.handle
 }
 
-func (w *Window) getInstance() w32.HINSTANCE {
-	return w32.HINSTANCE(w32.GetWindowLong(w.handle, w32.GWL_HINSTANCE))
-}
-
 func (w *Window) ClassName() string {
 	return w.className
 }
@@ -224,7 +200,7 @@ func (w *Window) Title() string { return w.title }
 func (w *Window) SetTitle(title string) {
 	w.title = title
 	if w.handle != 0 {
-		w32.SetWindowText(w.handle, title)
+		ui.setText(w.handle, title)
 	}
 }
 
@@ -232,21 +208,21 @@ func (w *Window) style() uint {
 	var s uint
 
 	if w.hidesBorder {
-		s |= w32.WS_POPUP
+		s |= win.WS_POPUP
 	} else {
-		s |= w32.WS_CAPTION | w32.WS_SYSMENU
+		s |= win.WS_CAPTION | win.WS_SYSMENU
 	}
 
 	if !w.fixedSize {
-		s |= w32.WS_SIZEBOX
+		s |= win.WS_SIZEBOX
 	}
 
 	if !w.hidesMinButton {
-		s |= w32.WS_MINIMIZEBOX
+		s |= win.WS_MINIMIZEBOX
 	}
 
 	if !w.hidesMaxButton {
-		s |= w32.WS_MAXIMIZEBOX
+		s |= win.WS_MAXIMIZEBOX
 	}
 
 	return s
@@ -254,17 +230,14 @@ func (w *Window) style() uint {
 
 func (w *Window) extendedStyle() uint {
 	if w.alpha != 255 {
-		return w32.WS_EX_LAYERED
+		return win.WS_EX_LAYERED
 	}
 	return 0
 }
 
 func (w *Window) readBounds() {
-	r := w32.GetWindowRect(w.handle)
-	w.x = int(r.Left)
-	w.y = int(r.Top)
-	w.width = int(r.Width())
-	w.height = int(r.Height())
+	x, y, width, height := ui.bounds(w.handle)
+	w.x, w.y, w.width, w.height = unscaleBounds(x, y, width, height, w.DPI())
 }
 
 func (w *Window) X() int {
@@ -338,11 +311,7 @@ func (w *Window) SetBounds(x, y, width, height int) {
 	if w.handle != 0 {
 		// The window will receive a WM_SIZE which will handle anchoring child
 		// controls.
-		w32.SetWindowPos(
-			w.handle, 0,
-			x, y, width, height,
-			w32.SWP_NOOWNERZORDER|w32.SWP_NOZORDER,
-		)
+		w.applyBounds(x, y, width, height)
 	} else {
 		oldW, oldH := w.InnerSize()
 		w.x = x
@@ -350,6 +319,16 @@ func (w *Window) SetBounds(x, y, width, height int) {
 		w.width = width
 		w.height = height
 		newW, newH := w.InnerSize()
+		w.repositionChildren(oldW, oldH, newW, newH)
+	}
+}
+
+// repositionChildren applies the layout if the window has one. Otherwise the
+// children are moved according to their anchors.
+func (w *Window) repositionChildren(oldW, oldH, newW, newH int) {
+	if w.layout != nil {
+		relayout(w)
+	} else {
 		repositionChidrenByAnchors(w, oldW, oldH, newW, newH)
 	}
 }
@@ -459,58 +438,47 @@ func (w *Window) SetInnerSize(width, height int) {
 
 func (w *Window) InnerBounds() (x, y, width, height int) {
 	if w.handle != 0 {
-		x, y = w32.ClientToScreen(w.handle, 0, 0)
-		r := w32.GetClientRect(w.handle)
-		width = int(r.Width())
-		height = int(r.Height())
+		x, y, width, height = ui.clientBounds(w.handle)
+		x, y, width, height = unscaleBounds(x, y, width, height, w.DPI())
 	} else {
 		x, y = w.Position()
-		var r w32.RECT
-		w32.AdjustWindowRectEx(&r, w.style(), w.menu != nil, w.extendedStyle())
-		x -= int(r.Left)
-		y -= int(r.Top)
-		width = w.width - int(r.Width())
-		height = w.height - int(r.Height())
+		left, top, right, bottom := ui.frameSize(
+			w.style(), w.extendedStyle(), w.menu != nil,
+		)
+		x += left
+		y += top
+		width = w.width - (left + right)
+		height = w.height - (top + bottom)
 	}
+	// The status bar covers the bottom of the client area.
+	height -= w.statusBarHeight()
 	return
 }
 
 func (w *Window) SetInnerBounds(x, y, width, height int) {
-	var r w32.RECT
-	w32.AdjustWindowRectEx(&r, w.style(), w.menu != nil, w.extendedStyle())
-	w.x = x + int(r.Left)
-	w.y = y + int(r.Top)
-	w.width = width + int(r.Width())
-	w.height = height + int(r.Height())
+	left, top, right, bottom := ui.frameSize(
+		w.style(), w.extendedStyle(), w.menu != nil,
+	)
+	w.x = x - left
+	w.y = y - top
+	w.width = width + left + right
+	w.height = height + top + bottom + w.statusBarHeight()
 	if w.handle != 0 {
-		w32.SetWindowPos(
-			w.handle, 0,
-			w.x, w.y, w.width, w.height,
-			w32.SWP_NOOWNERZORDER|w32.SWP_NOZORDER,
-		)
+		w.applyBounds(w.x, w.y, w.width, w.height)
 	}
 }
 
 func (w *Window) SetState(s WindowState) {
 	w.state = s
 	if w.handle != 0 {
-		w32.ShowWindow(w.handle, s.toCmd())
+		ui.setWindowState(w.handle, s)
 	}
 }
 
 func (w *Window) State() WindowState {
 	if w.handle != 0 {
-		var p w32.WINDOWPLACEMENT
-		if w32.GetWindowPlacement(w.handle, &p) {
-			switch p.ShowCmd {
-			case w32.SW_MAXIMIZE:
-				w.state = WindowMaximized
-			case w32.SW_SHOWMINIMIZED, w32.SW_MINIMIZE,
-				w32.SW_SHOWMINNOACTIVE, w32.SW_FORCEMINIMIZE:
-				w.state = WindowMinimized
-			default:
-				w.state = WindowNormal
-			}
+		if s, ok := ui.windowState(w.handle); ok {
+			w.state = s
 		}
 	}
 	return w.state
@@ -523,9 +491,7 @@ func (w *Window) GetBackground() Color {
 func (w *Window) SetBackground(c Color) {
 	w.background = c
 	if w.handle != 0 {
-		brush := w32.CreateSolidBrush(uint32(c))
-		w32.SetClassLongPtr(w.handle, w32.GCLP_HBRBACKGROUND, uintptr(brush))
-		w32.InvalidateRect(w.handle, nil, true)
+		ui.setBackground(w.handle, c)
 	}
 }
 
@@ -534,7 +500,7 @@ func (w *Window) Cursor() *Cursor { return w.cursor }
 func (w *Window) SetCursor(c *Cursor) {
 	w.cursor = c
 	if w.handle != 0 {
-		w32.SetClassLongPtr(w.handle, w32.GCLP_HCURSOR, uintptr(c.handle))
+		ui.setCursor(w.handle, c.handle)
 	}
 }
 
@@ -542,22 +508,89 @@ func (w *Window) Menu() *Menu {
 	return w.menu
 }
 
+// SetMenu sets the menu bar of the window, nil removes it.
 func (w *Window) SetMenu(m *Menu) {
 	w.menu = m
 	if w.handle != 0 {
-		// TODO update menu
+		// The old menu's items must forget the window first, in case the new
+		// menu has the same shortcuts.
+		old := w.menuBar
+		if old != nil {
+			old.detach()
+		}
+		w.menuBar = nil
+		var bar uintptr
+		if m != nil {
+			w.menuBar = newMenuTree(w, m, true)
+			bar = w.menuBar.handle
+		}
+		ui.setMenuBar(w.handle, bar)
+		if old != nil {
+			ui.destroyMenu(old.handle)
+		}
+	}
+}
+
+func (w *Window) StatusBar() *StatusBar {
+	return w.statusBar
+}
+
+// SetStatusBar attaches the status bar to the bottom of the window, nil removes
+// it. A status bar can only be attached to one window at a time. The window
+// keeps its size and the inner area shrinks or grows, the children are moved by
+// their anchors.
+func (w *Window) SetStatusBar(s *StatusBar) {
+	if s == w.statusBar {
+		return
+	}
+	if s != nil && s.window != nil {
+		s.window.SetStatusBar(nil)
+	}
+	oldW, oldH := w.InnerSize()
+	if old := w.statusBar; old != nil {
+		old.destroy()
+		old.window = nil
+	}
+	w.statusBar = s
+	if s != nil {
+		s.window = w
+		if w.handle != 0 {
+			s.create()
+		}
+	}
+	if w.handle != 0 {
+		w.resized(w.state)
+	} else {
+		newW, newH := w.InnerSize()
+		w.repositionChildren(oldW, oldH, newW, newH)
 	}
 }
 
+// statusBarHeight returns the height of the window's status bar, 0 if it has
+// none.
+func (w *Window) statusBarHeight() int {
+	if w.statusBar == nil {
+		return 0
+	}
+	return w.statusBar.height()
+}
+
 func (w *Window) Font() *Font {
 	return w.font
 }
 
 func (w *Window) SetFont(f *Font) {
+	old := w.font
 	w.font = f
+	if w.statusBar != nil {
+		w.statusBar.applyFont()
+	}
 	for _, c := range w.children {
 		c.parentFontChanged()
 	}
+	if old != f {
+		releaseScaledFonts(old)
+	}
 }
 
 func (w *Window) Add(c Control) {
@@ -569,19 +602,33 @@ func (w *Window) Add(c Control) {
 	if w.handle != 0 {
 		c.create(w.getIDFor(c))
 	}
+	relayout(w)
 }
 
 func (w *Window) Remove(c Control) {
 	for i, child := range w.children {
 		if child == c {
+			forgetChild(w, child)
 			child.setParent(nil)
 			child.destroy()
 			w.children = append(w.children[:i], w.children[i+1:]...)
+			relayout(w)
 			return
 		}
 	}
 }
 
+func (w *Window) Layout() Layout {
+	return w.layout
+}
+
+// SetLayout makes the layout position the window's children, see Layout. Pass
+// nil to position them by their anchors again. A layout can only be used by one
+// container at a time, setting it here removes it from its previous container.
+func (w *Window) SetLayout(l Layout) {
+	setLayout(w, &w.layout, l)
+}
+
 func (w *Window) getIDFor(c Control) int {
 	for i := range w.controls {
 		if c == w.controls[i] {
@@ -593,94 +640,140 @@ func (w *Window) getIDFor(c Control) int {
 }
 
 func (w *Window) OnShow() func() {
-	return w.onShow
+	return w.onShow.primary
 }
 
 func (w *Window) SetOnShow(f func()) {
-	w.onShow = f
+	w.onShow.primary = f
+}
+
+func (w *Window) ShowEvent() *Event {
+	return &w.onShow
 }
 
 func (w *Window) OnClose() func() {
-	return w.onClose
+	return w.onClose.primary
 }
 
 func (w *Window) SetOnClose(f func()) {
-	w.onClose = f
+	w.onClose.primary = f
+}
+
+func (w *Window) CloseEvent() *Event {
+	return &w.onClose
 }
 
 func (w *Window) OnCanClose() func() bool {
-	return w.onCanClose
+	return w.onCanClose.primary
 }
 
 // SetOnCanClose is passed a function that is called when the window is about to
 // be closed, e.g. when the user hits Alt+F4. If f returns true the window is
 // closed, if f returns false, the window stays open.
 func (w *Window) SetOnCanClose(f func() bool) {
-	w.onCanClose = f
+	w.onCanClose.primary = f
+}
+
+// CanCloseEvent lets more than one function decide whether the window may be
+// closed. It stays open if OnCanClose or any subscribed function returns false.
+func (w *Window) CanCloseEvent() *VetoEvent {
+	return &w.onCanClose
 }
 
 func (w *Window) OnMouseMove() func(x, y int) {
-	return w.onMouseMove
+	return w.onMouseMove.primary
 }
 
 func (w *Window) SetOnMouseMove(f func(x, y int)) {
-	w.onMouseMove = f
+	w.onMouseMove.primary = f
+}
+
+func (w *Window) MouseMoveEvent() *PointEvent {
+	return &w.onMouseMove
 }
 
 func (w *Window) OnMouseWheel() func(x, y int, delta float64) {
-	return w.onMouseWheel
+	return w.onMouseWheel.primary
 }
 
 func (w *Window) SetOnMouseWheel(f func(x, y int, delta float64)) {
-	w.onMouseWheel = f
+	w.onMouseWheel.primary = f
+}
+
+func (w *Window) MouseWheelEvent() *WheelEvent {
+	return &w.onMouseWheel
 }
 
 func (w *Window) OnMouseDown() func(button MouseButton, x, y int) {
-	return w.onMouseDown
+	return w.onMouseDown.primary
 }
 
 func (w *Window) SetOnMouseDown(f func(button MouseButton, x, y int)) {
-	w.onMouseDown = f
+	w.onMouseDown.primary = f
+}
+
+func (w *Window) MouseDownEvent() *MouseEvent {
+	return &w.onMouseDown
 }
 
 func (w *Window) OnMouseUp() func(button MouseButton, x, y int) {
-	return w.onMouseUp
+	return w.onMouseUp.primary
 }
 
 func (w *Window) SetOnMouseUp(f func(button MouseButton, x, y int)) {
-	w.onMouseUp = f
+	w.onMouseUp.primary = f
+}
+
+func (w *Window) MouseUpEvent() *MouseEvent {
+	return &w.onMouseUp
 }
 
 func (w *Window) OnKeyDown() func(key int) {
-	return w.onKeyDown
+	return w.onKeyDown.primary
 }
 
 func (w *Window) SetOnKeyDown(f func(key int)) {
-	w.onKeyDown = f
+	w.onKeyDown.primary = f
+}
+
+func (w *Window) KeyDownEvent() *IntEvent {
+	return &w.onKeyDown
 }
 
 func (w *Window) OnKeyUp() func(key int) {
-	return w.onKeyUp
+	return w.onKeyUp.primary
 }
 
 func (w *Window) SetOnKeyUp(f func(key int)) {
-	w.onKeyUp = f
+	w.onKeyUp.primary = f
+}
+
+func (w *Window) KeyUpEvent() *IntEvent {
+	return &w.onKeyUp
 }
 
 func (w *Window) SetOnChar(f func(r rune)) {
-	w.onChar = f
+	w.onChar.primary = f
+}
+
+func (w *Window) CharEvent() *RuneEvent {
+	return &w.onChar
 }
 
 func (w *Window) OnChar() func(r rune) {
-	return w.onChar
+	return w.onChar.primary
 }
 
 func (w *Window) OnResize() func() {
-	return w.onResize
+	return w.onResize.primary
 }
 
 func (w *Window) SetOnResize(f func()) {
-	w.onResize = f
+	w.onResize.primary = f
+}
+
+func (w *Window) ResizeEvent() *Event {
+	return &w.onResize
 }
 
 // MessageCallback is used as a hook into the main window procedure. It will run
@@ -702,47 +795,22 @@ func (w *Window) SetOnMessage(f MessageCallback) {
 
 func (w *Window) Close() {
 	if w.handle != 0 {
-		w32.SendMessage(w.handle, w32.WM_CLOSE, 0, 0)
+		ui.close(w.handle)
 	}
 }
 
-func (w *Window) interceptMessage(msg *w32.MSG) bool {
-	if msg.Message == w32.WM_KEYDOWN && msg.WParam == w32.VK_TAB {
-		focus := uintptr(w32.GetFocus())
-		cur := func() int {
-			for i := range w.controls {
-				if w.controls[i].Handle() == focus {
-					return i
-				}
-			}
-			return -1
-		}()
-		if cur != -1 && w.controls[cur].eatsTabs() {
-			return false
-		}
-		shiftDown := w32.GetKeyState(w32.VK_SHIFT)&0x8000 != 0
-		nth := func(i int) int {
-			return (cur + 1 + i) % len(w.controls)
-		}
-		if shiftDown {
-			nth = func(i int) int {
-				return (cur + len(w.controls) - 1 - i) % len(w.controls)
-			}
-		}
-		for i := range w.controls {
-			j := nth(i)
-			if w.controls[j].Parent() != nil &&
-				w.controls[j].canFocus() &&
-				Visible(w.controls[j]) &&
-				Enabled(w.controls[j]) {
-				w32.SetFocus(w32.HWND(w.controls[j].Handle()))
-				w.controls[j].wasFocussedWithTab()
-				return true
-			}
-		}
-		return true
+// tabPressed moves the keyboard focus to the next or previous control. It
+// returns false if the Tab key should instead go to the focused control, e.g. a
+// TextEdit that writes tabs.
+func (w *Window) tabPressed(shiftDown bool) bool {
+	cur := w.focusedIndex()
+	if cur != -1 && w.controls[cur].eatsTabs() {
+		return false
 	}
-	return false
+	if c := w.moveFocus(shiftDown); c != nil {
+		c.wasFocussedWithTab()
+	}
+	return true
 }
 
 // Visible return true if the given control and all of its parents are visible.
@@ -771,288 +839,165 @@ type EnabledControl interface {
 	Parent() Container
 }
 
-func (w *Window) onMsg(window w32.HWND, msg uint32, wParam, lParam uintptr) uintptr {
-	if w.onMessage != nil {
-		handled, result := w.onMessage(uintptr(window), msg, wParam, lParam)
-		if handled {
-			return result
-		}
+// resized repositions the child controls according to their anchors after the
+// window's inner size changed. state is the new window state.
+func (w *Window) resized(state WindowState) {
+	if w.statusBar != nil {
+		w.statusBar.applyParts()
 	}
+	oldW, oldH := w.lastInnerWidth, w.lastInnerHeight
+	newW, newH := w.InnerSize()
+	w.repositionChildren(oldW, oldH, newW, newH)
+	w.lastInnerWidth, w.lastInnerHeight = newW, newH
+	w.onResize.fire()
+	w.state = state
+}
 
-	mouseX := int(lParam & 0xFFFF)
-	mouseY := int(lParam&0xFFFF0000) >> 16
-	switch msg {
-	case w32.WM_MOUSEMOVE:
-		if w.onMouseMove != nil {
-			w.onMouseMove(mouseX, mouseY)
-			return 0
-		}
-	case w32.WM_MOUSEWHEEL:
-		if w.onMouseWheel != nil {
-			delta := float64(int16((wParam&0xFFFF0000)>>16)) / 120
-			w.onMouseWheel(mouseX, mouseY, delta)
-		}
-		return 0
-	case w32.WM_LBUTTONDOWN, w32.WM_MBUTTONDOWN, w32.WM_RBUTTONDOWN:
-		if w.onMouseDown != nil {
-			b := MouseButtonLeft
-			if msg == w32.WM_MBUTTONDOWN {
-				b = MouseButtonMiddle
-			}
-			if msg == w32.WM_RBUTTONDOWN {
-				b = MouseButtonRight
-			}
-			w.onMouseDown(b, mouseX, mouseY)
-		}
-		return 0
-	case w32.WM_LBUTTONUP, w32.WM_MBUTTONUP, w32.WM_RBUTTONUP:
-		if w.onMouseUp != nil {
-			b := MouseButtonLeft
-			if msg == w32.WM_MBUTTONUP {
-				b = MouseButtonMiddle
-			}
-			if msg == w32.WM_RBUTTONUP {
-				b = MouseButtonRight
-			}
-			w.onMouseUp(b, mouseX, mouseY)
-		}
-		return 0
-	case w32.WM_DRAWITEM:
-		w.onWM_DRAWITEM(wParam, lParam)
-		return 0
-	case w32.WM_KEYDOWN:
-		if w.onKeyDown != nil {
-			w.onKeyDown(int(wParam))
-			return 0
-		}
-	case w32.WM_KEYUP:
-		if w.onKeyUp != nil {
-			w.onKeyUp(int(wParam))
-			return 0
-		}
-	case w32.WM_CHAR:
-		if w.onChar != nil {
-			w.onChar(utf16.Decode([]uint16{uint16(wParam)})[0])
-			return 0
-		}
-	case w32.WM_COMMAND:
-		w.onWM_COMMAND(wParam, lParam)
-		return 0
-	case w32.WM_SYSCOMMAND:
-		if w.altF4disabled && wParam == w32.SC_CLOSE && (lParam>>16) <= 0 {
-			return 0
-		}
-		return w32.DefWindowProc(window, msg, wParam, lParam)
-	case w32.WM_NOTIFY:
-		w.onWM_NOTIFY(wParam, lParam)
-		return 0
-	case w32.WM_SIZE:
-		oldW, oldH := w.lastInnerWidth, w.lastInnerHeight
-		newW, newH := w.InnerSize()
-		repositionChidrenByAnchors(w, oldW, oldH, newW, newH)
-		w.lastInnerWidth, w.lastInnerHeight = newW, newH
-		if w.onResize != nil {
-			w.onResize()
-		}
-		w32.InvalidateRect(window, nil, true)
-		switch wParam {
-		case w32.SIZE_MAXIMIZED:
-			w.state = WindowMaximized
-		case w32.SIZE_MINIMIZED:
-			w.state = WindowMinimized
-		case w32.SIZE_RESTORED:
-			w.state = WindowNormal
-		}
-		return 0
-	case w32.WM_ACTIVATE:
-		active := wParam != 0
-		if active {
-			if w.lastFocus != 0 {
-				w32.SetFocus(w.lastFocus)
-			}
-		} else {
-			w.lastFocus = w32.GetFocus()
-		}
-		return 0
-	case w32.WM_HSCROLL, w32.WM_VSCROLL:
-		for _, c := range w.controls {
-			if lParam == c.Handle() {
-				if s, ok := c.(*Slider); ok {
-					s.handleChange(wParam & 0xFFFF)
-				}
-			}
-		}
-	case w32.WM_DESTROY:
-		w32.PostQuitMessage(0)
-		return 0
-	case w32.WM_CLOSE:
-		if w.onCanClose != nil {
-			if w.onCanClose() == false {
-				return 0
-			}
-		}
-		if w.parent != nil {
-			w32.EnableWindow(w.parent.handle, true)
-			w32.SetForegroundWindow(w.parent.handle)
-		}
-		if w.onClose != nil {
-			w.onClose()
-		}
-		w.closing()
-	}
-	return w32.DefWindowProc(window, msg, wParam, lParam)
-}
-
-func (w *Window) onWM_DRAWITEM(wParam, lParam uintptr) {
-	index := wParam
-	if 0 <= index && index < uintptr(len(w.controls)) {
-		if p, ok := w.controls[index].(*PaintBox); ok {
-			if p.onPaint != nil {
-				drawItem := ((*w32.DRAWITEMSTRUCT)(unsafe.Pointer(lParam)))
-				// create a back buffer
-				p.backBuffer.setMinSize(drawItem.HDC, p.width, p.height)
-				bmpOld := w32.SelectObject(
-					p.backBuffer.dc,
-					w32.HGDIOBJ(p.backBuffer.bmp),
-				)
-				c := &Canvas{
-					hdc:    p.backBuffer.dc,
-					width:  p.width,
-					height: p.height,
-				}
-				if p.parent != nil {
-					c.SetFont(p.parent.Font())
-				}
-				c.ClearDrawRegions()
-				p.onPaint(c)
-
-				// blit the backbuffer to the front
-				w32.BitBlt(
-					drawItem.HDC, 0, 0, p.width, p.height,
-					p.backBuffer.dc, 0, 0, w32.SRCCOPY,
-				)
-				w32.SelectObject(p.backBuffer.dc, bmpOld)
-			}
+// activated saves the keyboard focus when the window is deactivated and
+// restores it when the window is activated again.
+func (w *Window) activated(active bool) {
+	if active {
+		app.active = w
+		if w.lastFocus != 0 {
+			ui.focus(w.lastFocus)
 		}
+	} else {
+		w.lastFocus = ui.focused()
 	}
 }
 
-func (w *Window) Show() error {
-	if w.handle != 0 {
-		return errors.New("wui.Window.Show: window already visible")
+// closeRequested is called when the user or the program wants to close the
+// window. It returns false if OnCanClose prevents the window from closing.
+func (w *Window) closeRequested() bool {
+	if !w.onCanClose.fire() {
+		return false
 	}
+	if w.parent != nil {
+		ui.setEnabled(w.parent.handle, true)
+		ui.setForeground(w.parent.handle)
+	}
+	w.onClose.fire()
+	w.closing()
+	return true
+}
 
-	if windows.top() != nil {
-		return errors.New("wui.Window.Show: another window is already visible")
+func (w *Window) menuClicked(id int) {
+	if w.menuBar != nil {
+		w.menuBar.clicked(uint(id))
 	}
-	windows.push(w)
-	defer windows.pop()
+}
 
-	runtime.LockOSThread()
-	if !w.showConsole {
-		hideConsoleWindow()
+// menuOpened is called by the backend before the native menu is shown, it
+// can be a sub-menu of the menu bar or a popup menu.
+func (w *Window) menuOpened(menu uintptr) {
+	if w.popupMenu != nil && w.popupMenu.opened(menu) {
+		return
 	}
-	setManifest()
+	if w.menuBar != nil {
+		w.menuBar.opened(menu)
+	}
+}
 
-	class := w32.WNDCLASSEX{
-		Background: w32.CreateSolidBrush(uint32(w.background)),
-		WndProc:    syscall.NewCallback(w.onMsg),
-		Cursor:     w.cursor.handle,
-		ClassName:  syscall.StringToUTF16Ptr(w.className),
+func (w *Window) shortcutPressed(index int) {
+	if 0 <= index && index < len(w.shortcuts) {
+		w.shortcuts[index].f()
 	}
-	atom := w32.RegisterClassEx(&class)
-	if atom == 0 {
-		return errors.New("wui.Window.Show: RegisterClassEx failed")
+}
+
+func (w *Window) controlNotified(index int, cmd uintptr) {
+	if 0 <= index && index < len(w.controls) {
+		w.controls[index].handleNotification(cmd)
 	}
-	defer w32.UnregisterClassAtom(atom, w32.GetModuleHandle(""))
+}
+
+func (w *Window) sliderScrolled(handle uintptr, reason uintptr) {
+	for _, c := range w.controls {
+		if handle == c.Handle() {
+			if s, ok := c.(*Slider); ok {
+				s.handleChange(reason)
+			}
+		}
+	}
+}
+
+// Show opens the window and processes user input until all windows are
+// closed, see Run. If other windows are already open, e.g. when calling Show
+// from an event handler, Show opens the window and returns right away. The
+// running message loop then handles this window as well.
+func (w *Window) Show() error {
+	first := len(app.windows) == 0 && !app.running
+	if err := w.open("wui.Window.Show: "); err != nil {
+		return err
+	}
+	if first {
+		Run()
+	}
+	return nil
+}
+
+// Open shows the window without waiting for it to be closed. Call Run to
+// process the user input for all open windows. Open must be called on the
+// same goroutine as Run.
+func (w *Window) Open() error {
+	return w.open("wui.Window.Open: ")
+}
+
+func (w *Window) open(errPrefix string) error {
+	if w.handle != 0 {
+		return errors.New(errPrefix + "window already visible")
+	}
+	w.parent = nil
 
 	// We remember the desired state, the window setup will make a WM_SIZE
 	// message with a restored window state arrive before we call ShowWindow.
 	state := w.state
 
-	window := w32.CreateWindowEx(
-		w.extendedStyle(),
-		syscall.StringToUTF16Ptr(w.className),
-		syscall.StringToUTF16Ptr(w.title),
-		w.style(),
-		w.x, w.y, w.width, w.height,
-		0, 0, 0, nil,
-	)
-	if window == 0 {
-		return errors.New("wui.Window.Show: CreateWindowEx failed")
-	}
-	w.handle = window
-	if w.alpha != 255 {
-		w32.SetLayeredWindowAttributes(w.handle, 0, w.alpha, w32.LWA_ALPHA)
-	}
-	if w.hidesCloseButton {
-		w32.EnableMenuItem(
-			w32.GetSystemMenu(w.handle, false),
-			w32.SC_CLOSE,
-			w32.MF_BYCOMMAND|w32.MF_DISABLED|w32.MF_GRAYED,
-		)
+	if err := ui.createWindow(w); err != nil {
+		return errors.New(errPrefix + err.Error())
 	}
+	app.add(w)
+	w.calls.open(w.handle)
+	w.setUp(state)
+	return nil
+}
 
+// setUp creates the window's contents after its handle was created and shows
+// it in the given state.
+func (w *Window) setUp(state WindowState) {
+	// The window was created with its logical bounds as pixels. Now that we
+	// know which monitor it is on, we scale it.
+	w.dpi = 0
+	w.lastInnerWidth, w.lastInnerHeight = w.InnerSize()
+	w.dpi = ui.dpi(w.handle)
+	if w.dpi != DefaultDPI {
+		w.applyBounds(w.x, w.y, w.width, w.height)
+	}
 	w.updateAccelerators()
 	w.lastInnerWidth, w.lastInnerHeight = w.InnerSize()
 	w.createContents()
+	w.startTimers()
+	w.showTrayIcons()
 	w.applyIcon()
-	w32.ShowWindow(window, state.toCmd())
-	w.readBounds()
-	if w.onShow != nil {
-		w.onShow()
-	}
-
-	var msg w32.MSG
-	for w32.GetMessage(&msg, 0, 0, 0) != 0 {
-		if !w.interceptMessage(&msg) {
-			if w.accelTable == 0 || !w32.TranslateAccelerator(w.handle, w.accelTable, &msg) {
-				w32.TranslateMessage(&msg)
-				w32.DispatchMessage(&msg)
-			}
-		}
+	ui.setWindowState(w.handle, state)
+	if w.parent != nil {
+		ui.setEnabled(w.parent.handle, false)
 	}
-
-	w.destroy()
-
-	return nil
+	w.readBounds()
+	w.onShow.fire()
 }
 
 func (w *Window) createContents() {
 	if w.menu != nil {
-		var addItems func(m w32.HMENU, items []MenuItem)
-		addItems = func(m w32.HMENU, items []MenuItem) {
-			for _, item := range items {
-				switch menuItem := item.(type) {
-				case *Menu:
-					menu := w32.CreateMenu()
-					w32.AppendMenu(m, w32.MF_POPUP, uintptr(menu), menuItem.name)
-					addItems(menu, menuItem.items)
-				case *MenuString:
-					id := uintptr(len(w.menuStrings))
-					w32.AppendMenu(
-						m,
-						w32.MF_STRING,
-						id,
-						menuItem.text,
-					)
-					menuItem.window = w.handle
-					menuItem.menu = m
-					menuItem.id = uint(id)
-					w.menuStrings = append(w.menuStrings, menuItem)
-				case menuSeparator:
-					w32.AppendMenu(m, w32.MF_SEPARATOR, 0, "")
-				}
-			}
-		}
-		menuBar := w32.CreateMenu()
-		addItems(menuBar, w.menu.items)
-		w32.SetMenu(w.handle, menuBar)
-		for _, m := range w.menuStrings {
-			if m.Checked() {
-				m.SetChecked(true)
-			}
-		}
+		w.menuBar = newMenuTree(w, w.menu, true)
+		ui.setMenuBar(w.handle, w.menuBar.handle)
+	}
+
+	if w.statusBar != nil {
+		w.statusBar.create()
+		// The native status bar might not have the estimated height.
+		oldW, oldH := w.lastInnerWidth, w.lastInnerHeight
+		w.lastInnerWidth, w.lastInnerHeight = w.InnerSize()
+		w.repositionChildren(oldW, oldH, w.lastInnerWidth, w.lastInnerHeight)
 	}
 
 	for _, c := range w.children {
@@ -1066,132 +1011,45 @@ func (w *Window) closing() {
 	}
 }
 
+// destroyed is called by the backend when the native window was destroyed. It
+// is cleaned up later, see application.cleanUp.
+func (w *Window) destroyed() {
+	w.removeTrayIcons()
+	w.unregisterHotKeys()
+	// The menu bar is destroyed with the window.
+	if w.menuBar != nil {
+		w.menuBar.detach()
+		w.menuBar = nil
+	}
+	w.calls.close()
+	app.remove(w)
+	app.closed = append(app.closed, w)
+}
+
 func (w *Window) destroy() {
+	w.stopTimers()
 	if w.handle != 0 {
 		for _, c := range w.children {
 			c.destroy()
 		}
-		w32.DestroyWindow(w.handle)
-		w.handle = 0
-	}
-}
-
-func (w *Window) onWM_COMMAND(wParam, lParam uintptr) {
-	wHi := (wParam & 0xFFFF0000) >> 16
-	wLo := wParam & 0xFFFF
-	if lParam == 0 && wHi == 0 {
-		// low word of w contains menu ID
-		id := int(wLo)
-		if 0 <= id && id < len(w.menuStrings) {
-			f := w.menuStrings[id].onClick
-			if f != nil {
-				f()
-			}
-		}
-	} else if lParam == 0 && wHi == 1 {
-		// low word of w contains accelerator ID
-		index := int(wLo)
-		if 0 <= index && index < len(w.shortcuts) {
-			w.shortcuts[index].f()
-		}
-	} else if lParam != 0 {
-		// control clicked
-		index := wParam & 0xFFFF
-		cmd := (wParam & 0xFFFF0000) >> 16
-		if index < uintptr(len(w.controls)) {
-			w.controls[index].handleNotification(cmd)
-		}
-	}
-}
-
-func (w *Window) onWM_NOTIFY(wParam, lParam uintptr) {
-	header := *((*w32.NMHDR)(unsafe.Pointer(lParam)))
-	if header.Code == uint32(w32.UDN_DELTAPOS) {
-		i := int(wParam)
-		if 0 <= i && i < len(w.controls) {
-			if f, ok := w.controls[i].(*FloatUpDown); ok {
-				updown := *((*w32.NMUPDOWN)(unsafe.Pointer(lParam)))
-				f.SetValue(f.value - float64(updown.Delta))
-			}
-		}
-	} else if header.Code == w32.LVN_ITEMCHANGED&0xFFFFFFFF {
-		i := int(wParam)
-		if 0 <= i && i < len(w.controls) {
-			if t, ok := w.controls[i].(*StringTable); ok {
-				change := *((*w32.NMLISTVIEW)(unsafe.Pointer(lParam)))
-				if change.UChanged == w32.LVIF_STATE {
-					if change.UNewState&(w32.LVIS_FOCUSED|w32.LVIS_SELECTED) != 0 {
-						t.newItemSelected(int(change.IItem))
-					} else {
-						t.itemDeselected()
-					}
-				}
-			}
+		if w.statusBar != nil {
+			w.statusBar.destroy()
 		}
+		ui.destroy(w.handle)
+		w.handle = 0
+		// The tooltip window belongs to the window and is gone with it.
+		w.toolTipHandle = 0
+		releaseScaledFonts(fontsOf(w)...)
 	}
 }
 
-// hideConsoleWindow hides the associated console window that gets created for
-// Windows applications that are of type console instead of type GUI. When
-// building you can pass the ldflag H=windowsgui to suppress this but if you
-// just go build or go run, a console window will pop open along with the GUI
-// window. hideConsoleWindow hides it.
-func hideConsoleWindow() {
-	console := w32.GetConsoleWindow()
-	if console == 0 {
-		return // No console attached.
-	}
-	// If this application is the process that created the console window, then
-	// this program was not compiled with the -H=windowsgui flag and on start-up
-	// it created a console along with the main application window. In this case
-	// hide the console window. See
-	// http://stackoverflow.com/questions/9009333/how-to-check-if-the-program-is-run-from-a-console
-	_, consoleProcID := w32.GetWindowThreadProcessId(console)
-	if w32.GetCurrentProcessId() == consoleProcID {
-		w32.ShowWindowAsync(console, w32.SW_HIDE)
-	}
-}
-
-func setManifest() {
-	manifest := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<assembly xmlns="urn:schemas-microsoft-com:asm.v1" manifestVersion="1.0">
-    <dependency>
-        <dependentAssembly>
-            <assemblyIdentity
-				type="win32"
-				processorArchitecture="*"
-				language="*"
-				name="Microsoft.Windows.Common-Controls"
-				version="6.0.0.0"
-				publicKeyToken="6595b64144ccf1df"
-			/>
-        </dependentAssembly>
-    </dependency>
-</assembly>`
-	// Create a temporary manifest file, load it, then delete it.
-	f, err := ioutil.TempFile("", "manifest_")
-	if err != nil {
-		return
-	}
-	manifestPath := f.Name()
-	defer os.Remove(manifestPath)
-	f.WriteString(manifest)
-	f.Close()
-	ctx := w32.CreateActCtx(&w32.ACTCTX{
-		Source: syscall.StringToUTF16Ptr(manifestPath),
-	})
-	w32.ActivateActCtx(ctx)
-}
-
 func (w *Window) applyIcon() {
 	if w.handle != 0 {
 		var h uintptr
 		if w.icon != nil {
-			h = uintptr(w.icon.handle)
+			h = w.icon.handle
 		}
-		w32.SendMessage(w.handle, w32.WM_SETICON, w32.ICON_SMALL, h)
-		w32.SendMessage(w.handle, w32.WM_SETICON, w32.ICON_SMALL2, h)
-		w32.SendMessage(w.handle, w32.WM_SETICON, w32.ICON_BIG, h)
+		ui.setIcon(w.handle, h)
 	}
 }
 
@@ -1204,20 +1062,16 @@ func (w *Window) SetIcon(icon *Icon) {
 	w.applyIcon()
 }
 
-// TODO Show and ShowModal are very similar and need to stay in sync, refactor
-// the two functions for easier maintenance.
-
 func (w *Window) ShowModal() error {
 	if w.handle != 0 {
 		return errors.New("wui.Window.ShowModal: window already visible")
 	}
 
-	w.parent = windows.top()
-	if w.parent == nil {
+	parent := app.top()
+	if parent == nil {
 		return w.Show()
 	}
-	windows.push(w)
-	defer windows.pop()
+	w.parent = parent
 
 	if w.icon == nil {
 		w.icon = w.parent.icon
@@ -1230,60 +1084,16 @@ func (w *Window) ShowModal() error {
 	// message with a restored window state arrive before we call ShowWindow.
 	state := w.state
 
-	window := w32.CreateWindowEx(
-		w.extendedStyle(),
-		syscall.StringToUTF16Ptr(w.className),
-		syscall.StringToUTF16Ptr(w.title),
-		w.style(),
-		w.x, w.y, w.width, w.height,
-		w.parent.handle,
-		0, 0, nil,
-	)
-	if window == 0 {
-		return errors.New("wui.Window.ShowModal: CreateWindowEx failed")
-	}
-	w.handle = window
-	if w.alpha != 255 {
-		w32.SetLayeredWindowAttributes(w.handle, 0, w.alpha, w32.LWA_ALPHA)
-	}
-	if w.hidesCloseButton {
-		w32.EnableMenuItem(
-			w32.GetSystemMenu(w.handle, false),
-			w32.SC_CLOSE,
-			w32.MF_BYCOMMAND|w32.MF_DISABLED|w32.MF_GRAYED,
-		)
-	}
-
-	w32.SetWindowSubclass(w.handle, syscall.NewCallback(func(
-		window w32.HWND,
-		msg uint32,
-		wParam, lParam uintptr,
-		subclassID uintptr,
-		refData uintptr,
-	) uintptr {
-		return w.onMsg(window, msg, wParam, lParam)
-	}), 0, 0)
-
-	w.updateAccelerators()
-	w.lastInnerWidth, w.lastInnerHeight = w.InnerSize()
-	w.createContents()
-	w.applyIcon()
-	w32.ShowWindow(window, state.toCmd())
-	w32.EnableWindow(w.parent.handle, false)
-	w.readBounds()
-	if w.onShow != nil {
-		w.onShow()
-	}
-
-	var msg w32.MSG
-	for w32.GetMessage(&msg, 0, 0, 0) != 0 {
-		if !w.interceptMessage(&msg) {
-			if w.accelTable == 0 || !w32.TranslateAccelerator(w.handle, w.accelTable, &msg) {
-				w32.TranslateMessage(&msg)
-				w32.DispatchMessage(&msg)
-			}
-		}
+	if err := ui.createWindow(w); err != nil {
+		return errors.New("wui.Window.ShowModal: " + err.Error())
 	}
+	app.add(w)
+	w.calls.open(w.handle)
+	w.setUp(state)
+	ui.runMessageLoop(func() bool {
+		app.cleanUp()
+		return w.handle == 0 || app.quit
+	})
 	return nil
 }
 
@@ -1310,7 +1120,7 @@ func (w *Window) EnableAltF4() {
 
 func (w *Window) Destroy() {
 	if w.handle != 0 {
-		w32.DestroyWindow(w.handle)
+		ui.destroy(w.handle)
 	}
 }
 
@@ -1321,76 +1131,76 @@ func (w *Window) Alpha() uint8 {
 func (w *Window) SetAlpha(a uint8) {
 	w.alpha = a
 	if w.handle != 0 {
-		style := w32.GetWindowLong(w.handle, w32.GWL_EXSTYLE)
-		if w.alpha != 255 {
-			if style&w32.WS_EX_LAYERED == 0 {
-				w32.SetWindowLong(
-					w.handle,
-					w32.GWL_EXSTYLE,
-					style|w32.WS_EX_LAYERED,
-				)
-			}
-			w32.SetLayeredWindowAttributes(
-				w.handle,
-				0,
-				w.alpha,
-				w32.LWA_ALPHA,
-			)
-		} else {
-			w32.SetWindowLong(
-				w.handle,
-				w32.GWL_EXSTYLE,
-				style & ^w32.WS_EX_LAYERED,
-			)
-			w32.RedrawWindow(
-				w.handle,
-				nil,
-				0,
-				w32.RDW_ERASE|w32.RDW_INVALIDATE|w32.RDW_FRAME|w32.RDW_ALLCHILDREN,
-			)
-		}
+		ui.setAlpha(w.handle, w.alpha)
 	}
 }
 
 type shortcut struct {
-	// accel has its Cmd set to 0 for comparibility. It will be copied and Cmd
-	// assigned on the copies when creating the accelerator table.
-	accel w32.ACCEL
-	f     func()
+	keys accelerator
+	f    func()
+	// menuItem is the MenuString that registered the shortcut, nil if it was
+	// set with Window.SetShortcut.
+	menuItem *MenuString
 }
 
-func toACCEL(keys []Key) w32.ACCEL {
-	var a w32.ACCEL
-	a.Virt = w32.FVIRTKEY
+// accelerator is a key combination that triggers a shortcut. It is comparable
+// so we can find existing shortcuts for the same keys.
+type accelerator struct {
+	key                 Key
+	control, shift, alt bool
+}
+
+func toAccelerator(keys []Key) accelerator {
+	var a accelerator
 	for _, key := range keys {
 		switch key {
 		case KeyControl, KeyLeftControl, KeyRightControl:
-			a.Virt |= w32.FCONTROL
+			a.control = true
 		case KeyShift, KeyLeftShift, KeyRightShift:
-			a.Virt |= w32.FSHIFT
+			a.shift = true
 		case KeyAlt, KeyLeftAlt, KeyRightAlt:
-			a.Virt |= w32.FALT
+			a.alt = true
 		default:
-			a.Key = uint16(key)
+			a.key = key
 		}
 	}
 	return a
 }
 
 func (w *Window) SetShortcut(f func(), keys ...Key) {
+	w.setShortcut(shortcut{f: f}, keys)
+}
+
+// removeMenuShortcut removes the shortcut of the menu item, unless the keys
+// were assigned to something else in the meantime.
+func (w *Window) removeMenuShortcut(m *MenuString, keys []Key) {
 	if len(keys) == 0 {
 		return
 	}
-	if w.accelTable != 0 {
+	a := toAccelerator(keys)
+	for _, s := range w.shortcuts {
+		if s.keys == a && s.menuItem == m {
+			w.setShortcut(shortcut{}, keys)
+			return
+		}
+	}
+}
+
+// setShortcut sets s for the keys, s.f being nil removes the shortcut.
+func (w *Window) setShortcut(s shortcut, keys []Key) {
+	if len(keys) == 0 {
+		return
+	}
+	if w.handle != 0 {
 		defer w.updateAccelerators()
 	}
-	s := shortcut{accel: toACCEL(keys), f: f}
+	s.keys = toAccelerator(keys)
 	// Look for an existing shortcut for this key combination and replace it if
 	// we find it.
 	for i := range w.shortcuts {
-		if w.shortcuts[i].accel == s.accel {
-			w.shortcuts[i].f = f // Replace the handler function.
-			if f == nil {
+		if w.shortcuts[i].keys == s.keys {
+			w.shortcuts[i] = s // Replace the handler function and its owner.
+			if s.f == nil {
 				// Setting nil deletes the shortcut.
 				w.shortcuts = append(w.shortcuts[:i], w.shortcuts[i+1:]...)
 			}
@@ -1405,24 +1215,11 @@ func (w *Window) SetShortcut(f func(), keys ...Key) {
 }
 
 func (w *Window) updateAccelerators() {
-	if w.accelTable != 0 {
-		w32.DestroyAcceleratorTable(w.accelTable)
-		w.accelTable = 0
-	}
-	if len(w.shortcuts) > 0 {
-		// NOTE There is a bug somewhere in our system: when there is only one
-		// accelerator in the array, CreateAcceleratorTable will return an
-		// invalid 0 handle. Thus a single shortcut will never be triggered. As
-		// soon as there is another accelerator, it works. This is why we add +1
-		// to the length here. The last accelerator is never used, its index is
-		// outside w.shortcuts and thus ignored.
-		accels := make([]w32.ACCEL, len(w.shortcuts)+1)
-		for i := range w.shortcuts {
-			accels[i] = w.shortcuts[i].accel
-			accels[i].Cmd = uint16(i)
-		}
-		w.accelTable = w32.CreateAcceleratorTable(accels)
+	keys := make([]accelerator, len(w.shortcuts))
+	for i := range w.shortcuts {
+		keys[i] = w.shortcuts[i].keys
 	}
+	w.accelTable = ui.setAccelerators(w.handle, w.accelTable, keys)
 }
 
 // TODO Have good scrollbars. Do we still want to have Scroll? For other
@@ -1430,7 +1227,7 @@ func (w *Window) updateAccelerators() {
 
 func (w *Window) Scroll(dx, dy int) {
 	if w.handle != 0 {
-		w32.ScrollWindow(w.handle, dx, dy, nil, nil)
+		ui.scroll(w.handle, dx, dy)
 	}
 }
 
@@ -1438,8 +1235,57 @@ func (w *Window) Scroll(dx, dy int) {
 
 func (w *Window) Repaint() {
 	if w.handle != 0 {
-		w32.InvalidateRect(w.handle, nil, true)
+		ui.repaint(w.handle)
+	}
+}
+
+// DPI returns the resolution of the monitor that the window is on, see
+// DefaultDPI. Before the window is shown, DPI returns DefaultDPI.
+func (w *Window) DPI() int {
+	if w.handle == 0 || w.dpi == 0 {
+		return DefaultDPI
+	}
+	return w.dpi
+}
+
+// applyBounds sets the bounds of the native window, scaled to its DPI.
+func (w *Window) applyBounds(x, y, width, height int) {
+	x, y, width, height = scaleBounds(x, y, width, height, w.DPI())
+	ui.setBounds(w.handle, x, y, width, height)
+}
+
+func (w *Window) getDPI() int {
+	return w.DPI()
+}
+
+func (w *Window) OnDPIChange() func() {
+	return w.onDPIChange.primary
+}
+
+// SetOnDPIChange sets a function that is called after the window was moved to
+// a monitor with a different DPI and was scaled to it.
+func (w *Window) SetOnDPIChange(f func()) {
+	w.onDPIChange.primary = f
+}
+
+func (w *Window) DPIChangeEvent() *Event {
+	return &w.onDPIChange
+}
+
+// dpiChanged is called by the backend when the window was moved to a monitor
+// with a different DPI. x, y, width and height are the new window bounds in
+// pixels that the system suggests.
+func (w *Window) dpiChanged(dpi, x, y, width, height int) {
+	w.dpi = dpi
+	rescale(w)
+	if w.statusBar != nil {
+		w.statusBar.applyFont()
 	}
+	// The fonts for the old DPI might not be needed anymore.
+	releaseScaledFonts(fontsOf(w)...)
+	w.applyToolTipOptions()
+	ui.setBounds(w.handle, x, y, width, height)
+	w.onDPIChange.fire()
 }
 
 // Monitor returns the handle to the monitor (HMONITOR) that the window is over.
@@ -1448,7 +1294,7 @@ func (w *Window) Monitor() uintptr {
 	if w.handle == 0 {
 		return 0
 	}
-	return uintptr(w32.MonitorFromWindow(w.handle, w32.MONITOR_DEFAULTTONULL))
+	return ui.monitor(w.handle)
 }
 
 func (w *Window) Parent() Container {
@@ -1460,7 +1306,7 @@ func (w *Window) Parent() Container {
 }
 
 func (w *Window) Handle() uintptr {
-	return uintptr(w.handle)
+	return w.handle
 }
 
 func (w *Window) HasMinButton() bool {
@@ -1470,21 +1316,15 @@ func (w *Window) HasMinButton() bool {
 func (w *Window) SetHasMinButton(hasMin bool) {
 	w.hidesMinButton = !hasMin
 	if w.handle != 0 {
-		style := w32.GetWindowLong(w.handle, w32.GWL_STYLE)
+		style, exStyle := ui.style(w.handle)
 
 		if hasMin {
-			style = style | w32.WS_MINIMIZEBOX
+			style = style | win.WS_MINIMIZEBOX
 		} else {
-			style = style & ^w32.WS_MINIMIZEBOX
+			style = style & ^uint(win.WS_MINIMIZEBOX)
 		}
 
-		w32.SetWindowLong(w.handle, w32.GWL_STYLE, style)
-		w32.SetWindowPos(
-			w.handle,
-			0, 0, 0, 0, 0,
-			w32.SWP_FRAMECHANGED|w32.SWP_NOMOVE|w32.SWP_NOZORDER|
-				w32.SWP_NOSIZE|w32.SWP_NOACTIVATE,
-		)
+		ui.setStyle(w.handle, style, exStyle)
 	}
 }
 
@@ -1495,21 +1335,15 @@ func (w *Window) HasMaxButton() bool {
 func (w *Window) SetHasMaxButton(hasMax bool) {
 	w.hidesMaxButton = !hasMax
 	if w.handle != 0 {
-		style := w32.GetWindowLong(w.handle, w32.GWL_STYLE)
+		style, exStyle := ui.style(w.handle)
 
 		if hasMax {
-			style = style | w32.WS_MAXIMIZEBOX
+			style = style | win.WS_MAXIMIZEBOX
 		} else {
-			style = style & ^w32.WS_MAXIMIZEBOX
+			style = style & ^uint(win.WS_MAXIMIZEBOX)
 		}
 
-		w32.SetWindowLong(w.handle, w32.GWL_STYLE, style)
-		w32.SetWindowPos(
-			w.handle,
-			0, 0, 0, 0, 0,
-			w32.SWP_FRAMECHANGED|w32.SWP_NOMOVE|w32.SWP_NOZORDER|
-				w32.SWP_NOSIZE|w32.SWP_NOACTIVATE,
-		)
+		ui.setStyle(w.handle, style, exStyle)
 	}
 }
 
@@ -1520,11 +1354,7 @@ func (w *Window) HasCloseButton() bool {
 func (w *Window) SetHasCloseButton(hasClose bool) {
 	w.hidesCloseButton = !hasClose
 	if w.handle != 0 {
-		var state uint = w32.MF_BYCOMMAND | w32.MF_DISABLED | w32.MF_GRAYED
-		if hasClose {
-			state = w32.MF_BYCOMMAND | w32.MF_ENABLED
-		}
-		w32.EnableMenuItem(w32.GetSystemMenu(w.handle, false), w32.SC_CLOSE, state)
+		ui.setCloseButtonEnabled(w.handle, hasClose)
 	}
 }
 
@@ -1568,18 +1398,12 @@ func (w *Window) changeStyles(change func()) {
 		x, y, width, height := w.InnerBounds()
 		// Update to the new window styles.
 		change()
-		w32.SetWindowLong(w.handle, w32.GWL_STYLE, int32(w.style()))
-		w32.SetWindowLong(w.handle, w32.GWL_EXSTYLE, int32(w.extendedStyle()))
+		ui.setStyle(w.handle, w.style(), w.extendedStyle())
 		// Restore the original inner bounds.
 		w.SetInnerBounds(x, y, width, height)
-		// SetWindowPos and ShowWindow are necessary to make Windows realize the
+		// Showing the window again is necessary to make Windows realize the
 		// changes in styles.
-		w32.SetWindowPos(
-			w.handle, 0, 0, 0, 0, 0,
-			w32.SWP_FRAMECHANGED|w32.SWP_NOMOVE|w32.SWP_NOZORDER|
-				w32.SWP_NOSIZE|w32.SWP_NOACTIVATE,
-		)
-		w32.ShowWindow(w.handle, w32.SW_SHOWNORMAL)
+		ui.setWindowState(w.handle, WindowNormal)
 		// Restore the original window state.
 		w.SetState(state)
 	}
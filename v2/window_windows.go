@@ -0,0 +1,621 @@
+package wui
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+func toCmd(s WindowState) int {
+	switch s {
+	case WindowMaximized:
+		return w32.SW_MAXIMIZE
+	case WindowMinimized:
+		return w32.SW_MINIMIZE
+	default:
+		return w32.SW_SHOWNORMAL
+	}
+}
+
+func (a *winAPI) createWindow(w *Window) error {
+	var owner w32.HWND
+	var atom w32.ATOM
+	if w.parent == nil {
+		runtime.LockOSThread()
+		atomic.StoreUint32(&a.uiThread, getCurrentThreadId())
+		if !w.showConsole {
+			hideConsoleWindow()
+		}
+		setManifest()
+		enableDPIAwareness()
+
+		class := w32.WNDCLASSEX{
+			Background: w32.CreateSolidBrush(uint32(w.background)),
+			WndProc:    syscall.NewCallback(w.onMsg),
+			Cursor:     w32.HCURSOR(w.cursor.handle),
+			ClassName:  syscall.StringToUTF16Ptr(w.className),
+		}
+		atom = w32.RegisterClassEx(&class)
+		if atom == 0 {
+			return errors.New("RegisterClassEx failed")
+		}
+	} else {
+		owner = w32.HWND(w.parent.handle)
+	}
+
+	window := w32.CreateWindowEx(
+		w.extendedStyle(),
+		syscall.StringToUTF16Ptr(w.className),
+		syscall.StringToUTF16Ptr(w.title),
+		w.style(),
+		w.x, w.y, w.width, w.height,
+		owner,
+		0, 0, nil,
+	)
+	if window == 0 {
+		if atom != 0 {
+			w32.UnregisterClassAtom(atom, w32.GetModuleHandle(""))
+		}
+		return errors.New("CreateWindowEx failed")
+	}
+	w.handle = uintptr(window)
+
+	if atom != 0 {
+		a.classes[window] = atom
+	} else {
+		w32.SetWindowSubclass(window, syscall.NewCallback(func(
+			window w32.HWND,
+			msg uint32,
+			wParam, lParam uintptr,
+			subclassID uintptr,
+			refData uintptr,
+		) uintptr {
+			return w.onMsg(window, msg, wParam, lParam)
+		}), 0, 0)
+	}
+
+	if w.alpha != 255 {
+		w32.SetLayeredWindowAttributes(window, 0, w.alpha, w32.LWA_ALPHA)
+	}
+	if w.hidesCloseButton {
+		a.setCloseButtonEnabled(w.handle, false)
+	}
+	a.registerDropTarget(w)
+	a.listenToClipboard(w.handle)
+	return nil
+}
+
+// wmInvoke is sent to a window to make it run the calls queued with Post and
+// Invoke.
+var wmInvoke = registerWindowMessage("wui.Window.Invoke")
+
+func (*winAPI) wake(window uintptr) {
+	w32.PostMessage(w32.HWND(window), wmInvoke, 0, 0)
+}
+
+func (a *winAPI) isUIThread() bool {
+	thread := atomic.LoadUint32(&a.uiThread)
+	return thread != 0 && thread == getCurrentThreadId()
+}
+
+func (*winAPI) runMessageLoop(done func() bool) {
+	var msg w32.MSG
+	for !done() && w32.GetMessage(&msg, 0, 0, 0) > 0 {
+		// Keyboard input goes to the active window so we use its tab handling
+		// and shortcuts.
+		w := app.active
+		if w == nil {
+			w32.TranslateMessage(&msg)
+			w32.DispatchMessage(&msg)
+			continue
+		}
+		if msg.Message == w32.WM_KEYDOWN && msg.WParam == w32.VK_TAB {
+			shiftDown := w32.GetKeyState(w32.VK_SHIFT)&0x8000 != 0
+			if w.tabPressed(shiftDown) {
+				continue
+			}
+		}
+		accel := w32.HACCEL(w.accelTable)
+		if accel == 0 || !w32.TranslateAccelerator(w32.HWND(w.handle), accel, &msg) {
+			w32.TranslateMessage(&msg)
+			w32.DispatchMessage(&msg)
+		}
+	}
+}
+
+func (w *Window) onMsg(window w32.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if w.onMessage != nil {
+		handled, result := w.onMessage(uintptr(window), msg, wParam, lParam)
+		if handled {
+			return result
+		}
+	}
+
+	if msg == wmInvoke {
+		w.runCalls()
+		return 0
+	}
+	if msg == wmTrayIcon {
+		w.onTrayIconMessage(wParam, lParam)
+		return 0
+	}
+	if msg == wmTaskbarCreated {
+		ui.(*winAPI).taskbarCreated(w.handle)
+		w.showTrayIcons()
+		return 0
+	}
+
+	mouseX := int(lParam & 0xFFFF)
+	mouseY := int(lParam&0xFFFF0000) >> 16
+	switch msg {
+	case w32.WM_MOUSEMOVE:
+		if !w.onMouseMove.empty() {
+			w.onMouseMove.fire(mouseX, mouseY)
+			return 0
+		}
+	case w32.WM_MOUSEWHEEL:
+		if !w.onMouseWheel.empty() {
+			delta := float64(int16((wParam&0xFFFF0000)>>16)) / 120
+			w.onMouseWheel.fire(mouseX, mouseY, delta)
+		}
+		return 0
+	case w32.WM_LBUTTONDOWN, w32.WM_MBUTTONDOWN, w32.WM_RBUTTONDOWN:
+		if !w.onMouseDown.empty() {
+			b := MouseButtonLeft
+			if msg == w32.WM_MBUTTONDOWN {
+				b = MouseButtonMiddle
+			}
+			if msg == w32.WM_RBUTTONDOWN {
+				b = MouseButtonRight
+			}
+			w.onMouseDown.fire(b, mouseX, mouseY)
+		}
+		return 0
+	case w32.WM_LBUTTONUP, w32.WM_MBUTTONUP, w32.WM_RBUTTONUP:
+		if !w.onMouseUp.empty() {
+			b := MouseButtonLeft
+			if msg == w32.WM_MBUTTONUP {
+				b = MouseButtonMiddle
+			}
+			if msg == w32.WM_RBUTTONUP {
+				b = MouseButtonRight
+			}
+			w.onMouseUp.fire(b, mouseX, mouseY)
+		}
+		if msg == w32.WM_RBUTTONUP {
+			// DefWindowProc sends us the WM_CONTEXTMENU.
+			break
+		}
+		return 0
+	case w32.WM_INITMENUPOPUP:
+		w.menuOpened(wParam)
+	case w32.WM_DRAWITEM:
+		w.onWM_DRAWITEM(wParam, lParam)
+		return 0
+	case w32.WM_KEYDOWN:
+		if !w.onKeyDown.empty() {
+			w.onKeyDown.fire(int(wParam))
+			return 0
+		}
+	case w32.WM_KEYUP:
+		if !w.onKeyUp.empty() {
+			w.onKeyUp.fire(int(wParam))
+			// DefWindowProc turns the menu key into a WM_CONTEXTMENU.
+			if wParam != w32.VK_APPS {
+				return 0
+			}
+		}
+	case w32.WM_CHAR:
+		if !w.onChar.empty() {
+			w.onChar.fire(utf16.Decode([]uint16{uint16(wParam)})[0])
+			return 0
+		}
+	case w32.WM_COMMAND:
+		w.onWM_COMMAND(wParam, lParam)
+		return 0
+	case w32.WM_SYSCOMMAND:
+		if w.altF4disabled && wParam == w32.SC_CLOSE && (lParam>>16) <= 0 {
+			return 0
+		}
+		return w32.DefWindowProc(window, msg, wParam, lParam)
+	case w32.WM_NOTIFY:
+		return w.onWM_NOTIFY(wParam, lParam)
+	case w32.WM_SIZE:
+		state := w.state
+		switch wParam {
+		case w32.SIZE_MAXIMIZED:
+			state = WindowMaximized
+		case w32.SIZE_MINIMIZED:
+			state = WindowMinimized
+		case w32.SIZE_RESTORED:
+			state = WindowNormal
+		}
+		if w.statusBar != nil && w.statusBar.handle != 0 {
+			// The status bar moves itself to the bottom of the window.
+			w32.SendMessage(w32.HWND(w.statusBar.handle), w32.WM_SIZE, 0, 0)
+		}
+		w.resized(state)
+		w32.InvalidateRect(window, nil, true)
+		return 0
+	case wmDPIChanged:
+		r := *((*w32.RECT)(unsafe.Pointer(lParam)))
+		w.dpiChanged(
+			int(wParam&0xFFFF),
+			int(r.Left), int(r.Top), int(r.Width()), int(r.Height()),
+		)
+		return 0
+	case w32.WM_ACTIVATE:
+		w.activated(wParam != 0)
+		return 0
+	case w32.WM_HSCROLL, w32.WM_VSCROLL:
+		w.sliderScrolled(lParam, wParam&0xFFFF)
+	case w32.WM_TIMER:
+		w.timerFired(wParam)
+		return 0
+	case w32.WM_HOTKEY:
+		w.hotKeyPressed(int(wParam))
+		return 0
+	case w32.WM_CONTEXTMENU:
+		// wParam is the window or control that was clicked, it might be a
+		// control without a context menu of its own.
+		x, y := contextMenuPos(w32.HWND(wParam), lParam)
+		left, top, width, height := ui.clientBounds(w.handle)
+		inClient := left <= x && x < left+width && top <= y && y < top+height
+		if inClient && w.contextMenuRequested(x, y) {
+			return 0
+		}
+	case w32.WM_CLIPBOARDUPDATE:
+		Clipboard.changed()
+		return 0
+	case w32.WM_DESTROY:
+		ui.(*winAPI).revokeDropTarget(w.handle)
+		ui.(*winAPI).stopListeningToClipboard(w.handle)
+		w.destroyed()
+		return 0
+	case w32.WM_CLOSE:
+		if !w.closeRequested() {
+			return 0
+		}
+	}
+	return w32.DefWindowProc(window, msg, wParam, lParam)
+}
+
+func (w *Window) onWM_COMMAND(wParam, lParam uintptr) {
+	wHi := (wParam & 0xFFFF0000) >> 16
+	wLo := wParam & 0xFFFF
+	if lParam == 0 && wHi == 0 {
+		// low word of w contains menu ID
+		w.menuClicked(int(wLo))
+	} else if lParam == 0 && wHi == 1 {
+		// low word of w contains accelerator ID
+		w.shortcutPressed(int(wLo))
+	} else if lParam != 0 {
+		// control clicked
+		w.controlNotified(int(wLo), wHi)
+	}
+}
+
+func (w *Window) onWM_NOTIFY(wParam, lParam uintptr) uintptr {
+	header := *((*w32.NMHDR)(unsafe.Pointer(lParam)))
+	if header.Code == ttnGetDispInfoW {
+		w.onToolTipText(lParam)
+	} else if w.statusBar != nil &&
+		uintptr(header.HwndFrom) == w.statusBar.handle {
+		return w.statusBar.onNotify(header.Code, lParam)
+	} else if header.Code == uint32(w32.UDN_DELTAPOS) {
+		i := int(wParam)
+		if 0 <= i && i < len(w.controls) {
+			// Tab controls with many tabs have up-down controls of their own.
+			f, ok := w.controls[i].(*FloatUpDown)
+			if ok && uintptr(header.HwndFrom) == f.upDownHandle {
+				updown := *((*w32.NMUPDOWN)(unsafe.Pointer(lParam)))
+				f.SetValue(f.value - float64(updown.Delta))
+			}
+		}
+	} else if header.Code == w32.LVN_ITEMCHANGED&0xFFFFFFFF {
+		i := int(wParam)
+		if 0 <= i && i < len(w.controls) {
+			if t, ok := w.controls[i].(*StringTable); ok {
+				change := *((*w32.NMLISTVIEW)(unsafe.Pointer(lParam)))
+				if change.UChanged == w32.LVIF_STATE {
+					if change.UNewState&(w32.LVIS_FOCUSED|w32.LVIS_SELECTED) != 0 {
+						t.newItemSelected(int(change.IItem))
+					} else {
+						t.itemDeselected()
+					}
+				}
+			}
+		}
+	} else if i := int(wParam); 0 <= i && i < len(w.controls) {
+		switch c := w.controls[i].(type) {
+		case *TreeView:
+			return c.onNotify(header.Code, lParam)
+		case *TabControl:
+			return c.onNotify(header.Code, lParam)
+		}
+	}
+	return 0
+}
+
+// hideConsoleWindow hides the associated console window that gets created for
+// Windows applications that are of type console instead of type GUI. When
+// building you can pass the ldflag H=windowsgui to suppress this but if you
+// just go build or go run, a console window will pop open along with the GUI
+// window. hideConsoleWindow hides it.
+func hideConsoleWindow() {
+	console := w32.GetConsoleWindow()
+	if console == 0 {
+		return // No console attached.
+	}
+	// If this application is the process that created the console window, then
+	// this program was not compiled with the -H=windowsgui flag and on start-up
+	// it created a console along with the main application window. In this case
+	// hide the console window. See
+	// http://stackoverflow.com/questions/9009333/how-to-check-if-the-program-is-run-from-a-console
+	_, consoleProcID := w32.GetWindowThreadProcessId(console)
+	if w32.GetCurrentProcessId() == consoleProcID {
+		w32.ShowWindowAsync(console, w32.SW_HIDE)
+	}
+}
+
+func setManifest() {
+	manifest := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<assembly xmlns="urn:schemas-microsoft-com:asm.v1" manifestVersion="1.0">
+    <dependency>
+        <dependentAssembly>
+            <assemblyIdentity
+				type="win32"
+				processorArchitecture="*"
+				language="*"
+				name="Microsoft.Windows.Common-Controls"
+				version="6.0.0.0"
+				publicKeyToken="6595b64144ccf1df"
+			/>
+        </dependentAssembly>
+    </dependency>
+    <application xmlns="urn:schemas-microsoft-com:asm.v3">
+        <windowsSettings>
+            <dpiAwareness xmlns="http://schemas.microsoft.com/SMI/2016/WindowsSettings">PerMonitorV2</dpiAwareness>
+        </windowsSettings>
+    </application>
+</assembly>`
+	// Create a temporary manifest file, load it, then delete it.
+	f, err := ioutil.TempFile("", "manifest_")
+	if err != nil {
+		return
+	}
+	manifestPath := f.Name()
+	defer os.Remove(manifestPath)
+	f.WriteString(manifest)
+	f.Close()
+	ctx := w32.CreateActCtx(&w32.ACTCTX{
+		Source: syscall.StringToUTF16Ptr(manifestPath),
+	})
+	w32.ActivateActCtx(ctx)
+}
+
+// enableDPIAwareness makes Windows send us WM_DPICHANGED instead of stretching
+// our windows. Windows only reads the DPI awareness of the manifest that is
+// embedded in the executable, not the one we activate in setManifest, so we
+// have to set it here.
+func enableDPIAwareness() {
+	if !setProcessDpiAwarenessContext(dpiAwarenessContextPerMonitorAwareV2) {
+		// Before Windows 10 1703 there is only per-monitor awareness v1.
+		w32.SetProcessDpiAwareness(w32.PROCESS_PER_MONITOR_DPI_AWARE)
+	}
+}
+
+func (*winAPI) close(window uintptr) {
+	w32.SendMessage(w32.HWND(window), w32.WM_CLOSE, 0, 0)
+}
+
+func (*winAPI) bounds(window uintptr) (x, y, width, height int) {
+	r := w32.GetWindowRect(w32.HWND(window))
+	return int(r.Left), int(r.Top), int(r.Width()), int(r.Height())
+}
+
+func (*winAPI) clientBounds(window uintptr) (x, y, width, height int) {
+	x, y = w32.ClientToScreen(w32.HWND(window), 0, 0)
+	r := w32.GetClientRect(w32.HWND(window))
+	return x, y, int(r.Width()), int(r.Height())
+}
+
+func (*winAPI) windowState(window uintptr) (WindowState, bool) {
+	var p w32.WINDOWPLACEMENT
+	if !w32.GetWindowPlacement(w32.HWND(window), &p) {
+		return WindowNormal, false
+	}
+	switch p.ShowCmd {
+	case w32.SW_MAXIMIZE:
+		return WindowMaximized, true
+	case w32.SW_SHOWMINIMIZED, w32.SW_MINIMIZE,
+		w32.SW_SHOWMINNOACTIVE, w32.SW_FORCEMINIMIZE:
+		return WindowMinimized, true
+	default:
+		return WindowNormal, true
+	}
+}
+
+func (*winAPI) setWindowState(window uintptr, s WindowState) {
+	w32.ShowWindow(w32.HWND(window), toCmd(s))
+}
+
+func (*winAPI) setBackground(window uintptr, c Color) {
+	brush := w32.CreateSolidBrush(uint32(c))
+	w32.SetClassLongPtr(w32.HWND(window), w32.GCLP_HBRBACKGROUND, uintptr(brush))
+	w32.InvalidateRect(w32.HWND(window), nil, true)
+}
+
+func (*winAPI) setCursor(window uintptr, cursor uintptr) {
+	w32.SetClassLongPtr(w32.HWND(window), w32.GCLP_HCURSOR, cursor)
+}
+
+func (*winAPI) setIcon(window uintptr, icon uintptr) {
+	h := w32.HWND(window)
+	w32.SendMessage(h, w32.WM_SETICON, w32.ICON_SMALL, icon)
+	w32.SendMessage(h, w32.WM_SETICON, w32.ICON_SMALL2, icon)
+	w32.SendMessage(h, w32.WM_SETICON, w32.ICON_BIG, icon)
+}
+
+func (*winAPI) setAlpha(window uintptr, alpha uint8) {
+	h := w32.HWND(window)
+	style := w32.GetWindowLong(h, w32.GWL_EXSTYLE)
+	if alpha != 255 {
+		if style&w32.WS_EX_LAYERED == 0 {
+			w32.SetWindowLong(h, w32.GWL_EXSTYLE, style|w32.WS_EX_LAYERED)
+		}
+		w32.SetLayeredWindowAttributes(h, 0, alpha, w32.LWA_ALPHA)
+	} else {
+		w32.SetWindowLong(h, w32.GWL_EXSTYLE, style & ^w32.WS_EX_LAYERED)
+		w32.RedrawWindow(
+			h,
+			nil,
+			0,
+			w32.RDW_ERASE|w32.RDW_INVALIDATE|w32.RDW_FRAME|w32.RDW_ALLCHILDREN,
+		)
+	}
+}
+
+func (*winAPI) setCloseButtonEnabled(window uintptr, enabled bool) {
+	var state uint = w32.MF_BYCOMMAND | w32.MF_DISABLED | w32.MF_GRAYED
+	if enabled {
+		state = w32.MF_BYCOMMAND | w32.MF_ENABLED
+	}
+	w32.EnableMenuItem(w32.GetSystemMenu(w32.HWND(window), false), w32.SC_CLOSE, state)
+}
+
+func (*winAPI) setForeground(window uintptr) {
+	w32.SetForegroundWindow(w32.HWND(window))
+}
+
+func toACCEL(a accelerator) w32.ACCEL {
+	var acc w32.ACCEL
+	acc.Virt = w32.FVIRTKEY
+	if a.control {
+		acc.Virt |= w32.FCONTROL
+	}
+	if a.shift {
+		acc.Virt |= w32.FSHIFT
+	}
+	if a.alt {
+		acc.Virt |= w32.FALT
+	}
+	acc.Key = uint16(a.key)
+	return acc
+}
+
+func (*winAPI) setAccelerators(window, table uintptr, keys []accelerator) uintptr {
+	if table != 0 {
+		w32.DestroyAcceleratorTable(w32.HACCEL(table))
+	}
+	if len(keys) == 0 {
+		return 0
+	}
+	// NOTE There is a bug somewhere in our system: when there is only one
+	// accelerator in the array, CreateAcceleratorTable will return an invalid 0
+	// handle. Thus a single shortcut will never be triggered. As soon as there
+	// is another accelerator, it works. This is why we add +1 to the length
+	// here. The last accelerator is never used, its index is outside
+	// w.shortcuts and thus ignored.
+	accels := make([]w32.ACCEL, len(keys)+1)
+	for i := range keys {
+		accels[i] = toACCEL(keys[i])
+		accels[i].Cmd = uint16(i)
+	}
+	return uintptr(w32.CreateAcceleratorTable(accels))
+}
+
+func (*winAPI) scroll(window uintptr, dx, dy int) {
+	w32.ScrollWindow(w32.HWND(window), dx, dy, nil, nil)
+}
+
+func (*winAPI) monitor(window uintptr) uintptr {
+	return uintptr(w32.MonitorFromWindow(w32.HWND(window), w32.MONITOR_DEFAULTTONULL))
+}
+
+func (*winAPI) setTimer(window, id uintptr, interval time.Duration) {
+	w32.SetTimer(w32.HWND(window), id, uint(interval/time.Millisecond), 0)
+}
+
+func (*winAPI) killTimer(window, id uintptr) {
+	killTimer(window, id)
+}
+
+func (*winAPI) now() time.Time {
+	return time.Now()
+}
+
+func (*winAPI) dpi(window uintptr) int {
+	if dpi := getDpiForWindow(window); dpi != 0 {
+		return dpi
+	}
+	// Before Windows 10 there is only the system DPI.
+	dc := w32.GetDC(0)
+	defer w32.ReleaseDC(0, dc)
+	return w32.GetDeviceCaps(dc, w32.LOGPIXELSX)
+}
+
+func (*winAPI) createMenu() uintptr {
+	return uintptr(w32.CreateMenu())
+}
+
+func (*winAPI) insertMenuItem(menu uintptr, pos int, item menuItemInfo) {
+	info := menuItemInfoW(item)
+	info.Mask |= w32.MIIM_ID | w32.MIIM_SUBMENU
+	info.ID = uint32(item.id)
+	info.SubMenu = w32.HMENU(item.subMenu)
+	w32.InsertMenuItem(w32.HMENU(menu), uint(pos), true, &info)
+}
+
+func (*winAPI) setMenuItem(menu uintptr, pos int, item menuItemInfo) {
+	info := menuItemInfoW(item)
+	w32.SetMenuItemInfo(w32.HMENU(menu), uint(pos), true, &info)
+}
+
+// menuItemInfoW returns the type, state, text and bitmap of the item.
+func menuItemInfoW(item menuItemInfo) w32.MENUITEMINFO {
+	var info w32.MENUITEMINFO
+	info.Size = uint32(unsafe.Sizeof(info))
+	if item.separator {
+		info.Mask = w32.MIIM_FTYPE
+		info.Type = w32.MFT_SEPARATOR
+		return info
+	}
+	info.Mask = w32.MIIM_FTYPE | w32.MIIM_STATE | w32.MIIM_STRING | w32.MIIM_BITMAP
+	info.Type = w32.MFT_STRING
+	if item.radio {
+		info.Type |= w32.MFT_RADIOCHECK
+	}
+	if item.disabled {
+		info.State |= w32.MFS_DISABLED
+	}
+	if item.checked {
+		info.State |= w32.MFS_CHECKED
+	}
+	if item.isDefault {
+		info.State |= w32.MFS_DEFAULT
+	}
+	info.TypeData = uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(item.text)))
+	info.BmpItem = w32.HBITMAP(item.bitmap)
+	return info
+}
+
+func (*winAPI) removeMenuItem(menu uintptr, pos int) {
+	w32.DeleteMenu(w32.HMENU(menu), uint(pos), w32.MF_BYPOSITION)
+}
+
+func (*winAPI) setMenuBar(window, menu uintptr) {
+	w32.SetMenu(w32.HWND(window), w32.HMENU(menu))
+}
+
+func (*winAPI) drawMenuBar(window uintptr) {
+	w32.DrawMenuBar(w32.HWND(window))
+}